@@ -45,9 +45,7 @@ var waitDeployedTests = map[string]struct {
 }
 
 func TestWaitDeployed(t *testing.T) {
-	/*db := ethdb.NewMemDatabase()
-	ppos_storage.NewPPosTemp(db)
-	for name, test := range waitDeployedTests {
+	/*for name, test := range waitDeployedTests {
 		backend := backends.NewSimulatedBackend(
 			core.GenesisAlloc{
 				crypto.PubkeyToAddress(testKey.PublicKey): {Balance: big.NewInt(10000000000)},
@@ -51,7 +51,7 @@ import (
 const ExternalAPIVersion = "2.0.0"
 
 // InternalAPIVersion -- see intapi_changelog.md
-const InternalAPIVersion = "2.0.0"
+const InternalAPIVersion = "2.1.0"
 
 const legalWarning = `
 WARNING! 
@@ -99,6 +99,10 @@ var (
 		Usage: "File used for writing new 4byte-identifiers submitted via API",
 		Value: "./4byte-custom.json",
 	}
+	wasmDBFlag = cli.StringFlag{
+		Name:  "wasmabidb",
+		Usage: "File containing the abi definitions of WASM contracts, keyed by contract address",
+	}
 	auditLogFlag = cli.StringFlag{
 		Name:  "auditlog",
 		Usage: "File used to emit audit logs. Set to \"\" to disable",
@@ -187,6 +191,7 @@ func init() {
 		signerSecretFlag,
 		dBFlag,
 		customDBFlag,
+		wasmDBFlag,
 		auditLogFlag,
 		ruleFlag,
 		stdiouiFlag,
@@ -330,6 +335,12 @@ func signer(c *cli.Context) error {
 		utils.Fatalf(err.Error())
 	}
 	log.Info("Loaded 4byte db", "signatures", db.Size(), "file", c.String("4bytedb"))
+	if file := c.String(wasmDBFlag.Name); file != "" {
+		if err := db.LoadWasmAbis(file); err != nil {
+			utils.Fatalf(err.Error())
+		}
+		log.Info("Loaded WASM abi db", "file", file)
+	}
 
 	var (
 		api core.ExternalAPI
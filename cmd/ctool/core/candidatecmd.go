@@ -0,0 +1,73 @@
+package core
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/PlatONnetwork/PlatON-Go/cmd/utils"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	SignCandidateCmd = cli.Command{
+		Name:   "signCandidate",
+		Usage:  "sign a candidate deposit with the node key and prove its BLS key",
+		Action: signCandidateCmd,
+		Flags:  signCandidateCmdFlags,
+	}
+)
+
+func signCandidateCmd(c *cli.Context) error {
+	nodeKey := c.String(NodeKeyFilePathFlag.Name)
+	owner := c.String(CandidateOwnerFlag.Name)
+	chainId := c.Uint64(ChainIdFlag.Name)
+	nonce := c.Uint64(CandidateNonceFlag.Name)
+
+	sig, err := SignCandidate(nodeKey, owner, chainId, nonce)
+	if err != nil {
+		utils.Fatalf("Sign candidate error: %v", err)
+	}
+
+	blsPubKey, blsProof, err := ProveBlsKey(nodeKey)
+	if err != nil {
+		utils.Fatalf("Prove BLS key error: %v", err)
+	}
+
+	fmt.Printf("signature: %s\nblsPubKey: %s\nblsProof: %s\n", sig, blsPubKey, blsProof)
+	return nil
+}
+
+// SignCandidate signs the (owner, chain id, nonce) of a candidate deposit with the node
+// private key stored in nodeKeyFile, it returns the hex encoded signature passed as the
+// last param of CandidateDeposit.
+func SignCandidate(nodeKeyFile, owner string, chainId, nonce uint64) (string, error) {
+	if !common.IsHexAddress(owner) {
+		return "", fmt.Errorf("invalid owner address: %s", owner)
+	}
+	key, err := crypto.LoadECDSA(nodeKeyFile)
+	if err != nil {
+		return "", fmt.Errorf("load node key error: %v", err)
+	}
+	sig, err := types.SignCandidateDeposit(key, common.HexToAddress(owner), new(big.Int).SetUint64(chainId), nonce)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sig), nil
+}
+
+// ProveBlsKey returns the hex encoded BLS public key of the node whose private key is
+// stored in nodeKeyFile and the proof of possession of its secret key, they are passed
+// after the signature to CandidateDeposit.
+func ProveBlsKey(nodeKeyFile string) (string, string, error) {
+	key, err := crypto.LoadECDSA(nodeKeyFile)
+	if err != nil {
+		return "", "", fmt.Errorf("load node key error: %v", err)
+	}
+	sk := bls.NodeSecretKey(key)
+	return hex.EncodeToString(sk.PublicKey().Marshal()), hex.EncodeToString(sk.Prove().Marshal()), nil
+}
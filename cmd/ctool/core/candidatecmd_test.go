@@ -0,0 +1,54 @@
+package core
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+func TestSignCandidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ctool-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	key, _ := crypto.GenerateKey()
+	keyfile := filepath.Join(dir, "nodekey")
+	if err := crypto.SaveECDSA(keyfile, key); err != nil {
+		t.Fatal(err)
+	}
+	owner := "0x740ce31b3fac20dac379db243021a51e80ad00d7"
+	sig, err := SignCandidate(keyfile, owner, 101, 3)
+	if err != nil {
+		t.Fatalf("sign candidate error: %v", err)
+	}
+	blob, _ := hex.DecodeString(sig)
+	nodeId, err := types.CandidateDepositSender(blob, common.HexToAddress(owner), big.NewInt(101), 3)
+	if err != nil || nodeId != discover.PubkeyID(&key.PublicKey) {
+		t.Fatalf("recovered node mismatch: have %v, err %v", nodeId, err)
+	}
+
+	pubKey, proof, err := ProveBlsKey(keyfile)
+	if err != nil {
+		t.Fatalf("prove BLS key error: %v", err)
+	}
+	pkBlob, _ := hex.DecodeString(pubKey)
+	proofBlob, _ := hex.DecodeString(proof)
+	pk, err := bls.UnmarshalPublicKey(pkBlob)
+	if err != nil {
+		t.Fatalf("BLS key decode error: %v", err)
+	}
+	if sig, err := bls.UnmarshalSignature(proofBlob); err != nil || !bls.VerifyProof(pk, sig) {
+		t.Fatalf("BLS proof rejected, err %v", err)
+	}
+}
@@ -70,6 +70,22 @@ var (
 		Value: "0xDE0B6B3A7640000", //one
 		Usage: "transfer value",
 	}
+	NodeKeyFilePathFlag = cli.StringFlag{
+		Name:  "nodekey",
+		Usage: "node private key file path",
+	}
+	CandidateOwnerFlag = cli.StringFlag{
+		Name:  "owner",
+		Usage: "candidate owner addr",
+	}
+	ChainIdFlag = cli.Uint64Flag{
+		Name:  "chainid",
+		Usage: "chain id",
+	}
+	CandidateNonceFlag = cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "candidate deposit nonce",
+	}
 
 	deployCmdFlags = []cli.Flag{
 		ContractWasmFilePathFlag,
@@ -114,4 +130,10 @@ var (
 		TransferValueFlag,
 		ConfigPathFlag,
 	}
+	signCandidateCmdFlags = []cli.Flag{
+		NodeKeyFilePathFlag,
+		CandidateOwnerFlag,
+		ChainIdFlag,
+		CandidateNonceFlag,
+	}
 )
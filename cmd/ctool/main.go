@@ -24,6 +24,7 @@ func init() {
 		core.GetTxReceiptCmd,
 		core.StabilityCmd,
 		core.StabPrepareCmd,
+		core.SignCandidateCmd,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 	app.After = func(ctx *cli.Context) error {
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/PlatONnetwork/PlatON-Go/cmd/utils"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"gopkg.in/urfave/cli.v1"
+)
+
+type outputSignCandidate struct {
+	NodeId    string
+	Signature string
+}
+
+var (
+	ownerFlag = cli.StringFlag{
+		Name:  "owner",
+		Usage: "the owner address of the candidate deposit",
+	}
+	chainIdFlag = cli.Uint64Flag{
+		Name:  "chainid",
+		Usage: "the chain id of the network",
+	}
+	nonceFlag = cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "the deposit nonce of the node, as returned by GetCandidateNonce",
+	}
+)
+
+var commandSignCandidate = cli.Command{
+	Name:      "signcandidate",
+	Usage:     "sign a candidate deposit with the node key",
+	ArgsUsage: "<nodekeyfile>",
+	Description: `
+Sign the (owner, chain id, nonce) of a candidate deposit with the node private key,
+which proves that the owner operates the node.
+
+The node key file contains the hex encoded private key of the node, the one
+used by the node as its p2p and consensus key.
+`,
+	Flags: []cli.Flag{
+		jsonFlag,
+		ownerFlag,
+		chainIdFlag,
+		nonceFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		keyfilepath := ctx.Args().First()
+		if keyfilepath == "" {
+			utils.Fatalf("No node key file specified")
+		}
+		ownerStr := ctx.String(ownerFlag.Name)
+		if !common.IsHexAddress(ownerStr) {
+			utils.Fatalf("Invalid owner address: %s", ownerStr)
+		}
+		if !ctx.IsSet(chainIdFlag.Name) {
+			utils.Fatalf("No chain id specified")
+		}
+
+		key, err := crypto.LoadECDSA(keyfilepath)
+		if err != nil {
+			utils.Fatalf("Failed to load the node key at '%s': %v", keyfilepath, err)
+		}
+		chainId := new(big.Int).SetUint64(ctx.Uint64(chainIdFlag.Name))
+		signature, err := types.SignCandidateDeposit(key, common.HexToAddress(ownerStr), chainId, ctx.Uint64(nonceFlag.Name))
+		if err != nil {
+			utils.Fatalf("Failed to sign candidate deposit: %v", err)
+		}
+		out := outputSignCandidate{
+			NodeId:    discover.PubkeyID(&key.PublicKey).String(),
+			Signature: hex.EncodeToString(signature),
+		}
+		if ctx.Bool(jsonFlag.Name) {
+			mustPrintJSON(out)
+		} else {
+			fmt.Println("NodeId   :", out.NodeId)
+			fmt.Println("Signature:", out.Signature)
+		}
+		return nil
+	},
+}
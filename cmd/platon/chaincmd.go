@@ -382,7 +382,7 @@ func copyDb(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	hc, err := core.NewHeaderChain(db, chain.Config(), chain.Engine(), func() bool { return false })
+	hc, err := core.NewHeaderChain(db, nil, chain.Config(), chain.Engine(), func() bool { return false })
 	if err != nil {
 		return err
 	}
@@ -457,7 +457,7 @@ func dump(ctx *cli.Context) error {
 			fmt.Println("{}")
 			utils.Fatalf("block not found")
 		} else {
-			state, err := state.New(block.Root(), state.NewDatabase(chainDb), block.Number(), block.Hash())
+			state, err := state.New(block.Root(), state.NewDatabaseWithPPos(chainDb, chain.PPosTemp()), block.Number(), block.Hash())
 			if err != nil {
 				utils.Fatalf("could not create new state: %v", err)
 			}
@@ -59,6 +59,8 @@ var (
 		utils.BootnodesFlag,
 		utils.BootnodesV4Flag,
 		utils.BootnodesV5Flag,
+		utils.SentryNodesFlag,
+		utils.PrivateNodesFlag,
 		utils.DataDirFlag,
 		utils.KeyStoreDirFlag,
 		utils.NoUSBFlag,
@@ -77,6 +79,7 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolGlobalTxCountFlag,
+		utils.TxPoolPposSlotsFlag,
 		utils.TxPoolLifetimeFlag,
 		utils.SyncModeFlag,
 		utils.GCModeFlag,
@@ -105,6 +108,7 @@ var (
 		utils.MinerExtraDataFlag,
 		utils.MinerLegacyExtraDataFlag,
 		utils.MinerRecommitIntervalFlag,
+		utils.MinerPposGasShareFlag,
 		utils.MinerNoVerfiyFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
@@ -156,6 +160,8 @@ var (
 	}
 
 	metricsFlags = []cli.Flag{
+		utils.MetricsHTTPFlag,
+		utils.MetricsPortFlag,
 		utils.MetricsEnableInfluxDBFlag,
 		utils.MetricsInfluxDBEndpointFlag,
 		utils.MetricsInfluxDBDatabaseFlag,
@@ -168,6 +174,9 @@ var (
 		utils.MPCEnabledFlag,
 		utils.MPCIceFileFlag,
 		utils.MPCActorFlag,
+		utils.MPCExecutorFlag,
+		utils.MPCRedisFlag,
+		utils.MPCHTTPFlag,
 	}
 	vcFlags = []cli.Flag{
 		utils.VCEnabledFlag,
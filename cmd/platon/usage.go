@@ -114,6 +114,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolGlobalTxCountFlag,
+			utils.TxPoolPposSlotsFlag,
 			utils.TxPoolLifetimeFlag,
 		},
 	},
@@ -160,6 +161,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.BootnodesFlag,
 			utils.BootnodesV4Flag,
 			utils.BootnodesV5Flag,
+			utils.SentryNodesFlag,
+			utils.PrivateNodesFlag,
 			utils.ListenPortFlag,
 			utils.MaxPeersFlag,
 			utils.MaxPendingPeersFlag,
@@ -183,6 +186,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MinerEtherbaseFlag,
 			utils.MinerExtraDataFlag,
 			utils.MinerRecommitIntervalFlag,
+			utils.MinerPposGasShareFlag,
 			utils.MinerNoVerfiyFlag,
 		},
 	},
@@ -212,6 +216,8 @@ var AppHelpFlagGroups = []flagGroup{
 		Name: "METRICS AND STATS",
 		Flags: []cli.Flag{
 			utils.MetricsEnabledFlag,
+			utils.MetricsHTTPFlag,
+			utils.MetricsPortFlag,
 			utils.MetricsEnableInfluxDBFlag,
 			utils.MetricsInfluxDBEndpointFlag,
 			utils.MetricsInfluxDBDatabaseFlag,
@@ -239,6 +245,9 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.MPCEnabledFlag,
 			utils.MPCActorFlag,
+			utils.MPCExecutorFlag,
+			utils.MPCRedisFlag,
+			utils.MPCHTTPFlag,
 			utils.MPCIceFileFlag,
 		},
 	},
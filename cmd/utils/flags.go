@@ -46,6 +46,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/les"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/metrics"
+	"github.com/PlatONnetwork/PlatON-Go/metrics/exp"
 	"github.com/PlatONnetwork/PlatON-Go/metrics/influxdb"
 	"github.com/PlatONnetwork/PlatON-Go/node"
 	"github.com/PlatONnetwork/PlatON-Go/p2p"
@@ -262,6 +263,11 @@ var (
 		Usage: "Maximum number of non-executable transaction slots for all accounts",
 		Value: eth.DefaultConfig.TxPool.GlobalQueue,
 	}
+	TxPoolPposSlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.pposslots",
+		Usage: "Number of transaction slots reserved for the PPOS transactions",
+		Value: eth.DefaultConfig.TxPool.PposSlots,
+	}
 	TxPoolGlobalTxCountFlag = cli.Uint64Flag{
 		Name:  "txpool.globaltxcount",
 		Usage: "Maximum number of transactions for package",
@@ -360,6 +366,11 @@ var (
 		Usage: "Time interval to recreate the block being mined",
 		Value: eth.DefaultConfig.MinerRecommit,
 	}
+	MinerPposGasShareFlag = cli.Uint64Flag{
+		Name:  "miner.pposgasshare",
+		Usage: "Percentage of the block gas reserved for the PPOS transactions",
+		Value: eth.DefaultConfig.MinerPposGasShare,
+	}
 	MinerNoVerfiyFlag = cli.BoolFlag{
 		Name:  "miner.noverify",
 		Usage: "Disable remote sealing verification",
@@ -500,6 +511,16 @@ var (
 		Usage: "Comma separated enode URLs for P2P v5 discovery bootstrap (light server, light nodes)",
 		Value: "",
 	}
+	SentryNodesFlag = cli.StringFlag{
+		Name:  "sentrynodes",
+		Usage: "Comma separated enode URLs of the private sentries, a validator connects only to them",
+		Value: "",
+	}
+	PrivateNodesFlag = cli.StringFlag{
+		Name:  "privatenodes",
+		Usage: "Comma separated enode URLs of the validators behind this sentry",
+		Value: "",
+	}
 	NodeKeyFileFlag = cli.StringFlag{
 		Name:  "nodekey",
 		Usage: "P2P node key file",
@@ -568,6 +589,20 @@ var (
 		Name:  metrics.MetricsEnabledFlag,
 		Usage: "Enable metrics collection and reporting",
 	}
+	// MetricsHTTPFlag defines the endpoint for a stand-alone metrics HTTP endpoint.
+	// Since the pprof service enables sensitive/vulnerable behavior, this allows a user
+	// to enable a public-OK metrics endpoint without having to worry about ALSO exposing
+	// other profiling behavior or information.
+	MetricsHTTPFlag = cli.StringFlag{
+		Name:  "metrics.addr",
+		Usage: "Enable stand-alone metrics HTTP server listening interface, serving Prometheus metrics on /metrics",
+		Value: "127.0.0.1",
+	}
+	MetricsPortFlag = cli.IntFlag{
+		Name:  "metrics.port",
+		Usage: "Metrics HTTP server listening port",
+		Value: 6061,
+	}
 	MetricsEnableInfluxDBFlag = cli.BoolFlag{
 		Name:  "metrics.influxdb",
 		Usage: "Enable metrics export/push to an external InfluxDB database",
@@ -628,6 +663,21 @@ var (
 		Name:  "mpc",
 		Usage: "Enable mpc compute",
 	}
+	MPCExecutorFlag = cli.StringFlag{
+		Name:  "mpc.executor",
+		Usage: "Backend to execute mpc compute (cgo, redis, http, fake)",
+		Value: core.DefaultMPCPoolConfig.Executor,
+	}
+	MPCRedisFlag = cli.StringFlag{
+		Name:  "mpc.redis",
+		Usage: "Address of redis the mpc tasks are pushed to (redis executor)",
+		Value: "",
+	}
+	MPCHTTPFlag = cli.StringFlag{
+		Name:  "mpc.http",
+		Usage: "URL of the service the mpc tasks are posted to (http executor)",
+		Value: "",
+	}
 	VCEnabledFlag = cli.BoolFlag{
 		Name:  "vc",
 		Usage: "Enable vc compute",
@@ -762,6 +812,28 @@ func setBootstrapNodesV5(ctx *cli.Context, cfg *p2p.Config) {
 	}
 }
 
+// setSentryNodes creates the lists of the sentry and private nodes from the
+// command line flags.
+func setSentryNodes(ctx *cli.Context, cfg *p2p.Config) {
+	parse := func(flag string) []*discover.Node {
+		var nodes []*discover.Node
+		for _, url := range strings.Split(flag, ",") {
+			node, err := discover.ParseNode(url)
+			if err != nil {
+				log.Crit("Sentry URL invalid", "enode", url, "err", err)
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	if ctx.GlobalIsSet(SentryNodesFlag.Name) {
+		cfg.SentryNodes = parse(ctx.GlobalString(SentryNodesFlag.Name))
+	}
+	if ctx.GlobalIsSet(PrivateNodesFlag.Name) {
+		cfg.PrivateNodes = parse(ctx.GlobalString(PrivateNodesFlag.Name))
+	}
+}
+
 // setListenAddress creates a TCP listening address string from set command
 // line flags.
 func setListenAddress(ctx *cli.Context, cfg *p2p.Config) {
@@ -936,6 +1008,7 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	setListenAddress(ctx, cfg)
 	setBootstrapNodes(ctx, cfg)
 	setBootstrapNodesV5(ctx, cfg)
+	setSentryNodes(ctx, cfg)
 
 	lightClient := ctx.GlobalString(SyncModeFlag.Name) == "light"
 	lightServer := ctx.GlobalInt(LightServFlag.Name) != 0
@@ -1084,6 +1157,9 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolGlobalTxCountFlag.Name) {
 		cfg.GlobalTxCount = ctx.GlobalUint64(TxPoolGlobalTxCountFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolPposSlotsFlag.Name) {
+		cfg.PposSlots = ctx.GlobalUint64(TxPoolPposSlotsFlag.Name)
+	}
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
@@ -1096,6 +1172,15 @@ func setMpcPool(ctx *cli.Context, cfg *core.MPCPoolConfig) {
 	if ctx.GlobalIsSet(MPCActorFlag.Name) {
 		cfg.MpcActor = common.HexToAddress(ctx.GlobalString(MPCActorFlag.Name))
 	}
+	if ctx.GlobalIsSet(MPCExecutorFlag.Name) {
+		cfg.Executor = ctx.GlobalString(MPCExecutorFlag.Name)
+	}
+	if ctx.GlobalIsSet(MPCRedisFlag.Name) {
+		cfg.RedisURL = ctx.GlobalString(MPCRedisFlag.Name)
+	}
+	if ctx.GlobalIsSet(MPCHTTPFlag.Name) {
+		cfg.HTTPEndpoint = ctx.GlobalString(MPCHTTPFlag.Name)
+	}
 	if file := ctx.GlobalString(MPCIceFileFlag.Name); file != "" {
 		if _, err := os.Stat(file); err != nil {
 			fmt.Println("ice conf not exists.")
@@ -1253,6 +1338,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(MinerNoVerfiyFlag.Name) {
 		cfg.MinerNoverify = ctx.Bool(MinerNoVerfiyFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerPposGasShareFlag.Name) {
+		cfg.MinerPposGasShare = ctx.GlobalUint64(MinerPposGasShareFlag.Name)
+		if cfg.MinerPposGasShare > 100 {
+			Fatalf("--%s must be a percentage, have %d", MinerPposGasShareFlag.Name, cfg.MinerPposGasShare)
+		}
+	}
 	if ctx.GlobalIsSet(VMEnableDebugFlag.Name) {
 		// TODO(fjl): force-enable this in --dev mode
 		cfg.EnablePreimageRecording = ctx.GlobalBool(VMEnableDebugFlag.Name)
@@ -1406,6 +1497,11 @@ func SetupMetrics(ctx *cli.Context) {
 				"host": hosttag,
 			})
 		}
+
+		if ctx.GlobalIsSet(MetricsHTTPFlag.Name) {
+			address := fmt.Sprintf("%s:%d", ctx.GlobalString(MetricsHTTPFlag.Name), ctx.GlobalInt(MetricsPortFlag.Name))
+			exp.Setup(address)
+		}
 	}
 }
 
@@ -1466,7 +1562,7 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 		cache.TrieNodeLimit = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
 	}
 	vmcfg := vm.Config{EnablePreimageRecording: ctx.GlobalBool(VMEnableDebugFlag.Name)}
-	chain, err = core.NewBlockChain(chainDb, cache, config, engine, vmcfg, nil)
+	chain, err = core.NewBlockChain(chainDb, nil, cache, config, engine, vmcfg, nil)
 	if err != nil {
 		Fatalf("Can't create BlockChain: %v", err)
 	}
@@ -48,6 +48,8 @@ var (
 	RewardPoolAddr    = HexToAddress("0x1000000000000000000000000000000000000000")
 	CandidatePoolAddr = HexToAddress("0x1000000000000000000000000000000000000001")
 	TicketPoolAddr    = HexToAddress("0x1000000000000000000000000000000000000002")
+	VCVerifierAddr    = HexToAddress("0x1000000000000000000000000000000000000003")
+	GovernanceAddr    = HexToAddress("0x1000000000000000000000000000000000000004")
 	ZeroAddr          = HexToAddress(Address{}.String())
 )
 
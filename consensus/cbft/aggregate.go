@@ -0,0 +1,227 @@
+package cbft
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/cbfttypes"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+var (
+	errAggregateBitmap    = errors.New("aggregate bitmap doesn't match the consensus nodes")
+	errAggregateThreshold = errors.New("aggregate signers are below the threshold")
+	errAggregateBlsKey    = errors.New("aggregate signer has no BLS key")
+	errAggregateSignature = errors.New("invalid aggregate signature")
+	errConfirmThreshold   = errors.New("confirmations are below the threshold")
+	errAggregateNodes     = errors.New("consensus nodes of the aggregate are unknown")
+)
+
+// blsKeyReader reads the BLS public keys registered by the candidates.
+type blsKeyReader interface {
+	GetState(addr common.Address, key []byte) []byte
+}
+
+// blsSign signs the seal hash of a block with the BLS key of the node, it
+// returns nil if the node has no BLS key.
+func (cbft *Cbft) blsSign(sealHash common.Hash) []byte {
+	if cbft.blsKey == nil {
+		return nil
+	}
+	return cbft.blsKey.Sign(sealHash.Bytes()).Marshal()
+}
+
+// collectBlsSign collects the BLS signature of a received block signature, the
+// signer is recovered from the ECDSA signature which comes with it.
+func (cbft *Cbft) collectBlsSign(ext *BlockExt, sig *cbfttypes.BlockSignature) {
+	if len(sig.BlsSignature) == 0 || sig.Signature == nil {
+		return
+	}
+	pubkey, err := crypto.SigToPub(sig.SignHash.Bytes(), sig.Signature[:])
+	if err != nil {
+		log.Warn("cannot recover the signer of a BLS sign", "hash", sig.Hash, "err", err)
+		return
+	}
+	ext.blsSigns[discover.PubkeyID(pubkey)] = sig.BlsSignature
+}
+
+// aggregateConfirms aggregates the BLS signatures of a confirmed block, it returns
+// nil if the valid signatures of the consensus nodes don't reach the threshold, the
+// block keeps its ECDSA signatures then.
+func (cbft *Cbft) aggregateConfirms(ext *BlockExt) *types.BlockConfirmAggregate {
+	if len(ext.blsSigns) == 0 || cbft.blockChainCache == nil {
+		return nil
+	}
+	block := ext.block
+	parentNumber := new(big.Int).Sub(block.Number(), common.Big1)
+	nodes := cbft.ConsensusNodes(parentNumber, block.ParentHash(), block.Number())
+	if nodes == nil {
+		return nil
+	}
+	parent := cbft.blockChainCache.GetHeader(block.ParentHash(), parentNumber.Uint64())
+	if ext.parent != nil && ext.parent.block != nil {
+		parent = ext.parent.block.Header()
+	}
+	if parent == nil {
+		return nil
+	}
+	parentState, err := cbft.blockChainCache.GetState(parent)
+	if err != nil {
+		log.Warn("cannot read the BLS keys of the consensus nodes", "number", block.NumberU64(), "hash", block.Hash(), "err", err)
+		return nil
+	}
+	aggregate := aggregateBlsSigns(block.Header().SealHash(), nodes, blsPublicKeys(parentState, nodes), ext.blsSigns)
+	if aggregate != nil && bls.Bitmap(aggregate.Bitmap).Count() < cbft.calculateThreshold(nodes) {
+		return nil
+	}
+	return aggregate
+}
+
+// VerifyConfirmAggregate verifies the aggregated confirmation of a synced block,
+// state is the state of its parent which holds the BLS keys of the consensus nodes.
+// A block with an aggregate is rejected if its consensus nodes are unknown.
+func (cbft *Cbft) VerifyConfirmAggregate(block *types.Block, state *state.StateDB) error {
+	if block.ConfirmAggregate == nil {
+		return nil
+	}
+	parentNumber := new(big.Int).Sub(block.Number(), common.Big1)
+	nodes := cbft.ConsensusNodes(parentNumber, block.ParentHash(), block.Number())
+	if nodes == nil {
+		log.Warn("cannot verify the aggregate of an unknown round", "number", block.NumberU64(), "hash", block.Hash())
+		return errAggregateNodes
+	}
+	return verifyConfirmAggregate(block.Header().SealHash(), block.ConfirmAggregate, blsPublicKeys(state, nodes), cbft.calculateThreshold(nodes))
+}
+
+// BlockProducer recovers the consensus node which sealed the header.
+func BlockProducer(header *types.Header) (discover.NodeID, error) {
+	nodeID, _, err := ecrecover(header)
+	return nodeID, err
+}
+
+// VerifyConfirmations checks that the block of header was confirmed by more than
+// 2/3 of its consensus nodes, either by the aggregate or by the ECDSA signatures
+// of its body. state is the state of its parent, it is only read for the BLS keys
+// of the aggregate.
+func VerifyConfirmations(header *types.Header, signs []*common.BlockConfirmSign, aggregate *types.BlockConfirmAggregate, nodes []discover.NodeID, state *state.StateDB) error {
+	threshold := len(nodes)*2/3 + 1
+	if aggregate != nil {
+		return verifyConfirmAggregate(header.SealHash(), aggregate, blsPublicKeys(state, nodes), threshold)
+	}
+	sealHash := header.SealHash()
+	signers := make(map[discover.NodeID]struct{})
+	for _, sign := range signs {
+		pubkey, err := crypto.SigToPub(sealHash.Bytes(), sign[:])
+		if err != nil {
+			continue
+		}
+		if id := discover.PubkeyID(pubkey); containsNode(nodes, id) {
+			signers[id] = struct{}{}
+		}
+	}
+	if len(signers) < threshold {
+		return errConfirmThreshold
+	}
+	return nil
+}
+
+func containsNode(nodes []discover.NodeID, id discover.NodeID) bool {
+	for _, node := range nodes {
+		if node == id {
+			return true
+		}
+	}
+	return false
+}
+
+// blsPublicKeys returns the BLS keys registered by nodes, nil for the nodes without
+// a valid key.
+func blsPublicKeys(state blsKeyReader, nodes []discover.NodeID) []*bls.PublicKey {
+	keys := make([]*bls.PublicKey, len(nodes))
+	for i, id := range nodes {
+		if b := state.GetState(common.CandidatePoolAddr, vm.CandidateBlsKey(id)); len(b) > 0 {
+			keys[i], _ = bls.UnmarshalPublicKey(b)
+		}
+	}
+	return keys
+}
+
+// aggregateBlsSigns aggregates the signatures of sealHash by nodes, keys are the BLS
+// keys of nodes. The invalid signatures are left out of the aggregate, it returns nil
+// if no signature is valid.
+func aggregateBlsSigns(sealHash common.Hash, nodes []discover.NodeID, keys []*bls.PublicKey, signs map[discover.NodeID][]byte) *types.BlockConfirmAggregate {
+	var (
+		signers []int
+		sigs    []*bls.Signature
+		pks     []*bls.PublicKey
+	)
+	for i, id := range nodes {
+		if keys[i] == nil || signs[id] == nil {
+			continue
+		}
+		if sig, err := bls.UnmarshalSignature(signs[id]); err == nil {
+			signers = append(signers, i)
+			sigs = append(sigs, sig)
+			pks = append(pks, keys[i])
+		}
+	}
+	if len(sigs) > 0 && !bls.Verify(bls.AggregatePublicKeys(pks...), sealHash.Bytes(), bls.AggregateSignatures(sigs...)) {
+		// some signatures are invalid, leave them out
+		var validSigners []int
+		var validSigs []*bls.Signature
+		for k, sig := range sigs {
+			if bls.Verify(pks[k], sealHash.Bytes(), sig) {
+				validSigners = append(validSigners, signers[k])
+				validSigs = append(validSigs, sig)
+			}
+		}
+		signers, sigs = validSigners, validSigs
+	}
+	if len(sigs) == 0 {
+		return nil
+	}
+	bitmap := bls.NewBitmap(len(nodes))
+	for _, i := range signers {
+		bitmap.Set(i)
+	}
+	return &types.BlockConfirmAggregate{Bitmap: bitmap, Signature: bls.AggregateSignatures(sigs...).Marshal()}
+}
+
+// verifyConfirmAggregate checks that aggregate is signed by at least threshold of the
+// consensus nodes whose BLS keys are keys.
+func verifyConfirmAggregate(sealHash common.Hash, aggregate *types.BlockConfirmAggregate, keys []*bls.PublicKey, threshold int) error {
+	bitmap := bls.Bitmap(aggregate.Bitmap)
+	if len(bitmap) != len(bls.NewBitmap(len(keys))) {
+		return errAggregateBitmap
+	}
+	for i := len(keys); i < len(bitmap)*8; i++ {
+		if bitmap.Has(i) {
+			return errAggregateBitmap
+		}
+	}
+	if bitmap.Count() < threshold {
+		return errAggregateThreshold
+	}
+	var pks []*bls.PublicKey
+	for i, key := range keys {
+		if !bitmap.Has(i) {
+			continue
+		}
+		if key == nil {
+			return errAggregateBlsKey
+		}
+		pks = append(pks, key)
+	}
+	sig, err := bls.UnmarshalSignature(aggregate.Signature)
+	if err != nil || !bls.Verify(bls.AggregatePublicKeys(pks...), sealHash.Bytes(), sig) {
+		return errAggregateSignature
+	}
+	return nil
+}
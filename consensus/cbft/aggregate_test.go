@@ -0,0 +1,88 @@
+package cbft
+
+import (
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+func testBlsNodes(n int) ([]discover.NodeID, []*bls.SecretKey, []*bls.PublicKey) {
+	nodes := make([]discover.NodeID, n)
+	sks := make([]*bls.SecretKey, n)
+	pks := make([]*bls.PublicKey, n)
+	for i := range nodes {
+		nodes[i][0] = byte(i + 1)
+		sks[i] = bls.SecretKeyFromSeed(nodes[i].Bytes())
+		pks[i] = sks[i].PublicKey()
+	}
+	return nodes, sks, pks
+}
+
+func TestAggregateBlsSigns(t *testing.T) {
+	nodes, sks, pks := testBlsNodes(4)
+	sealHash := common.HexToHash("0x01")
+
+	signs := make(map[discover.NodeID][]byte)
+	for i := 0; i < 3; i++ {
+		signs[nodes[i]] = sks[i].Sign(sealHash.Bytes()).Marshal()
+	}
+	aggregate := aggregateBlsSigns(sealHash, nodes, pks, signs)
+	if aggregate == nil || bls.Bitmap(aggregate.Bitmap).Count() != 3 {
+		t.Fatalf("aggregate mismatch: %+v", aggregate)
+	}
+	if err := verifyConfirmAggregate(sealHash, aggregate, pks, 3); err != nil {
+		t.Fatalf("valid aggregate rejected: %v", err)
+	}
+
+	// an invalid signature is left out
+	signs[nodes[3]] = sks[3].Sign([]byte("another block")).Marshal()
+	aggregate = aggregateBlsSigns(sealHash, nodes, pks, signs)
+	if aggregate == nil || bls.Bitmap(aggregate.Bitmap).Has(3) || bls.Bitmap(aggregate.Bitmap).Count() != 3 {
+		t.Fatalf("invalid signature not left out: %+v", aggregate)
+	}
+	if err := verifyConfirmAggregate(sealHash, aggregate, pks, 3); err != nil {
+		t.Fatalf("valid aggregate rejected: %v", err)
+	}
+
+	// the nodes without a BLS key are left out
+	pks[0] = nil
+	aggregate = aggregateBlsSigns(sealHash, nodes, pks, signs)
+	if aggregate == nil || bls.Bitmap(aggregate.Bitmap).Has(0) || bls.Bitmap(aggregate.Bitmap).Count() != 2 {
+		t.Fatalf("node without key not left out: %+v", aggregate)
+	}
+}
+
+func TestVerifyConfirmAggregate(t *testing.T) {
+	nodes, sks, pks := testBlsNodes(4)
+	sealHash := common.HexToHash("0x01")
+	signs := make(map[discover.NodeID][]byte)
+	for i := 0; i < 3; i++ {
+		signs[nodes[i]] = sks[i].Sign(sealHash.Bytes()).Marshal()
+	}
+	aggregate := aggregateBlsSigns(sealHash, nodes, pks, signs)
+
+	if err := verifyConfirmAggregate(sealHash, aggregate, pks, 4); err != errAggregateThreshold {
+		t.Fatalf("error mismatch: have %v, want %v", err, errAggregateThreshold)
+	}
+	if err := verifyConfirmAggregate(common.HexToHash("0x02"), aggregate, pks, 3); err != errAggregateSignature {
+		t.Fatalf("error mismatch: have %v, want %v", err, errAggregateSignature)
+	}
+	if err := verifyConfirmAggregate(sealHash, aggregate, append(append(pks, pks...), pks...), 3); err != errAggregateBitmap {
+		t.Fatalf("error mismatch: have %v, want %v", err, errAggregateBitmap)
+	}
+	// a bit set past the consensus nodes
+	if err := verifyConfirmAggregate(sealHash, aggregate, pks[:2], 2); err != errAggregateBitmap {
+		t.Fatalf("error mismatch: have %v, want %v", err, errAggregateBitmap)
+	}
+	if err := verifyConfirmAggregate(sealHash, aggregate, []*bls.PublicKey{nil, pks[1], pks[2], pks[3]}, 3); err != errAggregateBlsKey {
+		t.Fatalf("error mismatch: have %v, want %v", err, errAggregateBlsKey)
+	}
+	// a signer claimed by the bitmap without its signature
+	forged := *aggregate
+	forged.Bitmap = []byte{0x0f}
+	if err := verifyConfirmAggregate(sealHash, &forged, pks, 3); err != errAggregateSignature {
+		t.Fatalf("error mismatch: have %v, want %v", err, errAggregateSignature)
+	}
+}
@@ -14,10 +14,12 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/consensus"
 	"github.com/PlatONnetwork/PlatON-Go/core"
 	"github.com/PlatONnetwork/PlatON-Go/core/cbfttypes"
+	"github.com/PlatONnetwork/PlatON-Go/core/ppos"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/params"
@@ -49,6 +51,8 @@ var (
 	errHighestLogicalBlock = errors.New("cannot find a logical block")
 	errListConfirmedBlocks = errors.New("list confirmed blocks error")
 	errMissingSignature    = errors.New("extra-data 65 byte signature suffix missing")
+	errInvalidTimestamp    = errors.New("invalid timestamp")
+	errInvalidTurn         = errors.New("block is out of the turn of its producer")
 	extraSeal              = 65
 	windowSize             = 10
 
@@ -83,6 +87,8 @@ type Cbft struct {
 	blockChainCache *core.BlockChainCache
 	netLatencyMap   map[discover.NodeID]*list.List
 	netLatencyLock  sync.RWMutex
+	flowControl     *FlowControl
+	blsKey          *bls.SecretKey //BLS key derived from the node key, it signs the block confirmations
 }
 
 func (cbft *Cbft) getRootIrreversible() *BlockExt {
@@ -111,14 +117,12 @@ func (cbft *Cbft) getHighestLogical() *BlockExt {
 	}
 }
 
-var cbft *Cbft
-
 // New creates a concurrent BFT consensus engine
 func New(config *params.CbftConfig, blockSignatureCh chan *cbfttypes.BlockSignature, cbftResultCh chan *cbfttypes.CbftResult, highestLogicalBlockCh chan *types.Block) *Cbft {
 
 	_ppos := newPpos(config)
 
-	cbft = &Cbft{
+	cbft := &Cbft{
 		config:                config,
 		ppos:                  _ppos,
 		rotating:              newRotating(_ppos, config.Duration),
@@ -130,12 +134,11 @@ func New(config *params.CbftConfig, blockSignatureCh chan *cbfttypes.BlockSignat
 		//signedSet:     make(map[uint64]struct{}),
 		dataReceiveCh: make(chan interface{}, 256),
 		netLatencyMap: make(map[discover.NodeID]*list.List),
+		flowControl:   NewFlowControl(config),
 	}
 
 	_ppos.ticketContext.SetChainInfo(cbft)
 
-	flowControl = NewFlowControl()
-
 	go cbft.dataReceiverLoop()
 
 	return cbft
@@ -160,6 +163,7 @@ type BlockExt struct {
 	Hash        string 	`json:"hash"`
 	rcvTime     int64
 	signs       []*common.BlockConfirmSign `json:"-"`//all signs for block
+	blsSigns    map[discover.NodeID][]byte `json:"-"`//all BLS signs for block, by signer
 	parent      *BlockExt
 	Children    []*BlockExt	`json:"children"`
 
@@ -182,6 +186,7 @@ func NewBlockExt(block *types.Block, blockNum uint64) *BlockExt {
 		block:  block,
 		Number: blockNum,
 		signs:  make([]*common.BlockConfirmSign, 0),
+		blsSigns: make(map[discover.NodeID][]byte),
 		Hash:	block.Hash().TerminalString(),
 	}
 }
@@ -190,11 +195,10 @@ func NewEmptyExt(blockNum uint64) *BlockExt {
 	return &BlockExt{
 		Number: blockNum,
 		signs:  make([]*common.BlockConfirmSign, 0),
+		blsSigns: make(map[discover.NodeID][]byte),
 	}
 }
 
-var flowControl *FlowControl
-
 // FlowControl is a rectifier for sequential blocks
 type FlowControl struct {
 	nodeID      discover.NodeID
@@ -203,11 +207,11 @@ type FlowControl struct {
 	minInterval int64
 }
 
-func NewFlowControl() *FlowControl {
+func NewFlowControl(config *params.CbftConfig) *FlowControl {
 	return &FlowControl{
 		nodeID:      discover.NodeID{},
-		maxInterval: int64(cbft.config.Period*1000 + cbft.config.Period*1000*periodMargin/100),
-		minInterval: int64(cbft.config.Period*1000 - cbft.config.Period*1000*periodMargin/100),
+		maxInterval: int64(config.Period*1000 + config.Period*1000*periodMargin/100),
+		minInterval: int64(config.Period*1000 - config.Period*1000*periodMargin/100),
 	}
 }
 
@@ -247,6 +251,9 @@ func (cbft *Cbft) collectSign(ext *BlockExt, sign *common.BlockConfirmSign) {
 		//if ext.isLinked && ext.block != nil {
 		if ext.inTree { // ext.block != nil is unnecessary
 			if len(ext.signs) >= cbft.getThreshold(parentNumber, ext.block.ParentHash(), blockNumber) {
+				if !ext.isConfirmed && ext.rcvTime > 0 {
+					blockConfirmLatencyHistogram.Update(toMilliseconds(time.Now()) - ext.rcvTime)
+				}
 				ext.isConfirmed = true
 			}
 		}
@@ -321,12 +328,19 @@ func (cbft *Cbft) findChildren(parent *BlockExt) []*BlockExt {
 func (cbft *Cbft) saveBlockExt(hash common.Hash, ext *BlockExt) {
 	cbft.blockExtMap.Store(hash, ext)
 
+	length := cbft.treeSize()
+	log.Debug("save block in memory", "hash", hash, "number", ext.Number, "totalBlocks", length)
+}
+
+// treeSize counts the blocks in memory and reports it to the fork tree gauge.
+func (cbft *Cbft) treeSize() int {
 	length := 0
 	cbft.blockExtMap.Range(func(_, _ interface{}) bool {
 		length++
 		return true
 	})
-	log.Debug("save block in memory", "hash", hash, "number", ext.Number, "totalBlocks", length)
+	forkTreeSizeGauge.Update(int64(length))
+	return length
 }
 
 // isAncestor checks if a block is another's ancestor
@@ -526,14 +540,19 @@ func (cbft *Cbft) sign(ext *BlockExt) {
 		cbft.signedSet.Store(ext.block.NumberU64(), struct{}{})
 
 		blockHash := ext.block.Hash()
+		blsSign := cbft.blsSign(sealHash)
+		if blsSign != nil {
+			ext.blsSigns[cbft.config.NodeID] = blsSign
+		}
 
 		//send the BlockSignature to channel
 		blockSign := &cbfttypes.BlockSignature{
-			SignHash:   sealHash,
-			Hash:       blockHash,
-			Number:     ext.block.Number(),
-			Signature:  sign,
-			ParentHash: ext.block.ParentHash(),
+			SignHash:     sealHash,
+			Hash:         blockHash,
+			Number:       ext.block.Number(),
+			Signature:    sign,
+			ParentHash:   ext.block.ParentHash(),
+			BlsSignature: blsSign,
 		}
 		cbft.blockSignOutCh <- blockSign
 	} else {
@@ -640,9 +659,11 @@ func reverse(s []*BlockExt) {
 func (cbft *Cbft) SetPrivateKey(privateKey *ecdsa.PrivateKey) {
 	cbft.config.PrivateKey = privateKey
 	cbft.config.NodeID = discover.PubkeyID(&privateKey.PublicKey)
+	cbft.blsKey = bls.NodeSecretKey(privateKey)
 }
 
-func SetBlockChainCache(blockChainCache *core.BlockChainCache) {
+// SetBlockChainCache sets the blockChainCache shared with the miner into cbft
+func (cbft *Cbft) SetBlockChainCache(blockChainCache *core.BlockChainCache) {
 	cbft.blockChainCache = blockChainCache
 }
 
@@ -653,7 +674,7 @@ func (cbft *Cbft) setHighestLogical(highestLogical *BlockExt) {
 }
 
 // SetBackend sets blockChain and txPool into cbft
-func SetBackend(blockChain *core.BlockChain, txPool *core.TxPool) {
+func (cbft *Cbft) SetBackend(blockChain *core.BlockChain, txPool *core.TxPool) {
 	log.Debug("call SetBackend()")
 	cbft.blockChain = blockChain
 	cbft.ppos.SetStartTimeOfEpoch(blockChain.Genesis().Time().Int64() / 1000)
@@ -687,8 +708,9 @@ func SetBackend(blockChain *core.BlockChain, txPool *core.TxPool) {
 	cbft.txPool = txPool
 }
 
-func SetPposOption(blockChain *core.BlockChain) {
-	cbft.ppos.setPPOS_Temp()
+// SetPposOption sets the ppos storage temp and the initial candidates of blockChain into cbft
+func (cbft *Cbft) SetPposOption(blockChain *core.BlockChain) {
+	cbft.ppos.setPPOS_Temp(blockChain.PPosTemp())
 	cbft.ppos.SetCandidateContextOption(blockChain, cbft.config.InitialNodes)
 }
 
@@ -946,6 +968,7 @@ func (cbft *Cbft) signReceiver(sig *cbfttypes.BlockSignature) error {
 	}
 
 	cbft.collectSign(current, sig.Signature)
+	cbft.collectBlsSign(current, sig)
 
 	var hashLog interface{}
 	if current.block != nil {
@@ -1086,6 +1109,7 @@ func (cbft *Cbft) blockReceiver(tmp *BlockExt) error {
 		parentNumber := new(big.Int).Sub(blockNumber, common.Big1)
 		inTurn := cbft.inTurnVerify(parentNumber, block.ParentHash(), blockNumber, blockExt.rcvTime, producerID)
 		if !inTurn {
+			turnMissMeter.Mark(1)
 			log.Warn("not in turn",
 				"RoutineID", common.CurrentGoRoutineID(),
 				"hash", block.Hash(),
@@ -1097,7 +1121,7 @@ func (cbft *Cbft) blockReceiver(tmp *BlockExt) error {
 
 		blockExt.inTurn = inTurn
 
-		//flowControl := flowControl.control(producerID, curTime)
+		//flowControl := cbft.flowControl.control(producerID, curTime)
 		flowControl := true
 		highestConfirmedIsAncestor := cbft.getHighestConfirmed().isAncestor(blockExt)
 
@@ -1238,6 +1262,7 @@ func (cbft *Cbft) flushReadyBlock() bool {
 
 		//remove all other blocks those their numbers are too low
 		cbft.cleanByNumber(cbft.getRootIrreversible().Number)
+		cbft.treeSize()
 		return true
 	}
 	return false
@@ -1374,12 +1399,64 @@ func (cbft *Cbft) VerifyHeader(chain consensus.ChainReader, header *types.Header
 		return errUnknownBlock
 	}
 
+	return cbft.verifyHeader(chain, header, nil)
+}
+
+// verifyHeader checks whether a header conforms to the consensus rules, the
+// parents are the headers of the batch the header is verified with.
+func (cbft *Cbft) verifyHeader(chain consensus.ChainReader, header *types.Header, parents []*types.Header) error {
+	if header.Number == nil {
+		return errUnknownBlock
+	}
+
 	if len(header.Extra) < extraSeal {
 		return errMissingSignature
 	}
+	number := header.Number.Uint64()
+	if number == 0 {
+		return nil
+	}
+	var parent *types.Header
+	if len(parents) > 0 {
+		parent = parents[len(parents)-1]
+	} else {
+		parent = chain.GetHeader(header.ParentHash, number-1)
+	}
+	if parent == nil || parent.Number.Uint64() != number-1 || parent.Hash() != header.ParentHash {
+		return consensus.ErrUnknownAncestor
+	}
+	if header.Time.Cmp(parent.Time) <= 0 {
+		return errInvalidTimestamp
+	}
+	producerID, _, err := ecrecover(header)
+	if err != nil {
+		return err
+	}
+	if !cbft.inProducerTurn(parent, header, producerID) {
+		return errInvalidTurn
+	}
 	return nil
 }
 
+// inProducerTurn reports whether the time of the header falls in the turn of its
+// producer, the max latency between the consensus nodes is allowed for the clock
+// drift. The turn can't be checked before the consensus nodes after the parent are
+// known, the block is checked again when it is processed.
+func (cbft *Cbft) inProducerTurn(parent, header *types.Header, producerID discover.NodeID) bool {
+	_, nodeIds := cbft.ppos.BlockProducerIndex(parent.Number, parent.Hash(), header.Number, producerID, all)
+	if nodeIds == nil {
+		return true
+	}
+	drift := cbft.ppos.CbftConfig(parent.Number, parent.Hash()).MaxLatency
+	blockTime := header.Time.Int64()
+	for _, timePoint := range []int64{blockTime, blockTime - drift, blockTime + drift} {
+		if cbft.calTurn(timePoint, parent.Number, parent.Hash(), header.Number, producerID, all) {
+			return true
+		}
+	}
+	return false
+}
+
 // VerifyHeaders is similar to VerifyHeader, but verifies a batch of headers. The
 // method returns a quit channel to abort the operations and a results channel to
 // retrieve the async verifications (the order is that of the input slice).
@@ -1390,8 +1467,8 @@ func (cbft *Cbft) VerifyHeaders(chain consensus.ChainReader, headers []*types.He
 	results := make(chan error, len(headers))
 
 	go func() {
-		for _, header := range headers {
-			err := cbft.VerifyHeader(chain, header, false)
+		for i, header := range headers {
+			err := cbft.verifyHeader(chain, header, headers[:i])
 
 			select {
 			case <-abort:
@@ -1447,7 +1524,14 @@ func (cbft *Cbft) Finalize(chain consensus.ChainReader, header *types.Header, st
 
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
 	header.UncleHash = types.CalcUncleHash(nil)
-	return types.NewBlock(header, txs, nil, receipts), nil
+	block := types.NewBlock(header, txs, nil, receipts)
+	// the bloom covers the system logs of the elections, the ticket expiry and the rewards
+	if systemLogs := state.GetLogs(types.SystemLogTxHash); len(systemLogs) > 0 && chain.Config().IsPposEvents(header.Number) {
+		h := block.Header()
+		h.Bloom = types.CreateBlockBloom(receipts, systemLogs)
+		block = types.NewBlockWithHeader(h).WithBody(block.Transactions(), nil, block.Signatures())
+	}
+	return block, nil
 }
 
 // to sign the block, and store the sign to header.Extra[32:97], send the sign to chanel to broadcast to other consensus nodes
@@ -1656,8 +1740,9 @@ func (cbft *Cbft) RemovePeer(nodeID discover.NodeID){
 	delete(cbft.netLatencyMap, nodeID)
 }
 
-// avgLatency statistics the net latency between local and other peers.
-func (cbft *Cbft) avgLatency(nodeID discover.NodeID) int64 {
+// avgLatency statistics the net latency between local and other peers, maxLatency
+// is returned if there is no statistics of the peer.
+func (cbft *Cbft) avgLatency(nodeID discover.NodeID, maxLatency int64) int64 {
 	if latencyList, exist := cbft.netLatencyMap[nodeID]; exist {
 		sum := int64(0)
 		counts := int64(0)
@@ -1671,7 +1756,7 @@ func (cbft *Cbft) avgLatency(nodeID discover.NodeID) int64 {
 			return sum / counts
 		}
 	}
-	return cbft.config.MaxLatency
+	return maxLatency
 }
 
 // HighestLogicalBlock returns the cbft.highestLogical.block.
@@ -1711,7 +1796,7 @@ func (cbft *Cbft) GetBlock(hash common.Hash, number uint64) *types.Block {
 }
 
 // IsSignedBySelf returns if the block is signed by local.
-func IsSignedBySelf(sealHash common.Hash, signature []byte) bool {
+func (cbft *Cbft) IsSignedBySelf(sealHash common.Hash, signature []byte) bool {
 	ok, err := verifySign(cbft.config.NodeID, sealHash, signature)
 	if err != nil {
 		log.Error("verify sign error", "errors", err)
@@ -1726,8 +1811,10 @@ func (cbft *Cbft) storeBlocks(blocksToStore []*BlockExt) {
 		cbftResult := &cbfttypes.CbftResult{
 			Block:             ext.block,
 			BlockConfirmSigns: ext.signs,
+			ConfirmAggregate:  cbft.aggregateConfirms(ext),
 		}
 		log.Debug("send consensus result to worker", "hash", ext.block.Hash(), "number", ext.block.NumberU64(), "signCount", len(ext.signs))
+		blockSignsHistogram.Update(int64(len(ext.signs)))
 		cbft.cbftResultOutCh <- cbftResult
 	}
 }
@@ -1762,7 +1849,7 @@ func (cbft *Cbft) inTurn(parentNumber *big.Int, parentHash common.Hash, commitNu
 //	return inTurnVerify
 //}
 func (cbft *Cbft) inTurnVerify(parentNumber *big.Int, parentHash common.Hash, blockNumber *big.Int, rcvTime int64, nodeID discover.NodeID) bool {
-	latency := cbft.avgLatency(nodeID)
+	latency := cbft.avgLatency(nodeID, cbft.ppos.CbftConfig(parentNumber, parentHash).MaxLatency)
 	if latency >= maxAvgLatency {
 		log.Warn("check if peer's turn to commit block", "result", false, "peerID", nodeID, "high latency ", latency)
 		return false
@@ -1772,6 +1859,12 @@ func (cbft *Cbft) inTurnVerify(parentNumber *big.Int, parentHash common.Hash, bl
 	return inTurnVerify
 }
 
+// InTurn checks that a block received at rcvTime is in the turn of its producer,
+// the average latency of the producer is taken into account.
+func (cbft *Cbft) InTurn(parentNumber *big.Int, parentHash common.Hash, blockNumber *big.Int, rcvTime int64, nodeID discover.NodeID) bool {
+	return cbft.inTurnVerify(parentNumber, parentHash, blockNumber, rcvTime, nodeID)
+}
+
 //shouldKeepIt verifies the time is legal to package new block for the nodeID.
 //func (cbft *Cbft) isLegal(rcvTime int64, producerID discover.NodeID) bool {
 //	offset := 1000 * (cbft.config.Duration/2 - 1)
@@ -1782,7 +1875,7 @@ func (cbft *Cbft) inTurnVerify(parentNumber *big.Int, parentHash common.Hash, bl
 //	return isLegal
 //}
 func (cbft *Cbft) isLegal(rcvTime int64, parentNumber *big.Int, parentHash common.Hash, blockNumber *big.Int, producerID discover.NodeID) bool {
-	offset := 1000 * (cbft.config.Duration/2 - 1)
+	offset := 1000 * (cbft.ppos.CbftConfig(parentNumber, parentHash).Duration/2 - 1)
 	isLegal := cbft.calTurn(rcvTime-offset, parentNumber, parentHash, blockNumber, producerID, all)
 	if !isLegal {
 		isLegal = cbft.calTurn(rcvTime+offset, parentNumber, parentHash, blockNumber, producerID, all)
@@ -1819,7 +1912,7 @@ func (cbft *Cbft) calTurn(timePoint int64, parentNumber *big.Int, parentHash com
 	startEpoch := cbft.ppos.StartTimeOfEpoch() * 1000
 
 	if nodeIdx >= 0 {
-		durationPerNode := cbft.config.Duration * 1000
+		durationPerNode := cbft.ppos.CbftConfig(parentNumber, parentHash).Duration * 1000
 
 		//consensusNodes := cbft.ConsensusNodes(parentNumber, parentHash, blockNumber)
 		if consensusNodes == nil || len(consensusNodes) <= 0 {
@@ -1829,17 +1922,7 @@ func (cbft *Cbft) calTurn(timePoint int64, parentNumber *big.Int, parentHash com
 			return true
 		}
 
-		durationPerTurn := durationPerNode * int64(len(consensusNodes))
-
-		min := nodeIdx * (durationPerNode)
-
-		value := (timePoint - startEpoch) % durationPerTurn
-
-		max := (nodeIdx + 1) * durationPerNode
-
-		if value > min && value < max {
-			return true
-		}
+		return inSlot(timePoint, startEpoch, durationPerNode, nodeIdx, len(consensusNodes))
 	}else{
 		log.Debug("local is not a consensus node", "localNode", nodeID.String(), "number", blockNumber)
 		for idx, nid := range  consensusNodes{
@@ -1849,12 +1932,38 @@ func (cbft *Cbft) calTurn(timePoint int64, parentNumber *big.Int, parentHash com
 	return false
 }
 
+// inSlot reports whether timePoint, in milliseconds, is in the slot of the node at
+// index idx of the n consensus nodes, which take turns of durationPerNode from
+// startEpoch on.
+func inSlot(timePoint, startEpoch, durationPerNode, idx int64, n int) bool {
+	durationPerTurn := durationPerNode * int64(n)
+	value := (timePoint - startEpoch) % durationPerTurn
+	return value > idx*durationPerNode && value < (idx+1)*durationPerNode
+}
+
+// ProducerInTurn reports whether the header, sealed by the node at index idx of
+// its n consensus nodes, is in the turn of the node within the max latency of
+// config, as verifyHeader does. startEpoch is the time of the genesis block in
+// milliseconds, truncated to seconds.
+func ProducerInTurn(header *types.Header, config *params.CbftConfig, startEpoch int64, idx, n int) bool {
+	if n == 1 {
+		return true
+	}
+	blockTime, drift := header.Time.Int64(), config.MaxLatency
+	for _, timePoint := range []int64{blockTime, blockTime - drift, blockTime + drift} {
+		if inSlot(timePoint, startEpoch, config.Duration*1000, int64(idx), n) {
+			return true
+		}
+	}
+	return false
+}
+
 // producer's signature = header.Extra[32:97]
 // public key can be recovered from signature, the length of public key is 65,
 // the length of NodeID is 64, nodeID = publicKey[1:]
 func ecrecover(header *types.Header) (discover.NodeID, []byte, error) {
 	var nodeID discover.NodeID
-	if len(header.Extra) < extraSeal {
+	if len(header.Extra) < 32+extraSeal {
 		return nodeID, []byte{}, errMissingSignature
 	}
 	signature := header.Extra[32:97]
@@ -2015,6 +2124,16 @@ func (cbft *Cbft) GetOwnNodeID() discover.NodeID {
 	return cbft.config.NodeID
 }
 
+// CandidatePoolContext returns the candidate pool context of the engine.
+func (cbft *Cbft) CandidatePoolContext() *pposm.CandidatePoolContext {
+	return cbft.ppos.candidateContext
+}
+
+// TicketPoolContext returns the ticket pool context of the engine.
+func (cbft *Cbft) TicketPoolContext() *pposm.TicketPoolContext {
+	return cbft.ppos.ticketContext
+}
+
 func (cbft *Cbft) SetNodeCache(state *state.StateDB, parentNumber, currentNumber *big.Int, parentHash, currentHash common.Hash) error {
 	log.Info("cbft SetNodeCache", "parentNumber", parentNumber, "parentHash", parentHash, "currentNumber", currentNumber, "currentHash", currentHash)
 	genesis := cbft.blockChain.Genesis()
@@ -2118,9 +2237,11 @@ func (cbft *Cbft) accumulateRewards(config *params.ChainConfig, state *state.Sta
 
 		//log.Info("Call accumulateRewards, Rewards detail", "blockReward: ", blockReward, "nodeReward: ", nodeReward, "ticketReward: ", ticketReward)
 		state.AddBalance(can.TOwner, ticketReward)
+		vm.AddPposSystemLog(config, state, common.RewardPoolAddr, header.Number, vm.RewardEvent, can.TOwner, can.CandidateId, can.TxHash, ticketReward)
 		log.Info("Ticket accumulateRewards", "txHash", can.TxHash.Hex(), "ticketOwner", can.TOwner.Hex(), "ticketReward", ticketReward, "ticketOwnerBalance", state.GetBalance(can.TOwner))
 	}
 	state.AddBalance(header.Coinbase, nodeReward)
+	vm.AddPposSystemLog(config, state, common.RewardPoolAddr, header.Number, vm.RewardEvent, header.Coinbase, can.CandidateId, can.TxHash, nodeReward)
 	state.SubBalance(common.RewardPoolAddr, blockReward)
 
 	log.Info("Call accumulateRewards SUCCESS !! ", "blockNumber", header.Number, "blockHash", header.Hash(),
@@ -2139,6 +2260,62 @@ func (cbft *Cbft) IncreaseRewardPool(state *state.StateDB, number *big.Int) {
 	}
 }
 
+// RecordLiveness checks that the block is in the turn of its producer and counts the
+// block slots skipped between the parent and the block, the slots are the calTurn
+// windows of the consensus nodes. Only the block timestamps are used so that every node
+// records the same, the confirm signatures are not part of the block and can't be counted.
+// The header assembled by the local miner is not sealed yet, it is in turn by construction.
+func (cbft *Cbft) RecordLiveness(chain consensus.ChainReader, state *state.StateDB, header *types.Header) error {
+	parentNumber := new(big.Int).Sub(header.Number, common.Big1)
+	parent := chain.GetHeader(header.ParentHash, parentNumber.Uint64())
+	if parent == nil {
+		return nil
+	}
+	if producerID, _, err := ecrecover(header); err == nil && !cbft.inProducerTurn(parent, header, producerID) {
+		return errInvalidTurn
+	}
+	nodeIds := cbft.ConsensusNodes(parentNumber, header.ParentHash, header.Number)
+	if len(nodeIds) <= 1 {
+		return nil
+	}
+	missed := missedSlots(parent.Time.Int64(), header.Time.Int64(), cbft.ppos.StartTimeOfEpoch()*1000, cbft.ppos.CbftConfig(parentNumber, header.ParentHash).Duration*1000, len(nodeIds))
+	for i, count := range missed {
+		if count == 0 {
+			continue
+		}
+		if err := cbft.ppos.MissSlots(state, nodeIds[i], count, header.Number); err != nil {
+			log.Error("Failed to record missed slots", "number", header.Number, "nodeID", nodeIds[i].String(), "count", count, "err", err)
+		}
+	}
+	return nil
+}
+
+// missedSlots returns the number of slots each of n nodes owned strictly between
+// the slot of parentTime and the slot of blockTime. A block counts at most one turn
+// of the nodes, a long gap such as a halt of the chain doesn't jail every node.
+func missedSlots(parentTime, blockTime, startEpoch, durationPerNode int64, n int) []uint32 {
+	if n <= 0 || durationPerNode <= 0 || parentTime < startEpoch || blockTime <= parentTime {
+		return nil
+	}
+	first := (parentTime-startEpoch)/durationPerNode + 1
+	last := (blockTime - startEpoch) / durationPerNode
+	if last <= first {
+		return nil
+	}
+	skipped := last - first
+	if skipped > int64(n) {
+		skipped = int64(n)
+	}
+	missed := make([]uint32, n)
+	for i := range missed {
+		missed[i] = uint32(skipped / int64(n))
+	}
+	for j := int64(0); j < skipped%int64(n); j++ {
+		missed[(first+j)%int64(n)]++
+	}
+	return missed
+}
+
 func GetAmount(number *big.Int) *big.Int {
 	cycle := new(big.Int).Div(number, common.YearBlocks)
 	rate := math2.BigPow(common.Rate.Int64(), cycle.Int64())
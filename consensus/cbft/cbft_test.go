@@ -7,6 +7,7 @@ import (
 	"flag"
 	"fmt"
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/consensus"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/log"
@@ -32,6 +33,7 @@ func TestMain(m *testing.M) {
 }
 
 //var cbftConfig *params.CbftConfig
+var cbft *Cbft
 var forkRootHash common.Hash
 var rootBlock *types.Block
 var rootNumber uint64
@@ -510,3 +512,51 @@ func convert(initialNodes []string) []discover.NodeID {
 	}
 	return NodeIDList
 }
+
+func TestMissedSlots(t *testing.T) {
+	tests := []struct {
+		parentTime, blockTime int64
+		want                  []uint32
+	}{
+		// the same slot, the next slot
+		{1500, 1900, nil},
+		{1500, 2100, nil},
+		// the slots of node 1 and 2 are skipped
+		{1500, 4100, []uint32{0, 1, 1, 0}},
+		// three skipped slots wrap around the round
+		{3500, 7100, []uint32{1, 1, 0, 1}},
+		// six skipped slots count one round at most
+		{2500, 9100, []uint32{1, 1, 1, 1}},
+		{900, 1500, nil},
+	}
+	for i, tt := range tests {
+		if have := missedSlots(tt.parentTime, tt.blockTime, 1000, 1000, 4); fmt.Sprint(have) != fmt.Sprint(tt.want) {
+			t.Errorf("test %d: missed slots mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
+func TestVerifyHeaderTime(t *testing.T) {
+	engine := &Cbft{}
+	parent := &types.Header{Number: big.NewInt(1), Time: big.NewInt(5000), Extra: make([]byte, 32+extraSeal)}
+	header := func(time int64) *types.Header {
+		return &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(2), Time: big.NewInt(time), Extra: make([]byte, 32+extraSeal)}
+	}
+	parents := []*types.Header{parent}
+
+	if err := engine.verifyHeader(nil, header(5000), parents); err != errInvalidTimestamp {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidTimestamp)
+	}
+	if err := engine.verifyHeader(nil, header(4000), parents); err != errInvalidTimestamp {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidTimestamp)
+	}
+	orphan := header(6000)
+	orphan.ParentHash = common.Hash{1}
+	if err := engine.verifyHeader(nil, orphan, parents); err != consensus.ErrUnknownAncestor {
+		t.Errorf("error mismatch: have %v, want %v", err, consensus.ErrUnknownAncestor)
+	}
+	// the producer of the block must be recovered from the seal
+	if err := engine.verifyHeader(nil, header(6000), parents); err == nil {
+		t.Errorf("unsealed header accepted")
+	}
+}
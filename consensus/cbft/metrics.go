@@ -0,0 +1,16 @@
+package cbft
+
+import (
+	"github.com/PlatONnetwork/PlatON-Go/metrics"
+)
+
+var (
+	// Milliseconds from receiving a block to collecting enough signatures to confirm it
+	blockConfirmLatencyHistogram = metrics.NewRegisteredHistogram("cbft/block/confirm/latency", nil, metrics.NewExpDecaySample(1028, 0.015))
+	// Signatures of the blocks flushed to chain
+	blockSignsHistogram = metrics.NewRegisteredHistogram("cbft/block/signs", nil, metrics.NewExpDecaySample(1028, 0.015))
+	// Blocks kept in memory, that is the fork tree and the signatures received ahead of blocks
+	forkTreeSizeGauge = metrics.NewRegisteredGauge("cbft/tree/size", nil)
+	// Blocks received out of the calTurn window of their producer
+	turnMissMeter = metrics.NewRegisteredMeter("cbft/turn/misses", nil)
+)
@@ -25,6 +25,7 @@ type ppos struct {
 	// if it is the first round, it starts from 1970.1.1.0.0.0.0. Unit: second
 	startTimeOfEpoch  int64
 	config            *params.PposConfig
+	cbftConfig        *params.CbftConfig
 
 	// added by candidateContext module
 	lock 					sync.RWMutex
@@ -39,11 +40,16 @@ type ppos struct {
 
 
 func newPpos(config *params.CbftConfig) *ppos {
+	candidateContext := pposm.NewCandidatePoolContext(config.PposConfig)
+	ticketContext := pposm.NewTicketPoolContext(config.PposConfig)
+	candidateContext.SetTicketPoolContext(ticketContext)
+	ticketContext.SetCandidatePoolContext(candidateContext)
 	return &ppos{
 		lastCycleBlockNum: 	0,
 		config:            	config.PposConfig,
-		candidateContext:   pposm.NewCandidatePoolContext(config.PposConfig),
-		ticketContext: 		pposm.NewTicketPoolContext(config.PposConfig),
+		cbftConfig:         config,
+		candidateContext:   candidateContext,
+		ticketContext: 		ticketContext,
 	}
 }
 
@@ -90,6 +96,17 @@ func (p *ppos) BlockProducerIndex(parentNumber *big.Int, parentHash common.Hash,
 	return -1, nil
 }
 
+// CbftConfig returns the consensus configs in effect after the block, the
+// configs of the node are returned if the block is not cached.
+func (p *ppos) CbftConfig(blockNumber *big.Int, blockHash common.Hash) *params.CbftConfig {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if cache := p.nodeRound.getNodeCache(blockNumber, blockHash); nil != cache && nil != cache.config {
+		return cache.config
+	}
+	return p.cbftConfig
+}
+
 func (p *ppos) roundIndex(nodeID discover.NodeID, round *pposRound) int64 {
 	for idx, nid := range round.nodeIds {
 		if nid == nodeID {
@@ -431,8 +448,8 @@ func (p *ppos) GetOwner(state vm.StateDB, nodeId discover.NodeID, blockNumber *b
 }
 
 // Getting allow block interval for refunds
-func (p *ppos) GetRefundInterval(blockNumber *big.Int) uint32 {
-	return p.candidateContext.GetRefundInterval(blockNumber)
+func (p *ppos) GetRefundInterval(state vm.StateDB, blockNumber *big.Int) uint32 {
+	return p.candidateContext.GetRefundInterval(state, blockNumber)
 }
 
 
@@ -447,6 +464,18 @@ func (p *ppos) VoteTicket (state vm.StateDB, owner common.Address, voteNumber ui
 	return p.ticketContext.VoteTicket(state, owner, voteNumber, deposit, nodeId, blockNumber)
 }
 
+func (p *ppos) MissSlots (state vm.StateDB, nodeId discover.NodeID, count uint32, blockNumber *big.Int) error {
+	return p.candidateContext.MissSlots(state, nodeId, count, blockNumber)
+}
+
+func (p *ppos) TransferTicket (state vm.StateDB, from common.Address, ticketId common.Hash, count uint32, to common.Address, blockNumber *big.Int) (common.Hash, error) {
+	return p.ticketContext.TransferTicket(state, from, ticketId, count, to, blockNumber)
+}
+
+func (p *ppos) RedelegateTicket (state vm.StateDB, from common.Address, ticketId common.Hash, count uint32, nodeId discover.NodeID, blockNumber *big.Int) (common.Hash, error) {
+	return p.ticketContext.RedelegateTicket(state, from, ticketId, count, nodeId, blockNumber)
+}
+
 func (d *ppos) GetTicket(state vm.StateDB, ticketId common.Hash) *types.Ticket {
 	return d.ticketContext.GetTicket(state, ticketId)
 }
@@ -467,8 +496,18 @@ func (p *ppos) GetTicketPrice (state vm.StateDB) *big.Int {
 	return p.ticketContext.GetTicketPrice(state)
 }
 
+func (p *ppos) GetTicketPriceHistory (state vm.StateDB, start, count uint32) []*types.TicketPriceRecord {
+	return p.ticketContext.GetTicketPriceHistory(state, start, count)
+}
+
 func (p *ppos) Notify (state vm.StateDB, blockNumber *big.Int) error {
-	return p.ticketContext.Notify(state, blockNumber)
+	if err := vm.NotifyGovernance(state, blockNumber); nil != err {
+		return err
+	}
+	if err := p.ticketContext.Notify(state, blockNumber); nil != err {
+		return err
+	}
+	return p.candidateContext.Notify(state, blockNumber)
 }
 
 func (p *ppos) StoreHash (state *state.StateDB, blockNumber *big.Int, blockHash common.Hash) {
@@ -806,6 +845,7 @@ func (p *ppos) setGeneralNodeCache (state *state.StateDB, genesisNumber, parentN
 		former: 	formerRound,
 		current: 	currentRound,
 		next: 		nextRound,
+		config: 	vm.GovernedCbftConfig(state, p.cbftConfig),
 	}
 	p.nodeRound.setNodeCache(big.NewInt(int64(currentNumber)), currentHash, cache)
 
@@ -1025,6 +1065,7 @@ func (p *ppos) setEarliestIrrNodeCache (/*parentState, */currentState *state.Sta
 		former: 	formerRound,
 		current: 	currentRound,
 		next: 		nextRound,
+		config: 	vm.GovernedCbftConfig(currentState, p.cbftConfig),
 	}
 	p.nodeRound.setNodeCache(big.NewInt(int64(currentNumber)), currentHash, cache)
 	log.Debug("Set the farthest allowed to cache the information of the reserved block", "currentBlockNum", currentNumber, "currentHash", currentHash.String())
@@ -1076,7 +1117,7 @@ func cmpSwitch (round, currentNum uint64) int {
 	}
 }
 
-func (p *ppos) setPPOS_Temp(){
-	p.pposTemp = ppos_storage.GetPPosTempPtr()
+func (p *ppos) setPPOS_Temp(pposTemp *ppos_storage.PPOS_TEMP){
+	p.pposTemp = pposTemp
 }
 
@@ -1,7 +1,6 @@
 package cbft
 
 import (
-	"github.com/PlatONnetwork/PlatON-Go/core/ppos_storage"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/params"
@@ -81,9 +80,13 @@ func buildPpos() (*ppos, *core.BlockChain) {
 			ExpireBlockNumber: 2,
 		},
 	}
+	candidateContext := pposm.NewCandidatePoolContext(&configs)
+	ticketContext := pposm.NewTicketPoolContext(&configs)
+	candidateContext.SetTicketPoolContext(ticketContext)
+	ticketContext.SetCandidatePoolContext(candidateContext)
 	ppos := &ppos{
-		candidateContext:  pposm.NewCandidatePoolContext(&configs),
-		ticketContext: pposm.NewTicketPoolContext(&configs),
+		candidateContext:  candidateContext,
+		ticketContext: ticketContext,
 	}
 
 	var (
@@ -92,12 +95,10 @@ func buildPpos() (*ppos, *core.BlockChain) {
 	)
 	fmt.Println("genesis", genesis)
 
-	// Initialize ppos storage
-	ppos_storage.NewPPosTemp(db)
-
 	// Initialize a fresh chain with only a genesis block
-	blockchain, _ := core.NewBlockChain(db, nil, params.AllEthashProtocolChanges, nil, vm.Config{}, nil)
+	blockchain, _ := core.NewBlockChain(db, nil, nil, params.AllEthashProtocolChanges, nil, vm.Config{}, nil)
 
+	ppos.setPPOS_Temp(blockchain.PPosTemp())
 	ppos.SetCandidateContextOption(blockchain, buildInitialNodes())
 
 	return ppos, blockchain
@@ -1678,12 +1679,17 @@ func BenchmarkPpos_GetOwner(b *testing.B) {
 
 // test GetRefundInterval
 func ppos_GetRefundInterval (logger interface{}, logFn func (args ... interface{}), errFn func (args ... interface{})){
-	ppos, _ := buildPpos()
-
+	ppos, bc := buildPpos()
+	var state *state.StateDB
+	if st, err := bc.State(); nil != err {
+		errFn("test GetRefundInterval getting state err", err)
+	}else {
+		state = st
+	}
 	logFn("test GetRefundInterval ...")
 
 	/** test  GetRefundInterval*/
-	num := ppos.GetRefundInterval(big.NewInt(1))
+	num := ppos.GetRefundInterval(state, big.NewInt(1))
 	logFn("RefundInterval:", num)
 }
 /*func TestPpos_GetRefundInterval(t *testing.T) {
@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 )
 
 type roundCache map[uint64]map[common.Hash]*nodeCache
@@ -13,6 +14,7 @@ type nodeCache struct {
 	former            *pposRound 	// the previous round of witnesses nodeId
 	current           *pposRound 	// the current round of witnesses nodeId
 	next              *pposRound 	// the next round of witnesses nodeId
+	config            *params.CbftConfig	// the governed consensus configs after the block
 }
 
 type pposRound struct {
@@ -133,6 +133,10 @@ type Bft interface {
 
 	ConsensusNodes(parentNumber *big.Int, parentHash common.Hash, blockNumber *big.Int) []discover.NodeID
 
+	// InTurn checks that a block received at rcvTime, in milliseconds, is in the
+	// turn of its producer nodeID
+	InTurn(parentNumber *big.Int, parentHash common.Hash, blockNumber *big.Int, rcvTime int64, nodeID discover.NodeID) bool
+
 	// whether the current node should packing
 	ShouldSeal(parentNumber *big.Int, parentHash common.Hash, commitNumber *big.Int) bool
 
@@ -148,6 +152,10 @@ type Bft interface {
 
 	// Send a signal if a block synced from other peer.
 	OnBlockSynced()
+
+	// verify the aggregated confirmation of a synced block against the BLS keys
+	// in the state of its parent
+	VerifyConfirmAggregate(block *types.Block, state *state.StateDB) error
 	//CheckConsensusNode(nodeID discover.NodeID) (bool, error)
 
 	//IsConsensusNode() (bool, error)
@@ -168,10 +176,17 @@ type Bft interface {
 
 	GetOwnNodeID() discover.NodeID
 
+	// IsSignedBySelf returns if the block is signed by local.
+	IsSignedBySelf(sealHash common.Hash, signature []byte) bool
+
 	SetNodeCache(state *state.StateDB, parentNumber, currentNumber *big.Int, parentHash, currentHash common.Hash) error
 
 	Notify(state vm.StateDB, blockNumber *big.Int) error
 
+	// RecordLiveness checks that the block is in the turn of its producer and records
+	// the slots the consensus nodes missed since the parent, before the ppos storage hash
+	RecordLiveness(chain ChainReader, state *state.StateDB, header *types.Header) error
+
 	StoreHash(state *state.StateDB, blockNumber *big.Int, blockHash common.Hash)
 
 	Submit2Cache(state *state.StateDB, currBlocknumber *big.Int, blockInterval *big.Int, currBlockhash common.Hash)
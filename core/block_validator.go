@@ -82,9 +82,13 @@ func (v *BlockValidator) ValidateState(block, parent *types.Block, statedb *stat
 	if block.GasUsed() != usedGas {
 		return fmt.Errorf("invalid gas used (remote: %d local: %d)", block.GasUsed(), usedGas)
 	}
-	// Validate the received block's bloom with the one derived from the generated receipts.
-	// For valid blocks this should always validate to true.
+	// Validate the received block's bloom with the one derived from the generated receipts
+	// and, from the PPOS events fork on, the system logs. For valid blocks this should always
+	// validate to true.
 	rbloom := types.CreateBloom(receipts)
+	if v.config.IsPposEvents(header.Number) {
+		rbloom = types.CreateBlockBloom(receipts, statedb.GetLogs(types.SystemLogTxHash))
+	}
 	if rbloom != header.Bloom {
 		return fmt.Errorf("invalid bloom (remote: %x  local: %x)", header.Bloom, rbloom)
 	}
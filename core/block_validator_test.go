@@ -35,7 +35,7 @@ func TestHeaderVerification(t *testing.T) {
 		headers[i] = block.Header()
 	}
 	// Run the header checker for blocks one-by-one, checking for both valid and invalid nonces
-	chain, _ := NewBlockChain(testdb, nil, params.TestChainConfig, cbft.NewFaker(), vm.Config{}, nil)
+	chain, _ := NewBlockChain(testdb, nil, nil, params.TestChainConfig, cbft.NewFaker(), vm.Config{}, nil)
 	defer chain.Stop()
 
 	for i := 0; i < len(blocks); i++ {
@@ -100,11 +100,11 @@ func testHeaderConcurrentVerification(t *testing.T, threads int) {
 		var results <-chan error
 
 		if valid {
-			chain, _ := NewBlockChain(testdb, nil, params.TestChainConfig, cbft.NewFaker(), vm.Config{}, nil)
+			chain, _ := NewBlockChain(testdb, nil, nil, params.TestChainConfig, cbft.NewFaker(), vm.Config{}, nil)
 			_, results = chain.engine.VerifyHeaders(chain, headers, seals)
 			chain.Stop()
 		} else {
-			chain, _ := NewBlockChain(testdb, nil, params.TestChainConfig, cbft.NewFaker(), vm.Config{}, nil)
+			chain, _ := NewBlockChain(testdb, nil, nil, params.TestChainConfig, cbft.NewFaker(), vm.Config{}, nil)
 			_, results = chain.engine.VerifyHeaders(chain, headers, seals)
 			chain.Stop()
 		}
@@ -168,7 +168,7 @@ func testHeaderConcurrentAbortion(t *testing.T, threads int) {
 	defer runtime.GOMAXPROCS(old)
 
 	// Start the verifications and immediately abort
-	chain, _ := NewBlockChain(testdb, nil, params.TestChainConfig, cbft.NewFaker(), vm.Config{}, nil)
+	chain, _ := NewBlockChain(testdb, nil, nil, params.TestChainConfig, cbft.NewFaker(), vm.Config{}, nil)
 	defer chain.Stop()
 
 	abort, results := chain.engine.VerifyHeaders(chain, headers, seals)
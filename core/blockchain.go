@@ -121,6 +121,7 @@ type BlockChain struct {
 	currentFastBlock atomic.Value // Current head of the fast-sync chain (may be above the block chain!)
 
 	stateCache   state.Database // State database to reuse between imports (contains state cache)
+	pposTemp     *ppos_storage.PPOS_TEMP // PPOS storage temp of the chain, the ppos caches of its states are built from it
 	bodyCache    *lru.Cache     // Cache for the most recent block bodies
 	bodyRLPCache *lru.Cache     // Cache for the most recent block bodies in RLP encoded format
 	blockCache   *lru.Cache     // Cache for the most recent entire blocks
@@ -150,8 +151,8 @@ type BlockChain struct {
 
 // NewBlockChain returns a fully initialised block chain using information
 // available in the database. It initialises the default Ethereum Validator and
-// Processor.
-func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *params.ChainConfig, engine consensus.Engine, vmConfig vm.Config, shouldPreserve func(block *types.Block) bool) (*BlockChain, error) {
+// Processor. If pposTemp is nil, the ppos storage is kept in the chain database.
+func NewBlockChain(db ethdb.Database, pposTemp *ppos_storage.PPOS_TEMP, cacheConfig *CacheConfig, chainConfig *params.ChainConfig, engine consensus.Engine, vmConfig vm.Config, shouldPreserve func(block *types.Block) bool) (*BlockChain, error) {
 	if cacheConfig == nil {
 		cacheConfig = &CacheConfig{
 			TrieNodeLimit: 256 * 1024 * 1024,
@@ -163,13 +164,17 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	blockCache, _ := lru.New(blockCacheLimit)
 	futureBlocks, _ := lru.New(maxFutureBlocks)
 	badBlocks, _ := lru.New(badBlockLimit)
+	if pposTemp == nil {
+		pposTemp = ppos_storage.NewPPosTemp(db)
+	}
 
 	bc := &BlockChain{
 		chainConfig:    chainConfig,
 		cacheConfig:    cacheConfig,
 		db:             db,
 		triegc:         prque.New(nil),
-		stateCache:     state.NewDatabase(db),
+		stateCache:     state.NewDatabaseWithPPos(db, pposTemp),
+		pposTemp:       pposTemp,
 		quit:           make(chan struct{}),
 		shouldPreserve: shouldPreserve,
 		bodyCache:      bodyCache,
@@ -185,7 +190,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	bc.SetProcessor(NewStateProcessor(chainConfig, bc, engine))
 
 	var err error
-	bc.hc, err = NewHeaderChain(db, chainConfig, engine, bc.getProcInterrupt)
+	bc.hc, err = NewHeaderChain(db, pposTemp, chainConfig, engine, bc.getProcInterrupt)
 	if err != nil {
 		return nil, err
 	}
@@ -213,8 +218,8 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 
 	// TODO PPOS ADD
 	currNum := bc.CurrentBlock().Number()
-	pposNum := ppos_storage.GetPPosTempPtr().BlockNumber
-	pposHash := ppos_storage.GetPPosTempPtr().BlockHash
+	pposNum := bc.pposTemp.BlockNumber
+	pposHash := bc.pposTemp.BlockHash
 	if pposNum.Cmp(big.NewInt(0)) != 0 && pposNum.Cmp(currNum) < 0  && pposHash != (common.Hash{}){
 
 		log.Debug("Call NewBlockChain, currentBlock is not equal ppostempBlock, Reset CurrentBlock on Chain", "currentNumber",
@@ -765,7 +770,7 @@ func (bc *BlockChain) Stop() {
 	// When current block number equal N*BaseSwitchness -21 OR  current block number less than 1*BaseSwitchness -21
 	if _, m := new(big.Int).DivMod(targetNum, big.NewInt(common.BaseSwitchWitness), new(big.Int)); m.Cmp(big.NewInt(0)) == 0 || bc.CurrentBlock().Number().Cmp(big.NewInt(common.BaseSwitchWitness - ((common.BaseSwitchWitness - common.BaseElection) + 1))) < 0{
 		log.Debug("Call BlockChain Stop, write ppos_storage", "blockNumber", bc.CurrentBlock().NumberU64(), "blockHash", bc.CurrentBlock().Hash().Hex())
-		if err := ppos_storage.GetPPosTempPtr().Commit2DB(bc.CurrentBlock().Number(), bc.CurrentBlock().Hash()); nil != err {
+		if err := bc.pposTemp.Commit2DB(bc.CurrentBlock().Number(), bc.CurrentBlock().Hash()); nil != err {
 			log.Error("BlockChain Stop, Failed to write ppos_storage", "blockNumber", bc.CurrentBlock().NumberU64(), "blockHash", bc.CurrentBlock().Hash().Hex(), "err", err)
 		}
 	}
@@ -1016,7 +1021,7 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	// When current block number equal N*BaseSwitchness -21 OR  current block number less than 1*BaseSwitchness -21
 	if _, m := new(big.Int).DivMod(targetNum, big.NewInt(common.BaseSwitchWitness), new(big.Int)); m.Cmp(big.NewInt(0)) == 0 || externBn.Cmp(big.NewInt(common.BaseSwitchWitness - ((common.BaseSwitchWitness - common.BaseElection) + 1))) < 0 {
 		log.Debug("Call WriteBlockWithState, write ppos_storage", "blockNumber", block.NumberU64(), "blockHash", block.Hash().Hex())
-		if err := ppos_storage.GetPPosTempPtr().Commit2DB(block.Number(), block.Hash()); nil != err {
+		if err := bc.pposTemp.Commit2DB(block.Number(), block.Hash()); nil != err {
 			return NonStatTy, errors.New("Failed to call WriteBlockWithState to write ppos_storage, err:=" + err.Error())
 		}
 	}
@@ -1085,6 +1090,12 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	// Write other block data using a batch.
 	batch := bc.db.NewBatch()
 	rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receipts)
+	if systemLogs := state.GetLogs(types.SystemLogTxHash); len(systemLogs) > 0 {
+		for _, l := range systemLogs {
+			l.BlockHash = block.Hash()
+		}
+		rawdb.WriteSystemLogs(batch, block.Hash(), block.NumberU64(), systemLogs)
+	}
 
 	// If the total difficulty is higher than our known, add it to the canonical chain
 	// Second clause in the if statement reduces the vulnerability to selfish mining.
@@ -1298,6 +1309,13 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 		if err != nil {
 			return i, events, coalescedLogs, err
 		}
+		// Verify the aggregated confirmation with the BLS keys known to the parent
+		if bft, ok := bc.engine.(consensus.Bft); ok {
+			if err := bft.VerifyConfirmAggregate(block, state); err != nil {
+				bc.reportBlock(block, nil, err)
+				return i, events, coalescedLogs, err
+			}
+		}
 		// Process block using the parent state as reference point.
 		receipts, logs, usedGas, err := bc.processor.Process(block, state, bc.vmConfig, common.Big1)
 		if err != nil {
@@ -1462,6 +1480,10 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 					deletedLogs = append(deletedLogs, &del)
 				}
 			}
+			for _, log := range rawdb.ReadSystemLogs(bc.db, hash, *number) {
+				log.Removed = true
+				deletedLogs = append(deletedLogs, log)
+			}
 		}
 	)
 
@@ -1729,6 +1751,9 @@ func (bc *BlockChain) Config() *params.ChainConfig { return bc.chainConfig }
 // Engine retrieves the blockchain's consensus engine.
 func (bc *BlockChain) Engine() consensus.Engine { return bc.engine }
 
+// PPosTemp retrieves the blockchain's ppos storage temp.
+func (bc *BlockChain) PPosTemp() *ppos_storage.PPOS_TEMP { return bc.pposTemp }
+
 // SubscribeRemovedLogsEvent registers a subscription of RemovedLogsEvent.
 func (bc *BlockChain) SubscribeRemovedLogsEvent(ch chan<- RemovedLogsEvent) event.Subscription {
 	return bc.scope.Track(bc.rmLogsFeed.Subscribe(ch))
@@ -13,6 +13,8 @@ type BlockSignature struct {
 	Number    *big.Int
 	Signature *common.BlockConfirmSign
 	ParentHash common.Hash
+	// BLS signature of SignHash, it is aggregated with the others once the block is confirmed
+	BlsSignature []byte
 }
 
 type BlockSynced struct {
@@ -23,4 +25,6 @@ type CbftResult struct {
 	//Receipts          types.Receipts
 	//State             *state.StateDB
 	BlockConfirmSigns []*common.BlockConfirmSign
+	// aggregate of the BLS signatures, nil if the confirmations could not be aggregated
+	ConfirmAggregate *types.BlockConfirmAggregate
 }
@@ -0,0 +1,372 @@
+package core
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+const (
+	maxTaskRetries   = 5               // Maximum number of failed dispatches before a task is given up
+	taskRetryBackoff = 2 * time.Second // Delay before the first retry, doubled on every further failure
+	maxTaskBackoff   = 5 * time.Minute // Upper bound of the retry delay
+	taskRetention    = 24 * time.Hour  // Time finished tasks are kept before they are pruned
+)
+
+var (
+	mpcTaskTableKey = []byte("mpcTaskTable")
+	vcTaskTableKey  = []byte("vcTaskTable")
+
+	ErrTaskNotFound = errors.New("compute task not found")
+)
+
+// TaskStatus is the lifecycle state of a mpc/vc compute task.
+type TaskStatus uint8
+
+const (
+	TaskPending    TaskStatus = iota // waiting for confirmations or a retry
+	TaskDispatched                   // handed over to the compute backend
+	TaskCompleted                    // the compute backend finished the task
+	TaskFailed                       // retries exhausted or timed out
+	TaskOrphaned                     // the start-calc block left the canonical chain
+)
+
+func (s TaskStatus) String() string {
+	switch s {
+	case TaskPending:
+		return "pending"
+	case TaskDispatched:
+		return "dispatched"
+	case TaskCompleted:
+		return "completed"
+	case TaskFailed:
+		return "failed"
+	case TaskOrphaned:
+		return "orphaned"
+	}
+	return "unknown"
+}
+
+// final reports whether the task won't change its state anymore.
+func (s TaskStatus) final() bool {
+	return s == TaskCompleted || s == TaskFailed || s == TaskOrphaned
+}
+
+// ComputeTask is the record of a mpc/vc task started on chain.
+type ComputeTask struct {
+	TaskId      string
+	TxHash      common.Hash
+	BlockNumber uint64
+	BlockHash   common.Hash
+	Status      TaskStatus
+	Attempts    uint64
+	NextRetry   uint64 // unix time before which the task isn't dispatched again
+	Error       string
+	Created     uint64 // unix time the task was recorded
+	Updated     uint64 // unix time of the last status change
+}
+
+// canonicalChain is used to detect a start-calc block removed by a reorg.
+type canonicalChain interface {
+	GetBlockByNumber(number uint64) *types.Block
+}
+
+// computeTaskTable tracks the lifecycle of the compute tasks by TaskId. Every
+// task is persisted in the database under its own key, next to an index of the
+// known task ids, so the state of a task survives node restarts.
+type computeTaskTable struct {
+	db  ethdb.Database
+	key []byte
+
+	mu    sync.RWMutex
+	tasks map[string]*ComputeTask
+}
+
+// newComputeTaskTable loads the tasks stored under the prefix key, the table
+// is kept in memory only if db is nil.
+func newComputeTaskTable(db ethdb.Database, key []byte) *computeTaskTable {
+	table := &computeTaskTable{
+		db:    db,
+		key:   key,
+		tasks: make(map[string]*ComputeTask),
+	}
+	if db == nil {
+		return table
+	}
+	enc, err := db.Get(table.indexKey())
+	if err != nil || len(enc) == 0 {
+		return table
+	}
+	var ids []string
+	if err := rlp.DecodeBytes(enc, &ids); err != nil {
+		log.Error("Failed to decode compute task index", "key", string(key), "err", err)
+		return table
+	}
+	for _, id := range ids {
+		enc, err := db.Get(table.taskKey(id))
+		if err != nil {
+			log.Warn("Missing compute task", "key", string(key), "taskId", id)
+			continue
+		}
+		task := new(ComputeTask)
+		if err := rlp.DecodeBytes(enc, task); err != nil {
+			log.Error("Failed to decode compute task", "key", string(key), "taskId", id, "err", err)
+			continue
+		}
+		table.tasks[id] = task
+	}
+	return table
+}
+
+// indexKey and taskKey use distinct prefixes, no task id maps to the index.
+func (t *computeTaskTable) indexKey() []byte {
+	return append(append([]byte{}, t.key...), []byte("-index")...)
+}
+
+func (t *computeTaskTable) taskKey(taskId string) []byte {
+	return append(append([]byte{}, t.key...), []byte("-task-"+taskId)...)
+}
+
+// storeTask writes a single task to the database, the caller must hold the lock.
+func (t *computeTaskTable) storeTask(task *ComputeTask) {
+	if t.db == nil {
+		return
+	}
+	enc, err := rlp.EncodeToBytes(task)
+	if err != nil {
+		log.Error("Failed to encode compute task", "key", string(t.key), "taskId", task.TaskId, "err", err)
+		return
+	}
+	if err := t.db.Put(t.taskKey(task.TaskId), enc); err != nil {
+		log.Error("Failed to store compute task", "key", string(t.key), "taskId", task.TaskId, "err", err)
+	}
+}
+
+// storeIndex writes the ids of the known tasks to the database, the caller
+// must hold the lock.
+func (t *computeTaskTable) storeIndex() {
+	if t.db == nil {
+		return
+	}
+	tasks := t.sorted()
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.TaskId
+	}
+	enc, err := rlp.EncodeToBytes(ids)
+	if err != nil {
+		log.Error("Failed to encode compute task index", "key", string(t.key), "err", err)
+		return
+	}
+	if err := t.db.Put(t.indexKey(), enc); err != nil {
+		log.Error("Failed to store compute task index", "key", string(t.key), "err", err)
+	}
+}
+
+func (t *computeTaskTable) sorted() []*ComputeTask {
+	tasks := make([]*ComputeTask, 0, len(t.tasks))
+	for _, task := range t.tasks {
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].BlockNumber != tasks[j].BlockNumber {
+			return tasks[i].BlockNumber < tasks[j].BlockNumber
+		}
+		return tasks[i].TaskId < tasks[j].TaskId
+	})
+	return tasks
+}
+
+// add records a new pending task for the start-calc transaction tx included in
+// the block blockHash. A task already known is left untouched unless it was orphaned.
+func (t *computeTaskTable) add(tx *types.TransactionWrap, blockHash common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	known, ok := t.tasks[tx.TaskId]
+	if ok && known.Status != TaskOrphaned {
+		return
+	}
+	now := uint64(time.Now().Unix())
+	task := &ComputeTask{
+		TaskId:      tx.TaskId,
+		TxHash:      tx.Hash(),
+		BlockNumber: tx.Bn,
+		BlockHash:   blockHash,
+		Status:      TaskPending,
+		Created:     now,
+		Updated:     now,
+	}
+	t.tasks[tx.TaskId] = task
+	t.storeTask(task)
+	if !ok {
+		t.storeIndex()
+	}
+}
+
+// get returns a copy of the task, or nil if it's unknown.
+func (t *computeTaskTable) get(taskId string) *ComputeTask {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if task, ok := t.tasks[taskId]; ok {
+		cpy := *task
+		return &cpy
+	}
+	return nil
+}
+
+// list returns copies of all tasks ordered by block number.
+func (t *computeTaskTable) list() []*ComputeTask {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	tasks := t.sorted()
+	for i, task := range tasks {
+		cpy := *task
+		tasks[i] = &cpy
+	}
+	return tasks
+}
+
+// ready reports whether the task may be dispatched now. Unknown tasks are
+// always ready, final ones never are.
+func (t *computeTaskTable) ready(taskId string, now time.Time) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	task, ok := t.tasks[taskId]
+	if !ok {
+		return true
+	}
+	return !task.Status.final() && task.Status != TaskDispatched && task.NextRetry <= uint64(now.Unix())
+}
+
+// setStatus moves the task to status, err is recorded if not nil.
+func (t *computeTaskTable) setStatus(taskId string, status TaskStatus, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	task, ok := t.tasks[taskId]
+	if !ok {
+		return
+	}
+	task.Status = status
+	if err != nil {
+		task.Error = err.Error()
+	}
+	task.Updated = uint64(time.Now().Unix())
+	t.storeTask(task)
+}
+
+// dispatched marks an attempt of handing the task to the compute backend.
+func (t *computeTaskTable) dispatched(taskId string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	task, ok := t.tasks[taskId]
+	if !ok {
+		return
+	}
+	task.Status = TaskDispatched
+	task.Attempts++
+	task.Updated = uint64(time.Now().Unix())
+	t.storeTask(task)
+}
+
+// failed records a failed dispatch. The task is scheduled for a retry with
+// exponential backoff and true is returned, unless the retries are exhausted.
+func (t *computeTaskTable) failed(taskId string, err error) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	task, ok := t.tasks[taskId]
+	if !ok {
+		return false
+	}
+	now := time.Now()
+	task.Error = err.Error()
+	task.Updated = uint64(now.Unix())
+	if task.Attempts >= maxTaskRetries {
+		task.Status = TaskFailed
+		t.storeTask(task)
+		return false
+	}
+	backoff := taskRetryBackoff << (task.Attempts - 1)
+	if backoff > maxTaskBackoff || backoff <= 0 {
+		backoff = maxTaskBackoff
+	}
+	task.Status = TaskPending
+	task.NextRetry = uint64(now.Add(backoff).Unix())
+	t.storeTask(task)
+	return true
+}
+
+// reorg marks all unfinished tasks whose start-calc block is no longer
+// canonical as orphaned, and returns their ids.
+func (t *computeTaskTable) reorg(chain canonicalChain) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var orphaned []string
+	for id, task := range t.tasks {
+		if task.Status.final() {
+			continue
+		}
+		if block := chain.GetBlockByNumber(task.BlockNumber); block != nil && block.Hash() == task.BlockHash {
+			continue
+		}
+		task.Status = TaskOrphaned
+		task.Updated = uint64(time.Now().Unix())
+		t.storeTask(task)
+		orphaned = append(orphaned, id)
+	}
+	return orphaned
+}
+
+// expire fails all tasks dispatched longer than timeout ago.
+func (t *computeTaskTable) expire(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	deadline := uint64(time.Now().Add(-timeout).Unix())
+	for _, task := range t.tasks {
+		if task.Status == TaskDispatched && task.Updated < deadline {
+			task.Status = TaskFailed
+			task.Error = "timeout"
+			task.Updated = uint64(time.Now().Unix())
+			t.storeTask(task)
+		}
+	}
+}
+
+// prune drops the finished tasks which didn't change for longer than retention.
+func (t *computeTaskTable) prune(retention time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	deadline := uint64(time.Now().Add(-retention).Unix())
+	pruned := false
+	for id, task := range t.tasks {
+		if task.Status.final() && task.Updated < deadline {
+			delete(t.tasks, id)
+			if t.db != nil {
+				t.db.Delete(t.taskKey(id))
+			}
+			pruned = true
+		}
+	}
+	if pruned {
+		t.storeIndex()
+	}
+}
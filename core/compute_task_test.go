@@ -0,0 +1,176 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+)
+
+type testCanonicalChain map[uint64]*types.Block
+
+func (c testCanonicalChain) GetBlockByNumber(number uint64) *types.Block {
+	return c[number]
+}
+
+func testTaskBlock(number uint64, extra byte) *types.Block {
+	return types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(number), Extra: []byte{extra}})
+}
+
+func testTaskTx(taskId string, bn uint64) *types.TransactionWrap {
+	return &types.TransactionWrap{
+		Transaction: types.NewTransaction(bn, common.Address{0x01}, big.NewInt(0), 0, big.NewInt(0), nil),
+		Bn:          bn,
+		TaskId:      taskId,
+	}
+}
+
+func TestComputeTaskPersistence(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	table := newComputeTaskTable(db, mpcTaskTableKey)
+
+	block := testTaskBlock(1, 0)
+	table.add(testTaskTx("task1", 1), block.Hash())
+	table.add(testTaskTx("task2", 2), block.Hash())
+	table.dispatched("task1")
+	table.setStatus("task1", TaskCompleted, nil)
+
+	reloaded := newComputeTaskTable(db, mpcTaskTableKey)
+	if tasks := reloaded.list(); len(tasks) != 2 {
+		t.Fatalf("task count mismatch: have %d, want %d", len(tasks), 2)
+	}
+	if task := reloaded.get("task1"); task.Status != TaskCompleted || task.Attempts != 1 {
+		t.Fatalf("task1 mismatch: have %v/%d, want %v/%d", task.Status, task.Attempts, TaskCompleted, 1)
+	}
+	if task := reloaded.get("task2"); task.Status != TaskPending || task.BlockHash != block.Hash() {
+		t.Fatalf("task2 mismatch: have %v", task)
+	}
+	if other := newComputeTaskTable(db, vcTaskTableKey); len(other.list()) != 0 {
+		t.Fatalf("vc table not empty")
+	}
+}
+
+func TestComputeTaskRetry(t *testing.T) {
+	table := newComputeTaskTable(nil, mpcTaskTableKey)
+	table.add(testTaskTx("task", 1), common.Hash{})
+
+	fail := errors.New("fail")
+	for i := 0; i < maxTaskRetries-1; i++ {
+		table.dispatched("task")
+		if !table.failed("task", fail) {
+			t.Fatalf("attempt %d: task not retried", i+1)
+		}
+		task := table.get("task")
+		if task.Status != TaskPending || task.Error != fail.Error() {
+			t.Fatalf("attempt %d: task mismatch: have %v", i+1, task)
+		}
+		if table.ready("task", time.Now()) {
+			t.Fatalf("attempt %d: task ready before backoff", i+1)
+		}
+		if !table.ready("task", time.Now().Add(maxTaskBackoff+time.Second)) {
+			t.Fatalf("attempt %d: task not ready after backoff", i+1)
+		}
+	}
+	table.dispatched("task")
+	if table.failed("task", fail) {
+		t.Fatalf("task retried after %d attempts", maxTaskRetries)
+	}
+	if task := table.get("task"); task.Status != TaskFailed {
+		t.Fatalf("status mismatch: have %v, want %v", task.Status, TaskFailed)
+	}
+	if table.ready("task", time.Now().Add(time.Hour)) {
+		t.Fatalf("failed task ready")
+	}
+}
+
+func TestComputeTaskReorg(t *testing.T) {
+	table := newComputeTaskTable(nil, vcTaskTableKey)
+
+	block1, block2 := testTaskBlock(1, 0), testTaskBlock(2, 0)
+	chain := testCanonicalChain{1: block1, 2: block2}
+	table.add(testTaskTx("task1", 1), block1.Hash())
+	table.add(testTaskTx("task2", 2), block2.Hash())
+	table.add(testTaskTx("task3", 2), block2.Hash())
+	table.setStatus("task3", TaskCompleted, nil)
+
+	if orphaned := table.reorg(chain); len(orphaned) != 0 {
+		t.Fatalf("tasks orphaned on canonical chain: %v", orphaned)
+	}
+	// Replace block 2 by a sibling
+	chain[2] = testTaskBlock(2, 1)
+	orphaned := table.reorg(chain)
+	if len(orphaned) != 1 || orphaned[0] != "task2" {
+		t.Fatalf("orphaned tasks mismatch: have %v, want [task2]", orphaned)
+	}
+	if task := table.get("task2"); task.Status != TaskOrphaned {
+		t.Fatalf("status mismatch: have %v, want %v", task.Status, TaskOrphaned)
+	}
+	if task := table.get("task3"); task.Status != TaskCompleted {
+		t.Fatalf("completed task changed: have %v", task.Status)
+	}
+	// The task is started again once it is included in the new chain
+	table.add(testTaskTx("task2", 2), chain[2].Hash())
+	if task := table.get("task2"); task.Status != TaskPending || task.BlockHash != chain[2].Hash() {
+		t.Fatalf("re-included task mismatch: have %v", task)
+	}
+}
+
+func TestComputeTaskExpire(t *testing.T) {
+	table := newComputeTaskTable(nil, mpcTaskTableKey)
+	table.add(testTaskTx("task", 1), common.Hash{})
+	table.dispatched("task")
+
+	table.expire(time.Hour)
+	if task := table.get("task"); task.Status != TaskDispatched {
+		t.Fatalf("status mismatch: have %v, want %v", task.Status, TaskDispatched)
+	}
+	table.mu.Lock()
+	table.tasks["task"].Updated -= 2 * 3600
+	table.mu.Unlock()
+
+	table.expire(time.Hour)
+	if task := table.get("task"); task.Status != TaskFailed || task.Error != "timeout" {
+		t.Fatalf("task mismatch: have %v", task)
+	}
+}
+
+func TestComputeTaskPrune(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	table := newComputeTaskTable(db, mpcTaskTableKey)
+	table.add(testTaskTx("task1", 1), common.Hash{})
+	table.add(testTaskTx("task2", 2), common.Hash{})
+	table.setStatus("task1", TaskCompleted, nil)
+	table.setStatus("task2", TaskCompleted, nil)
+
+	table.mu.Lock()
+	table.tasks["task1"].Updated -= 2 * 3600
+	table.mu.Unlock()
+
+	table.prune(time.Hour)
+	if task := table.get("task1"); task != nil {
+		t.Fatalf("finished task not pruned: %v", task)
+	}
+	if has, _ := db.Has(table.taskKey("task1")); has {
+		t.Fatalf("pruned task still in database")
+	}
+	reloaded := newComputeTaskTable(db, mpcTaskTableKey)
+	if tasks := reloaded.list(); len(tasks) != 1 || tasks[0].TaskId != "task2" {
+		t.Fatalf("tasks mismatch: have %v, want [task2]", tasks)
+	}
+}
+
+func TestComputeTaskKeys(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	table := newComputeTaskTable(db, mpcTaskTableKey)
+	table.add(testTaskTx("index", 1), common.Hash{})
+	table.add(testTaskTx("task1", 2), common.Hash{})
+
+	reloaded := newComputeTaskTable(db, mpcTaskTableKey)
+	if tasks := reloaded.list(); len(tasks) != 2 {
+		t.Fatalf("tasks mismatch: have %v, want [index task1]", tasks)
+	}
+}
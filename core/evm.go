@@ -21,6 +21,7 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/consensus"
+	"github.com/PlatONnetwork/PlatON-Go/core/ppos"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 )
@@ -35,6 +36,13 @@ type ChainContext interface {
 	GetHeader(common.Hash, uint64) *types.Header
 }
 
+// pposEngine is implemented by consensus engines which run the ppos pools of
+// the chain, the ppos precompiles of the EVM work on them.
+type pposEngine interface {
+	CandidatePoolContext() *pposm.CandidatePoolContext
+	TicketPoolContext() *pposm.TicketPoolContext
+}
+
 // NewEVMContext creates a new context for use in the EVM.
 func NewEVMContext(msg Message, header *types.Header, chain ChainContext, author *common.Address) vm.Context {
 	// If we don't have an explicit author (i.e. not mining), extract from the header
@@ -44,7 +52,7 @@ func NewEVMContext(msg Message, header *types.Header, chain ChainContext, author
 	} else {
 		beneficiary = *author
 	}
-	return vm.Context{
+	context := vm.Context{
 		CanTransfer: CanTransfer,
 		Transfer:    Transfer,
 		GetHash:     GetHashFn(header, chain),
@@ -56,6 +64,14 @@ func NewEVMContext(msg Message, header *types.Header, chain ChainContext, author
 		GasLimit:    header.GasLimit,
 		GasPrice:    new(big.Int).Set(msg.GasPrice()),
 	}
+	// ppos add, the ppos precompiles work on the pools of the chain's engine
+	if chain != nil {
+		if engine, ok := chain.Engine().(pposEngine); ok {
+			context.CandidatePoolContext = engine.CandidatePoolContext()
+			context.TicketPoolContext = engine.TicketPoolContext()
+		}
+	}
+	return context
 }
 
 // GetHashFn returns a GetHashFunc which retrieves header hashes by number
@@ -112,7 +112,7 @@ func TestSetupGenesis(t *testing.T) {
 				// Advance to block #4, past the homestead transition block of customg.
 				genesis := oldcustomg.MustCommit(db)
 
-				bc, _ := NewBlockChain(db, nil, oldcustomg.Config, cbft.NewFaker(), vm.Config{}, nil)
+				bc, _ := NewBlockChain(db, nil, nil, oldcustomg.Config, cbft.NewFaker(), vm.Config{}, nil)
 				defer bc.Stop()
 
 				blocks, _ := GenerateChain(oldcustomg.Config, genesis, cbft.NewFaker(), db, 4, nil)
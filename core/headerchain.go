@@ -69,7 +69,8 @@ type HeaderChain struct {
 //  getValidator should return the parent's validator
 //  procInterrupt points to the parent's interrupt semaphore
 //  wg points to the parent's shutdown wait group
-func NewHeaderChain(chainDb ethdb.Database, config *params.ChainConfig, engine consensus.Engine, procInterrupt func() bool) (*HeaderChain, error) {
+//  pposTemp is the parent's ppos storage temp, nil if the parent keeps none
+func NewHeaderChain(chainDb ethdb.Database, pposTemp *ppos_storage.PPOS_TEMP, config *params.ChainConfig, engine consensus.Engine, procInterrupt func() bool) (*HeaderChain, error) {
 	headerCache, _ := lru.New(headerCacheLimit)
 	numberCache, _ := lru.New(numberCacheLimit)
 
@@ -100,8 +101,10 @@ func NewHeaderChain(chainDb ethdb.Database, config *params.ChainConfig, engine c
 			/*hc.currentHeader.Store(chead)*/
 
 			// TODO PPOS ADD
-			pposNum := ppos_storage.GetPPosTempPtr().BlockNumber
-			pposHash := ppos_storage.GetPPosTempPtr().BlockHash
+			pposNum, pposHash := big.NewInt(0), common.Hash{}
+			if nil != pposTemp {
+				pposNum, pposHash = pposTemp.BlockNumber, pposTemp.BlockHash
+			}
 
 			lastNum := chead.Number
 			if pposNum.Cmp(big.NewInt(0)) != 0 &&  pposNum.Cmp(lastNum) < 0 && pposHash != (common.Hash{}) {
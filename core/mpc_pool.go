@@ -9,6 +9,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/event"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/mpc"
@@ -44,6 +45,7 @@ const (
 type mpcBlockChain interface {
 	CurrentBlock() *types.Block
 	GetBlock(hash common.Hash, number uint64) *types.Block
+	GetBlockByNumber(number uint64) *types.Block
 
 	SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription
 }
@@ -56,10 +58,17 @@ type MPCPoolConfig struct {
 	Rejournal   time.Duration // Time interval to regenerate the local transaction journal
 	GlobalQueue uint64        // Maximum number of non-executable transaction slots for all accounts
 	Lifetime    time.Duration // Maximum amount of time non-executable transaction are queued
+	TaskTimeout time.Duration // Maximum amount of time a dispatched task waits for its result
 
 	LocalRpcPort int            // LocalRpcPort of local rpc port
 	IceConf      string         // ice conf to init vm
 	MpcActor     common.Address // the actor of mpc compute
+
+	Executor     string          // backend of mpc compute: cgo, redis, http or fake (default cgo)
+	RedisURL     string          // address of redis the mpc tasks are pushed to
+	HTTPEndpoint string          // url of the http service the mpc tasks are posted to
+	HTTPTimeout  time.Duration   // timeout of a single post to the http service
+	MPCExecutor  mpc.MPCExecutor `toml:"-"` // explicit executor, takes precedence over Executor
 }
 
 var DefaultMPCPoolConfig = MPCPoolConfig{
@@ -67,6 +76,10 @@ var DefaultMPCPoolConfig = MPCPoolConfig{
 	Rejournal:   time.Second * 4,
 	GlobalQueue: 1024,
 	Lifetime:    3 * time.Hour,
+	TaskTimeout: time.Hour,
+
+	Executor:    mpc.ExecutorCgo,
+	HTTPTimeout: 10 * time.Second,
 }
 
 type MPCPool struct {
@@ -79,17 +92,27 @@ type MPCPool struct {
 	all     *mpcLookup  // All transactions to allow lookups
 	queue   *mpcList    // All transactions sorted by price
 
+	executor mpc.MPCExecutor   // backend the confirmed mpc tasks are handed to
+	tasks    *computeTaskTable // lifecycle of the mpc tasks by TaskId
+
 	quiteSign chan interface{}
 
 	wg sync.WaitGroup // for shutdown sync
 }
 
-func NewMPCPool(config MPCPoolConfig, chainconfig *params.ChainConfig, chain mpcBlockChain) *MPCPool {
+func NewMPCPool(config MPCPoolConfig, chainconfig *params.ChainConfig, chain mpcBlockChain, db ethdb.Database) (*MPCPool, error) {
+	executor, err := newMPCExecutor(config)
+	if err != nil {
+		return nil, err
+	}
 	pool := &MPCPool{
 		config:      config,
 		chainconfig: chainconfig,
 		chain:       chain,
 		all:         newMpcLookup(),
+		tasks:       newComputeTaskTable(db, mpcTaskTableKey),
+		quiteSign:   make(chan interface{}),
+		executor:    executor,
 	}
 
 	pool.queue = newMpcList(pool.all)
@@ -109,11 +132,31 @@ func NewMPCPool(config MPCPoolConfig, chainconfig *params.ChainConfig, chain mpc
 	// save to global attr
 	MPC_POOL = pool
 
-	// init mvm
-	url := "http://127.0.0.1:" + strconv.FormatUint(uint64(pool.config.LocalRpcPort), 10)
-	mpc.InitVM(pool.config.IceConf, url)
+	return pool, nil
+}
 
-	return pool
+// newMPCExecutor creates the executor backend selected in config.
+func newMPCExecutor(config MPCPoolConfig) (mpc.MPCExecutor, error) {
+	if config.MPCExecutor != nil {
+		return config.MPCExecutor, nil
+	}
+	switch config.Executor {
+	case "", mpc.ExecutorCgo:
+		return newCgoExecutor(config), nil
+	case mpc.ExecutorRedis:
+		return mpc.NewRedisExecutor(config.RedisURL)
+	case mpc.ExecutorHTTP:
+		return mpc.NewHTTPExecutor(config.HTTPEndpoint, config.HTTPTimeout)
+	case mpc.ExecutorFake:
+		return mpc.NewFakeExecutor(), nil
+	}
+	return nil, fmt.Errorf("%v: %s", mpc.ErrUnknownExecutor, config.Executor)
+}
+
+// newCgoExecutor inits the native mvm with the ice conf and the local rpc endpoint.
+func newCgoExecutor(config MPCPoolConfig) mpc.MPCExecutor {
+	url := "http://127.0.0.1:" + strconv.FormatUint(uint64(config.LocalRpcPort), 10)
+	return mpc.NewCgoExecutor(config.IceConf, url)
 }
 
 func (pool *MPCPool) loop() {
@@ -130,6 +173,10 @@ func (pool *MPCPool) loop() {
 
 	pop := time.NewTicker(time.Second * 1)
 
+	chainHeadCh := make(chan ChainHeadEvent, chainHeadChanSize)
+	chainHeadSub := pool.chain.SubscribeChainHeadEvent(chainHeadCh)
+	defer chainHeadSub.Unsubscribe()
+
 	// Keep waiting for and reacting to the various events
 	for {
 		select {
@@ -156,40 +203,129 @@ func (pool *MPCPool) loop() {
 		case <-pool.quiteSign:
 			return
 
+		// Detect the start-calc blocks removed by a reorg
+		case <-chainHeadCh:
+			if orphaned := pool.tasks.reorg(pool.chain); len(orphaned) > 0 {
+				log.Warn("Mpc tasks orphaned by chain reorg", "tasks", orphaned)
+			}
+			pool.tasks.expire(pool.config.TaskTimeout)
+			pool.tasks.prune(taskRetention)
+
+		// Be unsubscribed due to system stopped
+		case <-chainHeadSub.Err():
+			return
+
 		case <-pop.C:
 			if pool.queue.items.Len() != 0 {
 				pool.mu.Lock()
 				tx := pool.queue.Pop()
 				bn := pool.chain.CurrentBlock().Number().Int64()
-				if bn < int64(tx.Bn) || (bn-int64(tx.Bn)) >= MinBlockConfirms {
+				dispatch := false
+				switch {
+				case bn < int64(tx.Bn):
+					// The start-calc block is gone, the task won't be processed
+					pool.all.Remove(tx.Hash())
+					pool.tasks.setStatus(tx.TaskId, TaskOrphaned, nil)
+				case pool.finished(tx):
 					pool.all.Remove(tx.Hash())
-				} else {
+				case (bn-int64(tx.Bn)) >= MinBlockConfirms && pool.tasks.ready(tx.TaskId, time.Now()):
+					pool.all.Remove(tx.Hash())
+					dispatch = true
+				default:
 					pool.queue.Put(tx)
 				}
 				pool.mu.Unlock()
 
-				if (bn - int64(tx.Bn)) >= MinBlockConfirms {
-					log.Trace("Wow ~ Received mpc transaction", "hash", tx.Hash(), " bn", tx.Bn)
-					singer := types.MakeSigner(pool.chainconfig, big.NewInt(int64(tx.Bn)))
-					caller, pubKey, err := singer.SignatureAndSender(tx.Transaction)
-					if err != nil {
-						log.Warn("Get sig fail", "hash", tx.Hash())
-					}
-					log.Info("Recover pubKey success", "pubKey", common.Bytes2Hex(pubKey))
-					mpc.ExecuteMPCTx(mpc.MPCParams{
-						TaskId: tx.TaskId,
-						Pubkey: common.Bytes2Hex(pubKey),
-						From:   caller,
-						IRAddr: *tx.To(),
-						Method: tx.FuncName,
-						Extra:  "",
-					})
+				if dispatch {
+					pool.execute(tx)
 				}
 			}
 		}
 	}
 }
 
+// finished reports whether the task of tx was already completed, failed
+// or orphaned by a reorg.
+func (pool *MPCPool) finished(tx *types.TransactionWrap) bool {
+	task := pool.tasks.get(tx.TaskId)
+	return task != nil && task.Status.final()
+}
+
+// execute hands the confirmed mpc transaction to the executor, a failed task
+// is queued again to be retried with backoff. A dispatched task is completed
+// once the set_result callback of the backend is included in a block.
+func (pool *MPCPool) execute(tx *types.TransactionWrap) {
+	log.Trace("Wow ~ Received mpc transaction", "hash", tx.Hash(), " bn", tx.Bn)
+	pool.tasks.dispatched(tx.TaskId)
+
+	singer := types.MakeSigner(pool.chainconfig, big.NewInt(int64(tx.Bn)))
+	caller, pubKey, err := singer.SignatureAndSender(tx.Transaction)
+	if err != nil {
+		log.Warn("Get sig fail", "hash", tx.Hash())
+		pool.tasks.setStatus(tx.TaskId, TaskFailed, err)
+		return
+	}
+	log.Info("Recover pubKey success", "pubKey", common.Bytes2Hex(pubKey))
+	err = pool.executor.Execute(mpc.MPCParams{
+		TaskId: tx.TaskId,
+		Pubkey: common.Bytes2Hex(pubKey),
+		From:   caller,
+		IRAddr: *tx.To(),
+		Method: tx.FuncName,
+		Extra:  "",
+	})
+	if err != nil {
+		log.Error("Failed to execute mpc transaction", "hash", tx.Hash(), "taskId", tx.TaskId, "err", err)
+		if pool.tasks.failed(tx.TaskId, err) {
+			pool.mu.Lock()
+			pool.enqueueTx(tx.Hash(), tx)
+			pool.mu.Unlock()
+		}
+		return
+	}
+}
+
+// setResultTaskId returns the task id if data is a call of set_result, the
+// callback the compute backend reports the result of a task with.
+func setResultTaskId(data []byte) (string, bool) {
+	var input [][]byte
+	if err := rlp.DecodeBytes(data, &input); err != nil || len(input) < 3 {
+		return "", false
+	}
+	if string(input[1]) != "set_result" {
+		return "", false
+	}
+	return string(input[2]), true
+}
+
+// completeTask marks the task completed by the set_result transaction tx,
+// the result has to be reported to the contract which started the task.
+func (pool *MPCPool) completeTask(taskId string, tx *types.Transaction) {
+	task := pool.tasks.get(taskId)
+	if task == nil || task.Status.final() || tx.To() == nil {
+		return
+	}
+	block := pool.chain.GetBlock(task.BlockHash, task.BlockNumber)
+	if block == nil {
+		return
+	}
+	if start := block.Transaction(task.TxHash); start == nil || start.To() == nil || *start.To() != *tx.To() {
+		log.Debug("Discarding mpc result of another contract", "taskId", taskId, "hash", tx.Hash())
+		return
+	}
+	pool.tasks.setStatus(taskId, TaskCompleted, nil)
+}
+
+// Task returns the state of the mpc task, or nil if it's unknown.
+func (pool *MPCPool) Task(taskId string) *ComputeTask {
+	return pool.tasks.get(taskId)
+}
+
+// Tasks returns the state of all known mpc tasks.
+func (pool *MPCPool) Tasks() []*ComputeTask {
+	return pool.tasks.list()
+}
+
 func (pool *MPCPool) LoadActor() error {
 	absPath, err := filepath.Abs(DEFAULT_ACTOR_FILE_NAME)
 	if err != nil {
@@ -332,7 +468,13 @@ func (pool *MPCPool) InjectTxs(block *types.Block, receipts types.Receipts, bc *
 		log.Info("Wow ~ MPC Disable...")
 		return
 	}
-	for _, tx := range block.Transactions() {
+	for i, tx := range block.Transactions() {
+		if taskId, ok := setResultTaskId(tx.Data()); ok {
+			if i < len(receipts) && receipts[i].Status == types.ReceiptStatusSuccessful {
+				pool.completeTask(taskId, tx)
+			}
+			continue
+		}
 		isSave := false
 		var taskId string
 		for _, receipt := range receipts {
@@ -366,7 +508,9 @@ func (pool *MPCPool) InjectTxs(block *types.Block, receipts types.Receipts, bc *
 				log.Debug("God ~ Discarding the actor not belong to current mpc contract.", "hash", wrap.Hash(), "err", err.Error())
 				return
 			}
-			pool.add(wrap)
+			if _, err := pool.add(wrap); err == nil {
+				pool.tasks.add(wrap, block.Hash())
+			}
 		}
 	}
 }
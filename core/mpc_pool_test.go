@@ -2,15 +2,19 @@ package core
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/event"
+	"github.com/PlatONnetwork/PlatON-Go/mpc"
 	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"math/big"
 	"testing"
+	"time"
 )
 
 var mpcTestTxPoolConfig MPCPoolConfig
@@ -18,16 +22,19 @@ var mpcTestTxPoolConfig MPCPoolConfig
 func init() {
 	mpcTestTxPoolConfig = DefaultMPCPoolConfig
 	mpcTestTxPoolConfig.Journal = "mpc.rlp"
+	mpcTestTxPoolConfig.Executor = mpc.ExecutorFake
 }
 
 type testMpcBlockChain struct {
 	statedb       *state.StateDB
 	gasLimit      uint64
 	chainHeadFeed *event.Feed
+	number        uint64
 }
 
 func (bc *testMpcBlockChain) CurrentBlock() *types.Block {
 	return types.NewBlock(&types.Header{
+		Number:   new(big.Int).SetUint64(bc.number),
 		GasLimit: bc.gasLimit,
 	}, nil, nil, nil)
 }
@@ -36,6 +43,10 @@ func (bc *testMpcBlockChain) GetBlock(hash common.Hash, number uint64) *types.Bl
 	return bc.CurrentBlock()
 }
 
+func (bc *testMpcBlockChain) GetBlockByNumber(number uint64) *types.Block {
+	return bc.CurrentBlock()
+}
+
 func (bc *testMpcBlockChain) StateAt(common.Hash, *big.Int, common.Hash) (*state.StateDB, error) {
 	return bc.statedb, nil
 }
@@ -61,10 +72,10 @@ func mpcPricedTransaction(nonce uint64, gaslimit uint64, gasprice *big.Int, key
 // Configuring the MPCPool transaction pool.
 func setupMpcPool() (*MPCPool, *ecdsa.PrivateKey) {
 	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()), big.NewInt(0), common.Hash{})
-	blockchain := &testMpcBlockChain{statedb, 1000000, new(event.Feed)}
+	blockchain := &testMpcBlockChain{statedb, 1000000, new(event.Feed), 0}
 
 	key, _ := crypto.GenerateKey()
-	pool := NewMPCPool(mpcTestTxPoolConfig, params.TestChainConfig, blockchain)
+	pool, _ := NewMPCPool(mpcTestTxPoolConfig, params.TestChainConfig, blockchain, nil)
 
 	return pool, key
 }
@@ -149,14 +160,14 @@ func testMpcTransactionJournaling(t *testing.T, nolocals bool) {
 
 	// Create the original pool to inject transaction into the journal
 	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()), big.NewInt(0), common.Hash{})
-	blockchain := &testMpcBlockChain{statedb, 1000000, new(event.Feed)}
+	blockchain := &testMpcBlockChain{statedb, 1000000, new(event.Feed), 0}
 
 	config := mpcTestTxPoolConfig
 	config.NoLocals = nolocals
 	config.Journal = journal
 	config.Rejournal = time.Second
 
-	pool := NewMPCPool(config, params.TestChainConfig, blockchain)
+	pool, _ := NewMPCPool(config, params.TestChainConfig, blockchain, nil)
 
 	key, _ := crypto.GenerateKey()
 	tx := mpcTransaction("a2c4d041f7f88c8be5ea8bac94c0a28178b47bae1dfc01100a26b01de04dd360",0, 100, key)
@@ -174,3 +185,178 @@ func testMpcTransactionJournaling(t *testing.T, nolocals bool) {
 	}*/
 }
 
+
+func mpcCallTransaction(taskId string, bn uint64, key *ecdsa.PrivateKey) *types.TransactionWrap {
+	data, _ := rlp.EncodeToBytes([]interface{}{common.Int64ToBytes(TX_MPC), []byte("start_calc")})
+	tx, _ := types.SignTx(types.NewTransaction(bn, common.Address{0x01}, big.NewInt(0), 100000, big.NewInt(1), data), types.HomesteadSigner{}, key)
+	return &types.TransactionWrap{
+		Transaction: tx,
+		Bn:          bn,
+		TaskId:      taskId,
+	}
+}
+
+func TestMpcExecutorSelect(t *testing.T) {
+	config := DefaultMPCPoolConfig
+
+	config.Executor = mpc.ExecutorFake
+	if executor, err := newMPCExecutor(config); err != nil {
+		t.Fatalf("failed to create fake executor: %v", err)
+	} else if _, ok := executor.(*mpc.FakeExecutor); !ok {
+		t.Fatalf("executor type mismatch: have %T, want *mpc.FakeExecutor", executor)
+	}
+
+	config.Executor = mpc.ExecutorHTTP
+	if _, err := newMPCExecutor(config); err != mpc.ErrEmptyEndpoint {
+		t.Fatalf("error mismatch: have %v, want %v", err, mpc.ErrEmptyEndpoint)
+	}
+	config.HTTPEndpoint = "http://127.0.0.1:8080/task"
+	if executor, err := newMPCExecutor(config); err != nil {
+		t.Fatalf("failed to create http executor: %v", err)
+	} else if _, ok := executor.(*mpc.HTTPExecutor); !ok {
+		t.Fatalf("executor type mismatch: have %T, want *mpc.HTTPExecutor", executor)
+	}
+
+	config.Executor = mpc.ExecutorRedis
+	config.RedisURL = "127.0.0.1:6379"
+	if executor, err := newMPCExecutor(config); err != nil {
+		t.Fatalf("failed to create redis executor: %v", err)
+	} else if _, ok := executor.(*mpc.RedisExecutor); !ok {
+		t.Fatalf("executor type mismatch: have %T, want *mpc.RedisExecutor", executor)
+	}
+
+	config.Executor = "unknown"
+	if _, err := newMPCExecutor(config); err == nil {
+		t.Fatalf("expected error for unknown executor")
+	}
+	if _, err := NewMPCPool(config, params.TestChainConfig, nil, nil); err == nil {
+		t.Fatalf("expected pool creation to fail for unknown executor")
+	}
+
+	fake := mpc.NewFakeExecutor()
+	config.MPCExecutor = fake
+	if executor, _ := newMPCExecutor(config); executor != fake {
+		t.Fatalf("explicit executor not used")
+	}
+}
+
+// testMpcResultChain serves the start-calc block of a task to the pool.
+type testMpcResultChain struct {
+	*testMpcBlockChain
+	start *types.Block
+}
+
+func (bc *testMpcResultChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return bc.start
+}
+
+func mpcResultTransaction(taskId string, to common.Address, key *ecdsa.PrivateKey) *types.Transaction {
+	data := common.Hex2Bytes(genSetResultInput(taskId, []byte{0x01}))
+	tx, _ := types.SignTx(types.NewTransaction(1, to, big.NewInt(0), 100000, big.NewInt(1), data), types.HomesteadSigner{}, key)
+	return tx
+}
+
+// Tests that a pooled mpc transaction is handed to the executor once it
+// got enough confirmations, and completed by the set_result callback.
+func TestMpcTaskExecution(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()), big.NewInt(0), common.Hash{})
+	key, _ := crypto.GenerateKey()
+	tx := mpcCallTransaction("a2c4d041f7f88c8be5ea8bac94c0a28178b47bae1dfc01100a26b01de04dd368", 0, key)
+	blockchain := &testMpcResultChain{
+		testMpcBlockChain: &testMpcBlockChain{statedb, 1000000, new(event.Feed), uint64(MinBlockConfirms)},
+		start:             types.NewBlock(&types.Header{Number: big.NewInt(0)}, []*types.Transaction{tx.Transaction}, nil, nil),
+	}
+
+	fake := mpc.NewFakeExecutor()
+	config := mpcTestTxPoolConfig
+	config.Journal = ""
+	config.MPCExecutor = fake
+
+	pool, _ := NewMPCPool(config, params.TestChainConfig, blockchain, nil)
+	defer pool.Stop()
+
+	if err := pool.addTx(tx); err != nil {
+		t.Fatalf("failed to add mpc transaction: %v", err)
+	}
+	pool.tasks.add(tx, blockchain.CurrentBlock().Hash())
+
+	select {
+	case task := <-fake.Tasks():
+		if task.TaskId != tx.TaskId {
+			t.Errorf("taskId mismatch: have %s, want %s", task.TaskId, tx.TaskId)
+		}
+		if task.Method != "start_calc" {
+			t.Errorf("method mismatch: have %s, want %s", task.Method, "start_calc")
+		}
+		if want := crypto.PubkeyToAddress(key.PublicKey); task.From != want {
+			t.Errorf("from mismatch: have %x, want %x", task.From, want)
+		}
+		if task.IRAddr != *tx.To() {
+			t.Errorf("irAddr mismatch: have %x, want %x", task.IRAddr, *tx.To())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("mpc task not executed")
+	}
+	if _, queued := pool.Stats(); queued != 0 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 0)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if task := pool.Task(tx.TaskId); task == nil || task.Status != TaskDispatched {
+		t.Fatalf("task status mismatch: have %v, want %v", task, TaskDispatched)
+	}
+	// Results reported to another contract are ignored
+	result := mpcResultTransaction(tx.TaskId, common.Address{0x02}, key)
+	if taskId, ok := setResultTaskId(result.Data()); !ok || taskId != tx.TaskId {
+		t.Fatalf("set_result taskId mismatch: have %q, want %q", taskId, tx.TaskId)
+	}
+	pool.completeTask(tx.TaskId, result)
+	if task := pool.Task(tx.TaskId); task.Status != TaskDispatched {
+		t.Fatalf("task status mismatch: have %v, want %v", task.Status, TaskDispatched)
+	}
+	pool.completeTask(tx.TaskId, mpcResultTransaction(tx.TaskId, *tx.To(), key))
+	if task := pool.Task(tx.TaskId); task.Status != TaskCompleted {
+		t.Fatalf("task status mismatch: have %v, want %v", task.Status, TaskCompleted)
+	}
+}
+
+// Tests that a failed dispatch is retried with backoff.
+func TestMpcTaskRetry(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()), big.NewInt(0), common.Hash{})
+	blockchain := &testMpcBlockChain{statedb, 1000000, new(event.Feed), uint64(MinBlockConfirms)}
+
+	fake := mpc.NewFakeExecutor()
+	fake.SetError(errors.New("backend down"))
+	config := mpcTestTxPoolConfig
+	config.Journal = ""
+	config.MPCExecutor = fake
+
+	pool, _ := NewMPCPool(config, params.TestChainConfig, blockchain, nil)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	tx := mpcCallTransaction("a2c4d041f7f88c8be5ea8bac94c0a28178b47bae1dfc01100a26b01de04dd369", 0, key)
+	pool.tasks.add(tx, blockchain.CurrentBlock().Hash())
+	if err := pool.addTx(tx); err != nil {
+		t.Fatalf("failed to add mpc transaction: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if task := pool.Task(tx.TaskId); task.Attempts == 1 && task.Status == TaskPending {
+			if task.Error != "backend down" {
+				t.Fatalf("task error mismatch: have %q, want %q", task.Error, "backend down")
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("task not retried: %v", pool.Task(tx.TaskId))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	fake.SetError(nil)
+	select {
+	case <-fake.Tasks():
+	case <-time.After(10 * time.Second):
+		t.Fatalf("mpc task not executed after retry")
+	}
+}
@@ -12,110 +12,143 @@ import (
 
 type CandidatePoolContext struct {
 	Configs *params.PposConfig
+	// the ticket pool context working together with the candidate pool
+	tContext *TicketPoolContext
 }
 
-var cContext *CandidatePoolContext
-
-// Initialize the global candidate pool context object
+// Initialize a candidate pool context object
 func NewCandidatePoolContext(configs *params.PposConfig) *CandidatePoolContext {
-	cContext = &CandidatePoolContext{
+	return &CandidatePoolContext{
 		Configs: configs,
 	}
-	return cContext
 }
 
-func GetCandidateContextPtr() *CandidatePoolContext {
-	return cContext
+func (c *CandidatePoolContext) SetTicketPoolContext(tContext *TicketPoolContext) {
+	c.tContext = tContext
 }
 
-func (c *CandidatePoolContext) initCandidatePool() *CandidatePool {
-	return NewCandidatePool(c.Configs)
+// initCandidatePool builds a candidate pool with the configs in effect on state
+func (c *CandidatePoolContext) initCandidatePool(state vm.StateDB) *CandidatePool {
+	return NewCandidatePool(vm.GovernedPposConfig(state, c.Configs), c.tContext)
 }
 
 func (c *CandidatePoolContext) SetCandidate(state vm.StateDB, nodeId discover.NodeID, can *types.Candidate) error {
-	return c.initCandidatePool().SetCandidate(state, nodeId, can)
+	return c.initCandidatePool(state).SetCandidate(state, nodeId, can)
 }
 
 func (c *CandidatePoolContext) GetCandidate(state vm.StateDB, nodeId discover.NodeID, blockNumber *big.Int) *types.Candidate {
-	return c.initCandidatePool().GetCandidate(state, nodeId, blockNumber)
+	return c.initCandidatePool(state).GetCandidate(state, nodeId, blockNumber)
 }
 
 func (c *CandidatePoolContext) GetCandidateArr(state vm.StateDB, blockNumber *big.Int, nodeIds ...discover.NodeID) types.CandidateQueue {
-	return c.initCandidatePool().GetCandidateArr(state, blockNumber, nodeIds...)
+	return c.initCandidatePool(state).GetCandidateArr(state, blockNumber, nodeIds...)
 }
 
 func (c *CandidatePoolContext) GetWitnessCandidate(state vm.StateDB, nodeId discover.NodeID, flag int, blockNumber *big.Int) *types.Candidate {
-	return c.initCandidatePool().GetWitnessCandidate(state, nodeId, flag, blockNumber)
+	return c.initCandidatePool(state).GetWitnessCandidate(state, nodeId, flag, blockNumber)
 }
 
 func (c *CandidatePoolContext) WithdrawCandidate(state vm.StateDB, nodeId discover.NodeID, price, blockNumber *big.Int) error {
-	return c.initCandidatePool().WithdrawCandidate(state, nodeId, price, blockNumber)
+	return c.initCandidatePool(state).WithdrawCandidate(state, nodeId, price, blockNumber)
 }
 
 func (c *CandidatePoolContext) GetChosens(state vm.StateDB, flag int, blockNumber *big.Int) types.KindCanQueue {
-	return c.initCandidatePool().GetChosens(state, flag, blockNumber)
+	return c.initCandidatePool(state).GetChosens(state, flag, blockNumber)
 }
 
 func (c *CandidatePoolContext) GetCandidatePendArr (state vm.StateDB, flag int, blockNumber *big.Int) types.CandidateQueue {
-	return c.initCandidatePool().GetCandidatePendArr(state, flag, blockNumber)
+	return c.initCandidatePool(state).GetCandidatePendArr(state, flag, blockNumber)
 }
 
 func (c *CandidatePoolContext) GetChairpersons(state vm.StateDB, blockNumber *big.Int) types.CandidateQueue {
-	return c.initCandidatePool().GetChairpersons(state, blockNumber)
+	return c.initCandidatePool(state).GetChairpersons(state, blockNumber)
 }
 
 func (c *CandidatePoolContext) GetDefeat(state vm.StateDB, nodeId discover.NodeID, blockNumber *big.Int) types.RefundQueue {
-	return c.initCandidatePool().GetDefeat(state, nodeId, blockNumber)
+	return c.initCandidatePool(state).GetDefeat(state, nodeId, blockNumber)
 }
 
 func (c *CandidatePoolContext) IsDefeat(state vm.StateDB, nodeId discover.NodeID, blockNumber *big.Int) bool {
-	return c.initCandidatePool().IsDefeat(state, nodeId, blockNumber)
+	return c.initCandidatePool(state).IsDefeat(state, nodeId, blockNumber)
 }
 
 func (c *CandidatePoolContext) IsChosens(state vm.StateDB, nodeId discover.NodeID, blockNumber *big.Int) bool {
-	return c.initCandidatePool().IsChosens(state, nodeId, blockNumber)
+	return c.initCandidatePool(state).IsChosens(state, nodeId, blockNumber)
 }
 
 func (c *CandidatePoolContext) RefundBalance(state vm.StateDB, nodeId discover.NodeID, blockNumber *big.Int) error {
-	return c.initCandidatePool().RefundBalance(state, nodeId, blockNumber)
+	return c.initCandidatePool(state).RefundBalance(state, nodeId, blockNumber)
 }
 
 func (c *CandidatePoolContext) GetOwner(state vm.StateDB, nodeId discover.NodeID, blockNumber *big.Int) common.Address {
-	return c.initCandidatePool().GetOwner(state, nodeId, blockNumber)
+	return c.initCandidatePool(state).GetOwner(state, nodeId, blockNumber)
+}
+
+func (c *CandidatePoolContext) GetRefundInterval(state vm.StateDB, blockNumber *big.Int) uint32 {
+	return c.initCandidatePool(state).GetRefundInterval(blockNumber)
 }
 
-func (c *CandidatePoolContext) GetRefundInterval(blockNumber *big.Int) uint32 {
-	return c.initCandidatePool().GetRefundInterval(blockNumber)
+func (c *CandidatePoolContext) MaxCount(state vm.StateDB) uint32 {
+	return c.initCandidatePool(state).MaxCount()
 }
 
-func (c *CandidatePoolContext) MaxCount() uint32 {
-	return c.initCandidatePool().MaxCount()
+func (c *CandidatePoolContext) MaxChair(state vm.StateDB) uint32 {
+	return c.initCandidatePool(state).MaxChair()
 }
 
-func (c *CandidatePoolContext) MaxChair() uint32 {
-	return c.initCandidatePool().MaxChair()
+// PposConfig returns the ppos configs in effect at state.
+func (c *CandidatePoolContext) PposConfig(state vm.StateDB) *params.PposConfig {
+	return vm.GovernedPposConfig(state, c.Configs)
 }
 
 func (c *CandidatePoolContext) Election(state *state.StateDB, parentHash common.Hash, blocknumber *big.Int) ([]*discover.Node, error) {
-	return c.initCandidatePool().Election(state, parentHash, blocknumber)
+	return c.initCandidatePool(state).Election(state, parentHash, blocknumber)
 }
 
 func (c *CandidatePoolContext) Switch(state *state.StateDB, blockNumber *big.Int) bool {
-	return c.initCandidatePool().Switch(state, blockNumber)
+	return c.initCandidatePool(state).Switch(state, blockNumber)
 }
 
 func (c *CandidatePoolContext) GetWitness(state *state.StateDB, flag int, blockNumber *big.Int) ([]*discover.Node, error) {
-	return c.initCandidatePool().GetWitness(state, flag, blockNumber)
+	return c.initCandidatePool(state).GetWitness(state, flag, blockNumber)
 }
 
 func (c *CandidatePoolContext) GetAllWitness(state *state.StateDB, blockNumber *big.Int) ([]*discover.Node, []*discover.Node, []*discover.Node, error) {
-	return c.initCandidatePool().GetAllWitness(state, blockNumber)
+	return c.initCandidatePool(state).GetAllWitness(state, blockNumber)
 }
 
 func (c *CandidatePoolContext) SetCandidateExtra(state vm.StateDB, nodeId discover.NodeID, extra string) error {
-	return c.initCandidatePool().SetCandidateExtra(state, nodeId, extra)
+	return c.initCandidatePool(state).SetCandidateExtra(state, nodeId, extra)
+}
+
+func (c *CandidatePoolContext) IncreaseDeposit(state vm.StateDB, nodeId discover.NodeID, amount, blockNumber *big.Int) error {
+	return c.initCandidatePool(state).IncreaseDeposit(state, nodeId, amount, blockNumber)
+}
+
+func (c *CandidatePoolContext) UpdateCandidateInfo(state vm.StateDB, nodeId discover.NodeID, host, port string, fee uint32, blockNumber *big.Int) error {
+	return c.initCandidatePool(state).UpdateCandidateInfo(state, nodeId, host, port, fee, blockNumber)
+}
+
+func (c *CandidatePoolContext) GetPendingFee(state vm.StateDB, nodeId discover.NodeID) *types.CandidatePendingFee {
+	return c.initCandidatePool(state).GetPendingFee(state, nodeId)
+}
+
+func (c *CandidatePoolContext) MissSlots(state vm.StateDB, nodeId discover.NodeID, count uint32, blockNumber *big.Int) error {
+	return c.initCandidatePool(state).MissSlots(state, nodeId, count, blockNumber)
+}
+
+func (c *CandidatePoolContext) Unjail(state vm.StateDB, nodeId discover.NodeID, blockNumber *big.Int) error {
+	return c.initCandidatePool(state).Unjail(state, nodeId, blockNumber)
+}
+
+func (c *CandidatePoolContext) GetLiveness(state vm.StateDB, nodeId discover.NodeID) *types.CandidateLiveness {
+	return c.initCandidatePool(state).GetLiveness(state, nodeId)
+}
+
+func (c *CandidatePoolContext) Notify(state vm.StateDB, blockNumber *big.Int) error {
+	return c.initCandidatePool(state).Notify(state, blockNumber)
 }
 
 func (c *CandidatePoolContext) UpdateElectedQueue(state vm.StateDB, currBlockNumber *big.Int, nodeIds ...discover.NodeID) error {
-	return c.initCandidatePool().UpdateElectedQueue(state, currBlockNumber, nodeIds...)
+	return c.initCandidatePool(state).UpdateElectedQueue(state, currBlockNumber, nodeIds...)
 }
@@ -9,14 +9,17 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"math/big"
 	"net"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 const (
@@ -35,6 +38,8 @@ var (
 	WithdrawPriceErr            = errors.New("Withdraw Price err")
 	WithdrawLowErr              = errors.New("Withdraw Price too low")
 	RefundEmptyErr              = errors.New("Refund is empty")
+	CandidateNotJailedErr       = errors.New("Candidate is not jailed")
+	JailPeriodErr               = errors.New("Candidate is still in the jail period")
 )
 
 type candidateStorage map[discover.NodeID]*types.Candidate
@@ -53,6 +58,16 @@ type CandidatePool struct {
 	maxChair uint32
 	// allow block interval for refunds
 	refundBlockNumber uint32
+	// block interval before a fee change takes effect
+	feeChangeDelay uint32
+	// missed block slots in an election round before a witness is jailed
+	livenessThreshold uint32
+	// deposit forfeited by a jailed witness
+	livenessPenalty *big.Int
+	// blocks a jailed witness stays jailed
+	jailPeriod uint32
+	// sample the witnesses weighted by stake instead of taking the top maxChair
+	randomElection bool
 
 	// previous witness
 	preOriginCandidates candidateStorage
@@ -72,10 +87,13 @@ type CandidatePool struct {
 	//reserveCacheArr   types.CandidateQueue
 
 	storage *ppos_storage.Ppos_storage
+
+	// the ticket pool context the candidates are voted by
+	tContext *TicketPoolContext
 }
 
-// Initialize the global candidate pool object
-func NewCandidatePool(configs *params.PposConfig) *CandidatePool {
+// Initialize a candidate pool object which works with the given ticket pool context
+func NewCandidatePool(configs *params.PposConfig, tContext *TicketPoolContext) *CandidatePool {
 
 	log.Debug("Build a New CandidatePool Info ...")
 	if "" == strings.TrimSpace(configs.CandidateConfig.Threshold) {
@@ -87,6 +105,10 @@ func NewCandidatePool(configs *params.PposConfig) *CandidatePool {
 	} else {
 		threshold = thd
 	}
+	livenessPenalty, ok := new(big.Int).SetString(configs.CandidateConfig.LivenessPenalty, 10)
+	if !ok || livenessPenalty.Sign() < 0 {
+		livenessPenalty = big.NewInt(0)
+	}
 	return &CandidatePool{
 		threshold:            threshold,
 		depositLimit:         configs.CandidateConfig.DepositLimit,
@@ -94,6 +116,11 @@ func NewCandidatePool(configs *params.PposConfig) *CandidatePool {
 		maxCount:             configs.CandidateConfig.MaxCount,
 		maxChair:             configs.CandidateConfig.MaxChair,
 		refundBlockNumber:    configs.CandidateConfig.RefundBlockNumber,
+		feeChangeDelay:       configs.CandidateConfig.FeeChangeDelay,
+		livenessThreshold:    configs.CandidateConfig.LivenessThreshold,
+		livenessPenalty:      livenessPenalty,
+		jailPeriod:           configs.CandidateConfig.JailPeriod,
+		randomElection:       configs.CandidateConfig.RandomElection,
 		preOriginCandidates:  make(candidateStorage, 0),
 		originCandidates:     make(candidateStorage, 0),
 		nextOriginCandidates: make(candidateStorage, 0),
@@ -102,6 +129,7 @@ func NewCandidatePool(configs *params.PposConfig) *CandidatePool {
 		defeatCandidates:     make(refundStorage, 0),
 		//immediateCacheArr:    make(types.CandidateQueue, 0),
 		//reserveCacheArr:      make(types.CandidateQueue, 0),
+		tContext:             tContext,
 	}
 }
 
@@ -384,7 +412,7 @@ func (c *CandidatePool) SetCandidate(state vm.StateDB, nodeId discover.NodeID, c
 	nodeIds = c.setCandidateInfo(state, nodeId, can, can.BlockNumber, nil)
 	//go ticketPool.DropReturnTicket(state, nodeIds...)
 	if len(nodeIds) > 0 {
-		if err := tContext.DropReturnTicket(state, can.BlockNumber, nodeIds...); nil != err {
+		if err := c.tContext.DropReturnTicket(state, can.BlockNumber, nodeIds...); nil != err {
 			log.Error("Failed to DropReturnTicket on SetCandidate ...",  "current blockNumber", can.BlockNumber, "err", err)
 			//return err
 		}
@@ -398,7 +426,7 @@ func (c *CandidatePool) setCandidateInfo(state vm.StateDB, nodeId discover.NodeI
 
 	var allowed, delimmediate, delreserve bool
 	// check ticket count
-	if c.checkTicket(tContext.GetCandidateTicketCount(state, nodeId)) {
+	if c.checkTicket(c.tContext.GetCandidateTicketCount(state, nodeId)) {
 		allowed = true
 		if _, ok := c.reserveCandidates[can.CandidateId]; ok {
 			delreserve = true
@@ -443,7 +471,7 @@ func (c *CandidatePool) setCandidateInfo(state vm.StateDB, nodeId discover.NodeI
 		str := "Call setCandidateInfo to handleReserveFunc to sort the reserve queue ..."
 
 		// sort reserve array
-		makeCandidateSort(str, state, re_queueCopy)
+		c.makeCandidateSort(str, state, re_queueCopy)
 
 		nodeIds := make([]discover.NodeID, 0)
 
@@ -503,7 +531,7 @@ func (c *CandidatePool) setCandidateInfo(state vm.StateDB, nodeId discover.NodeI
 
 
 	// sort cache array
-	makeCandidateSort(str, state, cacheArr)
+	c.makeCandidateSort(str, state, cacheArr)
 
 	nodeIds := make([]discover.NodeID, 0)
 
@@ -572,7 +600,7 @@ func (c *CandidatePool) electionUpdateCanById(state vm.StateDB, currentBlockNumb
 
 	for _, nodeId := range nodeIds {
 		// check ticket count
-		if c.checkTicket(tContext.GetCandidateTicketCount(state, nodeId)) {
+		if c.checkTicket(c.tContext.GetCandidateTicketCount(state, nodeId)) {
 			if can, ok := c.reserveCandidates[nodeId]; ok {
 				re_del_temp[nodeId] = can
 			}
@@ -640,7 +668,7 @@ func (c *CandidatePool) electionUpdateCanById(state vm.StateDB, currentBlockNumb
 		str := "Call Election to handleReserveFunc to sort the reserve queue ..."
 
 		// sort reserve array
-		makeCandidateSort(str, state, re_queueCopy)
+		c.makeCandidateSort(str, state, re_queueCopy)
 
 		nodeIds := make([]discover.NodeID, 0)
 
@@ -678,7 +706,7 @@ func (c *CandidatePool) electionUpdateCanById(state vm.StateDB, currentBlockNumb
 	str := "Call Election to start sort immediate queue ..."
 
 	// sort immediate array
-	makeCandidateSort(str, state, im_queue)
+	c.makeCandidateSort(str, state, im_queue)
 
 	nodeIdArr := make([]discover.NodeID, 0)
 
@@ -746,7 +774,7 @@ func (c *CandidatePool) WithdrawCandidate(state vm.StateDB, nodeId discover.Node
 	}
 	//go ticketPool.DropReturnTicket(state, nodeIds...)
 	if len(nodeIds) > 0 {
-		if err := tContext.DropReturnTicket(state, blockNumber, nodeIds...); nil != err {
+		if err := c.tContext.DropReturnTicket(state, blockNumber, nodeIds...); nil != err {
 			log.Error("Failed to DropReturnTicket on WithdrawCandidate ...", "blockNumber", blockNumber.String(), "err", err)
 		}
 	}
@@ -1134,35 +1162,257 @@ func (c *CandidatePool) SetCandidateExtra(state vm.StateDB, nodeId discover.Node
 
 	log.Info("Call SetCandidateExtra:", "nodeId", nodeId.String(), "extra", extra)
 
+	if err := c.updateCandidate(state, nodeId, func(can *types.Candidate) {
+		can.Extra = extra
+	}, ppos_storage.IMMEDIATE, ppos_storage.RESERVE); nil != err {
+		return err
+	}
+	log.Debug("Call SetCandidateExtra SUCCESS !!!!!! ")
+	return nil
+}
+
+// Increase the deposit of an elected candidate, it keeps the BlockNumber and TxIndex
+// of its first pledge and the queues are re-sorted by updateQueue
+func (c *CandidatePool) IncreaseDeposit(state vm.StateDB, nodeId discover.NodeID, amount, blockNumber *big.Int) error {
+
+	log.Info("Call IncreaseDeposit:", "blockNumber", blockNumber.String(), "nodeId", nodeId.String(), "amount", amount.String())
+
+	if err := c.updateCandidate(state, nodeId, func(can *types.Candidate) {
+		can.Deposit = new(big.Int).Add(can.Deposit, amount)
+	}, ppos_storage.IMMEDIATE, ppos_storage.RESERVE); nil != err {
+		return err
+	}
+	log.Debug("Call IncreaseDeposit SUCCESS !!!!!! ")
+	return c.UpdateElectedQueue(state, blockNumber, nodeId)
+}
+
+// Update the host and port of an elected candidate at once, a fee change is kept
+// pending and applied by Notify after feeChangeDelay blocks, which protects the delegators
+func (c *CandidatePool) UpdateCandidateInfo(state vm.StateDB, nodeId discover.NodeID, host, port string, fee uint32, blockNumber *big.Int) error {
+
+	log.Info("Call UpdateCandidateInfo:", "blockNumber", blockNumber.String(), "nodeId", nodeId.String(), "host", host, "port", port, "fee", fee)
+
+	var currentFee uint32
+	if err := c.updateCandidate(state, nodeId, func(can *types.Candidate) {
+		can.Host = host
+		can.Port = port
+		currentFee = can.Fee
+	}, ppos_storage.IMMEDIATE, ppos_storage.RESERVE); nil != err {
+		return err
+	}
+
+	// the fee is set back before the change took effect, drop the pending one
+	if fee == currentFee {
+		return c.delPendingFee(state, nodeId)
+	}
+	pending := &types.CandidatePendingFee{
+		Fee:         fee,
+		BlockNumber: new(big.Int).Add(blockNumber, big.NewInt(int64(c.feeChangeDelay))),
+	}
+	log.Debug("Call UpdateCandidateInfo, the fee change is pending", "nodeId", nodeId.String(), "fee", fee, "effective blockNumber", pending.BlockNumber.String())
+	return c.setPendingFee(state, nodeId, pending)
+}
+
+// Get the fee change of the candidate waiting to take effect, nil if there is none
+func (c *CandidatePool) GetPendingFee(state vm.StateDB, nodeId discover.NodeID) *types.CandidatePendingFee {
+	pending, err := getPendingFeeByState(state, nodeId)
+	if nil != err {
+		log.Error("Failed to GetPendingFee", "nodeId", nodeId.String(), "err", err)
+		return nil
+	}
+	return pending
+}
+
+// Apply the pending fee changes whose effective block has been reached,
+// it is called once per block
+func (c *CandidatePool) Notify(state vm.StateDB, blockNumber *big.Int) error {
+	ids, err := getPendingFeeIdsByState(state)
+	if nil != err {
+		log.Error("Failed to decode pending fee ids on Notify", "blockNumber", blockNumber.String(), "err", err)
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	remain := make([]discover.NodeID, 0, len(ids))
+	for _, nodeId := range ids {
+		pending, err := getPendingFeeByState(state, nodeId)
+		if nil != err {
+			log.Error("Failed to decode pending fee on Notify", "blockNumber", blockNumber.String(), "nodeId", nodeId.String(), "err", err)
+			return err
+		}
+		if nil == pending {
+			continue
+		}
+		if pending.BlockNumber.Cmp(blockNumber) > 0 {
+			remain = append(remain, nodeId)
+			continue
+		}
+		// the witnesses carry their own copy of the candidate, the fee is used for the block rewards
+		if err := c.updateCandidate(state, nodeId, func(can *types.Candidate) {
+			can.Fee = pending.Fee
+		}, ppos_storage.IMMEDIATE, ppos_storage.RESERVE, ppos_storage.PREVIOUS, ppos_storage.CURRENT, ppos_storage.NEXT); nil != err {
+			log.Warn("The candidate of the pending fee is gone on Notify", "blockNumber", blockNumber.String(), "nodeId", nodeId.String())
+		} else {
+			log.Info("Apply the pending fee on Notify", "blockNumber", blockNumber.String(), "nodeId", nodeId.String(), "fee", pending.Fee)
+		}
+		setPendingFeeState(state, nodeId, []byte{})
+	}
+	return setPendingFeeIds(state, remain)
+}
+
+// Count the block slots the witness missed in the current election round, the witness is jailed
+// once livenessThreshold is reached: it is left out of the elections and forfeits the liveness
+// penalty of its deposit to the reward pool
+func (c *CandidatePool) MissSlots(state vm.StateDB, nodeId discover.NodeID, count uint32, blockNumber *big.Int) error {
+	if c.livenessThreshold == 0 || count == 0 {
+		return nil
+	}
+	if config := c.tContext.ChainConfig(); nil != config && !config.IsLiveness(blockNumber) {
+		return nil
+	}
+	liveness := state.GetPPOSCache().GetLiveness(nodeId)
+	if nil == liveness {
+		liveness = &types.CandidateLiveness{JailedBlock: big.NewInt(0), Penalty: big.NewInt(0)}
+	}
+	liveness.MissedSlots += count
+	log.Debug("Call MissSlots", "blockNumber", blockNumber.String(), "nodeId", nodeId.String(), "count", count, "missedSlots", liveness.MissedSlots)
+	if liveness.Jailed || liveness.MissedSlots < c.livenessThreshold {
+		state.GetPPOSCache().SetLiveness(nodeId, liveness)
+		return nil
+	}
+
+	liveness.Jailed = true
+	liveness.JailedBlock = new(big.Int).Set(blockNumber)
+	liveness.Penalty = big.NewInt(0)
+	if err := c.updateCandidate(state, nodeId, func(can *types.Candidate) {
+		penalty := new(big.Int).Set(c.livenessPenalty)
+		if penalty.Cmp(can.Deposit) > 0 {
+			penalty.Set(can.Deposit)
+		}
+		can.Deposit = new(big.Int).Sub(can.Deposit, penalty)
+		liveness.Penalty = penalty
+	}, ppos_storage.IMMEDIATE, ppos_storage.RESERVE); nil != err {
+		log.Warn("The jailed witness is not elected any more on MissSlots", "blockNumber", blockNumber.String(), "nodeId", nodeId.String())
+	}
+	if liveness.Penalty.Sign() > 0 {
+		state.SubBalance(common.CandidatePoolAddr, liveness.Penalty)
+		state.AddBalance(common.RewardPoolAddr, liveness.Penalty)
+	}
+	log.Info("Jail the witness on MissSlots", "blockNumber", blockNumber.String(), "nodeId", nodeId.String(), "missedSlots", liveness.MissedSlots, "penalty", liveness.Penalty.String())
+	vm.AddPposSystemLog(c.tContext.ChainConfig(), state, common.CandidatePoolAddr, blockNumber, vm.JailEvent, nodeId, liveness.MissedSlots, liveness.Penalty)
+	state.GetPPOSCache().SetLiveness(nodeId, liveness)
+	if liveness.Penalty.Sign() > 0 {
+		return c.UpdateElectedQueue(state, blockNumber, nodeId)
+	}
+	return nil
+}
+
+// Release a jailed candidate once jailPeriod blocks passed, it takes part in the next election again
+func (c *CandidatePool) Unjail(state vm.StateDB, nodeId discover.NodeID, blockNumber *big.Int) error {
+	log.Info("Call Unjail:", "blockNumber", blockNumber.String(), "nodeId", nodeId.String())
+	liveness := state.GetPPOSCache().GetLiveness(nodeId)
+	if nil == liveness || !liveness.Jailed {
+		return CandidateNotJailedErr
+	}
+	if blockNumber.Cmp(new(big.Int).Add(liveness.JailedBlock, new(big.Int).SetUint64(uint64(c.jailPeriod)))) < 0 {
+		log.Error("Failed to Unjail", "blockNumber", blockNumber.String(), "nodeId", nodeId.String(), "jailedBlock", liveness.JailedBlock.String(), "jailPeriod", c.jailPeriod)
+		return JailPeriodErr
+	}
+	log.Debug("Call Unjail SUCCESS !!!!!! ")
+	state.GetPPOSCache().DelLiveness(nodeId)
+	return nil
+}
+
+// Get the liveness record of the candidate, nil if it missed no slot in the current round
+func (c *CandidatePool) GetLiveness(state vm.StateDB, nodeId discover.NodeID) *types.CandidateLiveness {
+	return state.GetPPOSCache().GetLiveness(nodeId)
+}
+
+func (c *CandidatePool) isJailed(state vm.StateDB, nodeId discover.NodeID) bool {
+	liveness := state.GetPPOSCache().GetLiveness(nodeId)
+	return nil != liveness && liveness.Jailed
+}
+
+// resetLiveness starts counting the missed slots of a new election round,
+// only the records of the jailed candidates are kept
+func (c *CandidatePool) resetLiveness(state vm.StateDB) error {
+	for _, nodeId := range state.GetPPOSCache().GetLivenessIds() {
+		if liveness := state.GetPPOSCache().GetLiveness(nodeId); nil != liveness && !liveness.Jailed {
+			state.GetPPOSCache().DelLiveness(nodeId)
+		}
+	}
+	return nil
+}
+
+// updateCandidate applies update to a copy of the candidate in each of the given queues,
+// CandidateEmptyErr is returned if the candidate is in none of them
+func (c *CandidatePool) updateCandidate(state vm.StateDB, nodeId discover.NodeID, update func(can *types.Candidate), flags ...int) error {
 	c.initDataByState(state)
-	im_queue := c.getCandidateQueue(ppos_storage.IMMEDIATE)
 
-	for i, can := range im_queue {
-		if can.CandidateId == nodeId {
-			can.Extra = extra
-			im_queue[i] = can
-			c.setCandidateQueue(im_queue, ppos_storage.IMMEDIATE)
-			log.Debug("Call SetCandidateExtra SUCCESS !!!!!! ")
-			return nil
+	var exist bool
+	for _, flag := range flags {
+		queue := c.getCandidateQueue(flag)
+		for i, can := range queue {
+			if can.CandidateId == nodeId {
+				canCopy := types.CandidateQueue{can}.DeepCopy()[0]
+				update(canCopy)
+				queue[i] = canCopy
+				c.setCandidateQueue(queue, flag)
+				exist = true
+				break
+			}
 		}
 	}
+	if !exist {
+		return CandidateEmptyErr
+	}
+	return nil
+}
 
-	re_queue := c.getCandidateQueue(ppos_storage.RESERVE)
+func (c *CandidatePool) setPendingFee(state vm.StateDB, nodeId discover.NodeID, pending *types.CandidatePendingFee) error {
+	val, err := rlp.EncodeToBytes(pending)
+	if nil != err {
+		return err
+	}
+	ids, err := getPendingFeeIdsByState(state)
+	if nil != err {
+		return err
+	}
+	exist := false
+	for _, id := range ids {
+		if id == nodeId {
+			exist = true
+			break
+		}
+	}
+	if !exist {
+		if err := setPendingFeeIds(state, append(ids, nodeId)); nil != err {
+			return err
+		}
+	}
+	setPendingFeeState(state, nodeId, val)
+	return nil
+}
 
-	for i, can := range re_queue {
-		if can.CandidateId == nodeId {
-			can.Extra = extra
-			re_queue[i] = can
-			c.setCandidateQueue(re_queue, ppos_storage.RESERVE)
-			log.Debug("Call SetCandidateExtra SUCCESS !!!!!! ")
-			return nil
+func (c *CandidatePool) delPendingFee(state vm.StateDB, nodeId discover.NodeID) error {
+	ids, err := getPendingFeeIdsByState(state)
+	if nil != err {
+		return err
+	}
+	for i, id := range ids {
+		if id == nodeId {
+			ids = append(ids[:i], ids[i+1:]...)
+			setPendingFeeState(state, nodeId, []byte{})
+			return setPendingFeeIds(state, ids)
 		}
 	}
-	return CandidateEmptyErr
+	return nil
 }
 
 // Announce witness
 func (c *CandidatePool) Election(state *state.StateDB, parentHash common.Hash, currBlockNumber *big.Int) ([]*discover.Node, error) {
+	defer electionTimer.UpdateSince(time.Now())
 	log.Info("Call Election start ...", "current blockNumber", currBlockNumber.String(), "threshold", c.threshold.String(), "depositLimit", c.depositLimit, "allowed", c.allowed, "maxCount", c.maxCount, "maxChair", c.maxChair, "refundBlockNumber", c.refundBlockNumber)
 	c.initData2Cache(state, GET_IM_RE)
 
@@ -1182,7 +1432,7 @@ func (c *CandidatePool) Election(state *state.StateDB, parentHash common.Hash, c
 	for _, can := range nextQueue {
 		// Release lucky ticket TODO
 		if (common.Hash{}) != can.TxHash {
-			if err := tContext.ReturnTicket(state, can.CandidateId, can.TxHash, currBlockNumber); nil != err {
+			if err := c.tContext.ReturnTicket(state, can.CandidateId, can.TxHash, currBlockNumber); nil != err {
 				log.Error("Failed to ReturnTicket on Election", "current blockNumber", currBlockNumber.String(), "nodeId", can.CandidateId.String(), "ticketId", can.TxHash.String(), "err", err)
 				continue
 			}
@@ -1223,7 +1473,7 @@ func (c *CandidatePool) Election(state *state.StateDB, parentHash common.Hash, c
 	// Release the lost list
 	//go ticketPool.DropReturnTicket(state, nodeIds...)
 	if len(nodeIds) > 0 {
-		if err := tContext.DropReturnTicket(state, currBlockNumber, nodeIds...); nil != err {
+		if err := c.tContext.DropReturnTicket(state, currBlockNumber, nodeIds...); nil != err {
 			log.Error("Failed to DropReturnTicket on Election ...", "current blockNumber", currBlockNumber.String(), "err", err)
 		}
 	}
@@ -1241,14 +1491,18 @@ func (c *CandidatePool) election(state *state.StateDB, parentHash common.Hash, b
 
 	str := "When Election, to election to sort immediate queue ..."
 	// sort immediate candidates
-	makeCandidateSort(str, state, imm_queue)
+	c.makeCandidateSort(str, state, imm_queue)
 
 	log.Info("When Election, Sorted the immediate array length:", "current blockNumber", blockNumber.String(), "len", len(imm_queue))
 	PrintObject("When Election, Sorted the immediate array: current blockNumber:" + blockNumber.String() + ":", imm_queue)
-	// cache ids
-	immediateIds := make([]discover.NodeID, len(imm_queue))
-	for i, can := range imm_queue {
-		immediateIds[i] = can.CandidateId
+	// cache ids, the jailed candidates are left out
+	immediateIds := make([]discover.NodeID, 0, len(imm_queue))
+	for _, can := range imm_queue {
+		if c.isJailed(state, can.CandidateId) {
+			log.Info("When Election, skip the jailed candidate", "current blockNumber", blockNumber.String(), "nodeId", can.CandidateId.String())
+			continue
+		}
+		immediateIds = append(immediateIds, can.CandidateId)
 	}
 	PrintObject("When Election, current immediate is: current blockNumber:" + blockNumber.String() + " ,len:=" + fmt.Sprint(len(immediateIds)) + " ,arr is:", immediateIds)
 
@@ -1259,6 +1513,19 @@ func (c *CandidatePool) election(state *state.StateDB, parentHash common.Hash, b
 		nextIdArr = make([]discover.NodeID, len(immediateIds))
 		copy(nextIdArr, immediateIds)
 
+	} else if c.randomElection {
+		// sample the witnesses from the top candidates weighted by their stake
+		if len(immediateIds) > int(c.maxCount) {
+			immediateIds = immediateIds[:c.maxCount]
+		}
+		switchHash, err := c.switchBlockHash(parentHash, blockNumber)
+		if nil != err {
+			log.Error("Failed to find the switch block on Election", "current blockNumber", blockNumber.String(), "err", err)
+			return nil, nil, false, err
+		}
+		seed := electionSeed(switchHash, c.getCandidateQueue(ppos_storage.CURRENT))
+		nextIdArr = sampleCandidates(immediateIds, c.candidateWeights(state, imm_queue), int(c.maxChair), seed)
+		log.Info("When Election, sampled the witnesses", "current blockNumber", blockNumber.String(), "seed", seed.Hex())
 	} else {
 		// If the number of candidate selected exceeds the number of witnesses, the top N is extracted.
 		nextIdArr = make([]discover.NodeID, c.maxChair)
@@ -1292,7 +1559,7 @@ func (c *CandidatePool) election(state *state.StateDB, parentHash common.Hash, b
 	// handle all next witness information
 	for i, can := range nextQueue {
 		// After election to call Selected LuckyTicket TODO
-		luckyId, err := tContext.SelectionLuckyTicket(state, can.CandidateId, parentHash)
+		luckyId, err := c.tContext.SelectionLuckyTicket(state, can.CandidateId, parentHash)
 		if nil != err {
 			log.Error("Failed to take luckyId on Election", "current blockNumber", blockNumber.String(), "nodeId", can.CandidateId.String(), "err", err)
 			return nil, nil, false, errors.New(err.Error() + ", nodeId: " + can.CandidateId.String())
@@ -1303,7 +1570,7 @@ func (c *CandidatePool) election(state *state.StateDB, parentHash common.Hash, b
 			if luckyId == (common.Hash{}) {
 				can.TOwner = common.Address{}
 			} else {
-				if tick := tContext.GetTicket(state, luckyId); nil != tick {
+				if tick := c.tContext.GetTicket(state, luckyId); nil != tick {
 					can.TOwner = tick.Owner
 				}else {
 					can.TOwner = common.Address{}
@@ -1325,6 +1592,9 @@ func (c *CandidatePool) election(state *state.StateDB, parentHash common.Hash, b
 
 	// set next witness
 	c.setCandidateQueue(nextQueue, ppos_storage.NEXT)
+	for _, can := range nextQueue {
+		vm.AddPposSystemLog(c.tContext.ChainConfig(), state, common.CandidatePoolAddr, blockNumber, vm.WitnessElectedEvent, can.CandidateId, can.Owner, can.TxHash, can.Deposit)
+	}
 
 	log.Info("When Election,next round witness node count is:", "current blockNumber", blockNumber.String(), "len", len(nodeArr))
 	PrintObject("When Election,next round witness node information is: current blockNumber:" + blockNumber.String() + ":", nodeArr)
@@ -1426,6 +1696,12 @@ func (c *CandidatePool) Switch(state *state.StateDB, blockNumber *big.Int) bool
 	// set next witness
 	c.delCandidateQueue(ppos_storage.NEXT)
 
+	// the missed slots are counted per round
+	if err := c.resetLiveness(state); nil != err {
+		log.Error("Failed to reset liveness on Switch", "blockNumber", blockNumber.String(), "err", err)
+		return false
+	}
+
 	log.Info("Call Switch SUCCESS !!!!!!!")
 	return true
 }
@@ -1601,7 +1877,7 @@ func (c *CandidatePool) UpdateElectedQueue(state vm.StateDB, currBlockNumber *bi
 	log.Info("Call UpdateElectedQueue SUCCESS !!!!!!!!! ")
 	//go ticketPool.DropReturnTicket(state, ids...)
 	if len(arr) > 0 {
-		return tContext.DropReturnTicket(state, currBlockNumber, arr...)
+		return c.tContext.DropReturnTicket(state, currBlockNumber, arr...)
 	}
 	return nil
 }
@@ -1658,7 +1934,7 @@ func (c *CandidatePool) updateQueue(state vm.StateDB, currentBlockNumber *big.In
 		PrintObject("Call UpdateElectedQueue, handleReserveFunc, Before update the reserve len is " + fmt.Sprint(len(queueCopy)) + ", config.maxCount:" + fmt.Sprint(c.maxCount) + " , the queue is", queueCopy)
 
 		str := "Call UpdateElectedQueue, handleReserveFunc, o sort reserve queue ..."
-		makeCandidateSort(str, state, queueCopy)
+		c.makeCandidateSort(str, state, queueCopy)
 		if len(queueCopy) > int(c.maxCount) {
 			// Intercepting the lost candidates to tmpArr
 			tmpArr := (queueCopy)[c.maxCount:]
@@ -1745,7 +2021,7 @@ func (c *CandidatePool) updateQueue(state vm.StateDB, currentBlockNumber *big.In
 			str := "Call UpdateElectedQueue, workFunc to sort immediate queue ..."
 			// input immediate
 			new_queue = append(new_queue, can)
-			makeCandidateSort(str, state, new_queue)
+			c.makeCandidateSort(str, state, new_queue)
 
 			var inRes bool
 			if len(new_queue) > int(c.maxCount) {
@@ -1787,7 +2063,7 @@ func (c *CandidatePool) updateQueue(state vm.StateDB, currentBlockNumber *big.In
 	*/
 	for _, nodeId := range nodeIds {
 
-		tcount := c.checkTicket(tContext.GetCandidateTicketCount(state, nodeId))
+		tcount := c.checkTicket(c.tContext.GetCandidateTicketCount(state, nodeId))
 
 		switch c.checkExist(nodeId) {
 
@@ -1801,6 +2077,12 @@ func (c *CandidatePool) updateQueue(state vm.StateDB, currentBlockNumber *big.In
 				if ids := workFunc(ppos_storage.IMMEDIATE, ppos_storage.RESERVE, can); len(ids) != 0 {
 					resNodeIds = append(resNodeIds, ids...)
 				}
+			} else {
+
+				// the node stays in immediate, its deposit or tickets may have changed
+				im_queue := c.getCandidateQueue(ppos_storage.IMMEDIATE)
+				c.makeCandidateSort("Call UpdateElectedQueue, to sort immediate queue ...", state, im_queue)
+				c.setCandidateQueue(im_queue, ppos_storage.IMMEDIATE)
 			}
 
 		case IS_RESERVE:
@@ -1813,6 +2095,12 @@ func (c *CandidatePool) updateQueue(state vm.StateDB, currentBlockNumber *big.In
 				if ids := workFunc(ppos_storage.RESERVE, ppos_storage.IMMEDIATE, can); len(ids) != 0 {
 					resNodeIds = append(resNodeIds, ids...)
 				}
+			} else {
+
+				// the node stays in reserve, its deposit or tickets may have changed
+				if ids := handleReserveFunc(c.getCandidateQueue(ppos_storage.RESERVE)); len(ids) != 0 {
+					resNodeIds = append(resNodeIds, ids...)
+				}
 			}
 
 		default:
@@ -1851,7 +2139,7 @@ func (c *CandidatePool) checkFirstThreshold(can *types.Candidate) bool {
 //
 //
 //	// Number of vote ticket by nodes
-//	tcount := c.checkTicket(tContext.GetCandidateTicketCount(state, can.CandidateId))
+//	tcount := c.checkTicket(c.tContext.GetCandidateTicketCount(state, can.CandidateId))
 //
 //	// if self have already exist:
 //	// b、no first pledge: x >= self * 110%
@@ -2079,7 +2367,7 @@ func (c *CandidatePool) promoteReserveQueue(state vm.StateDB, currentBlockNumber
 
 
 	// current ticket price
-	ticket_price := tContext.GetTicketPrice(state)
+	ticket_price := c.tContext.GetTicketPrice(state)
 
 
 	//for i, can := range re_queue {
@@ -2090,7 +2378,7 @@ func (c *CandidatePool) promoteReserveQueue(state vm.StateDB, currentBlockNumber
 
 		log.Debug("Call promoteReserveQueue for range , Current reserve id", "blockNumber", currentBlockNumber.String(), "nodeId", can.CandidateId.String())
 
-		re_tCount := tContext.GetCandidateTicketCount(state, can.CandidateId)
+		re_tCount := c.tContext.GetCandidateTicketCount(state, can.CandidateId)
 		if re_tCount < c.allowed {
 			continue
 		}
@@ -2102,7 +2390,7 @@ func (c *CandidatePool) promoteReserveQueue(state vm.StateDB, currentBlockNumber
 
 			// last im can
 			last := im_queue[len(im_queue)-1]
-			last_tCount := tContext.GetCandidateTicketCount(state, last.CandidateId)
+			last_tCount := c.tContext.GetCandidateTicketCount(state, last.CandidateId)
 
 			last_tmoney := new(big.Int).Mul(big.NewInt(int64(last_tCount)), ticket_price)
 			last_money := new(big.Int).Add(last.Deposit, last_tmoney)
@@ -2134,7 +2422,7 @@ func (c *CandidatePool) promoteReserveQueue(state vm.StateDB, currentBlockNumber
 
 	str := "Call promoteReserveQueue to sort the new immediate queue ..."
 	// sort immediate
-	makeCandidateSort(str, state, im_queue)
+	c.makeCandidateSort(str, state, im_queue)
 
 	//nodeIds := make([]discover.NodeID, 0)
 
@@ -2169,7 +2457,7 @@ func (c *CandidatePool) promoteReserveQueue(state vm.StateDB, currentBlockNumber
 		re_queue = append(re_queue, addRe_queue...)
 
 		str := "Call promoteReserveQueue to sort the new reserve queue ..."
-		makeCandidateSort(str, state, re_queue)
+		c.makeCandidateSort(str, state, re_queue)
 
 	}
 
@@ -2215,6 +2503,55 @@ func (c *CandidatePool) delRefunds(nodeId discover.NodeID) {
 	c.storage.DelRefunds(nodeId)
 }
 
+func getPendingFeeIdsByState(state vm.StateDB) ([]discover.NodeID, error) {
+	var pendingIds []discover.NodeID
+	if valByte := state.GetState(common.CandidatePoolAddr, PendingFeeListKey()); len(valByte) != 0 {
+		if err := rlp.DecodeBytes(valByte, &pendingIds); nil != err {
+			return nil, err
+		}
+	} else {
+		return nil, nil
+	}
+	return pendingIds, nil
+}
+
+func setPendingFeeIds(state vm.StateDB, ids []discover.NodeID) error {
+	if len(ids) == 0 {
+		state.SetState(common.CandidatePoolAddr, PendingFeeListKey(), []byte{})
+		return nil
+	}
+	val, err := rlp.EncodeToBytes(ids)
+	if nil != err {
+		return err
+	}
+	state.SetState(common.CandidatePoolAddr, PendingFeeListKey(), val)
+	return nil
+}
+
+func getPendingFeeByState(state vm.StateDB, id discover.NodeID) (*types.CandidatePendingFee, error) {
+	var pending types.CandidatePendingFee
+	if valByte := state.GetState(common.CandidatePoolAddr, PendingFeeKey(id)); len(valByte) != 0 {
+		if err := rlp.DecodeBytes(valByte, &pending); nil != err {
+			return nil, err
+		}
+	} else {
+		return nil, nil
+	}
+	return &pending, nil
+}
+
+func setPendingFeeState(state vm.StateDB, id discover.NodeID, val []byte) {
+	state.SetState(common.CandidatePoolAddr, PendingFeeKey(id), val)
+}
+
+func PendingFeeKey(nodeId discover.NodeID) []byte {
+	return append(append(common.CandidatePoolAddr.Bytes(), PendingFeeBytePrefix...), nodeId.Bytes()...)
+}
+
+func PendingFeeListKey() []byte {
+	return append(common.CandidatePoolAddr.Bytes(), PendingFeeListBytePrefix...)
+}
+
 /*func getPreviousWitnessIdsState(state vm.StateDB) ([]discover.NodeID, error) {
 	var witnessIds []discover.NodeID
 	if valByte := state.GetState(common.CandidatePoolAddr, PreviousWitnessListKey()); len(valByte) != 0 {
@@ -2429,6 +2766,10 @@ func buildWitnessNode(can *types.Candidate) (*discover.Node, error) {
 	if nil == can {
 		return nil, CandidateEmptyErr
 	}
+	// A candidate behind sentries may hide its endpoint, it is reached through them
+	if can.Host == "" && can.Port == "" {
+		return discover.NewNode(can.CandidateId, nil, 0, 0), nil
+	}
 	ip := net.ParseIP(can.Host)
 	// uint16
 	var port uint16
@@ -2440,21 +2781,104 @@ func buildWitnessNode(can *types.Candidate) (*discover.Node, error) {
 	return discover.NewNode(can.CandidateId, ip, port, port), nil
 }
 
-func makeCandidateSort(logStr string, state vm.StateDB, arr types.CandidateQueue) {
+func (c *CandidatePool) makeCandidateSort(logStr string, state vm.StateDB, arr types.CandidateQueue) {
 
 	log.Debug(logStr)
 
+	arr.CandidateSort(c.candidateWeights(state, arr))
+}
+
+// candidateWeights returns the stake of each candidate of arr, its deposit plus
+// the value of its tickets at the current price.
+func (c *CandidatePool) candidateWeights(state vm.StateDB, arr types.CandidateQueue) types.CanConditions {
 	cand := make(types.CanConditions, 0)
 	for _, can := range arr {
-		tCount := tContext.GetCandidateTicketCount(state, can.CandidateId)
-		price := tContext.GetTicketPrice(state)
+		tCount := c.tContext.GetCandidateTicketCount(state, can.CandidateId)
+		price := c.tContext.GetTicketPrice(state)
 		tprice := new(big.Int).Mul(big.NewInt(int64(tCount)), price)
 
 		money := new(big.Int).Add(can.Deposit, tprice)
 
 		cand[can.CandidateId] = money
 	}
-	arr.CandidateSort(cand)
+	return cand
+}
+
+// switchBlockHash returns the hash of the switch block which opened the round of
+// the block number, it's an ancestor of the parent block.
+func (c *CandidatePool) switchBlockHash(parentHash common.Hash, blockNumber *big.Int) (common.Hash, error) {
+	number := blockNumber.Uint64() - 1
+	target := number - number%common.BaseSwitchWitness
+	hash := parentHash
+	for ; number > target; number-- {
+		header := c.tContext.GetHeader(hash, number)
+		if nil == header {
+			return common.Hash{}, fmt.Errorf("missing header %d %x", number, hash)
+		}
+		hash = header.ParentHash
+	}
+	return hash, nil
+}
+
+// electionSeed returns the seed of a randomized election, it is derived from the
+// switch block of the current round and its witnesses so every node gets the same
+// seed. The switch block is sealed a whole election window before the election,
+// the producers of the blocks close to the election can't grind the seed.
+func electionSeed(switchHash common.Hash, current types.CandidateQueue) common.Hash {
+	data := make([][]byte, 0, len(current)+1)
+	data = append(data, switchHash.Bytes())
+	for _, can := range current {
+		data = append(data, can.CandidateId.Bytes())
+	}
+	return crypto.Keccak256Hash(data...)
+}
+
+// sampleCandidates picks count of ids without replacement, the chance of an id is
+// proportional to its weight. The draws are derived from seed, so the result is
+// the same on every node, and the picked ids keep their order in ids. If the ids
+// have no weight at all the first count ids are picked.
+func sampleCandidates(ids []discover.NodeID, weights types.CanConditions, count int, seed common.Hash) []discover.NodeID {
+	if len(ids) <= count {
+		return append([]discover.NodeID{}, ids...)
+	}
+	remaining := append([]discover.NodeID{}, ids...)
+	picked := make(map[discover.NodeID]bool, count)
+	for draw := 0; draw < count; draw++ {
+		total := new(big.Int)
+		for _, id := range remaining {
+			if w, ok := weights[id]; ok && w.Sign() > 0 {
+				total.Add(total, w)
+			}
+		}
+		if total.Sign() == 0 {
+			// nothing left to weight, fill up in order
+			for _, id := range remaining[:count-draw] {
+				picked[id] = true
+			}
+			break
+		}
+		r := new(big.Int).SetBytes(crypto.Keccak256(seed.Bytes(), big.NewInt(int64(draw)).Bytes()))
+		r.Mod(r, total)
+		for i, id := range remaining {
+			w, ok := weights[id]
+			if !ok || w.Sign() <= 0 {
+				continue
+			}
+			if r.Cmp(w) < 0 {
+				picked[id] = true
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				break
+			}
+			r.Sub(r, w)
+		}
+	}
+	result := make([]discover.NodeID, 0, count)
+	for _, id := range ids {
+		if picked[id] {
+			result = append(result, id)
+		}
+	}
+	return result
 }
 
 /*
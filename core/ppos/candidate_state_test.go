@@ -13,7 +13,7 @@ package pposm_test
 //
 //
 //	// Initialize a fresh chain with only a genesis block
-//	blockchain, _ := core.NewBlockChain(db, nil, params.AllEthashProtocolChanges, nil, vm.Config{}, nil)
+//	blockchain, _ := core.NewBlockChain(db, nil, nil, params.AllEthashProtocolChanges, nil, vm.Config{}, nil)
 //
 //	var state *state.StateDB
 //	if statedb, err := blockchain.State(); nil != err {
@@ -22,6 +22,9 @@ const (
 	// need refund
 	DefeatPrefix     = "df"
 	DefeatListPrefix = "dL"
+	// pending fee changes
+	PendingFeePrefix     = "pf"
+	PendingFeeListPrefix = "pL"
 
 	/** about ticket pool */
 	// Remaining number key
@@ -32,6 +35,9 @@ const (
 	CandidateAttach	= "ca"
 	// Ticket pool hash
 	TicketPoolHash	= "tph"
+	// tickets split from another one by transfers and re-delegations
+	DerivedTicket		= "dt"
+	DerivedTicketList	= "dtL"
 
 )
 
@@ -68,6 +74,9 @@ var (
 	// need refund
 	DefeatBytePrefix     = []byte(DefeatPrefix)
 	DefeatListBytePrefix = []byte(DefeatListPrefix)
+	// pending fee changes
+	PendingFeeBytePrefix     = []byte(PendingFeePrefix)
+	PendingFeeListBytePrefix = []byte(PendingFeeListPrefix)
 
 	/** about ticket pool */
 	// Remaining number key
@@ -78,4 +87,7 @@ var (
 
 	TicketPoolHashKey			= []byte(TicketPoolHash)
 
+	DerivedTicketPrefix			= []byte(DerivedTicket)
+	DerivedTicketListPrefix		= []byte(DerivedTicketList)
+
 )
\ No newline at end of file
@@ -0,0 +1,155 @@
+package pposm
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+func testCandidateIds(n int) []discover.NodeID {
+	ids := make([]discover.NodeID, n)
+	for i := range ids {
+		ids[i][0] = byte(i + 1)
+	}
+	return ids
+}
+
+func TestSampleCandidates(t *testing.T) {
+	ids := testCandidateIds(6)
+	weights := make(types.CanConditions)
+	for i, id := range ids {
+		weights[id] = big.NewInt(int64(100 * (i + 1)))
+	}
+	seed := common.HexToHash("0x01")
+
+	picked := sampleCandidates(ids, weights, 3, seed)
+	if len(picked) != 3 {
+		t.Fatalf("picked count mismatch: have %d, want 3", len(picked))
+	}
+	if again := sampleCandidates(ids, weights, 3, seed); !reflect.DeepEqual(picked, again) {
+		t.Fatalf("sampling is not reproducible: %v != %v", picked, again)
+	}
+	// the picked ids are distinct and keep the order of the queue
+	for i, j := 0, 0; i < len(picked); i++ {
+		for j < len(ids) && ids[j] != picked[i] {
+			j++
+		}
+		if j == len(ids) {
+			t.Fatalf("picked ids %v are not an ordered subset of %v", picked, ids)
+		}
+		j++
+	}
+
+	if all := sampleCandidates(ids[:2], weights, 3, seed); !reflect.DeepEqual(all, ids[:2]) {
+		t.Fatalf("short queue mismatch: have %v, want %v", all, ids[:2])
+	}
+	if top := sampleCandidates(ids, types.CanConditions{}, 3, seed); !reflect.DeepEqual(top, ids[:3]) {
+		t.Fatalf("unweighted queue mismatch: have %v, want %v", top, ids[:3])
+	}
+}
+
+func TestSampleCandidatesWeight(t *testing.T) {
+	ids := testCandidateIds(4)
+	// a whale and three small candidates, one seat
+	weights := types.CanConditions{
+		ids[0]: big.NewInt(1),
+		ids[1]: big.NewInt(1),
+		ids[2]: big.NewInt(1),
+		ids[3]: big.NewInt(97),
+	}
+	wins := make(map[discover.NodeID]int)
+	for i := 0; i < 1000; i++ {
+		seed := crypto.Keccak256Hash(big.NewInt(int64(i)).Bytes())
+		wins[sampleCandidates(ids, weights, 1, seed)[0]]++
+	}
+	if wins[ids[3]] < 900 || wins[ids[3]] == 1000 {
+		t.Fatalf("whale seats mismatch: have %d of 1000, want about 970", wins[ids[3]])
+	}
+	for _, id := range ids[:3] {
+		if wins[id] == 0 {
+			t.Errorf("candidate %x never elected", id[:1])
+		}
+	}
+}
+
+func TestElectionSeed(t *testing.T) {
+	switchHash := common.HexToHash("0x02")
+	ids := testCandidateIds(2)
+	current := types.CandidateQueue{{CandidateId: ids[0]}, {CandidateId: ids[1]}}
+
+	seed := electionSeed(switchHash, current)
+	if seed != electionSeed(switchHash, current) {
+		t.Fatalf("seed is not reproducible")
+	}
+	if seed == electionSeed(common.HexToHash("0x03"), current) {
+		t.Fatalf("seed doesn't depend on the switch block")
+	}
+	if seed == electionSeed(switchHash, current[:1]) {
+		t.Fatalf("seed doesn't depend on the current witnesses")
+	}
+}
+
+// headerChain serves the headers of a test chain.
+type headerChain struct {
+	ChainInfo
+	headers map[common.Hash]*types.Header
+}
+
+func (c *headerChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header, ok := c.headers[hash]; ok && header.Number.Uint64() == number {
+		return header
+	}
+	return nil
+}
+
+func TestSwitchBlockHash(t *testing.T) {
+	chain := &headerChain{headers: make(map[common.Hash]*types.Header)}
+	hashes := make([]common.Hash, common.BaseSwitchWitness+common.BaseElection)
+	parent := common.Hash{}
+	for i := range hashes {
+		header := &types.Header{ParentHash: parent, Number: big.NewInt(int64(i))}
+		hashes[i] = header.Hash()
+		chain.headers[hashes[i]] = header
+		parent = hashes[i]
+	}
+	tContext := NewTicketPoolContext(nil)
+	tContext.SetChainInfo(chain)
+	pool := &CandidatePool{tContext: tContext}
+
+	for _, number := range []int{common.BaseElection, common.BaseSwitchWitness + 1, common.BaseSwitchWitness + common.BaseElection} {
+		hash, err := pool.switchBlockHash(hashes[number-1], big.NewInt(int64(number)))
+		if err != nil {
+			t.Fatalf("block %d: switchBlockHash fail: %v", number, err)
+		}
+		want := hashes[(number-1)/common.BaseSwitchWitness*common.BaseSwitchWitness]
+		if hash != want {
+			t.Errorf("block %d: switch block mismatch: have %x, want %x", number, hash, want)
+		}
+	}
+	if _, err := pool.switchBlockHash(common.Hash{1}, big.NewInt(common.BaseElection)); err == nil {
+		t.Errorf("unknown parent accepted")
+	}
+}
+
+func TestBuildWitnessNodeSentried(t *testing.T) {
+	id := testCandidateIds(1)[0]
+	node, err := buildWitnessNode(&types.Candidate{CandidateId: id})
+	if err != nil {
+		t.Fatalf("candidate without endpoint rejected: %v", err)
+	}
+	if node.ID != id || !node.Incomplete() {
+		t.Errorf("node mismatch: %v", node)
+	}
+	node, err = buildWitnessNode(&types.Candidate{CandidateId: id, Host: "10.0.0.1", Port: "16789"})
+	if err != nil || node.TCP != 16789 || node.Incomplete() {
+		t.Errorf("candidate endpoint mismatch: %v (%v)", node, err)
+	}
+	if _, err := buildWitnessNode(&types.Candidate{CandidateId: id, Host: "10.0.0.1"}); err == nil {
+		t.Errorf("candidate without port accepted")
+	}
+}
@@ -0,0 +1,12 @@
+package pposm
+
+import (
+	"github.com/PlatONnetwork/PlatON-Go/metrics"
+)
+
+var (
+	electionTimer            = metrics.NewRegisteredTimer("ppos/election", nil)
+	immediateCandidatesGauge = metrics.NewRegisteredGauge("ppos/candidates/immediate", nil)
+	reserveCandidatesGauge   = metrics.NewRegisteredGauge("ppos/candidates/reserve", nil)
+	remainingTicketsGauge    = metrics.NewRegisteredGauge("ppos/tickets/remaining", nil)
+)
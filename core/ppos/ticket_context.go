@@ -20,17 +20,16 @@ type ChainInfo interface {
 type TicketPoolContext struct {
 	Configs 			*params.PposConfig
 	chainConfig 		*params.ChainConfig
+	// the candidate pool context working together with the ticket pool
+	cContext 			*CandidatePoolContext
 	ChainInfo
 }
 
-var tContext *TicketPoolContext
-
-// Initialize the global ticket pool context object
+// Initialize a ticket pool context object
 func NewTicketPoolContext(configs *params.PposConfig) *TicketPoolContext {
-	tContext = &TicketPoolContext{
+	return &TicketPoolContext{
 		Configs: configs,
 	}
-	return tContext
 }
 
 func (c *TicketPoolContext) SetChainInfo(ci ChainInfo) {
@@ -41,80 +40,101 @@ func (c *TicketPoolContext) SetChainConfig(chainConfig *params.ChainConfig) {
 	c.chainConfig = chainConfig
 }
 
-func GetTicketPoolContextPtr() *TicketPoolContext {
-	return tContext
+// ChainConfig returns the chain config of the context, nil if it isn't set
+func (c *TicketPoolContext) ChainConfig() *params.ChainConfig {
+	if nil == c {
+		return nil
+	}
+	return c.chainConfig
 }
 
-func (c *TicketPoolContext) initTicketPool() *TicketPool {
-	return NewTicketPool(c.Configs)
+func (c *TicketPoolContext) SetCandidatePoolContext(cContext *CandidatePoolContext) {
+	c.cContext = cContext
+}
+
+// initTicketPool builds a ticket pool with the configs in effect on state
+func (c *TicketPoolContext) initTicketPool(state vm.StateDB) *TicketPool {
+	return NewTicketPool(vm.GovernedPposConfig(state, c.Configs), c)
 }
 
 func (c *TicketPoolContext) GetPoolNumber (state vm.StateDB) uint32 {
-	return c.initTicketPool().GetPoolNumber(state)
+	return c.initTicketPool(state).GetPoolNumber(state)
 }
 
 func (c *TicketPoolContext) VoteTicket (state vm.StateDB, owner common.Address, voteNumber uint32, deposit *big.Int, nodeId discover.NodeID, blockNumber *big.Int) (uint32, error) {
-	return c.initTicketPool().VoteTicket(state, owner, voteNumber, deposit, nodeId, blockNumber)
+	return c.initTicketPool(state).VoteTicket(state, owner, voteNumber, deposit, nodeId, blockNumber)
+}
+
+func (c *TicketPoolContext) TransferTicket (state vm.StateDB, from common.Address, ticketId common.Hash, count uint32, to common.Address, blockNumber *big.Int) (common.Hash, error) {
+	return c.initTicketPool(state).TransferTicket(state, from, ticketId, count, to, blockNumber)
+}
+
+func (c *TicketPoolContext) RedelegateTicket (state vm.StateDB, from common.Address, ticketId common.Hash, count uint32, nodeId discover.NodeID, blockNumber *big.Int) (common.Hash, error) {
+	return c.initTicketPool(state).RedelegateTicket(state, from, ticketId, count, nodeId, blockNumber)
 }
 
 func (c *TicketPoolContext) GetTicket(state vm.StateDB, ticketId common.Hash) *types.Ticket {
-	return c.initTicketPool().GetTicket(state, ticketId)
+	return c.initTicketPool(state).GetTicket(state, ticketId)
 }
 
 func (c *TicketPoolContext) GetExpireTicketIds(state vm.StateDB, blockNumber *big.Int) []common.Hash {
-	return c.initTicketPool().GetExpireTicketIds(state, blockNumber)
+	return c.initTicketPool(state).GetExpireTicketIds(state, blockNumber)
 }
 
 func (c *TicketPoolContext) GetTicketList (state vm.StateDB, ticketIds []common.Hash) []*types.Ticket {
-	return c.initTicketPool().GetTicketList(state, ticketIds)
+	return c.initTicketPool(state).GetTicketList(state, ticketIds)
 }
 
 func (c *TicketPoolContext) GetCandidateTicketIds (state vm.StateDB, nodeId discover.NodeID) []common.Hash {
-	return c.initTicketPool().GetCandidateTicketIds(state, nodeId)
+	return c.initTicketPool(state).GetCandidateTicketIds(state, nodeId)
 }
 
 func (c *TicketPoolContext) GetCandidateEpoch (state vm.StateDB, nodeId discover.NodeID) uint64 {
-	return c.initTicketPool().GetCandidateEpoch(state, nodeId)
+	return c.initTicketPool(state).GetCandidateEpoch(state, nodeId)
 }
 
 func (c *TicketPoolContext) GetTicketPrice (state vm.StateDB) *big.Int {
-	return c.initTicketPool().GetTicketPrice(state)
+	return c.initTicketPool(state).GetTicketPrice(state)
+}
+
+func (c *TicketPoolContext) GetTicketPriceHistory (state vm.StateDB, start, count uint32) []*types.TicketPriceRecord {
+	return c.initTicketPool(state).GetTicketPriceHistory(state, start, count)
 }
 
 func (c *TicketPoolContext) Notify (state vm.StateDB, blockNumber *big.Int) error {
-	return c.initTicketPool().Notify(state, blockNumber)
+	return c.initTicketPool(state).Notify(state, blockNumber)
 }
 
 func (c *TicketPoolContext) StoreHash (state vm.StateDB, blockNumber *big.Int, blockHash common.Hash) error {
-	return c.initTicketPool().CommitHash(state, blockNumber, blockHash)
+	return c.initTicketPool(state).CommitHash(state, blockNumber, blockHash)
 }
 
 func (c *TicketPoolContext) GetCandidateTicketCount (state vm.StateDB, nodeId discover.NodeID) uint32 {
-	return c.initTicketPool().GetCandidateTicketCount(state, nodeId)
+	return c.initTicketPool(state).GetCandidateTicketCount(state, nodeId)
 }
 
 func (c *TicketPoolContext) GetCandidatesTicketCount (state vm.StateDB, nodeIds []discover.NodeID) map[discover.NodeID]uint32 {
-	return c.initTicketPool().GetCandidatesTicketCount(state, nodeIds)
+	return c.initTicketPool(state).GetCandidatesTicketCount(state, nodeIds)
 }
 
 func (c *TicketPoolContext) GetCandidatesTicketIds (state vm.StateDB, nodeIds []discover.NodeID) map[discover.NodeID][]common.Hash {
-	return c.initTicketPool().GetCandidatesTicketIds(state, nodeIds)
+	return c.initTicketPool(state).GetCandidatesTicketIds(state, nodeIds)
 }
 
 func (c *TicketPoolContext) DropReturnTicket(stateDB vm.StateDB, blockNumber *big.Int, nodeIds ...discover.NodeID) error {
-	return c.initTicketPool().DropReturnTicket(stateDB, blockNumber, nodeIds...)
+	return c.initTicketPool(stateDB).DropReturnTicket(stateDB, blockNumber, nodeIds...)
 }
 
 func (c *TicketPoolContext) ReturnTicket(stateDB vm.StateDB, nodeId discover.NodeID, ticketId common.Hash, blockNumber *big.Int) error {
-	return c.initTicketPool().ReturnTicket(stateDB, nodeId, ticketId, blockNumber)
+	return c.initTicketPool(stateDB).ReturnTicket(stateDB, nodeId, ticketId, blockNumber)
 }
 
 func (c *TicketPoolContext) SelectionLuckyTicket(stateDB vm.StateDB, nodeId discover.NodeID, blockHash common.Hash) (common.Hash, error) {
-	return c.initTicketPool().SelectionLuckyTicket(stateDB, nodeId, blockHash)
+	return c.initTicketPool(stateDB).SelectionLuckyTicket(stateDB, nodeId, blockHash)
 }
 
 func (c *TicketPoolContext) GetBatchTicketRemaining(stateDB vm.StateDB, ticketIds []common.Hash) map[common.Hash]uint32 {
-	return c.initTicketPool().GetBatchTicketRemaining(stateDB, ticketIds)
+	return c.initTicketPool(stateDB).GetBatchTicketRemaining(stateDB, ticketIds)
 }
 
 
@@ -10,6 +10,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/core/ppos_storage"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/params"
@@ -36,8 +37,21 @@ var (
 	GetCandidateAttachErr = errors.New("Get CandidateAttach error")
 	SetCandidateAttachErr = errors.New("Update CandidateAttach error")
 	VoteTicketErr         = errors.New("Voting failed")
+	TicketOwnerErr        = errors.New("The ticket does not belong to the sender")
+	TicketCountErr        = errors.New("The ticket has not enough remaining")
+	TicketCooldownErr     = errors.New("Tickets can't be moved right before the election")
+	RedelegateSelfErr     = errors.New("The ticket already votes for the candidate")
+	TicketIdExistErr      = errors.New("The ticket id already exists")
 )
 
+// a ticket split from another one by a transfer or a re-delegation
+type derivedTicket struct {
+	// the ticket bought by VoteTicket which the ticket comes from
+	Root common.Hash
+	// owner, price, candidate and purchase block of the ticket
+	Ticket *types.Ticket
+}
+
 type TicketPool struct {
 	// Ticket price
 	TicketPrice *big.Int
@@ -45,13 +59,27 @@ type TicketPool struct {
 	MaxCount uint32
 	// Reach expired quantity
 	ExpireBlockNumber uint32
+	// blocks before an election in which tickets can't be moved
+	TransferCooldown uint32
+	// block interval between price adjustments, zero keeps the price fixed
+	PriceEpoch uint32
+	// percentage of sold tickets the price adjustments steer to
+	TargetUtilization uint32
+	// the price changes at most by 1/PriceAdjustDenominator per epoch
+	PriceAdjustDenominator uint32
+	// bounds of the adjusted price, nil for no bound
+	MinTicketPrice *big.Int
+	MaxTicketPrice *big.Int
 	lock              *sync.Mutex
+
+	// the ticket pool context the pool belongs to
+	tContext *TicketPoolContext
 }
 
 //var ticketPool *TicketPool
 
-// initialize the global ticket pool object
-func NewTicketPool(configs *params.PposConfig) *TicketPool {
+// initialize a ticket pool object which belongs to the given ticket pool context
+func NewTicketPool(configs *params.PposConfig, tContext *TicketPoolContext) *TicketPool {
 	//if nil != ticketPool {
 	//	return ticketPool
 	//}
@@ -70,7 +98,18 @@ func NewTicketPool(configs *params.PposConfig) *TicketPool {
 		TicketPrice:       ticketPrice,
 		MaxCount:          configs.TicketConfig.MaxCount,
 		ExpireBlockNumber: configs.TicketConfig.ExpireBlockNumber,
+		PriceEpoch:             configs.TicketConfig.PriceEpoch,
+		TargetUtilization:      configs.TicketConfig.TargetUtilization,
+		PriceAdjustDenominator: configs.TicketConfig.PriceAdjustDenominator,
+		TransferCooldown:       configs.TicketConfig.TransferCooldown,
 		lock:              &sync.Mutex{},
+		tContext:          tContext,
+	}
+	if price, ok := new(big.Int).SetString(configs.TicketConfig.MinTicketPrice, 10); ok {
+		ticketPool.MinTicketPrice = price
+	}
+	if price, ok := new(big.Int).SetString(configs.TicketConfig.MaxTicketPrice, 10); ok {
+		ticketPool.MaxTicketPrice = price
 	}
 	return ticketPool
 }
@@ -85,7 +124,7 @@ func (t *TicketPool) VoteTicket(stateDB vm.StateDB, owner common.Address, voteNu
 	}
 	// Voting completed, candidates reordered
 	log.Debug("Successfully voted to start updating the list of candidates,VoteTicket", "successNum", successCount)
-	if err := cContext.UpdateElectedQueue(stateDB, blockNumber, nodeId); nil != err {
+	if err := t.tContext.cContext.UpdateElectedQueue(stateDB, blockNumber, nodeId); nil != err {
 		log.Error("Failed to Update candidate when voteTicket success", "err", err)
 	}
 	if successCount > 0 {
@@ -151,7 +190,7 @@ func (t *TicketPool) GetExpireTicketIds(stateDB vm.StateDB, blockNumber *big.Int
 	log.Debug("Call GetExpireTicketIds", "statedb addr", fmt.Sprintf("%p", stateDB))
 	start := common.NewTimer()
 	start.Begin()
-	body := tContext.GetBody(blockNumber.Uint64())
+	body := t.tContext.GetBody(blockNumber.Uint64())
 	txs := make([]common.Hash, 0)
 	for _, tx := range body.Transactions {
 		if tx.To() != nil && *tx.To() == common.TicketPoolAddr {
@@ -192,13 +231,152 @@ func (t *TicketPool) handleExpireTicket(stateDB vm.StateDB, expireBlockNumber *b
 			candidateAttachMap[ticket.CandidateId] = true
 			changeNodeIdList = append(changeNodeIdList, ticket.CandidateId)
 		}
-		if _, err := t.releaseTxTicket(stateDB, ticket.CandidateId, ticketId, currentBlockNumber); nil != err {
+		if err := t.expireTicket(stateDB, ticket.CandidateId, ticketId, currentBlockNumber); nil != err {
 			return changeNodeIdList, err
 		}
+		// the tickets split from it expire together
+		for _, derivedId := range getDerivedTicketIds(stateDB, ticketId) {
+			derived := t.GetTicket(stateDB, derivedId)
+			if derived == nil {
+				continue
+			}
+			if _, ok := candidateAttachMap[derived.CandidateId]; !ok {
+				candidateAttachMap[derived.CandidateId] = true
+				changeNodeIdList = append(changeNodeIdList, derived.CandidateId)
+			}
+			if err := t.expireTicket(stateDB, derived.CandidateId, derivedId, currentBlockNumber); nil != err {
+				return changeNodeIdList, err
+			}
+		}
 	}
 	return changeNodeIdList, nil
 }
 
+// expireTicket releases the remaining tickets of ticketId and emits the expiry as a system log
+func (t *TicketPool) expireTicket(stateDB vm.StateDB, candidateId discover.NodeID, ticketId common.Hash, blockNumber *big.Int) error {
+	ticket, err := t.releaseTxTicket(stateDB, candidateId, ticketId, blockNumber)
+	if nil != err {
+		return err
+	}
+	if nil != ticket && ticket.Remaining > 0 {
+		vm.AddPposSystemLog(t.tContext.ChainConfig(), stateDB, common.TicketPoolAddr, blockNumber, vm.TicketExpireEvent, ticketId, candidateId, ticket.Owner, ticket.Remaining)
+	}
+	return nil
+}
+
+// TransferTicket moves count tickets of ticketId to the account to,
+// the moved tickets keep the candidate, the price and the expiry of ticketId.
+func (t *TicketPool) TransferTicket(stateDB vm.StateDB, from common.Address, ticketId common.Hash, count uint32, to common.Address, blockNumber *big.Int) (common.Hash, error) {
+	log.Info("Start transferring tickets on TransferTicket", "from", from.Hex(), "ticketId", ticketId.Hex(), "count", count, "to", to.Hex(), "blockNumber", blockNumber.Uint64())
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	ticket, err := t.checkTicketMove(stateDB, from, ticketId, count, blockNumber)
+	if nil != err {
+		log.Error("Transferring failed", "ticketId", ticketId.Hex(), "to", to.Hex(), "err", err)
+		return common.Hash{}, err
+	}
+	return t.splitTicket(stateDB, ticketId, ticket, count, to, ticket.CandidateId)
+}
+
+// RedelegateTicket moves count tickets of ticketId to the candidate nodeId,
+// the moved tickets keep the owner, the price and the expiry of ticketId.
+func (t *TicketPool) RedelegateTicket(stateDB vm.StateDB, from common.Address, ticketId common.Hash, count uint32, nodeId discover.NodeID, blockNumber *big.Int) (common.Hash, error) {
+	log.Info("Start re-delegating tickets on RedelegateTicket", "from", from.Hex(), "ticketId", ticketId.Hex(), "count", count, "nodeId", nodeId.String(), "blockNumber", blockNumber.Uint64())
+	oldNodeId, newTicketId, err := t.redelegateTicket(stateDB, from, ticketId, count, nodeId, blockNumber)
+	if nil != err {
+		log.Error("Re-delegating failed", "ticketId", ticketId.Hex(), "nodeId", nodeId.String(), "err", err)
+		return common.Hash{}, err
+	}
+	// both candidates changed their ticket count, reorder them
+	if err := t.tContext.cContext.UpdateElectedQueue(stateDB, blockNumber, oldNodeId, nodeId); nil != err {
+		log.Error("Failed to Update candidate when RedelegateTicket success", "err", err)
+	}
+	return newTicketId, nil
+}
+
+func (t *TicketPool) redelegateTicket(stateDB vm.StateDB, from common.Address, ticketId common.Hash, count uint32, nodeId discover.NodeID, blockNumber *big.Int) (discover.NodeID, common.Hash, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	ticket, err := t.checkTicketMove(stateDB, from, ticketId, count, blockNumber)
+	if nil != err {
+		return discover.NodeID{}, common.Hash{}, err
+	}
+	if ticket.CandidateId == nodeId {
+		return discover.NodeID{}, common.Hash{}, RedelegateSelfErr
+	}
+	if nil == t.tContext.cContext.GetCandidate(stateDB, nodeId, blockNumber) {
+		return discover.NodeID{}, common.Hash{}, CandidateNotFindErr
+	}
+	newTicketId, err := t.splitTicket(stateDB, ticketId, ticket, count, ticket.Owner, nodeId)
+	return ticket.CandidateId, newTicketId, err
+}
+
+// checkTicketMove checks that from may move count tickets of ticketId at blockNumber.
+func (t *TicketPool) checkTicketMove(stateDB vm.StateDB, from common.Address, ticketId common.Hash, count uint32, blockNumber *big.Int) (*types.Ticket, error) {
+	if t.inTransferCooldown(blockNumber) {
+		return nil, TicketCooldownErr
+	}
+	ticket := t.GetTicket(stateDB, ticketId)
+	if nil == ticket {
+		return nil, TicketNotFindErr
+	}
+	if ticket.Owner != from {
+		return nil, TicketOwnerErr
+	}
+	if count == 0 || stateDB.GetPPOSCache().GetTicketRemainByTxHash(ticketId) < count {
+		return nil, TicketCountErr
+	}
+	return ticket, nil
+}
+
+// inTransferCooldown reports whether blockNumber is within TransferCooldown blocks before an election.
+func (t *TicketPool) inTransferCooldown(blockNumber *big.Int) bool {
+	if t.TransferCooldown == 0 {
+		return false
+	}
+	_, distance := new(big.Int).DivMod(new(big.Int).Sub(big.NewInt(common.BaseElection), blockNumber), big.NewInt(common.BaseSwitchWitness), new(big.Int))
+	return distance.Uint64() < uint64(t.TransferCooldown)
+}
+
+// splitTicket takes count tickets off ticketId and records them as a new ticket
+// of owner voting for nodeId, identified by an id derived from the current transaction.
+func (t *TicketPool) splitTicket(stateDB vm.StateDB, ticketId common.Hash, ticket *types.Ticket, count uint32, owner common.Address, nodeId discover.NodeID) (common.Hash, error) {
+	root := ticketId
+	if derived := getDerivedTicket(stateDB, ticketId); nil != derived {
+		root = derived.Root
+	}
+	derivedIds := getDerivedTicketIds(stateDB, root)
+	newTicketId := derivedTicketId(stateDB.TxHash(), ticketId, uint64(len(derivedIds)))
+	if nil != getDerivedTicket(stateDB, newTicketId) || stateDB.GetPPOSCache().GetTicketRemainByTxHash(newTicketId) > 0 {
+		log.Error("Failed to split ticket", "ticketId", ticketId.Hex(), "newTicketId", newTicketId.Hex(), "err", TicketIdExistErr)
+		return common.Hash{}, TicketIdExistErr
+	}
+	tinfo, err := stateDB.GetPPOSCache().SubTicketCount(ticket.CandidateId, ticketId, count)
+	if nil != err {
+		return common.Hash{}, err
+	}
+	if err := stateDB.GetPPOSCache().AppendTicket(nodeId, newTicketId, count, tinfo.Price); nil != err {
+		return common.Hash{}, err
+	}
+	derived := &derivedTicket{
+		Root: root,
+		Ticket: &types.Ticket{
+			Owner:       owner,
+			Deposit:     new(big.Int).Set(tinfo.Price),
+			CandidateId: nodeId,
+			BlockNumber: new(big.Int).Set(ticket.BlockNumber),
+		},
+	}
+	if err := setDerivedTicket(stateDB, newTicketId, derived); nil != err {
+		return common.Hash{}, err
+	}
+	if err := setDerivedTicketIds(stateDB, root, append(derivedIds, newTicketId)); nil != err {
+		return common.Hash{}, err
+	}
+	log.Debug("Split ticket success", "ticketId", ticketId.Hex(), "newTicketId", newTicketId.Hex(), "root", root.Hex(), "count", count, "owner", owner.Hex(), "nodeId", nodeId.String())
+	return newTicketId, nil
+}
+
 // Get ticket list
 func (t *TicketPool) GetTicketList(stateDB vm.StateDB, ticketIds []common.Hash) []*types.Ticket {
 	log.Debug("Call GetTickList", "statedb addr", fmt.Sprintf("%p", stateDB))
@@ -224,10 +402,14 @@ func (t *TicketPool) GetTicket(stateDB vm.StateDB, txHash common.Hash) *types.Ti
 	if value := ppos_storage.GetTicket(txHash); nil != value {
 		return value
 	}
+	if derived := getDerivedTicket(stateDB, txHash); nil != derived {
+		ppos_storage.PutTicket(txHash, derived.Ticket)
+		return derived.Ticket
+	}
 
 	startTx := common.NewTimer()
 	startTx.Begin()
-	tx, _, blockNumber,_ := tContext.FindTransaction(txHash)
+	tx, _, blockNumber,_ := t.tContext.FindTransaction(txHash)
 	log.Debug("GetTicket Time Tx",  "txHash", tx.Hash(), "Time spent", fmt.Sprintf("%v ms", startTx.End()))
 	if nil != tx && len(tx.Data()) > 0 {
 		startDecode := common.NewTimer()
@@ -244,7 +426,7 @@ func (t *TicketPool) GetTicket(stateDB vm.StateDB, txHash common.Hash) *types.Ti
 		ticket := new(types.Ticket)
 		startSigner := common.NewTimer()
 		startSigner.Begin()
-		signer := types.NewEIP155Signer(tContext.chainConfig.ChainID)
+		signer := types.NewEIP155Signer(t.tContext.chainConfig.ChainID)
 		if addr, err := signer.Sender(tx); nil != err {
 			log.Error("Failed to GetTicket, get tx owner is empty !!!!", "tx", tx.Hash().Hex(), "err", err)
 			return nil
@@ -254,11 +436,11 @@ func (t *TicketPool) GetTicket(stateDB vm.StateDB, txHash common.Hash) *types.Ti
 		log.Debug("GetTicket Time startSigner", "Time spent", fmt.Sprintf("%v ms", startSigner.End()))
 		//startGetHeader := common.NewTimer()
 		//startGetHeader.Begin()
-		//block := tContext.GetHeader(blockHash, blockNumber)
+		//block := t.tContext.GetHeader(blockHash, blockNumber)
 		//log.Debug("GetTicket Time startGetHeader", "Time spent", fmt.Sprintf("%v ms", startGetHeader.End()))
 		//startGetNewStateDB := common.NewTimer()
 		//startGetNewStateDB.Begin()
-		//if oldState, err := tContext.GetNewStateDB(block.Root, new(big.Int).SetUint64(blockNumber), blockHash); nil != err {
+		//if oldState, err := t.tContext.GetNewStateDB(block.Root, new(big.Int).SetUint64(blockNumber), blockHash); nil != err {
 		//	return nil
 		//} else {
 		//	ticket.Deposit = t.GetTicketPrice(oldState)
@@ -435,12 +617,18 @@ func (t *TicketPool) Notify(stateDB vm.StateDB, blockNumber *big.Int) error {
 			// Notify the candidate to update the list information after processing the expired ticket
 			log.Debug("After processing the expired ticket, start updating the candidate list on Notify", "blockNumber", blockNumber.Uint64(), "nodeIdList", len(nodeIdList))
 			if len(nodeIdList) > 0 {
-				if err := cContext.UpdateElectedQueue(stateDB, blockNumber, nodeIdList...); nil != err {
+				if err := t.tContext.cContext.UpdateElectedQueue(stateDB, blockNumber, nodeIdList...); nil != err {
 					log.Error("Failed to Update candidate when handleExpireTicket success on Notify", "err", err)
 				}
 			}
 		}
 	}
+	if t.dynamicPricing() && blockNumber.Uint64()%uint64(t.PriceEpoch) == 0 {
+		if err := t.adjustTicketPrice(stateDB, blockNumber); nil != err {
+			log.Error("Failed to adjust the ticket price on Notify", "blockNumber", blockNumber.Uint64(), "err", err)
+			return err
+		}
+	}
 	// Increase the total number of epoch for each candidate
 	/*log.Debug("Increase the total number of epoch for each candidate on Notify", "blockNumber", blockNumber.Uint64())
 	if err := t.calcCandidateEpoch(stateDB, blockNumber); nil != err {
@@ -452,7 +640,7 @@ func (t *TicketPool) Notify(stateDB vm.StateDB, blockNumber *big.Int) error {
 /*func (t *TicketPool) calcCandidateEpoch(stateDB vm.StateDB, blockNumber *big.Int) error {
 	t.lock.Lock()
 	defer t.lock.Unlock()
-	candidateList := cContext.GetCandidatePendArr(stateDB, 0)
+	candidateList := t.tContext.cContext.GetCandidatePendArr(stateDB, 0)
 	for _, candidate := range candidateList {
 		epoch := t.GetCandidateEpoch(stateDB, candidate.CandidateId)
 		// Get the total number of votes, increase the total epoch
@@ -631,17 +819,143 @@ func (t *TicketPool) GetCandidateEpoch(stateDB vm.StateDB, nodeId discover.NodeI
 	return stateDB.GetPPOSCache().GetCandidateTicketAge(nodeId)
 }
 
+// GetTicketPrice returns the price of a ticket, the configured price unless
+// dynamic pricing adjusted it already. A price adjusted from another configured
+// price is discarded, so that governance changes of the price apply at once.
 func (t *TicketPool) GetTicketPrice(stateDB vm.StateDB) *big.Int {
+	if t.dynamicPricing() {
+		if price, base := stateDB.GetPPOSCache().GetTicketPrice(); nil != price && nil != base && base.Cmp(t.TicketPrice) == 0 {
+			return price
+		}
+	}
 	return t.TicketPrice
 }
 
+// dynamicPricing reports whether the price follows the pool utilization.
+func (t *TicketPool) dynamicPricing() bool {
+	return t.PriceEpoch > 0 && t.TargetUtilization > 0 && t.TargetUtilization < 100 && t.PriceAdjustDenominator > 0 && t.MaxCount > 0
+}
+
+// adjustTicketPrice moves the price towards the target utilization of the pool,
+// by price * (utilization - target) / (target * PriceAdjustDenominator),
+// bounded by the min and max price, and records it in the price history.
+func (t *TicketPool) adjustTicketPrice(stateDB vm.StateDB, blockNumber *big.Int) error {
+	price := t.GetTicketPrice(stateDB)
+	remaining := t.GetPoolNumber(stateDB)
+	if remaining > t.MaxCount {
+		remaining = t.MaxCount
+	}
+	sold := uint64(t.MaxCount - remaining)
+	target := uint64(t.TargetUtilization) * uint64(t.MaxCount)
+	diff := new(big.Int).Sub(new(big.Int).SetUint64(sold*100), new(big.Int).SetUint64(target))
+	delta := new(big.Int).Mul(price, diff)
+	delta.Quo(delta, new(big.Int).SetUint64(target*uint64(t.PriceAdjustDenominator)))
+	// keep a small price moving up under demand
+	if diff.Sign() > 0 && delta.Sign() == 0 {
+		delta.SetUint64(1)
+	}
+	newPrice := new(big.Int).Add(price, delta)
+	if nil != t.MinTicketPrice && newPrice.Cmp(t.MinTicketPrice) < 0 {
+		newPrice.Set(t.MinTicketPrice)
+	}
+	if nil != t.MaxTicketPrice && newPrice.Cmp(t.MaxTicketPrice) > 0 {
+		newPrice.Set(t.MaxTicketPrice)
+	}
+	if newPrice.Sign() <= 0 {
+		newPrice.SetUint64(1)
+	}
+	log.Debug("Adjust the ticket price", "blockNumber", blockNumber.Uint64(), "remaining", remaining, "price", price, "newPrice", newPrice)
+	storage := stateDB.GetPPOSCache()
+	storage.SetTicketPrice(newPrice, t.TicketPrice)
+	storage.AppendTicketPriceRecord(&types.TicketPriceRecord{
+		BlockNumber: new(big.Int).Set(blockNumber),
+		Price:       newPrice,
+		Remaining:   remaining,
+	})
+	return nil
+}
+
+// GetTicketPriceHistory returns at most count of the latest price adjustments
+// from the index start, oldest first.
+func (t *TicketPool) GetTicketPriceHistory(stateDB vm.StateDB, start, count uint32) []*types.TicketPriceRecord {
+	history := stateDB.GetPPOSCache().GetTicketPriceHistory()
+	if uint64(start) >= uint64(len(history)) {
+		return make([]*types.TicketPriceRecord, 0)
+	}
+	end := uint64(start) + uint64(count)
+	if end > uint64(len(history)) {
+		end = uint64(len(history))
+	}
+	return history[start:end]
+}
+
+func getDerivedTicket(stateDB vm.StateDB, ticketId common.Hash) *derivedTicket {
+	if val := stateDB.GetState(common.TicketPoolAddr, derivedTicketKey(ticketId)); len(val) > 0 {
+		derived := new(derivedTicket)
+		if err := rlp.DecodeBytes(val, derived); nil != err {
+			log.Error("Failed to decode derived ticket", "ticketId", ticketId.Hex(), "err", err)
+			return nil
+		}
+		return derived
+	}
+	return nil
+}
+
+func setDerivedTicket(stateDB vm.StateDB, ticketId common.Hash, derived *derivedTicket) error {
+	val, err := rlp.EncodeToBytes(derived)
+	if nil != err {
+		return err
+	}
+	setTicketPoolState(stateDB, derivedTicketKey(ticketId), val)
+	return nil
+}
+
+// getDerivedTicketIds returns the tickets split from the ticket bought by root
+func getDerivedTicketIds(stateDB vm.StateDB, root common.Hash) []common.Hash {
+	var ids []common.Hash
+	if err := getTicketPoolState(stateDB, append(addCommonPrefix(DerivedTicketListPrefix), root.Bytes()...), &ids); nil != err {
+		return nil
+	}
+	return ids
+}
+
+func setDerivedTicketIds(stateDB vm.StateDB, root common.Hash, ids []common.Hash) error {
+	val, err := rlp.EncodeToBytes(ids)
+	if nil != err {
+		return err
+	}
+	setTicketPoolState(stateDB, append(addCommonPrefix(DerivedTicketListPrefix), root.Bytes()...), val)
+	return nil
+}
+
+// derivedTicketId returns the id of the tickets split from ticketId by the
+// transaction txHash, the counter tells apart the tickets split from the same root.
+func derivedTicketId(txHash, ticketId common.Hash, counter uint64) common.Hash {
+	return crypto.Keccak256Hash(txHash.Bytes(), ticketId.Bytes(), byteutil.Uint64ToBytes(counter))
+}
+
+func derivedTicketKey(ticketId common.Hash) []byte {
+	return append(addCommonPrefix(DerivedTicketPrefix), ticketId.Bytes()...)
+}
+
+// StorageHashKey returns the key of the hash of the ppos storage, which CommitHash
+// saves in the state of the ticket pool
+func StorageHashKey() []byte {
+	return addCommonPrefix(TicketPoolHashKey)
+}
+
 // Save the hash value of the current state of the ticket pool
 func (t *TicketPool) CommitHash(stateDB vm.StateDB, blockNumber *big.Int, blockHash common.Hash) error {
+	storage := stateDB.GetPPOSCache()
+	immediateCandidatesGauge.Update(int64(storage.GetCandidateQueueLen(ppos_storage.IMMEDIATE)))
+	reserveCandidatesGauge.Update(int64(storage.GetCandidateQueueLen(ppos_storage.RESERVE)))
+	remainingTicketsGauge.Update(int64(t.GetPoolNumber(stateDB)))
+
 	//hash := common.Hash{}
 	if hash, err := stateDB.GetPPOSCache().CalculateHash(blockNumber, blockHash); nil != err {
 		return err
 	}else {
-		setTicketPoolState(stateDB, addCommonPrefix(TicketPoolHashKey), hash.Bytes())
+		setTicketPoolState(stateDB, StorageHashKey(), hash.Bytes())
 		return nil
 	}
 }
@@ -8,8 +8,7 @@ package pposm_test
 	)
 	fmt.Println("genesis", genesis)
 	// Initialize a fresh chain with only a genesis block
-	ppos_storage.NewPPosTemp(db)
-	blockchain, _ := core.NewBlockChain(db, nil, params.AllEthashProtocolChanges, nil, vm.Config{}, nil)
+	blockchain, _ := core.NewBlockChain(db, nil, nil, params.AllEthashProtocolChanges, nil, vm.Config{}, nil)
 
 	configs := params.PposConfig{
 		CandidateConfig: &params.CandidateConfig{
@@ -173,7 +172,7 @@ package pposm_test
 //	)
 //	fmt.Println("genesis", genesis)
 //	// Initialize a fresh chain with only a genesis block
-//	blockchain, _ := core.NewBlockChain(db, nil, params.AllEthashProtocolChanges, nil, vm.Config{}, nil)
+//	blockchain, _ := core.NewBlockChain(db, nil, nil, params.AllEthashProtocolChanges, nil, vm.Config{}, nil)
 //	ticketcache.NewTicketIdsCache(db)
 //	configs := params.PposConfig{
 //		CandidateConfig: &params.CandidateConfig{
@@ -0,0 +1,10 @@
+package ppos_storage
+
+import (
+	"github.com/PlatONnetwork/PlatON-Go/metrics"
+)
+
+var (
+	calculateHashTimer   = metrics.NewRegisteredTimer("ppos/storage/hash", nil)
+	commitBytesHistogram = metrics.NewRegisteredHistogram("ppos/storage/commit/bytes", nil, metrics.NewExpDecaySample(1028, 0.015))
+)
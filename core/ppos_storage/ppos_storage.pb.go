@@ -17,6 +17,8 @@ It has these top-level messages:
 	TicketTemp
 	PB_PPosTemp
 	SortTemp
+	PriceRecord
+	Liveness
 */
 package ppos_storage
 
@@ -190,6 +192,8 @@ type CandidateTemp struct {
 	Res  []*CandidateInfo `protobuf:"bytes,5,rep,name=res" json:"res,omitempty"`
 	// refunds
 	Refunds map[string]*RefundArr `protobuf:"bytes,6,rep,name=refunds" json:"refunds,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// liveness of the witnesses
+	Liveness map[string]*Liveness `protobuf:"bytes,7,rep,name=liveness" json:"liveness,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *CandidateTemp) Reset()                    { *m = CandidateTemp{} }
@@ -239,6 +243,13 @@ func (m *CandidateTemp) GetRefunds() map[string]*RefundArr {
 	return nil
 }
 
+func (m *CandidateTemp) GetLiveness() map[string]*Liveness {
+	if m != nil {
+		return m.Liveness
+	}
+	return nil
+}
+
 type Field struct {
 	TxHash    string `protobuf:"bytes,1,opt,name=TxHash" json:"TxHash,omitempty"`
 	Remaining uint32 `protobuf:"varint,2,opt,name=Remaining" json:"Remaining,omitempty"`
@@ -302,6 +313,10 @@ type TicketTemp struct {
 	//    map<string, TicketInfo> Infos = 2;
 	//    map<string, TxHashArr> Ets  = 3;
 	Dependencys map[string]*TicketDependency `protobuf:"bytes,4,rep,name=Dependencys" json:"Dependencys,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// the ticket price adjusted by the dynamic pricing and the configured price it derives from
+	Price        string         `protobuf:"bytes,5,opt,name=Price" json:"Price,omitempty"`
+	PriceBase    string         `protobuf:"bytes,6,opt,name=PriceBase" json:"PriceBase,omitempty"`
+	PriceHistory []*PriceRecord `protobuf:"bytes,7,rep,name=PriceHistory" json:"PriceHistory,omitempty"`
 }
 
 func (m *TicketTemp) Reset()                    { *m = TicketTemp{} }
@@ -323,6 +338,27 @@ func (m *TicketTemp) GetDependencys() map[string]*TicketDependency {
 	return nil
 }
 
+func (m *TicketTemp) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *TicketTemp) GetPriceBase() string {
+	if m != nil {
+		return m.PriceBase
+	}
+	return ""
+}
+
+func (m *TicketTemp) GetPriceHistory() []*PriceRecord {
+	if m != nil {
+		return m.PriceHistory
+	}
+	return nil
+}
+
 type PB_PPosTemp struct {
 	CanTmp      *CandidateTemp `protobuf:"bytes,1,opt,name=CanTmp" json:"CanTmp,omitempty"`
 	TickTmp     *TicketTemp    `protobuf:"bytes,2,opt,name=TickTmp" json:"TickTmp,omitempty"`
@@ -372,6 +408,13 @@ type SortTemp struct {
 	Sq      int32               `protobuf:"varint,4,opt,name=sq" json:"sq,omitempty"`
 	NodeIds []string            `protobuf:"bytes,5,rep,name=nodeIds" json:"nodeIds,omitempty"`
 	Deps    []*TicketDependency `protobuf:"bytes,6,rep,name=deps" json:"deps,omitempty"`
+	// ticket price
+	Price        string         `protobuf:"bytes,7,opt,name=price" json:"price,omitempty"`
+	PriceBase    string         `protobuf:"bytes,8,opt,name=priceBase" json:"priceBase,omitempty"`
+	PriceHistory []*PriceRecord `protobuf:"bytes,9,rep,name=priceHistory" json:"priceHistory,omitempty"`
+	// liveness
+	LivenessIds []string    `protobuf:"bytes,10,rep,name=livenessIds" json:"livenessIds,omitempty"`
+	Liveness    []*Liveness `protobuf:"bytes,11,rep,name=liveness" json:"liveness,omitempty"`
 }
 
 func (m *SortTemp) Reset()                    { *m = SortTemp{} }
@@ -421,6 +464,113 @@ func (m *SortTemp) GetDeps() []*TicketDependency {
 	return nil
 }
 
+func (m *SortTemp) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *SortTemp) GetPriceBase() string {
+	if m != nil {
+		return m.PriceBase
+	}
+	return ""
+}
+
+func (m *SortTemp) GetPriceHistory() []*PriceRecord {
+	if m != nil {
+		return m.PriceHistory
+	}
+	return nil
+}
+
+func (m *SortTemp) GetLivenessIds() []string {
+	if m != nil {
+		return m.LivenessIds
+	}
+	return nil
+}
+
+func (m *SortTemp) GetLiveness() []*Liveness {
+	if m != nil {
+		return m.Liveness
+	}
+	return nil
+}
+
+type PriceRecord struct {
+	BlockNumber string `protobuf:"bytes,1,opt,name=BlockNumber" json:"BlockNumber,omitempty"`
+	Price       string `protobuf:"bytes,2,opt,name=Price" json:"Price,omitempty"`
+	Remaining   uint32 `protobuf:"varint,3,opt,name=Remaining" json:"Remaining,omitempty"`
+}
+
+func (m *PriceRecord) Reset()                    { *m = PriceRecord{} }
+func (m *PriceRecord) String() string            { return proto.CompactTextString(m) }
+func (*PriceRecord) ProtoMessage()               {}
+func (*PriceRecord) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *PriceRecord) GetBlockNumber() string {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return ""
+}
+
+func (m *PriceRecord) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *PriceRecord) GetRemaining() uint32 {
+	if m != nil {
+		return m.Remaining
+	}
+	return 0
+}
+
+type Liveness struct {
+	MissedSlots uint32 `protobuf:"varint,1,opt,name=MissedSlots" json:"MissedSlots,omitempty"`
+	Jailed      bool   `protobuf:"varint,2,opt,name=Jailed" json:"Jailed,omitempty"`
+	JailedBlock string `protobuf:"bytes,3,opt,name=JailedBlock" json:"JailedBlock,omitempty"`
+	Penalty     string `protobuf:"bytes,4,opt,name=Penalty" json:"Penalty,omitempty"`
+}
+
+func (m *Liveness) Reset()                    { *m = Liveness{} }
+func (m *Liveness) String() string            { return proto.CompactTextString(m) }
+func (*Liveness) ProtoMessage()               {}
+func (*Liveness) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *Liveness) GetMissedSlots() uint32 {
+	if m != nil {
+		return m.MissedSlots
+	}
+	return 0
+}
+
+func (m *Liveness) GetJailed() bool {
+	if m != nil {
+		return m.Jailed
+	}
+	return false
+}
+
+func (m *Liveness) GetJailedBlock() string {
+	if m != nil {
+		return m.JailedBlock
+	}
+	return ""
+}
+
+func (m *Liveness) GetPenalty() string {
+	if m != nil {
+		return m.Penalty
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*CandidateInfo)(nil), "ppos_storage.CandidateInfo")
 	proto.RegisterType((*Refund)(nil), "ppos_storage.Refund")
@@ -431,52 +581,67 @@ func init() {
 	proto.RegisterType((*TicketTemp)(nil), "ppos_storage.TicketTemp")
 	proto.RegisterType((*PB_PPosTemp)(nil), "ppos_storage.PB_PPosTemp")
 	proto.RegisterType((*SortTemp)(nil), "ppos_storage.SortTemp")
+	proto.RegisterType((*PriceRecord)(nil), "ppos_storage.PriceRecord")
+	proto.RegisterType((*Liveness)(nil), "ppos_storage.Liveness")
 }
 
 func init() { proto.RegisterFile("ppos_storage.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 672 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0xdd, 0x6e, 0xd3, 0x30,
-	0x14, 0x56, 0x92, 0xa6, 0x5d, 0x4f, 0x37, 0x34, 0x99, 0x09, 0xac, 0x31, 0xa1, 0x2a, 0x57, 0xdd,
-	0xc5, 0x8a, 0xd6, 0x71, 0x81, 0xe0, 0x6a, 0xbf, 0xda, 0x84, 0xb4, 0x55, 0x6e, 0xaf, 0xb8, 0x60,
-	0xca, 0x1a, 0x77, 0x44, 0x5b, 0xec, 0xd4, 0x4e, 0xa1, 0x7b, 0x18, 0xc4, 0x5b, 0xf0, 0x0e, 0xbc,
-	0x06, 0x4f, 0x82, 0x7c, 0x9c, 0x90, 0x64, 0xac, 0xda, 0x24, 0xee, 0x7c, 0xbe, 0x7c, 0xdf, 0xf1,
-	0xf1, 0x39, 0x9f, 0x1d, 0x20, 0x69, 0x2a, 0xf5, 0xa5, 0xce, 0xa4, 0x0a, 0xaf, 0x79, 0x3f, 0x55,
-	0x32, 0x93, 0x64, 0xb5, 0x8a, 0x05, 0xdf, 0x5d, 0x58, 0x3b, 0x0c, 0x45, 0x14, 0x47, 0x61, 0xc6,
-	0xcf, 0xc4, 0x54, 0x12, 0x0a, 0xad, 0x23, 0x9e, 0x4a, 0x1d, 0x67, 0xd4, 0xe9, 0x3a, 0xbd, 0x36,
-	0x2b, 0x42, 0xd2, 0x85, 0xce, 0xc1, 0xad, 0x9c, 0xdc, 0x9c, 0xcf, 0x93, 0x2b, 0xae, 0xa8, 0x8b,
-	0x5f, 0xab, 0x90, 0xd1, 0x8e, 0x17, 0x67, 0x22, 0xe2, 0x0b, 0xea, 0x75, 0x9d, 0xde, 0x1a, 0x2b,
-	0x42, 0xa3, 0x2d, 0xb7, 0x89, 0x68, 0xc3, 0x6a, 0x2b, 0x10, 0x21, 0xd0, 0x38, 0x95, 0x3a, 0xa3,
-	0x3e, 0x7e, 0xc2, 0xb5, 0xc1, 0x86, 0x52, 0x65, 0xb4, 0x69, 0x31, 0xb3, 0x26, 0x1b, 0xe0, 0x5f,
-	0x7c, 0x13, 0x5c, 0xd1, 0x16, 0x82, 0x36, 0x30, 0xe8, 0xf1, 0x22, 0x53, 0x21, 0x5d, 0xb1, 0x28,
-	0x06, 0x64, 0x1d, 0xbc, 0x13, 0xce, 0x69, 0x1b, 0x6b, 0x31, 0x4b, 0xf2, 0x02, 0x9a, 0xe3, 0xc5,
-	0x69, 0xa8, 0xbf, 0x50, 0x40, 0x62, 0x1e, 0x21, 0x6e, 0xd3, 0x76, 0x72, 0x1c, 0xa3, 0xe0, 0x13,
-	0x34, 0x19, 0x9f, 0xce, 0x45, 0xf4, 0x5f, 0x7d, 0xf9, 0x5b, 0xb3, 0x57, 0xa9, 0x39, 0xf8, 0x00,
-	0x6d, 0x9b, 0x7b, 0x5f, 0x29, 0xd2, 0x37, 0xe9, 0xa7, 0x3c, 0xcc, 0x34, 0x75, 0xba, 0x5e, 0xaf,
-	0x33, 0xd8, 0xe8, 0xd7, 0x86, 0x67, 0x99, 0xac, 0x20, 0x05, 0x3f, 0xbc, 0xca, 0xe0, 0xc6, 0x3c,
-	0x49, 0xc9, 0x1b, 0x68, 0xa4, 0x8a, 0x17, 0xf2, 0x57, 0x75, 0x79, 0x6d, 0xc6, 0x0c, 0x89, 0x64,
-	0x17, 0xfc, 0xc9, 0x5c, 0x29, 0x4d, 0xdd, 0xc7, 0x15, 0x96, 0x69, 0x24, 0x82, 0x2f, 0x32, 0x4d,
-	0xbd, 0x27, 0x48, 0x90, 0x69, 0xca, 0x8a, 0x93, 0x44, 0xd3, 0xc6, 0x13, 0xca, 0x32, 0x44, 0xb2,
-	0x03, 0x9e, 0x39, 0x86, 0xff, 0x38, 0xdf, 0xf0, 0xc8, 0x01, 0xb4, 0x14, 0xf6, 0x46, 0xd3, 0x26,
-	0x4a, 0x7a, 0x4b, 0x24, 0xa6, 0x49, 0x79, 0x1b, 0xf5, 0xb1, 0xc8, 0xd4, 0x1d, 0x2b, 0x84, 0x9b,
-	0x23, 0x58, 0xad, 0x7e, 0x30, 0xbe, 0xb9, 0xe1, 0x77, 0xf9, 0x9c, 0xcd, 0x92, 0xec, 0x80, 0xff,
-	0x35, 0xbc, 0x9d, 0x73, 0x9c, 0x6e, 0x67, 0xf0, 0xf2, 0xa1, 0xe1, 0xec, 0x2b, 0xc5, 0x2c, 0xeb,
-	0xbd, 0xfb, 0xce, 0x09, 0x46, 0xe0, 0x9f, 0xc4, 0xfc, 0x36, 0xaa, 0x78, 0xce, 0xa9, 0x79, 0x6e,
-	0xcb, 0xcc, 0x3f, 0x09, 0x63, 0x11, 0x8b, 0x6b, 0xcc, 0xbb, 0xc6, 0x4a, 0xc0, 0x78, 0x66, 0xa8,
-	0xe2, 0x09, 0x2f, 0x3c, 0x83, 0x41, 0x70, 0x01, 0xeb, 0xe3, 0x78, 0x72, 0xc3, 0xb3, 0x23, 0x9e,
-	0x72, 0x11, 0x71, 0x31, 0xc1, 0x6a, 0xcf, 0xe7, 0x49, 0x9e, 0xc1, 0x2c, 0xc9, 0x36, 0xf8, 0xe3,
-	0x58, 0x4c, 0x65, 0x3e, 0xa6, 0xe7, 0xf5, 0x6a, 0xb1, 0x2a, 0x66, 0x19, 0xc1, 0x2f, 0x07, 0xc0,
-	0x66, 0x44, 0x13, 0x3d, 0x03, 0x77, 0x34, 0xc3, 0x3a, 0x7d, 0xe6, 0x8e, 0x66, 0xe4, 0x23, 0x74,
-	0xca, 0x9d, 0x8a, 0x21, 0x6e, 0xd7, 0xf3, 0x95, 0xf2, 0x7e, 0x85, 0x6b, 0x5b, 0x5c, 0x55, 0x6f,
-	0x7e, 0x86, 0xf5, 0xfb, 0x84, 0x07, 0x5a, 0xfd, 0xb6, 0xde, 0xea, 0xd7, 0x0f, 0x6d, 0x56, 0xa6,
-	0xa9, 0x76, 0xfc, 0xa7, 0x03, 0x9d, 0xe1, 0xc1, 0xe5, 0x70, 0x28, 0x35, 0x1e, 0x66, 0x0f, 0x9a,
-	0x87, 0xa1, 0x18, 0x27, 0x29, 0xa6, 0x5f, 0x6e, 0x26, 0x43, 0x66, 0x39, 0x95, 0x0c, 0xa0, 0x65,
-	0xf6, 0x30, 0x2a, 0x5b, 0x00, 0x5d, 0x76, 0x5a, 0x56, 0x10, 0xef, 0xbf, 0x00, 0xde, 0xbf, 0x2f,
-	0xc0, 0x16, 0xb4, 0x31, 0x44, 0x1b, 0xd8, 0xd7, 0xaf, 0x04, 0x82, 0xdf, 0x0e, 0xac, 0x8c, 0xa4,
-	0xca, 0x8a, 0x7b, 0x3c, 0x09, 0xc5, 0xd3, 0xee, 0xb1, 0x21, 0x1a, 0xa7, 0x28, 0x7e, 0x16, 0xd9,
-	0x7b, 0xdc, 0x66, 0x36, 0x20, 0xbb, 0xe5, 0xbd, 0xb0, 0x2e, 0x58, 0xea, 0xd9, 0x82, 0x67, 0x86,
-	0xaf, 0x67, 0x58, 0x9d, 0xcf, 0x5c, 0x3d, 0x33, 0x4f, 0x9e, 0x90, 0x11, 0xa6, 0xf6, 0x31, 0x75,
-	0x11, 0x92, 0x01, 0x34, 0x22, 0x9e, 0x16, 0x37, 0xee, 0xb1, 0x11, 0x21, 0xf7, 0xaa, 0x89, 0xff,
-	0x9f, 0xbd, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x8a, 0x69, 0xff, 0xba, 0x95, 0x06, 0x00, 0x00,
+	// 878 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0x06, 0x49, 0x51, 0x3f, 0x23, 0xbb, 0x30, 0xb6, 0x41, 0xba, 0x75, 0x83, 0x42, 0xe0, 0xc9,
+	0x01, 0x1a, 0x17, 0x51, 0x7a, 0x28, 0x5a, 0xf4, 0x10, 0x27, 0x0e, 0xec, 0xfe, 0x24, 0xc2, 0x4a,
+	0xa7, 0x1e, 0x1a, 0x30, 0xe2, 0x3a, 0x25, 0x2c, 0x2d, 0xe9, 0x5d, 0x2a, 0x95, 0x6f, 0x7d, 0x91,
+	0x3e, 0x41, 0xef, 0xbd, 0xb4, 0x0f, 0x57, 0xcc, 0x2c, 0xd7, 0x5c, 0xaa, 0xb2, 0x65, 0xa0, 0xb7,
+	0x9d, 0xe1, 0x37, 0xdf, 0xcc, 0xee, 0x7c, 0x3b, 0x4b, 0x60, 0x65, 0x59, 0x98, 0xb7, 0xa6, 0x2a,
+	0x74, 0xfa, 0x5e, 0x1e, 0x97, 0xba, 0xa8, 0x0a, 0xb6, 0xe7, 0xfb, 0x92, 0x3f, 0x42, 0xd8, 0x7f,
+	0x91, 0xaa, 0x2c, 0xcf, 0xd2, 0x4a, 0x9e, 0xab, 0x8b, 0x82, 0x71, 0xe8, 0xbd, 0x94, 0x65, 0x61,
+	0xf2, 0x8a, 0x07, 0xa3, 0xe0, 0x68, 0x20, 0x9c, 0xc9, 0x46, 0x30, 0x3c, 0x59, 0x14, 0xf3, 0xcb,
+	0xd7, 0xab, 0xe5, 0x3b, 0xa9, 0x79, 0x48, 0x5f, 0x7d, 0x17, 0xc6, 0xce, 0xd6, 0xe7, 0x2a, 0x93,
+	0x6b, 0x1e, 0x8d, 0x82, 0xa3, 0x7d, 0xe1, 0x4c, 0x8c, 0x6d, 0xd2, 0x64, 0xbc, 0x63, 0x63, 0x3d,
+	0x17, 0x63, 0xd0, 0x39, 0x2b, 0x4c, 0xc5, 0x63, 0xfa, 0x44, 0x6b, 0xf4, 0x4d, 0x0a, 0x5d, 0xf1,
+	0xae, 0xf5, 0xe1, 0x9a, 0x3d, 0x80, 0xf8, 0xcd, 0x6f, 0x4a, 0x6a, 0xde, 0x23, 0xa7, 0x35, 0xd0,
+	0x7b, 0xba, 0xae, 0x74, 0xca, 0xfb, 0xd6, 0x4b, 0x06, 0x3b, 0x80, 0xe8, 0x95, 0x94, 0x7c, 0x40,
+	0xb5, 0xe0, 0x92, 0x3d, 0x84, 0xee, 0x6c, 0x7d, 0x96, 0x9a, 0x5f, 0x39, 0x10, 0xb0, 0xb6, 0xc8,
+	0x6f, 0x69, 0x87, 0xb5, 0x9f, 0xac, 0xe4, 0x67, 0xe8, 0x0a, 0x79, 0xb1, 0x52, 0xd9, 0xff, 0x3a,
+	0x97, 0x9b, 0x9a, 0x23, 0xaf, 0xe6, 0xe4, 0x5b, 0x18, 0x58, 0xee, 0xe7, 0x5a, 0xb3, 0x63, 0xa4,
+	0xbf, 0x90, 0x69, 0x65, 0x78, 0x30, 0x8a, 0x8e, 0x86, 0xe3, 0x07, 0xc7, 0xad, 0xe6, 0x59, 0xa4,
+	0x70, 0xa0, 0xe4, 0xef, 0x8e, 0xd7, 0xb8, 0x99, 0x5c, 0x96, 0xec, 0x4b, 0xe8, 0x94, 0x5a, 0xba,
+	0xf0, 0xcf, 0xda, 0xe1, 0xad, 0x1e, 0x0b, 0x02, 0xb2, 0xa7, 0x10, 0xcf, 0x57, 0x5a, 0x1b, 0x1e,
+	0xee, 0x8e, 0xb0, 0x48, 0x0c, 0x51, 0x72, 0x5d, 0x19, 0x1e, 0xdd, 0x23, 0x84, 0x90, 0x58, 0x56,
+	0xbe, 0x5c, 0x1a, 0xde, 0xb9, 0x47, 0x59, 0x08, 0x64, 0x4f, 0x20, 0xc2, 0x6d, 0xc4, 0xbb, 0xf1,
+	0x88, 0x63, 0x27, 0xd0, 0xd3, 0x74, 0x36, 0x86, 0x77, 0x29, 0xe4, 0xe8, 0x96, 0x10, 0x3c, 0xa4,
+	0xfa, 0x18, 0xcd, 0xa9, 0xaa, 0xf4, 0xb5, 0x70, 0x81, 0xec, 0x14, 0xfa, 0x8b, 0xfc, 0x83, 0x54,
+	0xd2, 0x18, 0xde, 0x23, 0x92, 0xc7, 0x77, 0x91, 0xfc, 0x58, 0x63, 0x2d, 0xcb, 0x4d, 0xe8, 0xe1,
+	0x14, 0xf6, 0x7c, 0x7e, 0x94, 0xdf, 0xa5, 0xbc, 0xae, 0xe5, 0x82, 0x4b, 0xf6, 0x04, 0xe2, 0x0f,
+	0xe9, 0x62, 0x25, 0x49, 0x24, 0xc3, 0xf1, 0x27, 0xdb, 0x7a, 0xfc, 0x5c, 0x6b, 0x61, 0x51, 0xdf,
+	0x84, 0x5f, 0x07, 0x87, 0x53, 0xd8, 0x6f, 0xe5, 0xdb, 0xc2, 0xfa, 0x45, 0x9b, 0xf5, 0x61, 0x9b,
+	0xd5, 0x45, 0x7b, 0xa4, 0xc9, 0x14, 0xe2, 0x57, 0xb9, 0x5c, 0x64, 0xde, 0x7d, 0x08, 0x5a, 0xf7,
+	0xe1, 0x11, 0x6a, 0x73, 0x99, 0xe6, 0x2a, 0x57, 0xef, 0x89, 0x76, 0x5f, 0x34, 0x0e, 0xd4, 0xf3,
+	0x44, 0xe7, 0x73, 0xe9, 0xf4, 0x4c, 0x46, 0xf2, 0x06, 0x0e, 0x66, 0xf9, 0xfc, 0x52, 0x56, 0x2f,
+	0x65, 0x29, 0x55, 0x26, 0xd5, 0x9c, 0x8a, 0x7d, 0xbd, 0x5a, 0xd6, 0x0c, 0xb8, 0x64, 0x8f, 0x21,
+	0x9e, 0xe5, 0xea, 0xa2, 0xa8, 0x25, 0xf4, 0x71, 0xbb, 0x58, 0xaa, 0x4a, 0x58, 0x44, 0xf2, 0x4f,
+	0x08, 0x60, 0x19, 0x49, 0xe0, 0x1f, 0x41, 0x38, 0xbd, 0xa2, 0x3a, 0x63, 0x11, 0x4e, 0xaf, 0xd8,
+	0x0f, 0x30, 0x6c, 0x32, 0x39, 0x81, 0x6d, 0x34, 0xae, 0x09, 0x3f, 0xf6, 0xb0, 0xb6, 0x71, 0x7e,
+	0x74, 0xb3, 0xa5, 0xd8, 0xdb, 0x12, 0x1e, 0x03, 0x2d, 0x4e, 0x52, 0x23, 0xeb, 0x29, 0xd4, 0x38,
+	0xd8, 0x77, 0xb0, 0x47, 0xc6, 0x59, 0x8e, 0xe9, 0xae, 0x6b, 0xe9, 0x7c, 0xda, 0xae, 0x80, 0x10,
+	0x42, 0xce, 0x0b, 0x9d, 0x89, 0x16, 0xfc, 0xf0, 0x17, 0x38, 0xd8, 0xac, 0x69, 0x4b, 0x73, 0xbf,
+	0x6a, 0x37, 0xf7, 0xf3, 0x6d, 0xfb, 0x6b, 0x68, 0xfc, 0x26, 0xff, 0x15, 0xc0, 0x70, 0x72, 0xf2,
+	0x76, 0x32, 0x29, 0x0c, 0x9d, 0xdf, 0x33, 0xe8, 0xbe, 0x48, 0xd5, 0x6c, 0x59, 0x12, 0xfd, 0xed,
+	0x77, 0x0b, 0xc1, 0xa2, 0x86, 0xb2, 0x31, 0xf4, 0x30, 0x07, 0x46, 0xd9, 0x02, 0xf8, 0x6d, 0x07,
+	0x2c, 0x1c, 0x70, 0x73, 0x20, 0x46, 0xff, 0x1d, 0x88, 0x8f, 0x60, 0x40, 0x26, 0x29, 0xcf, 0x3e,
+	0x06, 0x8d, 0x23, 0xf9, 0x33, 0x82, 0xfe, 0xb4, 0xd0, 0x95, 0x1b, 0x6b, 0xf3, 0x54, 0xdd, 0x6f,
+	0xac, 0x21, 0x10, 0x3b, 0xa9, 0xe5, 0x79, 0x66, 0xc7, 0xda, 0x40, 0x58, 0x83, 0x3d, 0x6d, 0xc6,
+	0x84, 0x15, 0xde, 0xad, 0x77, 0xcf, 0xe1, 0x50, 0x6f, 0xe6, 0x8a, 0xaa, 0x8b, 0x45, 0x68, 0xae,
+	0xf0, 0x05, 0x50, 0x45, 0x46, 0xd4, 0x31, 0x51, 0x3b, 0x93, 0x8d, 0xa1, 0x93, 0xc9, 0xd2, 0x0d,
+	0xa0, 0x5d, 0x2d, 0x22, 0x2c, 0x96, 0x59, 0x92, 0xe0, 0xea, 0x77, 0xac, 0x74, 0x82, 0x2b, 0x6f,
+	0x04, 0x67, 0xdf, 0xb2, 0x41, 0xe9, 0x0b, 0xae, 0xf4, 0x05, 0x37, 0xd8, 0x29, 0x38, 0x1f, 0x8e,
+	0x7d, 0x71, 0xb3, 0x0a, 0x37, 0x01, 0xb4, 0x09, 0xdf, 0xc5, 0xc6, 0xde, 0x20, 0x1c, 0x8e, 0xa2,
+	0x3b, 0x86, 0xc9, 0x0d, 0x2e, 0x99, 0xc3, 0xd0, 0x4b, 0xb9, 0xd9, 0xfc, 0x60, 0xeb, 0x6b, 0x68,
+	0xaf, 0x5a, 0xb8, 0x71, 0xd5, 0x9a, 0x89, 0x13, 0x6d, 0x4c, 0x9c, 0xe4, 0xf7, 0x00, 0xfa, 0x2e,
+	0x37, 0xa6, 0xf8, 0x29, 0x37, 0x46, 0x66, 0xd3, 0x45, 0x41, 0xef, 0x25, 0x82, 0x7d, 0x17, 0x8e,
+	0xb5, 0xef, 0xd3, 0x7c, 0x21, 0x33, 0xca, 0xd1, 0x17, 0xb5, 0x85, 0x91, 0x76, 0x45, 0xf5, 0x38,
+	0x65, 0x7a, 0x2e, 0x6c, 0xf2, 0x44, 0xaa, 0x74, 0x51, 0x5d, 0xd7, 0xba, 0x74, 0xe6, 0xbb, 0x2e,
+	0xfd, 0x3f, 0x3d, 0xfb, 0x77, 0x00, 0xee, 0x59, 0x6a, 0x05, 0x55, 0x09, 0x00, 0x00,
 }
@@ -13,6 +13,7 @@ import (
 	"sort"
 	"sync"
 	"crypto/md5"
+	"time"
 )
 
 const (
@@ -28,6 +29,9 @@ var (
 	TicketNotFindErr        	= errors.New("The Ticket not find")
 )
 
+// MaxTicketPriceHistory is the number of the latest ticket price adjustments kept
+const MaxTicketPriceHistory = 1024
+
 var ticketCache = sync.Map{}
 
 func PutTicket(txHash common.Hash, ticket *types.Ticket) {
@@ -47,6 +51,8 @@ func RemoveTicket(txHash common.Hash) {
 
 type refundStorage map[discover.NodeID]types.RefundQueue
 
+type livenessStorage map[discover.NodeID]*types.CandidateLiveness
+
 type candidate_temp struct {
 	// previous witness
 	pres types.CandidateQueue
@@ -60,6 +66,8 @@ type candidate_temp struct {
 	res types.CandidateQueue
 	// refund
 	refunds refundStorage
+	// liveness of the witnesses
+	liveness livenessStorage
 }
 
 type ticketDependency struct {
@@ -111,6 +119,12 @@ type ticket_temp struct {
 	//Ets map[string][]common.Hash
 	// ticket's attachment  of node
 	Dependencys map[discover.NodeID]*ticketDependency
+	// ticket price adjusted by the dynamic pricing, nil before the first adjustment
+	Price *big.Int
+	// configured ticket price the adjusted price derives from
+	PriceBase *big.Int
+	// the latest ticket price adjustments, oldest first
+	PriceHistory []*types.TicketPriceRecord
 }
 
 type Ppos_storage struct {
@@ -146,6 +160,7 @@ func NewPPOS_storage () *Ppos_storage {
 			imms: 	make(types.CandidateQueue, 0),
 			res: 	make(types.CandidateQueue, 0),
 			refunds: make(refundStorage, 0),
+			liveness: make(livenessStorage, 0),
 		},
 
 		t_storage: &ticket_temp{
@@ -269,6 +284,11 @@ func (p *Ppos_storage) CopyCandidateStorage ()  *candidate_temp {
 		cache[nodeId] = queue.DeepCopy()
 	}
 
+	liveness := make(livenessStorage, len(p.c_storage.liveness))
+	for nodeId, l := range p.c_storage.liveness {
+		liveness[nodeId] = copyLiveness(l)
+	}
+
 	temp := &candidate_temp{
 		pres: 		p.c_storage.pres.DeepCopy(),
 		currs: 		p.c_storage.currs.DeepCopy(),
@@ -278,6 +298,7 @@ func (p *Ppos_storage) CopyCandidateStorage ()  *candidate_temp {
 		res: 		p.c_storage.res.DeepCopy(),
 
 		refunds: 	cache,
+		liveness: 	liveness,
 	}
 
 	/*temp := new(candidate_temp)
@@ -327,6 +348,9 @@ func (p *Ppos_storage) CopyTicketStorage() *ticket_temp {
 	ticket_cache := &ticket_temp{
 		Sq: 	p.t_storage.Sq,
 		Dependencys: 	cache,
+		Price: 		copyBigInt(p.t_storage.Price),
+		PriceBase: 	copyBigInt(p.t_storage.PriceBase),
+		PriceHistory: 	copyPriceHistory(p.t_storage.PriceHistory),
 	}
 
 
@@ -433,6 +457,24 @@ func (p *Ppos_storage) GetCandidateQueue(flag int) types.CandidateQueue {
 	}
 }
 
+// Get the length of CandidateQueue without copying it
+func (p *Ppos_storage) GetCandidateQueueLen(flag int) int {
+	switch flag {
+	case PREVIOUS:
+		return len(p.c_storage.pres)
+	case CURRENT:
+		return len(p.c_storage.currs)
+	case NEXT:
+		return len(p.c_storage.nexts)
+	case IMMEDIATE:
+		return len(p.c_storage.imms)
+	case RESERVE:
+		return len(p.c_storage.res)
+	default:
+		return 0
+	}
+}
+
 // Set CandidateQueue
 func (p *Ppos_storage) SetCandidateQueue(queue types.CandidateQueue, flag int) {
 	switch flag {
@@ -517,6 +559,45 @@ func (p *Ppos_storage) DelRefunds(nodeId discover.NodeID) {
 	delete(p.c_storage.refunds, nodeId)
 }
 
+// Get the liveness of the witness, nil if there is no record
+func (p *Ppos_storage) GetLiveness(nodeId discover.NodeID) *types.CandidateLiveness {
+	if l, ok := p.c_storage.liveness[nodeId]; ok {
+		return copyLiveness(l)
+	}
+	return nil
+}
+
+// Set the liveness of the witness
+func (p *Ppos_storage) SetLiveness(nodeId discover.NodeID, liveness *types.CandidateLiveness) {
+	p.c_storage.liveness[nodeId] = copyLiveness(liveness)
+}
+
+// Delete the liveness of the witness
+func (p *Ppos_storage) DelLiveness(nodeId discover.NodeID) {
+	delete(p.c_storage.liveness, nodeId)
+}
+
+// Get the witnesses with a liveness record
+func (p *Ppos_storage) GetLivenessIds() []discover.NodeID {
+	ids := make([]discover.NodeID, 0, len(p.c_storage.liveness))
+	for nodeId := range p.c_storage.liveness {
+		ids = append(ids, nodeId)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+	return ids
+}
+
+func copyLiveness(liveness *types.CandidateLiveness) *types.CandidateLiveness {
+	return &types.CandidateLiveness{
+		MissedSlots: liveness.MissedSlots,
+		Jailed:      liveness.Jailed,
+		JailedBlock: copyBigInt(liveness.JailedBlock),
+		Penalty:     copyBigInt(liveness.Penalty),
+	}
+}
+
 /** ticket related func */
 
 // Get total remian
@@ -755,6 +836,37 @@ func (p *Ppos_storage) RemoveTicket(nodeId discover.NodeID, txHash common.Hash)
 	return ticket, nil
 }
 
+// Take count tickets off the ticket txHash of the node, the ticket is removed once it has none left
+func (p *Ppos_storage) SubTicketCount(nodeId discover.NodeID, txHash common.Hash, count uint32) (*ticketInfo, error) {
+	value := p.GetTicketDependency(nodeId)
+	if nil == value {
+		return nil, TicketNotFindErr
+	}
+	var ticket *ticketInfo
+	for _, tinfo := range value.Tinfo {
+		if tinfo.TxHash == txHash {
+			ticket = tinfo
+			break
+		}
+	}
+	if nil == ticket || ticket.Remaining < count {
+		return nil, TicketNotFindErr
+	}
+	ticket.Remaining -= count
+	value.Num -= count
+	if ticket.Remaining == 0 {
+		if list := removeTinfo(txHash, value.Tinfo); len(list) > 0 {
+			value.Tinfo = list
+		} else {
+			value.Tinfo = make([]*ticketInfo, 0)
+		}
+	}
+	if value.Num == 0 {
+		p.RemoveTicketDependency(nodeId)
+	}
+	return ticket, nil
+}
+
 func (p *Ppos_storage) GetCandidateTicketCount(nodeId discover.NodeID) uint32 {
 	if value := p.GetTicketDependency(nodeId); value != nil {
 		log.Debug("Gets the ticket count of node", "nodeId", nodeId.String(), "tcount", value.Num)
@@ -777,6 +889,58 @@ func (p *Ppos_storage) SetCandidateTicketAge(nodeId discover.NodeID, age uint64)
 	}*/
 }
 
+// Get the adjusted ticket price and the configured price it derives from,
+// both are nil before the first adjustment
+func (p *Ppos_storage) GetTicketPrice() (price, base *big.Int) {
+	return copyBigInt(p.t_storage.Price), copyBigInt(p.t_storage.PriceBase)
+}
+
+// Set the adjusted ticket price and the configured price it derives from
+func (p *Ppos_storage) SetTicketPrice(price, base *big.Int) {
+	p.t_storage.Price = copyBigInt(price)
+	p.t_storage.PriceBase = copyBigInt(base)
+}
+
+// Get the latest ticket price adjustments, oldest first
+func (p *Ppos_storage) GetTicketPriceHistory() []*types.TicketPriceRecord {
+	return copyPriceHistory(p.t_storage.PriceHistory)
+}
+
+// Append a ticket price adjustment, only the latest MaxTicketPriceHistory are kept
+func (p *Ppos_storage) AppendTicketPriceRecord(record *types.TicketPriceRecord) {
+	history := append(p.t_storage.PriceHistory, copyPriceRecord(record))
+	if len(history) > MaxTicketPriceHistory {
+		history = history[len(history)-MaxTicketPriceHistory:]
+	}
+	p.t_storage.PriceHistory = history
+}
+
+func copyBigInt(v *big.Int) *big.Int {
+	if nil == v {
+		return nil
+	}
+	return new(big.Int).Set(v)
+}
+
+func copyPriceRecord(record *types.TicketPriceRecord) *types.TicketPriceRecord {
+	return &types.TicketPriceRecord{
+		BlockNumber: copyBigInt(record.BlockNumber),
+		Price:       copyBigInt(record.Price),
+		Remaining:   record.Remaining,
+	}
+}
+
+func copyPriceHistory(history []*types.TicketPriceRecord) []*types.TicketPriceRecord {
+	if len(history) == 0 {
+		return nil
+	}
+	cpy := make([]*types.TicketPriceRecord, len(history))
+	for i, record := range history {
+		cpy[i] = copyPriceRecord(record)
+	}
+	return cpy
+}
+
 func (p *Ppos_storage) GetTicketRemainByTxHash(txHash common.Hash) uint32 {
 	//PrintObject("Call GetTicketRemainByTxHash", p.t_storage.Dependencys)
 	//log.Debug("Call GetTicketRemainByTxHash", "ticketId", txHash.Hex())
@@ -824,6 +988,7 @@ func (p *Ppos_storage) CalculateHash(blockNumber *big.Int, blockHash common.Hash
 	log.Debug("Call CalculateHash start ...", "blockNumber", blockNumber, "blockHash", blockHash.Hex())
 	start := common.NewTimer()
 	start.Begin()
+	defer calculateHashTimer.UpdateSince(time.Now())
 
 	if verifyStorageEmpty(p) {
 		return common.Hash{}, nil
@@ -887,6 +1052,30 @@ func (p *Ppos_storage) CalculateHash(blockNumber *big.Int, blockHash common.Hash
 		return nodeIdStrArr, refundArrQueue
 	}
 
+	// declare can liveness func
+	LivenessFunc := func(livenessMap livenessStorage) ([]string, []*Liveness) {
+
+		if len(livenessMap) == 0 {
+			return nil, nil
+		}
+
+		nodeIdStrArr := make([]string, 0, len(livenessMap))
+		tempMap := make(map[string]discover.NodeID, len(livenessMap))
+		for nodeId := range livenessMap {
+			nodeIdStr := nodeId.String()
+			nodeIdStrArr = append(nodeIdStrArr, nodeIdStr)
+			tempMap[nodeIdStr] = nodeId
+		}
+
+		sort.Strings(nodeIdStrArr)
+
+		livenessArr := make([]*Liveness, len(nodeIdStrArr))
+		for i, nodeIdStr := range nodeIdStrArr {
+			livenessArr[i] = buildPBliveness(livenessMap[tempMap[nodeIdStr]])
+		}
+		return nodeIdStrArr, livenessArr
+	}
+
 	// declare can dependency func
 	DependencyFunc := func(dependencys map[discover.NodeID]*ticketDependency) ([]string, []*TicketDependency) {
 
@@ -960,7 +1149,7 @@ func (p *Ppos_storage) CalculateHash(blockNumber *big.Int, blockHash common.Hash
 	sortTemp := new(SortTemp)
 
 	var wg sync.WaitGroup
-	wg.Add(7)
+	wg.Add(8)
 
 	resqueue := make([][]*CandidateInfo, 5)
 
@@ -999,6 +1188,11 @@ func (p *Ppos_storage) CalculateHash(blockNumber *big.Int, blockHash common.Hash
 		wg.Done()
 	}()
 
+	go func() {
+		sortTemp.LivenessIds, sortTemp.Liveness = LivenessFunc(p.c_storage.liveness)
+		wg.Done()
+	}()
+
 	// calculate tick dependency Hash
 	go func() {
 		dependencyNodeIdArr, dependencyArr := DependencyFunc(p.t_storage.Dependencys)
@@ -1011,6 +1205,7 @@ func (p *Ppos_storage) CalculateHash(blockNumber *big.Int, blockHash common.Hash
 
 	// assemble data
 	sortTemp.Sq = p.t_storage.Sq
+	sortTemp.Price, sortTemp.PriceBase, sortTemp.PriceHistory = buildPBprice(p.t_storage)
 
 	for _, canArr := range resqueue {
 		if len(canArr) != 0 {
@@ -23,12 +23,14 @@ var (
 
 	// The key of ppos storage in disk （leveldb）
 	PPOS_STORAGE_KEY = []byte("PPOS_STORAGE_KEY")
+
+	errInvalidPposStorage = errors.New("invalid ppos storage encoding")
 )
 
 type numTempMap map[string]hashTempMap
 type hashTempMap map[common.Hash]*Ppos_storage
 
-// Global PPOS Dependency TEMP
+// PPOS Dependency TEMP, owned by the blockchain that uses it
 type PPOS_TEMP struct {
 
 	BlockNumber 	*big.Int
@@ -45,22 +47,15 @@ type PPOS_TEMP struct {
 	lock  *sync.Mutex
 }
 
-/**
-This is a Global ppos data temp
- */
-var  ppos_temp *PPOS_TEMP
-
-
+// Create a ppos data temp backed by db and load the last committed ppos storage from it
 func NewPPosTemp(db ethdb.Database) *PPOS_TEMP {
 
 	timer := common.NewTimer()
 	timer.Begin()
 
 	log.Info("NewPPosTemp start ...")
-	if nil != ppos_temp {
-		return ppos_temp
-	}
-	ppos_temp = new(PPOS_TEMP)
+
+	ppos_temp := new(PPOS_TEMP)
 
 	ppos_temp.db = db
 
@@ -76,19 +71,19 @@ func NewPPosTemp(db ethdb.Database) *PPOS_TEMP {
 
 	if data, err := db.Get(PPOS_STORAGE_KEY); nil != err {
 		if ppos_empty_indb != err.Error() {
-			log.Error("Failed to Call NewPPosTemp to get Global ppos temp by levelDB", "err", err)
+			log.Error("Failed to Call NewPPosTemp to get ppos temp by levelDB", "err", err)
 			return ppos_temp
 		}
 	} else {
-		log.Debug("Call NewPPosTemp to Unmarshal Global ppos temp", "pb data len", len(data))
+		log.Debug("Call NewPPosTemp to Unmarshal ppos temp", "pb data len", len(data))
 
 		pb_pposTemp := new(PB_PPosTemp)
 		if err := proto.Unmarshal(data, pb_pposTemp); err != nil {
-			log.Error("Failed to Call NewPPosTemp to Unmarshal Global ppos temp", "err", err)
+			log.Error("Failed to Call NewPPosTemp to Unmarshal ppos temp", "err", err)
 			return ppos_temp
 		}else {
 			/**
-			build global ppos_temp
+			build ppos_temp
 			 */
 
 			 // TODO
@@ -119,13 +114,10 @@ func NewPPosTemp(db ethdb.Database) *PPOS_TEMP {
 	return ppos_temp
 }
 
-func GetPPosTempPtr() *PPOS_TEMP {
-	return ppos_temp
-}
-
-
-func BuildPposCache(blockNumber *big.Int, blockHash common.Hash) *Ppos_storage {
-	return ppos_temp.getPposCacheFromTemp(blockNumber, blockHash)
+// Build a ppos storage cache by block from the temp,
+// a nil temp always yields an empty ppos storage
+func (temp *PPOS_TEMP) BuildPposCache(blockNumber *big.Int, blockHash common.Hash) *Ppos_storage {
+	return temp.getPposCacheFromTemp(blockNumber, blockHash)
 }
 
 
@@ -137,7 +129,7 @@ func (temp *PPOS_TEMP) getPposCacheFromTemp(blockNumber *big.Int, blockHash comm
 	notGenesisBlock := blockNumber.Cmp(big.NewInt(0)) > 0
 
 	if nil == temp && notGenesisBlock {
-		log.Warn("Warn Call getPposCacheFromTemp of PPOS_TEMP, the Global PPOS_TEMP instance is nil !!!!!!!!!!!!!!!", "blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex())
+		log.Warn("Warn Call getPposCacheFromTemp of PPOS_TEMP, the PPOS_TEMP instance is nil !!!!!!!!!!!!!!!", "blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex())
 		return ppos_storage
 	}
 
@@ -301,6 +293,7 @@ func (temp *PPOS_TEMP) Commit2DB(blockNumber *big.Int, blockHash common.Hash) er
 
 				temp.BlockNumber = blockNumber
 				temp.BlockHash = blockHash
+				commitBytesHistogram.Update(int64(len(data)))
 
 			}
 			log.Info("Call Commit2DB, write ppos storage data to disk", "blockNumber", blockNumber, "blockHash", blockHash.Hex(), "data len", len(data), "dataMD5", md5.Sum(data), "Time spent", fmt.Sprintf("%v ms", start.End()))
@@ -318,15 +311,15 @@ func  (temp *PPOS_TEMP) GetPPosStorageProto() (common.Hash, []byte, error) {
 			log.Debug("Call GetPPosStorageProto, ppos storage is empty in disk ...")
 			return common.Hash{}, nil, nil
 		}else {
-			log.Warn("Failed to Call GetPPosStorageProto to get Global ppos temp by levelDB", "err", err)
+			log.Warn("Failed to Call GetPPosStorageProto to get ppos temp by levelDB", "err", err)
 			return common.Hash{}, nil, err
 		}
 	} else {
-		log.Debug("Call GetPPosStorageProto to Unmarshal Global ppos temp", "pb data len", len(data))
+		log.Debug("Call GetPPosStorageProto to Unmarshal ppos temp", "pb data len", len(data))
 
 		pb_pposTemp := new(PB_PPosTemp)
 		if err := proto.Unmarshal(data, pb_pposTemp); err != nil {
-			log.Error("Failed to Call GetPPosStorageProto to Unmarshal Global ppos temp", "err", err)
+			log.Error("Failed to Call GetPPosStorageProto to Unmarshal ppos temp", "err", err)
 			return common.Hash{}, nil, err
 		}else {
 			// TODO
@@ -352,11 +345,11 @@ func (temp *PPOS_TEMP) PushPPosStorageProto(data []byte)  error {
 	start.Begin()
 	pb_pposTemp := new(PB_PPosTemp)
 	if err := proto.Unmarshal(data, pb_pposTemp); err != nil {
-		log.Error("Failed to Call PushPPosStorageProto to Unmarshal Global ppos temp", "err", err)
+		log.Error("Failed to Call PushPPosStorageProto to Unmarshal ppos temp", "err", err)
 		return err
 	}else {
 		/**
-		build global ppos_temp
+		build ppos_temp
 		 */
 		 // TODO
 
@@ -366,9 +359,9 @@ func (temp *PPOS_TEMP) PushPPosStorageProto(data []byte)  error {
 
 		var hashMap map[common.Hash]*Ppos_storage
 
-		ppos_temp.lock.Lock()
+		temp.lock.Lock()
 
-		if hashData, ok := ppos_temp.TempMap[pb_pposTemp.BlockNumber]; ok {
+		if hashData, ok := temp.TempMap[pb_pposTemp.BlockNumber]; ok {
 			hashMap = hashData
 		}else {
 			hashMap = make(map[common.Hash]*Ppos_storage, 1)
@@ -380,11 +373,11 @@ func (temp *PPOS_TEMP) PushPPosStorageProto(data []byte)  error {
 
 		blockHash := common.HexToHash(pb_pposTemp.BlockHash)
 		hashMap[blockHash] = pposStorage
-		ppos_temp.TempMap[pb_pposTemp.BlockNumber] = hashMap
+		temp.TempMap[pb_pposTemp.BlockNumber] = hashMap
 
 
 
-		ppos_temp.lock.Unlock()
+		temp.lock.Unlock()
 
 
 		// flush data into disk
@@ -425,7 +418,7 @@ func buildPBStorage(blockNumber *big.Int, blockHash common.Hash, ps *Ppos_storag
 		canTemp := new(CandidateTemp)
 
 
-		wg.Add(6)
+		wg.Add(7)
 		// previous witness
 		go func() {
 			if queue := buildPBcanqueue("buildPBStorage pres", ps.c_storage.pres); len(queue) != 0 {
@@ -476,6 +469,15 @@ func buildPBStorage(blockNumber *big.Int, blockHash common.Hash, ps *Ppos_storag
 			wg.Done()
 		}()
 
+		// liveness
+		go func() {
+			if livenessMap := buildPBlivenessMap(ps.c_storage.liveness); len(livenessMap) != 0 {
+				canTemp.Liveness = livenessMap
+				empty |= 1
+			}
+			wg.Done()
+		}()
+
 		wg.Wait()
 		ppos_temp.CanTmp = canTemp
 	}
@@ -523,6 +525,11 @@ func buildPBStorage(blockNumber *big.Int, blockHash common.Hash, ps *Ppos_storag
 		//}()
 
 		//wg.Wait()
+
+		// ticket price
+		if tickTemp.Price, tickTemp.PriceBase, tickTemp.PriceHistory = buildPBprice(ps.t_storage); tickTemp.Price != "" || len(tickTemp.PriceHistory) != 0 {
+			empty |= 1
+		}
 		ppos_temp.TickTmp = tickTemp
 	}
 
@@ -614,6 +621,20 @@ func unmarshalPBStorage(pb_temp *PB_PPosTemp) *Ppos_storage {
 		}
 
 		canTemp.refunds = defeatMap
+
+		// liveness
+		livenessMap := make(livenessStorage, len(canGlobalTemp.Liveness))
+		for nodeId, l := range canGlobalTemp.Liveness {
+			jailedBlock, _ := new(big.Int).SetString(l.JailedBlock, 10)
+			penalty, _ := new(big.Int).SetString(l.Penalty, 10)
+			livenessMap[discover.MustHexID(nodeId)] = &types.CandidateLiveness{
+				MissedSlots: l.MissedSlots,
+				Jailed:      l.Jailed,
+				JailedBlock: jailedBlock,
+				Penalty:     penalty,
+			}
+		}
+		canTemp.liveness = livenessMap
 		ppos_storage.c_storage = canTemp
 	}
 
@@ -715,12 +736,69 @@ func unmarshalPBStorage(pb_temp *PB_PPosTemp) *Ppos_storage {
 		}
 
 		tickTemp.Dependencys = dependencyMap
+
+		// ticket price
+		tickTemp.Price, _ = new(big.Int).SetString(tickGlobalTemp.Price, 10)
+		tickTemp.PriceBase, _ = new(big.Int).SetString(tickGlobalTemp.PriceBase, 10)
+		if len(tickGlobalTemp.PriceHistory) != 0 {
+			history := make([]*types.TicketPriceRecord, len(tickGlobalTemp.PriceHistory))
+			for i, record := range tickGlobalTemp.PriceHistory {
+				num, _ := new(big.Int).SetString(record.BlockNumber, 10)
+				price, _ := new(big.Int).SetString(record.Price, 10)
+				history[i] = &types.TicketPriceRecord{
+					BlockNumber: 	num,
+					Price: 			price,
+					Remaining: 		record.Remaining,
+				}
+			}
+			tickTemp.PriceHistory = history
+		}
 		ppos_storage.t_storage = tickTemp
 	}
 
 	return ppos_storage
 }
 
+// Encode a ppos storage of a block in the format of the disk, for the light clients.
+// An empty ppos storage is encoded to nil
+func EncodePposStorage(storage *Ppos_storage) ([]byte, error) {
+	if nil == storage || verifyStorageEmpty(storage) {
+		return nil, nil
+	}
+	pb_temp := buildPBStorage(big.NewInt(0), common.Hash{}, storage)
+	if nil == pb_temp {
+		return nil, nil
+	}
+	return proto.Marshal(pb_temp)
+}
+
+// Decode a ppos storage encoded by EncodePposStorage, nil data decodes to an empty ppos storage.
+// The data may come from an untrusted peer, so an invalid node id is an error rather than a panic
+func DecodePposStorage(data []byte) (storage *Ppos_storage, err error) {
+	if len(data) == 0 {
+		return NewPPOS_storage(), nil
+	}
+	pb_temp := new(PB_PPosTemp)
+	if err := proto.Unmarshal(data, pb_temp); nil != err {
+		return nil, err
+	}
+	defer func() {
+		if r := recover(); nil != r {
+			storage, err = nil, errInvalidPposStorage
+		}
+	}()
+	storage = unmarshalPBStorage(pb_temp)
+
+	empty := NewPPOS_storage()
+	if nil == storage.c_storage {
+		storage.c_storage = empty.c_storage
+	}
+	if nil == storage.t_storage {
+		storage.t_storage = empty.t_storage
+	}
+	return storage, nil
+}
+
 func buildPBcanqueue (title string, canQqueue types.CandidateQueue) []*CandidateInfo {
 
 	PrintObject(title + " ,buildPBcanqueue:", canQqueue)
@@ -784,6 +862,32 @@ func buildPBrefunds(refunds refundStorage) map[string]*RefundArr {
 }
 
 
+func buildPBlivenessMap(liveness livenessStorage) map[string]*Liveness {
+	if len(liveness) == 0 {
+		return nil
+	}
+	livenessMap := make(map[string]*Liveness, len(liveness))
+	for nodeId, l := range liveness {
+		livenessMap[nodeId.String()] = buildPBliveness(l)
+	}
+	return livenessMap
+}
+
+func buildPBliveness(liveness *types.CandidateLiveness) *Liveness {
+	l := &Liveness{
+		MissedSlots: liveness.MissedSlots,
+		Jailed:      liveness.Jailed,
+	}
+	if nil != liveness.JailedBlock {
+		l.JailedBlock = liveness.JailedBlock.String()
+	}
+	if nil != liveness.Penalty {
+		l.Penalty = liveness.Penalty.String()
+	}
+	return l
+}
+
+
 //func buildPBticketMap(tickets map[common.Hash]*types.Ticket) map[string]*TicketInfo {
 //	if len(tickets) == 0 {
 //		return nil
@@ -877,6 +981,30 @@ func buildPBdependencys(dependencys map[discover.NodeID]*ticketDependency) map[s
 	return pb_dependency
 }
 
+// buildPBprice converts the ticket price of the storage, an unset price converts
+// to empty values so that the encoding of a storage without price is unchanged
+func buildPBprice(tickets *ticket_temp) (string, string, []*PriceRecord) {
+	var price, base string
+	if nil != tickets.Price {
+		price = tickets.Price.String()
+	}
+	if nil != tickets.PriceBase {
+		base = tickets.PriceBase.String()
+	}
+	if len(tickets.PriceHistory) == 0 {
+		return price, base, nil
+	}
+	history := make([]*PriceRecord, len(tickets.PriceHistory))
+	for i, record := range tickets.PriceHistory {
+		history[i] = &PriceRecord{
+			BlockNumber: 	record.BlockNumber.String(),
+			Price: 			record.Price.String(),
+			Remaining: 		record.Remaining,
+		}
+	}
+	return price, base, history
+}
+
 func (temp *PPOS_TEMP) deleteAnyTemp (blockNumber, blockInterval *big.Int, blockHash common.Hash) {
 
 	// delete font any data
@@ -923,7 +1051,8 @@ func verifyStorageEmpty(storage *Ppos_storage) bool {
 	canStorage := storage.c_storage
 	if nil != canStorage {
 		if len(canStorage.pres) == 0 && len(canStorage.currs) == 0 && len(canStorage.nexts) == 0 &&
-			len(canStorage.imms) == 0 && len(canStorage.res) == 0 && len(canStorage.refunds) == 0 {
+			len(canStorage.imms) == 0 && len(canStorage.res) == 0 && len(canStorage.refunds) == 0 &&
+			len(canStorage.liveness) == 0 {
 			canEmpty = true
 		}
 	}
@@ -931,7 +1060,8 @@ func verifyStorageEmpty(storage *Ppos_storage) bool {
 	tickStorage := storage.t_storage
 	if nil != tickStorage {
 		if tickStorage.Sq == -1 && /*len(tickStorage.Infos) == 0 &&
-			len(tickStorage.Ets) == 0 &&*/ len(tickStorage.Dependencys) == 0 {
+			len(tickStorage.Ets) == 0 &&*/ len(tickStorage.Dependencys) == 0 &&
+			nil == tickStorage.Price && len(tickStorage.PriceHistory) == 0 {
 			tickEmpty = true
 		}
 	}
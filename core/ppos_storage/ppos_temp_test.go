@@ -1,5 +1,53 @@
 package ppos_storage
 
+import (
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+func TestTicketPriceStorage(t *testing.T) {
+	storage := NewPPOS_storage()
+	emptyHash, _ := storage.CalculateHash(big.NewInt(1), common.Hash{})
+
+	storage.SetTicketPrice(big.NewInt(90), big.NewInt(100))
+	for i := 0; i < MaxTicketPriceHistory+1; i++ {
+		storage.AppendTicketPriceRecord(&types.TicketPriceRecord{BlockNumber: big.NewInt(int64(i)), Price: big.NewInt(90), Remaining: 5})
+	}
+	if history := storage.GetTicketPriceHistory(); len(history) != MaxTicketPriceHistory || history[0].BlockNumber.Int64() != 1 {
+		t.Fatalf("history mismatch: length %d", len(history))
+	}
+	if hash, _ := storage.CalculateHash(big.NewInt(1), common.Hash{}); hash == emptyHash {
+		t.Fatalf("the price is not part of the storage hash")
+	}
+
+	data, err := EncodePposStorage(storage)
+	if err != nil {
+		t.Fatalf("EncodePposStorage fail: %v", err)
+	}
+	decoded, err := DecodePposStorage(data)
+	if err != nil {
+		t.Fatalf("DecodePposStorage fail: %v", err)
+	}
+	price, base := decoded.GetTicketPrice()
+	if price.Cmp(big.NewInt(90)) != 0 || base.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("price mismatch: have %v from %v, want 90 from 100", price, base)
+	}
+	history := decoded.GetTicketPriceHistory()
+	if len(history) != MaxTicketPriceHistory || history[len(history)-1].BlockNumber.Int64() != MaxTicketPriceHistory || history[0].Remaining != 5 {
+		t.Fatalf("decoded history mismatch: length %d", len(history))
+	}
+	// the copies don't share the price
+	cpy := decoded.Copy()
+	cpy.SetTicketPrice(big.NewInt(1), big.NewInt(1))
+	if price, _ := decoded.GetTicketPrice(); price.Cmp(big.NewInt(90)) != 0 {
+		t.Fatalf("price of the copy is shared")
+	}
+}
+
 //func TestData(t *testing.T) {
 //	ldb, err := ethdb.NewLDBDatabase("E:/platon-data/platon/ppos_storage", 0, 0)
 //	if err!=nil {
@@ -342,3 +390,42 @@ package ppos_storage
 //		t.Log("Test Commit2DB efficiency", "startTime", startTime, "endTime", endTime, "time", endTime/1e6-startTime/1e6)
 //	}
 //}
+
+func TestLivenessStorage(t *testing.T) {
+	storage := NewPPOS_storage()
+	emptyHash, _ := storage.CalculateHash(big.NewInt(1), common.Hash{})
+
+	jailedId, countedId := discover.NodeID{1}, discover.NodeID{2}
+	storage.SetLiveness(jailedId, &types.CandidateLiveness{MissedSlots: 3, Jailed: true, JailedBlock: big.NewInt(9), Penalty: big.NewInt(300)})
+	storage.SetLiveness(countedId, &types.CandidateLiveness{MissedSlots: 1, JailedBlock: big.NewInt(0), Penalty: big.NewInt(0)})
+	if ids := storage.GetLivenessIds(); len(ids) != 2 || ids[0] != jailedId || ids[1] != countedId {
+		t.Fatalf("liveness ids mismatch: %v", ids)
+	}
+	if hash, _ := storage.CalculateHash(big.NewInt(1), common.Hash{}); hash == emptyHash {
+		t.Fatalf("the liveness is not part of the storage hash")
+	}
+
+	data, err := EncodePposStorage(storage)
+	if err != nil {
+		t.Fatalf("EncodePposStorage fail: %v", err)
+	}
+	decoded, err := DecodePposStorage(data)
+	if err != nil {
+		t.Fatalf("DecodePposStorage fail: %v", err)
+	}
+	liveness := decoded.GetLiveness(jailedId)
+	if liveness == nil || liveness.MissedSlots != 3 || !liveness.Jailed || liveness.JailedBlock.Cmp(big.NewInt(9)) != 0 || liveness.Penalty.Cmp(big.NewInt(300)) != 0 {
+		t.Fatalf("decoded liveness mismatch: %+v", liveness)
+	}
+	// the copies don't share the liveness
+	cpy := decoded.Copy()
+	cpy.DelLiveness(jailedId)
+	if decoded.GetLiveness(jailedId) == nil {
+		t.Fatalf("liveness of the copy is shared")
+	}
+	decoded.DelLiveness(jailedId)
+	decoded.DelLiveness(countedId)
+	if hash, _ := decoded.CalculateHash(big.NewInt(1), common.Hash{}); hash != emptyHash {
+		t.Fatalf("hash mismatch without liveness: have %x, want %x", hash, emptyHash)
+	}
+}
@@ -276,6 +276,46 @@ func WriteReceipts(db DatabaseWriter, hash common.Hash, number uint64, receipts
 	}
 }
 
+// ReadSystemLogs retrieves the logs a block emitted outside of its transactions.
+func ReadSystemLogs(db DatabaseReader, hash common.Hash, number uint64) []*types.Log {
+	data, _ := db.Get(blockSystemLogsKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	storageLogs := []*types.LogForStorage{}
+	if err := rlp.DecodeBytes(data, &storageLogs); err != nil {
+		log.Error("Invalid system log array RLP", "hash", hash, "err", err)
+		return nil
+	}
+	logs := make([]*types.Log, len(storageLogs))
+	for i, l := range storageLogs {
+		logs[i] = (*types.Log)(l)
+	}
+	return logs
+}
+
+// WriteSystemLogs stores the logs a block emitted outside of its transactions.
+func WriteSystemLogs(db DatabaseWriter, hash common.Hash, number uint64, logs []*types.Log) {
+	storageLogs := make([]*types.LogForStorage, len(logs))
+	for i, l := range logs {
+		storageLogs[i] = (*types.LogForStorage)(l)
+	}
+	bytes, err := rlp.EncodeToBytes(storageLogs)
+	if err != nil {
+		log.Crit("Failed to encode block system logs", "err", err)
+	}
+	if err := db.Put(blockSystemLogsKey(number, hash), bytes); err != nil {
+		log.Crit("Failed to store block system logs", "err", err)
+	}
+}
+
+// DeleteSystemLogs removes the system logs of a block.
+func DeleteSystemLogs(db DatabaseDeleter, hash common.Hash, number uint64) {
+	if err := db.Delete(blockSystemLogsKey(number, hash)); err != nil {
+		log.Crit("Failed to delete block system logs", "err", err)
+	}
+}
+
 // ReadBlockConfirmSigns retrieves all the block confirmSigns belonging to a block.
 func ReadBlockConfirmSigns(db DatabaseReader, hash common.Hash, number uint64) []*common.BlockConfirmSign {
 	data, _ := db.Get(blockConfirmSignsKey(number, hash))
@@ -324,7 +364,7 @@ func ReadBlock(db DatabaseReader, hash common.Hash, number uint64) *types.Block
 	if body == nil {
 		return nil
 	}
-	return types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles, body.Signatures)
+	return types.NewBlockWithHeader(header).WithFullBody(body)
 }
 
 // WriteBlock serializes a block into the database, header and body separately.
@@ -336,6 +376,7 @@ func WriteBlock(db DatabaseWriter, block *types.Block) {
 // DeleteBlock removes all block data associated with a hash.
 func DeleteBlock(db DatabaseDeleter, hash common.Hash, number uint64) {
 	DeleteReceipts(db, hash, number)
+	DeleteSystemLogs(db, hash, number)
 	DeleteHeader(db, hash, number)
 	DeleteBody(db, hash, number)
 }
@@ -19,6 +19,7 @@ package rawdb
 import (
 	"bytes"
 	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
@@ -294,3 +295,31 @@ func TestBlockReceiptStorage(t *testing.T) {
 		t.Fatalf("deleted receipts returned: %v", rs)
 	}
 }
+
+// Tests that the system logs of a block are stored with all their fields.
+func TestBlockSystemLogStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	logs := []*types.Log{
+		{Address: common.BytesToAddress([]byte{0x11}), Topics: []common.Hash{common.HexToHash("dead"), common.HexToHash("beef")}, Data: []byte{0x01, 0x00, 0xff}, BlockNumber: 7, TxIndex: 2, BlockHash: common.HexToHash("0x0314"), Index: 4},
+		{Address: common.BytesToAddress([]byte{0x22}), Topics: []common.Hash{common.HexToHash("dad")}, Data: []byte{0x02}, BlockNumber: 7, TxIndex: 2, BlockHash: common.HexToHash("0x0314"), Index: 5},
+	}
+	hash := common.HexToHash("0x0314")
+	if ls := ReadSystemLogs(db, hash, 7); len(ls) != 0 {
+		t.Fatalf("non existent system logs returned: %v", ls)
+	}
+	WriteSystemLogs(db, hash, 7, logs)
+	ls := ReadSystemLogs(db, hash, 7)
+	if len(ls) != len(logs) {
+		t.Fatalf("system logs count mismatch: have %d, want %d", len(ls), len(logs))
+	}
+	for i := range logs {
+		if !reflect.DeepEqual(ls[i], logs[i]) {
+			t.Fatalf("system log #%d mismatch: have %v, want %v", i, ls[i], logs[i])
+		}
+	}
+	DeleteSystemLogs(db, hash, 7)
+	if ls := ReadSystemLogs(db, hash, 7); len(ls) != 0 {
+		t.Fatalf("deleted system logs returned: %v", ls)
+	}
+}
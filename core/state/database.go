@@ -21,6 +21,7 @@ import (
 	"sync"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/ppos_storage"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/trie"
 	"github.com/hashicorp/golang-lru"
@@ -59,6 +60,9 @@ type Database interface {
 	TrieDB() *trie.Database
 
 	ContractAbi(addrHash, abiHash common.Hash) ([]byte, error)
+
+	// PPosTemp retrieves the ppos storage temp the state caches are built from.
+	PPosTemp() *ppos_storage.PPOS_TEMP
 }
 
 // Trie is a Ethereum Merkle Trie.
@@ -79,11 +83,18 @@ type Trie interface {
 // intermediate trie-node memory pool between the low level storage layer and the
 // high level trie abstraction.
 func NewDatabase(db ethdb.Database) Database {
+	return NewDatabaseWithPPos(db, nil)
+}
+
+// NewDatabaseWithPPos creates a backing store for state whose ppos caches are
+// read from the given ppos storage temp.
+func NewDatabaseWithPPos(db ethdb.Database, pposTemp *ppos_storage.PPOS_TEMP) Database {
 	//LRU
 	csc, _ := lru.New(codeSizeCacheSize)
 	return &cachingDB{
 		db:            trie.NewDatabase(db),
 		codeSizeCache: csc,
+		pposTemp:      pposTemp,
 	}
 }
 
@@ -92,6 +103,7 @@ type cachingDB struct {
 	mu            sync.Mutex
 	pastTries     []*trie.SecureTrie
 	codeSizeCache *lru.Cache
+	pposTemp      *ppos_storage.PPOS_TEMP
 }
 
 //OpenTrie opens the main account trie.
@@ -170,6 +182,11 @@ func (db *cachingDB) TrieDB() *trie.Database {
 	return db.db
 }
 
+// PPosTemp retrieves the ppos storage temp of the database.
+func (db *cachingDB) PPosTemp() *ppos_storage.PPOS_TEMP {
+	return db.pposTemp
+}
+
 // cachedTrie inserts its trie into a cachingDB on commit.
 type cachedTrie struct {
 	*trie.SecureTrie
@@ -106,7 +106,7 @@ func New(root common.Hash, db Database, blocknumber *big.Int, blockhash common.H
 		logs:              make(map[common.Hash][]*types.Log),
 		preimages:         make(map[common.Hash][]byte),
 		journal:           newJournal(),
-		pposCache:   	   ppos_storage.BuildPposCache(blocknumber, blockhash),
+		pposCache:   	   db.PPosTemp().BuildPposCache(blocknumber, blockhash),
 	}, nil
 }
 
@@ -779,6 +779,12 @@ func (self *StateDB) GetPPOSCache() *ppos_storage.Ppos_storage {
 	return self.pposCache
 }
 
+// SetPPOSCache replaces the ppos storage of the state, it is used by the light
+// clients whose database holds no ppos storage.
+func (self *StateDB) SetPPOSCache(storage *ppos_storage.Ppos_storage) {
+	self.pposCache = storage
+}
+
 func (self *StateDB) SnapShotPPOSCache() *ppos_storage.Ppos_storage {
 	return self.pposCache.Copy()
 }
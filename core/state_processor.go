@@ -22,12 +22,14 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/consensus"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/misc"
+	"github.com/PlatONnetwork/PlatON-Go/core/ppos"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"math/big"
 	"sync"
 )
@@ -86,6 +88,9 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		allLogs = append(allLogs, receipt.Logs...)
 	}
 
+	// the logs emitted outside of the transactions are the system logs of the block
+	statedb.Prepare(types.SystemLogTxHash, block.Hash(), len(block.Transactions()))
+
 	if cbftEngine, ok := p.bc.engine.(consensus.Bft); ok {
 		// Notify call
 		if err := cbftEngine.Notify(statedb, block.Number()); err != nil {
@@ -104,14 +109,33 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 			if !cbftEngine.Switch(statedb, block.Number()) {
 				log.Error("---Failed to SwitchWitness call when processing block:---", "number", block.Number(), "hash", block.Hash())
 			}
+			// The switch block commits to the consensus nodes of the next round
+			if p.config.IsWitnessCommit(block.Number()) {
+				parentNumber := new(big.Int).Sub(block.Number(), common.Big1)
+				hash, err := SwitchWitnessHash(cbftEngine, statedb, parentNumber, block.ParentHash(), block.Number())
+				if err != nil {
+					return nil, nil, 0, err
+				}
+				if remote := types.HeaderWitnessHash(header); remote != hash {
+					return nil, nil, 0, fmt.Errorf("invalid witness hash (remote: %x local: %x)", remote, hash)
+				}
+			}
 
 		}
+		// Liveness of the consensus nodes
+		if p.config.IsLiveness(block.Number()) {
+			if err := cbftEngine.RecordLiveness(p.bc, statedb, header); err != nil {
+				log.Error("---Failed to RecordLiveness call when processing block:---", "err", err, "number", block.Number(), "hash", block.Hash())
+				return nil, nil, 0, err
+			}
+		}
 		// ppos Store Hash
 		cbftEngine.StoreHash(statedb, block.Number(), block.Hash())
 	}
 
 	// Finalize the block, applying any consensus engine specific extras (e.g. block rewards)
 	p.engine.Finalize(p.bc, header, statedb, block.Transactions(), block.Uncles(), receipts)
+	allLogs = append(allLogs, statedb.GetLogs(types.SystemLogTxHash)...)
 
 	if cbftEngine, ok := p.bc.engine.(consensus.Bft); ok {
 		// SetNodeCache
@@ -124,6 +148,25 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	return receipts, allLogs, *usedGas, nil
 }
 
+// SwitchWitnessHash returns the commitment of the switch block of number to the
+// consensus nodes of the next round, it is called on the state after the switch.
+// Without a new round elected, the next round of the parent goes on.
+func SwitchWitnessHash(bft consensus.Bft, statedb *state.StateDB, parentNumber *big.Int, parentHash common.Hash, number *big.Int) (common.Hash, error) {
+	nodes, err := bft.GetWitness(statedb, pposm.CURRENT_C, number)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var ids []discover.NodeID
+	if len(nodes) > 0 {
+		for _, node := range nodes {
+			ids = append(ids, node.ID)
+		}
+	} else {
+		ids = bft.ConsensusNodes(parentNumber, parentHash, new(big.Int).Add(number, common.Big1))
+	}
+	return types.WitnessHash(ids), nil
+}
+
 // ApplyTransaction attempts to apply a transaction to the given state database
 // and uses the input parameters for its environment. It returns the receipt
 // for the transaction, gas used and an error if the transaction failed,
@@ -25,7 +25,6 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/params"
-	"github.com/PlatONnetwork/PlatON-Go/core/ppos"
 )
 
 var (
@@ -108,9 +107,6 @@ func IntrinsicGas(data []byte, contractCreation, homestead bool) (uint64, error)
 
 // NewStateTransition initialises and returns a new state transition object.
 func NewStateTransition(evm *vm.EVM, msg Message, gp *GasPool) *StateTransition {
-	//ppos
-	evm.CandidatePoolContext = pposm.GetCandidateContextPtr()
-	evm.TicketPoolContext = pposm.GetTicketPoolContextPtr()
 	return &StateTransition{
 		gp:       gp,
 		evm:      evm,
@@ -50,9 +50,6 @@ type TicketTempCache struct {
 	lock  *sync.Mutex
 }
 
-// global obj of ticket related
-var ticketTemp *TicketTempCache
-
 //func NewTicketIdsCache(db ethdb.Database) *NumBlocks {
 func NewTicketIdsCache(db ethdb.Database) *TicketTempCache {
 	/*
@@ -62,10 +59,7 @@ func NewTicketIdsCache(db ethdb.Database) *TicketTempCache {
 	//logInfo("NewTicketIdsCache==> Init ticketidsCache call NewTicketIdsCache func")
 	timer := Timer{}
 	timer.Begin()
-	if nil != ticketTemp {
-		return ticketTemp
-	}
-	ticketTemp = &TicketTempCache{
+	ticketTemp := &TicketTempCache{
 		Cache: &NumBlocks{
 			NBlocks: make(map[string]*BlockNodes),
 		},
@@ -74,12 +68,12 @@ func NewTicketIdsCache(db ethdb.Database) *TicketTempCache {
 	}
 
 	if cache, err := db.Get(ticketPoolCacheKey); nil != err {
-		log.Warn("Warn call ticketcache NewTicketIdsCache to get Global Cache by levelDB", "err", err)
+		log.Warn("Warn call ticketcache NewTicketIdsCache to get Cache by levelDB", "err", err)
 	} else {
-		log.Info("Call ticketcache NewTicketIdsCache to Unmarshal Global Cache", "Cachelen", len(cache))
+		log.Info("Call ticketcache NewTicketIdsCache to Unmarshal Cache", "Cachelen", len(cache))
 		//if err := proto.Unmarshal(cache, ticketidsCache); err != nil {
 		if err := proto.Unmarshal(cache, ticketTemp.Cache); err != nil {
-			log.Error("Failed call NewTicketIdsCache to Unmarshal Global Cache", "err", err)
+			log.Error("Failed call NewTicketIdsCache to Unmarshal Cache", "err", err)
 			return ticketTemp
 		}
 	}
@@ -87,25 +81,6 @@ func NewTicketIdsCache(db ethdb.Database) *TicketTempCache {
 	return ticketTemp
 }
 
-// Create a ticket cache by blocknumber and blockHash from global temp
-func GetNodeTicketsCacheMap(blocknumber *big.Int, blockhash common.Hash) (ret TicketCache) {
-	log.Debug("Call ticketcache GetNodeTicketsCacheMap", "blocknumber", blocknumber, "blockhash", blockhash.Hex())
-	if ticketTemp != nil {
-
-		// getting a ticket cache by blocknumber and blockHash from global temp
-		ret = ticketTemp.GetNodeTicketsMap(blocknumber, blockhash)
-	} else {
-		if blocknumber.Cmp(big.NewInt(0)) > 0 {
-			log.Warn("Warn call ticketcache GetNodeTicketsCacheMap, the Global ticketTemp instance is nil !!!!!!!!!!!!!!!", "blocknumber", blocknumber.Uint64(), "blockHash", blockhash.Hex())
-		}
-	}
-	return
-}
-
-func GetTicketidsCachePtr() *TicketTempCache {
-	return ticketTemp
-}
-
 func Hash(cache TicketCache) (common.Hash, error) {
 
 	if len(cache) == 0 {
@@ -197,6 +197,18 @@ func (m *txSortedMap) Len() int {
 	return len(m.items)
 }
 
+// PposPrefixLen returns the number of transactions of the list up to its last PPOS
+// transaction of all, those before a PPOS transaction are needed to execute it.
+func (l *txList) PposPrefixLen(all *txLookup) int {
+	txs := l.txs.Flatten()
+	for i := len(txs) - 1; i >= 0; i-- {
+		if all.IsPpos(txs[i].Hash()) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
 // Flatten creates a nonce-sorted slice of transactions based on the loosely
 // sorted internal representation. The result of the sorting is cached in case
 // it's requested again before any modifications are made to the contents.
@@ -356,6 +368,17 @@ func (l *txList) Empty() bool {
 	return l.Len() == 0
 }
 
+// PposCount returns the number of PPOS transactions of all in the list.
+func (l *txList) PposCount(all *txLookup) int {
+	count := 0
+	for _, tx := range l.txs.items {
+		if all.IsPpos(tx.Hash()) {
+			count++
+		}
+	}
+	return count
+}
+
 // Flatten creates a nonce-sorted slice of transactions based on the loosely
 // sorted internal representation. The result of the sorting is cached in case
 // it's requested again before any modifications are made to the contents.
@@ -505,8 +528,8 @@ func (l *txPricedList) Discard(count int, local *accountSet) types.Transactions
 			l.stales--
 			continue
 		}
-		// Non stale transaction found, discard unless local
-		if local.containsTx(tx) {
+		// Non stale transaction found, discard unless local or ppos
+		if local.containsTx(tx) || l.all.IsPpos(tx.Hash()) {
 			save = append(save, tx)
 		} else {
 			drop = append(drop, tx)
@@ -518,3 +541,33 @@ func (l *txPricedList) Discard(count int, local *accountSet) types.Transactions
 	}
 	return drop
 }
+
+// DiscardPpos finds the most underpriced remote PPOS transaction, removes it from
+// the priced list and returns it for further removal from the entire pool. It is
+// only discarded if it is cheaper than tx, or whatever its price if force is set.
+func (l *txPricedList) DiscardPpos(tx *types.Transaction, force bool, local *accountSet) *types.Transaction {
+	var drop *types.Transaction
+	save := make(types.Transactions, 0, 64) // Local or ordinary underpriced transactions to keep
+
+	for len(*l.items) > 0 {
+		// Discard stale transactions if found during cleanup
+		cheapest := heap.Pop(l.items).(*types.Transaction)
+		if l.all.Get(cheapest.Hash()) == nil {
+			l.stales--
+			continue
+		}
+		save = append(save, cheapest)
+		if local.containsTx(cheapest) || !l.all.IsPpos(cheapest.Hash()) {
+			continue
+		}
+		// Cheapest remote ppos transaction found, discard it if tx is better
+		if force || cheapest.GasPrice().Cmp(tx.GasPrice()) < 0 {
+			drop, save = cheapest, save[:len(save)-1]
+		}
+		break
+	}
+	for _, tx := range save {
+		heap.Push(l.items, tx)
+	}
+	return drop
+}
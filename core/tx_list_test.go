@@ -17,11 +17,16 @@
 package core
 
 import (
+	"crypto/ecdsa"
+	"math/big"
 	"math/rand"
 	"testing"
 
+	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 )
 
 // Tests that transactions can be added to strict lists and list contents and
@@ -49,3 +54,107 @@ func TestStrictTxListAdd(t *testing.T) {
 		}
 	}
 }
+
+// pposTransaction creates a PPOS transaction calling the command name of the
+// contract at to.
+func pposTransaction(nonce uint64, to common.Address, name string, gasprice *big.Int, key *ecdsa.PrivateKey) *types.Transaction {
+	input, _ := vm.EncodeInput(name)
+	tx, _ := types.SignTx(types.NewTransaction(nonce, to, big.NewInt(0), 100000, gasprice, input), types.HomesteadSigner{}, key)
+	return tx
+}
+
+// Tests that the PPOS transactions are counted apart and never discarded to make
+// room for the ordinary transactions.
+func TestPposTxAccounting(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := types.HomesteadSigner{}
+
+	ppos := types.Transactions{
+		pposTransaction(0, common.CandidatePoolAddr, "Unjail", big.NewInt(1), key),
+		pposTransaction(1, common.TicketPoolAddr, "VoteTicket", big.NewInt(1), key),
+	}
+	ordinary := pricedTransaction(2, 100000, big.NewInt(2), key)
+	isPpos := func(tx *types.Transaction) bool {
+		return IsPposTx(params.TestChainConfig, big.NewInt(1), tx)
+	}
+	if !isPpos(ppos[0]) || !isPpos(ppos[1]) || isPpos(ordinary) {
+		t.Fatalf("ppos transaction mismatch")
+	}
+	// the queries, the commands of another contract and the malformed calls are ordinary
+	malformed, _ := types.SignTx(types.NewTransaction(0, common.TicketPoolAddr, big.NewInt(0), 100000, big.NewInt(1), []byte{0x01}), signer, key)
+	for i, tx := range []*types.Transaction{
+		pposTransaction(0, common.CandidatePoolAddr, "GetCandidateNonce", big.NewInt(1), key),
+		pposTransaction(0, common.CandidatePoolAddr, "VoteTicket", big.NewInt(1), key),
+		malformed,
+	} {
+		if isPpos(tx) {
+			t.Errorf("transaction %d: counted as ppos", i)
+		}
+	}
+
+	all, list := newTxLookup(), newTxList(true)
+	priced := newTxPricedList(all)
+	for _, tx := range append(ppos, ordinary) {
+		all.Add(tx, isPpos(tx))
+		priced.Put(tx)
+		list.Add(tx, DefaultTxPoolConfig.PriceBump)
+	}
+	all.Add(ppos[0], true)
+	if have := all.PposCount(); have != len(ppos) {
+		t.Fatalf("lookup ppos count mismatch: have %d, want %d", have, len(ppos))
+	}
+	if have := list.PposCount(all); have != len(ppos) {
+		t.Fatalf("list ppos count mismatch: have %d, want %d", have, len(ppos))
+	}
+	if have := list.PposPrefixLen(all); have != len(ppos) {
+		t.Fatalf("list ppos prefix mismatch: have %d, want %d", have, len(ppos))
+	}
+	// the cheaper ppos transactions are kept, the ordinary one is dropped
+	drop := priced.Discard(3, newAccountSet(signer))
+	if len(drop) != 1 || drop[0] != ordinary {
+		t.Fatalf("discarded transactions mismatch: %v", drop)
+	}
+	all.Remove(ppos[1].Hash())
+	if have := all.PposCount(); have != len(ppos)-1 {
+		t.Fatalf("lookup ppos count mismatch: have %d, want %d", have, len(ppos)-1)
+	}
+}
+
+// Tests that a PPOS transaction only makes room in the PPOS lane by evicting a
+// cheaper remote PPOS transaction.
+func TestPposTxDiscard(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	local, _ := crypto.GenerateKey()
+	signer := types.HomesteadSigner{}
+
+	all := newTxLookup()
+	priced := newTxPricedList(all)
+	locals := newAccountSet(signer)
+	locals.add(crypto.PubkeyToAddress(local.PublicKey))
+	txs := types.Transactions{
+		pricedTransaction(0, 100000, big.NewInt(1), key),
+		pposTransaction(0, common.TicketPoolAddr, "VoteTicket", big.NewInt(1), local),
+		pposTransaction(1, common.TicketPoolAddr, "VoteTicket", big.NewInt(2), key),
+		pposTransaction(2, common.TicketPoolAddr, "VoteTicket", big.NewInt(3), key),
+	}
+	for _, tx := range txs {
+		all.Add(tx, IsPposTx(params.TestChainConfig, big.NewInt(1), tx))
+		priced.Put(tx)
+	}
+	cheap := pposTransaction(3, common.TicketPoolAddr, "VoteTicket", big.NewInt(2), key)
+	if drop := priced.DiscardPpos(cheap, false, locals); drop != nil {
+		t.Fatalf("ppos transaction discarded for an underpriced one: %v", drop)
+	}
+	better := pposTransaction(3, common.TicketPoolAddr, "VoteTicket", big.NewInt(4), key)
+	if drop := priced.DiscardPpos(better, false, locals); drop != txs[2] {
+		t.Fatalf("discarded transaction mismatch: have %v, want %v", drop, txs[2])
+	}
+	all.Remove(txs[2].Hash())
+	if drop := priced.DiscardPpos(cheap, true, locals); drop != txs[3] {
+		t.Fatalf("forced discarded transaction mismatch: have %v, want %v", drop, txs[3])
+	}
+	all.Remove(txs[3].Hash())
+	if drop := priced.DiscardPpos(better, true, locals); drop != nil {
+		t.Fatalf("local or ordinary transaction discarded: %v", drop)
+	}
+}
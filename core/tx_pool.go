@@ -30,6 +30,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common/prque"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/event"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/metrics"
@@ -61,6 +62,10 @@ var (
 	// configured for the transaction pool.
 	ErrUnderpriced = errors.New("transaction underpriced")
 
+	// ErrPposSlotsFull is returned if all the slots reserved for the PPOS
+	// transactions are taken.
+	ErrPposSlotsFull = errors.New("ppos transaction slots full")
+
 	// ErrReplaceUnderpriced is returned if a transaction is attempted to be replaced
 	// with a different one without the required price bump.
 	ErrReplaceUnderpriced = errors.New("replacement transaction underpriced")
@@ -144,6 +149,7 @@ type TxPoolConfig struct {
 	AccountQueue  uint64 // Maximum number of non-executable transaction slots permitted per account
 	GlobalQueue   uint64 // Maximum number of non-executable transaction slots for all accounts
 	GlobalTxCount uint64 // Maximum number of transactions for package
+	PposSlots     uint64 // Number of transaction slots reserved for the PPOS transactions
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
 }
@@ -162,6 +168,7 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	AccountQueue:  64,
 	GlobalQueue:   1024,
 	GlobalTxCount: 3000,
+	PposSlots:     1024,
 
 	Lifetime: 3 * time.Hour,
 }
@@ -182,6 +189,10 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool price bump", "provided", conf.PriceBump, "updated", DefaultTxPoolConfig.PriceBump)
 		conf.PriceBump = DefaultTxPoolConfig.PriceBump
 	}
+	if conf.PposSlots < 1 {
+		log.Warn("Sanitizing invalid txpool ppos slots", "provided", conf.PposSlots, "updated", DefaultTxPoolConfig.PposSlots)
+		conf.PposSlots = DefaultTxPoolConfig.PposSlots
+	}
 	return conf
 }
 
@@ -210,6 +221,7 @@ type TxPool struct {
 	currentState  *state.StateDB      // Current state in the blockchain head
 	pendingState  *state.ManagedState // Pending state tracking virtual nonces
 	currentMaxGas uint64              // Current gas limit for transaction caps
+	pendingNumber *big.Int            // Number of the block following the current head
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
@@ -469,6 +481,7 @@ func (pool *TxPool) ForkedReset(origTress, newTress []*types.Block) {
 	pool.currentState = statedb
 	pool.pendingState = state.ManageState(statedb)
 	pool.currentMaxGas = newTress[len(newTress)-1].Header().GasLimit
+	pool.pendingNumber = new(big.Int).Add(newTress[len(newTress)-1].Number(), common.Big1)
 
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
@@ -578,6 +591,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.currentState = statedb
 	pool.pendingState = state.ManageState(statedb)
 	pool.currentMaxGas = newHead.GasLimit
+	pool.pendingNumber = new(big.Int).Add(newHead.Number, common.Big1)
 
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
@@ -715,14 +729,28 @@ func (pool *TxPool) PendingLimited() (map[common.Address]types.Transactions, err
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
+	// The PPOS transactions are always packaged along with the transactions before
+	// them, only the latter count against the limit
 	txCount := 0
 	pending := make(map[common.Address]types.Transactions)
 	for addr, list := range pool.pending {
-		pending[addr] = list.Flatten()
-		txCount += len(pending[addr])
+		if kept := list.PposPrefixLen(pool.all); kept > 0 {
+			txs := list.Flatten()[:kept]
+			for _, tx := range txs {
+				if !pool.all.IsPpos(tx.Hash()) {
+					txCount++
+				}
+			}
+			pending[addr] = txs
+		}
+	}
+	for addr, list := range pool.pending {
 		if txCount >= int(pool.config.GlobalTxCount) {
 			break
 		}
+		txs := list.Flatten()
+		txCount += len(txs) - len(pending[addr])
+		pending[addr] = txs
 	}
 	log.Trace("Get pending", "duration", time.Since(now))
 	return pending, nil
@@ -823,8 +851,23 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		invalidTxCounter.Inc(1)
 		return false, err
 	}
-	// If the transaction pool is full, discard underpriced transactions
-	if uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
+	// The PPOS transactions have their own slots, they are never discarded to make
+	// room for the ordinary ones. If all the slots are used, the cheapest remote
+	// PPOS transaction makes room for a better one.
+	if pool.isPposTx(tx) {
+		if uint64(pool.all.PposCount()) >= pool.config.PposSlots {
+			drop := pool.priced.DiscardPpos(tx, local, pool.locals)
+			if drop == nil {
+				log.Trace("Discarding ppos transaction, slots full", "hash", hash, "price", tx.GasPrice())
+				underpricedTxCounter.Inc(1)
+				return false, ErrPposSlotsFull
+			}
+			log.Trace("Discarding freshly underpriced ppos transaction", "hash", drop.Hash(), "price", drop.GasPrice())
+			underpricedTxCounter.Inc(1)
+			pool.removeTx(drop.Hash(), false)
+		}
+	} else if ordinary := pool.all.Count() - pool.all.PposCount(); uint64(ordinary) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
+		// If the transaction pool is full, discard underpriced transactions
 		// If the new transaction is underpriced, don't accept it
 		if !local && pool.priced.Underpriced(tx, pool.locals) {
 			log.Trace("Discarding underpriced transaction", "hash", hash, "price", tx.GasPrice())
@@ -832,7 +875,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 			return false, ErrUnderpriced
 		}
 		// New transaction is better than our worse ones, make room for it
-		drop := pool.priced.Discard(pool.all.Count()-int(pool.config.GlobalSlots+pool.config.GlobalQueue-1), pool.locals)
+		drop := pool.priced.Discard(ordinary-int(pool.config.GlobalSlots+pool.config.GlobalQueue-1), pool.locals)
 		for _, tx := range drop {
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
 			underpricedTxCounter.Inc(1)
@@ -854,7 +897,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 			pool.priced.Removed()
 			pendingReplaceCounter.Inc(1)
 		}
-		pool.all.Add(tx)
+		pool.all.Add(tx, pool.isPposTx(tx))
 		pool.priced.Put(tx)
 		pool.journalTx(from, tx)
 
@@ -905,7 +948,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 		queuedReplaceCounter.Inc(1)
 	}
 	if pool.all.Get(hash) == nil {
-		pool.all.Add(tx)
+		pool.all.Add(tx, pool.isPposTx(tx))
 		pool.priced.Put(tx)
 	}
 	return old != nil, nil
@@ -952,7 +995,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	}
 	// Failsafe to work around direct pending inserts (tests)
 	if pool.all.Get(hash) == nil {
-		pool.all.Add(tx)
+		pool.all.Add(tx, pool.isPposTx(tx))
 		pool.priced.Put(tx)
 	}
 	// Set the potentially new pending nonce and notify any subsystems of the new tx
@@ -1307,19 +1350,30 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 	if len(promoted) > 0 {
 		go pool.txFeed.Send(NewTxsEvent{promoted})
 	}
-	// If the pending limit is overflown, start equalizing allowances
+	// If the pending limit is overflown, start equalizing allowances. The PPOS
+	// transactions don't count against the limit, they are kept along with the
+	// transactions before them, only the transactions after the last PPOS one of
+	// an account can be evicted.
 	pending := uint64(0)
 	for _, list := range pool.pending {
-		pending += uint64(list.Len())
+		pending += uint64(list.Len() - list.PposCount(pool.all))
 	}
 	if pending > pool.config.GlobalSlots {
 		pendingBeforeCap := pending
 		// Assemble a spam order to penalize large transactors first
 		spammers := prque.New(nil)
+		kept := make(map[common.Address]int)
+		evictable := func(addr common.Address) int {
+			return pool.pending[addr].Len() - kept[addr]
+		}
 		for addr, list := range pool.pending {
+			if pool.locals.contains(addr) {
+				continue
+			}
+			kept[addr] = list.PposPrefixLen(pool.all)
 			// Only evict transactions from high rollers
-			if !pool.locals.contains(addr) && uint64(list.Len()) > pool.config.AccountSlots {
-				spammers.Push(addr, int64(list.Len()))
+			if uint64(evictable(addr)) > pool.config.AccountSlots {
+				spammers.Push(addr, int64(evictable(addr)))
 			}
 		}
 		// Gradually drop transactions from offenders
@@ -1332,10 +1386,10 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 			// Equalize balances until all the same or below threshold
 			if len(offenders) > 1 {
 				// Calculate the equalization threshold for all current offenders
-				threshold := pool.pending[offender.(common.Address)].Len()
+				threshold := evictable(offender.(common.Address))
 
 				// Iteratively reduce all offenders until below limit or threshold reached
-				for pending > pool.config.GlobalSlots && pool.pending[offenders[len(offenders)-2]].Len() > threshold {
+				for pending > pool.config.GlobalSlots && evictable(offenders[len(offenders)-2]) > threshold {
 					for i := 0; i < len(offenders)-1; i++ {
 						list := pool.pending[offenders[i]]
 						for _, tx := range list.Cap(list.Len() - 1) {
@@ -1357,7 +1411,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		}
 		// If still above threshold, reduce to limit or min allowance
 		if pending > pool.config.GlobalSlots && len(offenders) > 0 {
-			for pending > pool.config.GlobalSlots && uint64(pool.pending[offenders[len(offenders)-1]].Len()) > pool.config.AccountSlots {
+			for pending > pool.config.GlobalSlots && uint64(evictable(offenders[len(offenders)-1])) > pool.config.AccountSlots {
 				for _, addr := range offenders {
 					list := pool.pending[addr]
 					for _, tx := range list.Cap(list.Len() - 1) {
@@ -1381,7 +1435,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 	// If we've queued more transactions than the hard limit, drop oldest ones
 	queued := uint64(0)
 	for _, list := range pool.queue {
-		queued += uint64(list.Len())
+		queued += uint64(list.Len() - list.PposCount(pool.all))
 	}
 	if queued > pool.config.GlobalQueue {
 		// Sort all accounts with queued transactions by heartbeat
@@ -1400,9 +1454,13 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 
 			addresses = addresses[:len(addresses)-1]
 
+			// The PPOS transactions and the ones before them are kept
+			kept := list.PposPrefixLen(pool.all)
+			txs := list.Flatten()
+
 			// Drop all transactions if they are less than the overflow
-			if size := uint64(list.Len()); size <= drop {
-				for _, tx := range list.Flatten() {
+			if size := uint64(len(txs) - kept); size <= drop {
+				for _, tx := range txs[kept:] {
 					pool.removeTx(tx.Hash(), true)
 				}
 				drop -= size
@@ -1410,8 +1468,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 				continue
 			}
 			// Otherwise drop only last few transactions
-			txs := list.Flatten()
-			for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
+			for i := len(txs) - 1; i >= kept && drop > 0; i-- {
 				pool.removeTx(txs[i].Hash(), true)
 				drop--
 				queuedRateLimitCounter.Inc(1)
@@ -1539,13 +1596,15 @@ func (as *accountSet) flatten() []common.Address {
 // TxPool.mu mutex.
 type txLookup struct {
 	all  map[common.Hash]*types.Transaction
+	ppos map[common.Hash]struct{} // Hashes of the PPOS transactions
 	lock sync.RWMutex
 }
 
 // newTxLookup returns a new txLookup structure.
 func newTxLookup() *txLookup {
 	return &txLookup{
-		all: make(map[common.Hash]*types.Transaction),
+		all:  make(map[common.Hash]*types.Transaction),
+		ppos: make(map[common.Hash]struct{}),
 	}
 }
 
@@ -1577,12 +1636,33 @@ func (t *txLookup) Count() int {
 	return len(t.all)
 }
 
-// Add adds a transaction to the lookup.
-func (t *txLookup) Add(tx *types.Transaction) {
+// PposCount returns the current number of PPOS transactions in the lookup.
+func (t *txLookup) PposCount() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return len(t.ppos)
+}
+
+// IsPpos reports whether the transaction of hash was added as a PPOS transaction.
+func (t *txLookup) IsPpos(hash common.Hash) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	_, ok := t.ppos[hash]
+	return ok
+}
+
+// Add adds a transaction to the lookup, ppos tells whether it is a PPOS transaction.
+func (t *txLookup) Add(tx *types.Transaction, ppos bool) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	t.all[tx.Hash()] = tx
+	hash := tx.Hash()
+	if _, ok := t.all[hash]; !ok && ppos {
+		t.ppos[hash] = struct{}{}
+	}
+	t.all[hash] = tx
 }
 
 // Remove removes a transaction from the lookup.
@@ -1590,5 +1670,24 @@ func (t *txLookup) Remove(hash common.Hash) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	delete(t.ppos, hash)
 	delete(t.all, hash)
 }
+
+// IsPposTx reports whether tx calls a command changing the state of one of the
+// PPOS system contracts in the block number num, which has its own slots in the
+// pool and gas in the blocks. The queries, the commands unknown in the block and
+// the malformed calls are ordinary transactions.
+func IsPposTx(config *params.ChainConfig, num *big.Int, tx *types.Transaction) bool {
+	to := tx.To()
+	if to == nil || (*to != common.CandidatePoolAddr && *to != common.TicketPoolAddr) {
+		return false
+	}
+	return vm.IsPposCommand(config, num, *to, tx.Data())
+}
+
+// isPposTx reports whether tx is a PPOS transaction in the pending block, the
+// transactions are classified once as they enter the pool.
+func (pool *TxPool) isPposTx(tx *types.Transaction) bool {
+	return IsPposTx(pool.chainconfig, pool.pendingNumber, tx)
+}
@@ -161,6 +161,33 @@ type Body struct {
 	Transactions []*Transaction
 	Uncles       []*Header
 	Signatures	 []*common.BlockConfirmSign
+	// Aggregate holds at most one aggregated confirmation, it is the tail of the
+	// encoding so that the bodies without it keep their former encoding.
+	Aggregate []*BlockConfirmAggregate `rlp:"tail"`
+}
+
+// BlockConfirmAggregate is the BLS aggregate of the confirmations of a block,
+// Bitmap marks the consensus nodes of the block whose signatures are aggregated
+// into Signature.
+type BlockConfirmAggregate struct {
+	Bitmap    []byte
+	Signature []byte
+}
+
+// confirmAggregate returns the aggregate of a block body, nil if it has none.
+func confirmAggregate(aggregate []*BlockConfirmAggregate) *BlockConfirmAggregate {
+	if len(aggregate) == 0 {
+		return nil
+	}
+	return aggregate[0]
+}
+
+// aggregateTail returns the body tail holding aggregate.
+func aggregateTail(aggregate *BlockConfirmAggregate) []*BlockConfirmAggregate {
+	if aggregate == nil {
+		return nil
+	}
+	return []*BlockConfirmAggregate{aggregate}
 }
 
 // Block represents an entire block in the Ethereum blockchain.
@@ -178,6 +205,8 @@ type Block struct {
 	ReceivedAt   time.Time
 	ReceivedFrom interface{}
 	ConfirmSigns []*common.BlockConfirmSign
+	// ConfirmAggregate replaces ConfirmSigns once the confirmations are aggregated
+	ConfirmAggregate *BlockConfirmAggregate
 }
 
 // [deprecated by eth/63]
@@ -192,6 +221,7 @@ type extblock struct {
 	Txs    []*Transaction
 	Uncles []*Header
 	ConfirmSigns []*common.BlockConfirmSign
+	Aggregate []*BlockConfirmAggregate `rlp:"tail"`
 }
 
 // [deprecated by eth/63]
@@ -273,6 +303,7 @@ func (b *Block) DecodeRLP(s *rlp.Stream) error {
 		return err
 	}
 	b.header, b.uncles, b.transactions, b.ConfirmSigns = eb.Header, eb.Uncles, eb.Txs, eb.ConfirmSigns
+	b.ConfirmAggregate = confirmAggregate(eb.Aggregate)
 	b.size.Store(common.StorageSize(rlp.ListSize(size)))
 	return nil
 }
@@ -284,6 +315,7 @@ func (b *Block) EncodeRLP(w io.Writer) error {
 		Txs:    b.transactions,
 		Uncles: b.uncles,
 		ConfirmSigns: b.ConfirmSigns,
+		Aggregate: aggregateTail(b.ConfirmAggregate),
 	})
 }
 
@@ -332,7 +364,9 @@ func (b *Block) Extra() []byte            { return common.CopyBytes(b.header.Ext
 func (b *Block) Header() *Header { return CopyHeader(b.header) }
 
 // Body returns the non-header content of the block.
-func (b *Block) Body() *Body { return &Body{b.transactions, b.uncles, b.ConfirmSigns} }
+func (b *Block) Body() *Body {
+	return &Body{b.transactions, b.uncles, b.ConfirmSigns, aggregateTail(b.ConfirmAggregate)}
+}
 
 // Size returns the true RLP encoded storage size of the block, either by encoding
 // and returning it, or returning a previsouly cached value.
@@ -385,6 +419,14 @@ func (b *Block) WithBody(transactions []*Transaction, uncles []*Header, signatur
 	return block
 }
 
+// WithFullBody returns a new block with the contents of body, including its
+// aggregated confirmation.
+func (b *Block) WithFullBody(body *Body) *Block {
+	block := b.WithBody(body.Transactions, body.Uncles, body.Signatures)
+	block.ConfirmAggregate = confirmAggregate(body.Aggregate)
+	return block
+}
+
 // Hash returns the keccak256 hash of b's header.
 // The hash is computed on the first call and cached thereafter.
 func (b *Block) Hash() common.Hash {
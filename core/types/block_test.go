@@ -84,3 +84,37 @@ func TestBlockEncoding(t *testing.T) {
 	ourBlockEnc2, _ := rlp.EncodeToBytes(&b)
 	fmt.Println("ourBlockEnc2", common.Bytes2Hex(ourBlockEnc2), b.Hash().Hex())*/
 }
+
+func TestBodyAggregateEncoding(t *testing.T) {
+	// the bodies encoded before the aggregates were introduced
+	type legacyBody struct {
+		Transactions []*Transaction
+		Uncles       []*Header
+		Signatures   []*common.BlockConfirmSign
+	}
+	sign := common.NewBlockConfirmSign(make([]byte, common.BlockConfirmSignLength))
+	legacy, _ := rlp.EncodeToBytes(&legacyBody{Signatures: []*common.BlockConfirmSign{sign}})
+
+	body := &Body{Signatures: []*common.BlockConfirmSign{sign}}
+	if enc, _ := rlp.EncodeToBytes(body); !bytes.Equal(enc, legacy) {
+		t.Fatalf("encoding without aggregate mismatch: have %x, want %x", enc, legacy)
+	}
+	var dec Body
+	if err := rlp.DecodeBytes(legacy, &dec); err != nil || len(dec.Signatures) != 1 || len(dec.Aggregate) != 0 {
+		t.Fatalf("legacy body decode mismatch: %+v, err %v", dec, err)
+	}
+
+	aggregate := &BlockConfirmAggregate{Bitmap: []byte{0x07}, Signature: []byte{1, 2, 3}}
+	block := NewBlockWithHeader(&Header{Number: big.NewInt(1)}).WithFullBody(&Body{Aggregate: []*BlockConfirmAggregate{aggregate}})
+	enc, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		t.Fatal("encode error: ", err)
+	}
+	var decBlock Block
+	if err := rlp.DecodeBytes(enc, &decBlock); err != nil {
+		t.Fatal("decode error: ", err)
+	}
+	if !reflect.DeepEqual(decBlock.ConfirmAggregate, aggregate) || !reflect.DeepEqual(decBlock.Body().Aggregate, []*BlockConfirmAggregate{aggregate}) {
+		t.Fatalf("aggregate mismatch: have %+v, want %+v", decBlock.ConfirmAggregate, aggregate)
+	}
+}
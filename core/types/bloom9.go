@@ -100,6 +100,14 @@ func CreateBloom(receipts Receipts) Bloom {
 	return BytesToBloom(bin.Bytes())
 }
 
+// CreateBlockBloom returns the bloom of the receipts and of the system logs of a block.
+func CreateBlockBloom(receipts Receipts, systemLogs []*Log) Bloom {
+	bin := CreateBloom(receipts).Big()
+	bin.Or(bin, LogsBloom(systemLogs))
+
+	return BytesToBloom(bin.Bytes())
+}
+
 func LogsBloom(logs []*Log) *big.Int {
 	bin := new(big.Int)
 	for _, log := range logs {
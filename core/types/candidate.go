@@ -1,11 +1,16 @@
 package types
 
 import (
+	"crypto/ecdsa"
+	"errors"
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"math/big"
 )
 
+var ErrInvalidNodeSig = errors.New("invalid node key signature")
+
 type CanConditions map[discover.NodeID]*big.Int
 
 type KindCanQueue []CandidateQueue
@@ -147,6 +152,26 @@ type Candidate struct {
 	TOwner common.Address
 }
 
+// Fee change of a candidate waiting for its effective block
+type CandidatePendingFee struct {
+	// the new brokerage
+	Fee uint32
+	// block number from which the new fee applies
+	BlockNumber *big.Int
+}
+
+// Liveness record of a witness, the missed slots are counted per election round
+type CandidateLiveness struct {
+	// block slots of the witness without a block
+	MissedSlots uint32
+	// whether the witness is excluded from the elections until it is unjailed
+	Jailed bool
+	// block number the witness was jailed at
+	JailedBlock *big.Int
+	// deposit forfeited to the reward pool for the jailing
+	Penalty *big.Int
+}
+
 type RefundQueue []*CandidateRefund
 
 func (queue RefundQueue) DeepCopy() RefundQueue {
@@ -175,3 +200,48 @@ type CandidateRefund struct {
 	// Mortgage beneficiary's account address
 	Owner common.Address
 }
+
+// CandidateDepositHash returns the hash the node key signs to prove the node
+// is operated by owner. The nonce is the deposit nonce of the node, it makes a
+// signature usable for a single deposit only.
+func CandidateDepositHash(owner common.Address, chainId *big.Int, nonce uint64) common.Hash {
+	return rlpHash([]interface{}{
+		owner,
+		chainId,
+		nonce,
+	})
+}
+
+// SignCandidateDeposit signs the deposit of the node for owner with the node private key.
+func SignCandidateDeposit(prv *ecdsa.PrivateKey, owner common.Address, chainId *big.Int, nonce uint64) ([]byte, error) {
+	h := CandidateDepositHash(owner, chainId, nonce)
+	return crypto.Sign(h[:], prv)
+}
+
+// CandidateDepositSender recovers the id of the node which signed the deposit for owner.
+func CandidateDepositSender(sig []byte, owner common.Address, chainId *big.Int, nonce uint64) (discover.NodeID, error) {
+	if len(sig) != 65 {
+		return discover.NodeID{}, ErrInvalidNodeSig
+	}
+	h := CandidateDepositHash(owner, chainId, nonce)
+	pub, err := crypto.SigToPub(h[:], sig)
+	if err != nil {
+		return discover.NodeID{}, ErrInvalidNodeSig
+	}
+	return discover.PubkeyID(pub), nil
+}
+
+// WitnessHash returns the commitment of a switch block to the consensus nodes of
+// the next round, it is held by the vanity of the header extra data.
+func WitnessHash(nodes []discover.NodeID) common.Hash {
+	return rlpHash(nodes)
+}
+
+// HeaderWitnessHash returns the commitment to the consensus nodes of the next round
+// held by the switch block header.
+func HeaderWitnessHash(header *Header) common.Hash {
+	if len(header.Extra) < common.HashLength {
+		return common.Hash{}
+	}
+	return common.BytesToHash(header.Extra[:common.HashLength])
+}
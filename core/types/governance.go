@@ -0,0 +1,45 @@
+package types
+
+import (
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+// status of a parameter proposal
+const (
+	ProposalVoting uint32 = iota
+	ProposalApproved
+	ProposalRejected
+	ProposalActive
+)
+
+// Parameter change proposal submitted by a verifier
+type ParamProposal struct {
+	// hash of the transaction which submitted the proposal
+	ProposalId common.Hash
+	// verifier which submitted the proposal
+	Proposer discover.NodeID
+	// name of the parameter, e.g. "Candidate.MaxCount"
+	Name string
+	// new value of the parameter
+	Value string
+	// block number of the submission
+	SubmitBlock uint64
+	// last block number accepting votes
+	EndVotingBlock uint64
+	// block number from which the new value applies
+	ActiveBlock uint64
+	// verifiers which voted for the proposal, the proposer included
+	Yeas   []discover.NodeID
+	Status uint32
+}
+
+// Version of a governed parameter
+type ParamVersion struct {
+	Name  string
+	Value string
+	// block number from which the value applies
+	ActiveBlock uint64
+	// the proposal which approved the value
+	ProposalId common.Hash
+}
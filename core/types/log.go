@@ -24,6 +24,12 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 )
 
+// SystemLogTxHash is the TxHash of the system logs, the logs the consensus engine
+// emits outside of transactions such as elections, ticket expiry and rewards.
+// The system logs of a block have no receipt, their TxIndex is the number of
+// transactions in the block.
+var SystemLogTxHash = common.Hash{}
+
 //go:generate gencodec -type Log -field-override logMarshaling -out gen_log_json.go
 
 // Log represents a contract log event. These events are generated by the LOG opcode and
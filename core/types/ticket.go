@@ -54,3 +54,13 @@ func (t *Ticket) DeepCopy() *Ticket {
 	}
 	return ticket
 }
+
+// ticket price of a pricing epoch
+type TicketPriceRecord struct {
+	// block number the price was adjusted at
+	BlockNumber *big.Int
+	// the adjusted price
+	Price *big.Int
+	// remaining tickets of the pool when adjusting
+	Remaining uint32
+}
@@ -2,27 +2,25 @@ package core
 
 import (
 	"bytes"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
-	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
-	"strconv"
-	"strings"
 	"sync"
 	"time"
 
-	//"github.com/PlatONnetwork/PlatON-Go/accounts"
-	//"github.com/PlatONnetwork/PlatON-Go/accounts/keystore"
+	"github.com/PlatONnetwork/PlatON-Go/accounts"
+	"github.com/PlatONnetwork/PlatON-Go/accounts/keystore"
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/math"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/event"
 	"github.com/PlatONnetwork/PlatON-Go/life/utils"
 	"github.com/PlatONnetwork/PlatON-Go/log"
@@ -36,9 +34,24 @@ const (
 	TX_VC = TX_MPC + 1
 )
 
+var (
+	errVCNoTxBackend     = errors.New("no keystore or transaction pool to submit vc result")
+	errVCNoActor         = errors.New("vc actor not set")
+	errVCComputeReverted = errors.New("real_compute reverted")
+	errVCResultReverted  = errors.New("set_result reverted")
+)
+
+// vcTxPool is the transaction pool the vc results are submitted to.
+type vcTxPool interface {
+	AddLocal(tx *types.Transaction) error
+	State() *state.ManagedState
+	GasPrice() *big.Int
+}
+
 type VCBlockChain interface {
 	CurrentBlock() *types.Block
 	GetBlock(hash common.Hash, number uint64) *types.Block
+	GetBlockByNumber(number uint64) *types.Block
 
 	SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription
 }
@@ -50,6 +63,7 @@ type VCPoolConfig struct {
 	Rejournal   time.Duration // Time interval to regenerate the local transaction journal
 	GlobalQueue uint64        // Maximum number of non-executable transaction slots for all accounts
 	Lifetime    time.Duration // Maximum amount of time non-executable transaction are queued
+	TaskTimeout time.Duration // Maximum amount of time a dispatched task waits for its result
 
 	LocalRpcPort int            // LocalRpcPort of local rpc port
 	IceConf      string         // ice conf to init vm
@@ -62,6 +76,7 @@ var DefaultVCPoolConfig = VCPoolConfig{
 	Rejournal:   time.Second * 4,
 	GlobalQueue: 1024,
 	Lifetime:    3 * time.Hour,
+	TaskTimeout: time.Hour,
 }
 
 type VCPool struct {
@@ -73,18 +88,26 @@ type VCPool struct {
 	all       *vcLookup // All transactions to allow lookups
 	queue     *vcList   // All transactions sorted by price
 	work_pool *GoroutinePool
+	tasks     *computeTaskTable  // lifecycle of the vc tasks by TaskId
+	txPool    vcTxPool           // pool the set_result transactions are injected to
+	keystore  *keystore.KeyStore // keystore holding the key of the vc actor
+	nonceMu   sync.Mutex         // serializes the nonce assignment of the set_result transactions
 	quiteSign chan interface{}
 
 	wg sync.WaitGroup // for shutdown sync
 }
 
-func NewVCPool(config VCPoolConfig, chainconfig *params.ChainConfig, chain *BlockChain) *VCPool {
+func NewVCPool(config VCPoolConfig, chainconfig *params.ChainConfig, chain *BlockChain, db ethdb.Database, txPool vcTxPool, ks *keystore.KeyStore) *VCPool {
 	pool := &VCPool{
 		config:      config,
 		chainconfig: chainconfig,
 		chain:       chain,
 		all:         newVCLookup(),
 		work_pool:   new(GoroutinePool),
+		tasks:       newComputeTaskTable(db, vcTaskTableKey),
+		txPool:      txPool,
+		keystore:    ks,
+		quiteSign:   make(chan interface{}),
 	}
 
 	pool.queue = newVCList(pool.all)
@@ -139,31 +162,7 @@ func genSetResultInput(taskid string, result []byte) string {
 	return common.Bytes2Hex(buffer.Bytes())
 }
 
-//send POST
-func (pool *VCPool) Post(url string, data interface{}, contentType string) (content string, err error) {
-	jsonStr, _ := json.Marshal(data)
-	fmt.Println(string(jsonStr))
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonStr))
-	req.Header.Add("content-type", contentType)
-	if err != nil {
-		return "", err
-	}
-	defer req.Body.Close()
-
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, error := client.Do(req)
-	if error != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	result, _ := ioutil.ReadAll(resp.Body)
-	content = string(result)
-	return content, nil
-}
-
 func (pool *VCPool) real_compute(tx *types.TransactionWrap) error {
-	pool.mu.Lock()
 	signer := types.MakeSigner(pool.chainconfig, big.NewInt(int64(tx.Bn)))
 	caller, _, err := signer.SignatureAndSender(tx.Transaction)
 	if err != nil {
@@ -188,13 +187,14 @@ func (pool *VCPool) real_compute(tx *types.TransactionWrap) error {
 	evm := vm.NewEVM(context, state, bc.chainConfig, bc.vmConfig)
 	log.Debug("start evm call real_compute")
 	ret, _, vmerr, err := ApplyMessage(evm, msg, gp)
-	if vmerr || err != nil {
+	if err != nil {
 		log.Error("ApplyMessage real_compute wrong ", "vmerr", vmerr, "error", err)
 		return err
 	}
-	pool.mu.Unlock()
+	if vmerr {
+		return errVCComputeReverted
+	}
 
-	//fmt.Println(ret)
 	//TODO rm 64 bytes messy code
 	if len(ret) < 64 {
 		log.Error("ApplyMessage real_compute return error ")
@@ -202,41 +202,75 @@ func (pool *VCPool) real_compute(tx *types.TransactionWrap) error {
 	}
 
 	res := ret[64:len(ret)]
-	//fmt.Println(string(res))
-	//var a accounts.Account
-	fmt.Println("unlock: ", pool.config.VcActor.Hex())
-	//fmt.Println("password ", pool.config.VcPassword)
 	data := genSetResultInput(tx.TaskId, bytes.TrimLeft(res, "\x00"))
-	//a.Address = pool.config.VcActor
-	//ks := keystore.NewKeyStore(filepath.Join("./build/bin/data/", "keystore"), keystore.StandardScryptN, keystore.StandardScryptP)
-	//ks.Unlock(a, pool.config.VcPassword)
-
-	vc_data := make(map[string]interface{})
-	vc_data["jsonrpc"] = "2.0"
-	vc_data["method"] = "eth_sendTransaction"
-	params := make([]map[string]interface{}, 1)
-	param := make(map[string]interface{})
-	param["from"] = pool.config.VcActor.Hex()
-	param["to"] = (*(tx.To())).Hex()
-	param["gas"] = "0x166709"
-	param["gasPrice"] = "0x8250de00"
-	param["value"] = "0x0"
-	param["data"] = "0x" + data
-	params[0] = param
-	vc_data["params"] = params
-	vc_data["id"] = 1
-
-	url := "http://127.0.0.1:" + strconv.FormatUint(uint64(pool.config.LocalRpcPort), 10)
-	format := "application/json"
-	postres, err := pool.Post(url, vc_data, format)
+	if data == "" {
+		return fmt.Errorf("encode set_result input fail")
+	}
+	return pool.submitResult(*tx.To(), common.Hex2Bytes(data))
+}
+
+// submitResult signs the set_result transaction with the key of the vc actor
+// and injects it into the local transaction pool.
+func (pool *VCPool) submitResult(to common.Address, data []byte) error {
+	if pool.keystore == nil || pool.txPool == nil {
+		return errVCNoTxBackend
+	}
+	actor := pool.config.VcActor
+	if actor == (common.Address{}) {
+		return errVCNoActor
+	}
+	gas, err := pool.estimateGas(actor, to, data)
+	if err != nil {
+		return err
+	}
+
+	// Serialize the nonce assignment between the compute workers
+	pool.nonceMu.Lock()
+	defer pool.nonceMu.Unlock()
+
+	nonce := pool.txPool.State().GetNonce(actor)
+	tx := types.NewTransaction(nonce, to, new(big.Int), gas, pool.txPool.GasPrice(), data)
+	signed, err := pool.keystore.SignTxWithPassphrase(accounts.Account{Address: actor}, pool.config.VcPassword, tx, pool.chainconfig.ChainID)
 	if err != nil {
 		return err
 	}
-	fmt.Println(strings.ToLower(string(postres)))
-	//log.Debug("start evm call over")
+	if err := pool.txPool.AddLocal(signed); err != nil {
+		return err
+	}
+	log.Info("Submitted vc result", "hash", signed.Hash(), "from", actor, "to", to, "nonce", nonce, "gas", gas)
 	return nil
 }
 
+// estimateGas binary searches the gas needed to execute the call on top of
+// the current state.
+func (pool *VCPool) estimateGas(from common.Address, to common.Address, data []byte) (uint64, error) {
+	header := pool.chain.CurrentHeader()
+	statedb, err := pool.chain.State()
+	if err != nil {
+		return 0, err
+	}
+	executable := func(gas uint64) bool {
+		msg := types.NewMessage(from, &to, 0, new(big.Int), gas, new(big.Int), data, false)
+		context := NewEVMContext(msg, header, pool.chain, nil)
+		evm := vm.NewEVM(context, statedb.Copy(), pool.chain.chainConfig, pool.chain.vmConfig)
+		_, _, failed, err := ApplyMessage(evm, msg, new(GasPool).AddGas(math.MaxUint64))
+		return err == nil && !failed
+	}
+	lo, hi := params.TxGas-1, header.GasLimit
+	if !executable(hi) {
+		return 0, fmt.Errorf("gas required exceeds allowance or always failing transaction")
+	}
+	for lo+1 < hi {
+		mid := (hi + lo) / 2
+		if executable(mid) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi, nil
+}
+
 func (pool *VCPool) loop() {
 
 	defer pool.wg.Done()
@@ -248,6 +282,10 @@ func (pool *VCPool) loop() {
 
 	pop := time.NewTicker(time.Second * 1)
 
+	chainHeadCh := make(chan ChainHeadEvent, chainHeadChanSize)
+	chainHeadSub := pool.chain.SubscribeChainHeadEvent(chainHeadCh)
+	defer chainHeadSub.Unsubscribe()
+
 	// Keep waiting for and reacting to the various events
 	for {
 		select {
@@ -264,6 +302,18 @@ func (pool *VCPool) loop() {
 		case <-pool.quiteSign:
 			return
 
+		// Detect the start-calc blocks removed by a reorg
+		case <-chainHeadCh:
+			if orphaned := pool.tasks.reorg(pool.chain); len(orphaned) > 0 {
+				log.Warn("VC tasks orphaned by chain reorg", "tasks", orphaned)
+			}
+			pool.tasks.expire(pool.config.TaskTimeout)
+			pool.tasks.prune(taskRetention)
+
+		// Be unsubscribed due to system stopped
+		case <-chainHeadSub.Err():
+			return
+
 		case <-pop.C:
 			if pool.queue.items.Len() > 0 {
 
@@ -271,22 +321,32 @@ func (pool *VCPool) loop() {
 				tx := pool.queue.Pop()
 				bn := pool.chain.CurrentBlock().Number().Int64()
 
-				// The latest block is smaller than the current store,
-				// indicating that a fork has occurred and the transaction is removed.
+				// The latest block is smaller than the current store or the block of
+				// the task was reorged out, the transaction is removed.
 				//
 				// If the block to which the transaction belongs is not separated
 				// from the latest block by 20 confirmation blocks, no processing is performed.
-				log.Debug("start evm ------------------------------------------------call ", "bn", bn)
-				if bn < int64(tx.Bn) || (bn-int64(tx.Bn)) >= MinBlockConfirms {
+				dispatch := false
+				switch {
+				case bn < int64(tx.Bn):
+					pool.all.Remove(tx.Hash())
+					pool.tasks.setStatus(tx.TaskId, TaskOrphaned, nil)
+				case pool.finished(tx):
 					pool.all.Remove(tx.Hash())
-				} else {
+				case (bn-int64(tx.Bn)) >= MinBlockConfirms && pool.tasks.ready(tx.TaskId, time.Now()):
+					pool.all.Remove(tx.Hash())
+					dispatch = true
+				default:
 					pool.queue.Put(tx)
 				}
 				pool.mu.Unlock()
 
-				if (bn - int64(tx.Bn)) >= MinBlockConfirms {
+				if dispatch {
+					pool.tasks.dispatched(tx.TaskId)
 					pool.work_pool.AddTask(func() error {
-						return pool.real_compute(tx)
+						err := pool.real_compute(tx)
+						pool.computed(tx, err)
+						return err
 					})
 				}
 
@@ -295,6 +355,64 @@ func (pool *VCPool) loop() {
 	}
 }
 
+// finished reports whether the task of tx was already completed, failed
+// or orphaned by a reorg.
+func (pool *VCPool) finished(tx *types.TransactionWrap) bool {
+	task := pool.tasks.get(tx.TaskId)
+	return task != nil && task.Status.final()
+}
+
+// computed records the outcome of the task, a failed task is queued
+// again to be retried with backoff. A computed task stays dispatched until
+// its set_result transaction is included in a block, or the task times out.
+func (pool *VCPool) computed(tx *types.TransactionWrap, err error) {
+	if err == nil {
+		return
+	}
+	log.Error("Failed to compute vc transaction", "hash", tx.Hash(), "taskId", tx.TaskId, "err", err)
+	if pool.tasks.failed(tx.TaskId, err) {
+		pool.mu.Lock()
+		pool.enqueueTx(tx.Hash(), tx)
+		pool.mu.Unlock()
+	}
+}
+
+// resultMined records the outcome of the set_result transaction tx included
+// in a block. The result has to be reported to the contract which started the
+// task, only the reverted results of the vc actor fail the task.
+func (pool *VCPool) resultMined(signer types.Signer, taskId string, tx *types.Transaction, receipt *types.Receipt) {
+	task := pool.tasks.get(taskId)
+	if task == nil || task.Status.final() || tx.To() == nil {
+		return
+	}
+	block := pool.chain.GetBlock(task.BlockHash, task.BlockNumber)
+	if block == nil {
+		return
+	}
+	if start := block.Transaction(task.TxHash); start == nil || start.To() == nil || *start.To() != *tx.To() {
+		log.Debug("Discarding vc result of another contract", "taskId", taskId, "hash", tx.Hash())
+		return
+	}
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		pool.tasks.setStatus(taskId, TaskCompleted, nil)
+		return
+	}
+	if from, err := types.Sender(signer, tx); err == nil && from == pool.config.VcActor {
+		log.Error("VC result reverted", "taskId", taskId, "hash", tx.Hash())
+		pool.tasks.setStatus(taskId, TaskFailed, errVCResultReverted)
+	}
+}
+
+// Task returns the state of the vc task, or nil if it's unknown.
+func (pool *VCPool) Task(taskId string) *ComputeTask {
+	return pool.tasks.get(taskId)
+}
+
+// Tasks returns the state of all known vc tasks.
+func (pool *VCPool) Tasks() []*ComputeTask {
+	return pool.tasks.list()
+}
+
 func (pool *VCPool) LoadActor() error {
 	absPath, err := filepath.Abs(DEFAULT_ACTOR_FILE_NAME)
 	if err != nil {
@@ -408,7 +526,14 @@ func (pool *VCPool) InjectTxs(block *types.Block, receipts types.Receipts, bc *B
 		return
 	}
 
-	for _, tx := range block.Transactions() {
+	signer := types.MakeSigner(pool.chainconfig, block.Number())
+	for i, tx := range block.Transactions() {
+		if taskId, ok := setResultTaskId(tx.Data()); ok {
+			if i < len(receipts) {
+				pool.resultMined(signer, taskId, tx, receipts[i])
+			}
+			continue
+		}
 		isSave := false
 		var taskId string
 		for _, receipt := range receipts {
@@ -445,7 +570,9 @@ func (pool *VCPool) InjectTxs(block *types.Block, receipts types.Receipts, bc *B
 			// 	return
 			log.Debug("Wow ~ VC add pool--------------------------------------...")
 			// }
-			pool.add(wrap)
+			if _, err := pool.add(wrap); err == nil {
+				pool.tasks.add(wrap, block.Hash())
+			}
 		}
 	}
 }
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/accounts/keystore"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/consensus"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+)
+
+// testVCTxPool is a transaction pool recording the injected transactions.
+type testVCTxPool struct {
+	state *state.ManagedState
+	txs   []*types.Transaction
+}
+
+func (pool *testVCTxPool) AddLocal(tx *types.Transaction) error {
+	pool.txs = append(pool.txs, tx)
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return err
+	}
+	pool.state.SetNonce(from, tx.Nonce()+1)
+	return nil
+}
+
+func (pool *testVCTxPool) State() *state.ManagedState { return pool.state }
+
+func (pool *testVCTxPool) GasPrice() *big.Int { return big.NewInt(1) }
+
+// testVCEngine is the consensus engine of the vc tests, only the author of
+// the blocks is needed by the EVM context.
+type testVCEngine struct {
+	consensus.Engine
+}
+
+func (testVCEngine) Author(header *types.Header) (common.Address, error) {
+	return header.Coinbase, nil
+}
+
+func TestVCSubmitResult(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vc-keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	actor, err := ks.NewAccount("vc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := ethdb.NewMemDatabase()
+	gspec := &Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  GenesisAlloc{actor.Address: {Balance: big.NewInt(params.Ether)}},
+	}
+	gspec.MustCommit(db)
+	chain, err := NewBlockChain(db, nil, nil, gspec.Config, testVCEngine{}, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	statedb, _ := chain.State()
+	txPool := &testVCTxPool{state: state.ManageState(statedb)}
+
+	config := DefaultVCPoolConfig
+	config.VcActor = actor.Address
+	config.VcPassword = "vc"
+	pool := &VCPool{
+		config:      config,
+		chainconfig: gspec.Config,
+		chain:       chain,
+		txPool:      txPool,
+		keystore:    ks,
+	}
+
+	to := common.HexToAddress("0xC1FB0780933718Ccb12DF862726776C840F22C33")
+	data := common.Hex2Bytes(genSetResultInput("task", []byte("result")))
+	for i := 0; i < 2; i++ {
+		if err := pool.submitResult(to, data); err != nil {
+			t.Fatalf("submit %d failed: %v", i, err)
+		}
+	}
+	if len(txPool.txs) != 2 {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(txPool.txs), 2)
+	}
+	signer := types.NewEIP155Signer(gspec.Config.ChainID)
+	for i, tx := range txPool.txs {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			t.Fatalf("tx %d: invalid signature: %v", i, err)
+		}
+		if from != actor.Address {
+			t.Errorf("tx %d: sender mismatch: have %x, want %x", i, from, actor.Address)
+		}
+		if tx.Nonce() != uint64(i) {
+			t.Errorf("tx %d: nonce mismatch: have %d, want %d", i, tx.Nonce(), i)
+		}
+		if tx.Gas() < params.TxGas {
+			t.Errorf("tx %d: gas too low: %d", i, tx.Gas())
+		}
+		if *tx.To() != to {
+			t.Errorf("tx %d: recipient mismatch: have %x, want %x", i, *tx.To(), to)
+		}
+	}
+
+	// A wrong password is reported to the caller
+	pool.config.VcPassword = "wrong"
+	if err := pool.submitResult(to, data); err == nil {
+		t.Fatalf("expected error for wrong password")
+	}
+	pool.keystore = nil
+	if err := pool.submitResult(to, data); err != errVCNoTxBackend {
+		t.Fatalf("error mismatch: have %v, want %v", err, errVCNoTxBackend)
+	}
+}
+
+// Tests that a computed vc task stays dispatched until its set_result
+// transaction is included in a block, and fails if the result reverted.
+func TestVCResultMined(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	gspec := &Genesis{Config: params.TestChainConfig}
+	gspec.MustCommit(db)
+	chain, err := NewBlockChain(db, nil, nil, gspec.Config, testVCEngine{}, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	actor, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	to := common.HexToAddress("0xC1FB0780933718Ccb12DF862726776C840F22C33")
+	start, _ := types.SignTx(types.NewTransaction(0, to, big.NewInt(0), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, other)
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{start}, nil, nil)
+	rawdb.WriteBlock(db, block)
+
+	config := DefaultVCPoolConfig
+	config.VcActor = crypto.PubkeyToAddress(actor.PublicKey)
+	pool := &VCPool{
+		config:      config,
+		chainconfig: gspec.Config,
+		chain:       chain,
+		tasks:       newComputeTaskTable(nil, vcTaskTableKey),
+	}
+	wrap := &types.TransactionWrap{Transaction: start, Bn: 1, TaskId: "task"}
+	pool.tasks.add(wrap, block.Hash())
+	pool.tasks.dispatched("task")
+
+	pool.computed(wrap, nil)
+	if task := pool.Task("task"); task.Status != TaskDispatched {
+		t.Fatalf("task status mismatch: have %v, want %v", task.Status, TaskDispatched)
+	}
+	signer := types.MakeSigner(gspec.Config, big.NewInt(2))
+	failed := &types.Receipt{Status: types.ReceiptStatusFailed}
+	// A reverted result of another sender doesn't fail the task
+	pool.resultMined(signer, "task", mpcResultTransaction("task", to, other), failed)
+	if task := pool.Task("task"); task.Status != TaskDispatched {
+		t.Fatalf("task status mismatch: have %v, want %v", task.Status, TaskDispatched)
+	}
+	pool.resultMined(signer, "task", mpcResultTransaction("task", to, actor), failed)
+	if task := pool.Task("task"); task.Status != TaskFailed || task.Error != errVCResultReverted.Error() {
+		t.Fatalf("task mismatch: have %v, want failed", task)
+	}
+
+	pool.tasks.setStatus("task", TaskDispatched, nil)
+	succeeded := &types.Receipt{Status: types.ReceiptStatusSuccessful}
+	pool.resultMined(signer, "task", mpcResultTransaction("task", common.Address{0x02}, actor), succeeded)
+	if task := pool.Task("task"); task.Status != TaskDispatched {
+		t.Fatalf("task status mismatch: have %v, want %v", task.Status, TaskDispatched)
+	}
+	pool.resultMined(signer, "task", mpcResultTransaction("task", to, actor), succeeded)
+	if task := pool.Task("task"); task.Status != TaskCompleted {
+		t.Fatalf("task status mismatch: have %v, want %v", task.Status, TaskCompleted)
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/byteutil"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
-	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/params"
-	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"math/big"
+	"reflect"
+	"strings"
 )
 
 var (
@@ -23,13 +26,24 @@ var (
 	ErrCandidatePoolEmpty    = errors.New("Candidate Pool is null")
 	ErrCandidateNotExist     = errors.New("The candidate is not exist")
 	ErrCandidateAlreadyExist = errors.New("The candidate is already exist")
+	ErrNodeSigIllegal        = errors.New("The node key signature is illegal")
+	ErrBlsKeyIllegal         = errors.New("The BLS public key or its proof of possession is illegal")
+	ErrSentriesIllegal       = errors.New("The sentry enode URLs are illegal")
 )
 
+// MaxCandidateSentries is the maximum number of sentries a candidate can advertise.
+const MaxCandidateSentries = 8
+
 const (
 	CandidateDepositEvent       = "CandidateDepositEvent"
 	CandidateApplyWithdrawEvent = "CandidateApplyWithdrawEvent"
 	CandidateWithdrawEvent      = "CandidateWithdrawEvent"
 	SetCandidateExtraEvent      = "SetCandidateExtraEvent"
+	IncreaseDepositEvent        = "IncreaseDepositEvent"
+	UpdateCandidateInfoEvent    = "UpdateCandidateInfoEvent"
+	UnjailEvent                 = "UnjailEvent"
+	SetCandidateSentriesEvent   = "SetCandidateSentriesEvent"
+	SetCandidateBlsKeyEvent     = "SetCandidateBlsKeyEvent"
 )
 
 type candidatePoolContext interface {
@@ -45,9 +59,15 @@ type candidatePoolContext interface {
 	RefundBalance(state StateDB, nodeId discover.NodeID, blockNumber *big.Int) error
 	GetOwner(state StateDB, nodeId discover.NodeID, blockNumber *big.Int) common.Address
 	SetCandidateExtra(state StateDB, nodeId discover.NodeID, extra string) error
-	GetRefundInterval(blockNumber *big.Int) uint32
-	MaxCount() uint32
-	MaxChair() uint32
+	IncreaseDeposit(state StateDB, nodeId discover.NodeID, amount, blockNumber *big.Int) error
+	UpdateCandidateInfo(state StateDB, nodeId discover.NodeID, host, port string, fee uint32, blockNumber *big.Int) error
+	GetPendingFee(state StateDB, nodeId discover.NodeID) *types.CandidatePendingFee
+	Unjail(state StateDB, nodeId discover.NodeID, blockNumber *big.Int) error
+	GetLiveness(state StateDB, nodeId discover.NodeID) *types.CandidateLiveness
+	GetRefundInterval(state StateDB, blockNumber *big.Int) uint32
+	MaxCount(state StateDB) uint32
+	MaxChair(state StateDB) uint32
+	PposConfig(state StateDB) *params.PposConfig
 }
 
 type CandidateContract struct {
@@ -56,7 +76,32 @@ type CandidateContract struct {
 }
 
 func (c *CandidateContract) RequiredGas(input []byte) uint64 {
-	return params.EcrecoverGas
+	return c.Evm.ChainConfig().PposGasTable(c.Evm.BlockNumber).Call
+}
+
+// useGas charges the candidate updates of the command, the refunds checked by
+// CandidateWithdraw and the candidates requested by GetCandidateDetails.
+func (c *CandidateContract) useGas(name string, args []reflect.Value) error {
+	gt := c.Evm.ChainConfig().PposGasTable(c.Evm.BlockNumber)
+	var gas uint64
+	switch name {
+	case "CandidateDeposit", "CandidateApplyWithdraw", "IncreaseDeposit", "UpdateCandidateInfo", "Unjail",
+		"SetCandidateExtra", "SetCandidateSentries", "SetCandidateBlsKey":
+		gas = gt.QueueUpdate
+	case "CandidateWithdraw":
+		// RefundBalance walks every refund of the candidate
+		nodeId := args[0].Interface().(discover.NodeID)
+		refunds := c.Evm.CandidatePoolContext.GetDefeat(c.Evm.StateDB, nodeId, c.Evm.BlockNumber)
+		gas = gt.QueueUpdate + uint64(len(refunds))*gt.Refund
+	case "GetCandidateDetails":
+		gas = uint64(args[0].Len()) * gt.QueryItem
+	}
+	return usePposGas(c.Contract, gas)
+}
+
+// useQueryGas charges the items returned by a query whose length depends on the state.
+func (c *CandidateContract) useQueryGas(items int) error {
+	return usePposGas(c.Contract, uint64(items)*c.Evm.ChainConfig().PposGasTable(c.Evm.BlockNumber).QueryItem)
 }
 
 func (c *CandidateContract) Run(input []byte) ([]byte, error) {
@@ -64,21 +109,186 @@ func (c *CandidateContract) Run(input []byte) ([]byte, error) {
 		log.Error("Failed to CandidateContract Run", "ErrCandidatePoolEmpty: ", ErrCandidatePoolEmpty.Error())
 		return nil, ErrCandidatePoolEmpty
 	}
-	var command = map[string]interface{}{
+	return execute(input, c.commandsAt(c.Evm.BlockNumber), c.useGas)
+}
+
+// commands returns the command table of the contract.
+func (c *CandidateContract) commands() map[string]interface{} {
+	return map[string]interface{}{
 		"CandidateDeposit":          c.CandidateDeposit,
+		"GetCandidateNonce":         c.GetCandidateNonce,
+		"GetCandidateBlsKey":        c.GetCandidateBlsKey,
 		"CandidateApplyWithdraw":    c.CandidateApplyWithdraw,
 		"CandidateWithdraw":         c.CandidateWithdraw,
 		"SetCandidateExtra":         c.SetCandidateExtra,
+		"SetCandidateSentries":      c.SetCandidateSentries,
+		"SetCandidateBlsKey":        c.SetCandidateBlsKey,
+		"GetCandidateSentries":      c.GetCandidateSentries,
+		"IncreaseDeposit":           c.IncreaseDeposit,
+		"UpdateCandidateInfo":       c.UpdateCandidateInfo,
+		"Unjail":                    c.Unjail,
+		"GetCandidatePendingFee":    c.GetCandidatePendingFee,
+		"GetCandidateLiveness":      c.GetCandidateLiveness,
 		"GetCandidateWithdrawInfos": c.GetCandidateWithdrawInfos,
 		"GetCandidateDetails":       c.GetCandidateDetails,
 		"GetCandidateList":          c.GetCandidateList,
 		"GetVerifiersList":          c.GetVerifiersList,
 	}
-	return execute(input, command)
+}
+
+// commandsAt returns the command table of the contract in the block number,
+// the commands changed by a fork keep their former arguments before it and
+// the commands added by a fork are unknown before it.
+func (c *CandidateContract) commandsAt(number *big.Int) map[string]interface{} {
+	commands := c.commands()
+	config := c.Evm.ChainConfig()
+	switch {
+	case !config.IsNodeSig(number):
+		commands["CandidateDeposit"] = c.candidateDepositV1
+	case !config.IsBls(number):
+		commands["CandidateDeposit"] = c.candidateDepositV2
+	}
+	if !config.IsBls(number) {
+		delete(commands, "SetCandidateBlsKey")
+	}
+	if !config.IsCandidateUpdate(number) {
+		delete(commands, "IncreaseDeposit")
+		delete(commands, "UpdateCandidateInfo")
+		delete(commands, "GetCandidatePendingFee")
+	}
+	if !config.IsLiveness(number) {
+		delete(commands, "Unjail")
+		delete(commands, "GetCandidateLiveness")
+	}
+	if !config.IsSentry(number) {
+		delete(commands, "SetCandidateSentries")
+		delete(commands, "GetCandidateSentries")
+	}
+	return commands
+}
+
+// CandidateNonceKey returns the state key of the deposit nonce of the node.
+func CandidateNonceKey(nodeId discover.NodeID) []byte {
+	return append([]byte("CandidateNonce"), nodeId.Bytes()...)
+}
+
+// candidateNonce returns the deposit nonce of the node, the number of deposits
+// which were accepted for it so far.
+func (c *CandidateContract) candidateNonce(nodeId discover.NodeID) uint64 {
+	return byteutil.BytesTouint64(c.Evm.StateDB.GetState(common.CandidatePoolAddr, CandidateNonceKey(nodeId)))
+}
+
+// CandidateBlsKey returns the state key of the BLS public key of the node.
+func CandidateBlsKey(nodeId discover.NodeID) []byte {
+	return append([]byte("CandidateBlsKey"), nodeId.Bytes()...)
+}
+
+// CandidateSentriesKey returns the state key of the sentries advertised by the node.
+func CandidateSentriesKey(nodeId discover.NodeID) []byte {
+	return append([]byte("CandidateSentries"), nodeId.Bytes()...)
+}
+
+// parseSentries parses the comma separated enode URLs of the sentries.
+func parseSentries(sentries string) ([]*discover.Node, error) {
+	if sentries == "" {
+		return nil, nil
+	}
+	urls := strings.Split(sentries, ",")
+	if len(urls) > MaxCandidateSentries {
+		return nil, ErrSentriesIllegal
+	}
+	nodes := make([]*discover.Node, 0, len(urls))
+	for _, url := range urls {
+		node, err := discover.ParseNode(strings.TrimSpace(url))
+		if err != nil || node.Incomplete() {
+			return nil, ErrSentriesIllegal
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// CandidateSentryNodes returns the sentries advertised by the node, nil if the
+// node is reached directly.
+func CandidateSentryNodes(state StateDB, nodeId discover.NodeID) []*discover.Node {
+	nodes, _ := parseSentries(string(state.GetState(common.CandidatePoolAddr, CandidateSentriesKey(nodeId))))
+	return nodes
+}
+
+// ConsensusDialNodes returns the nodes to dial to reach the consensus nodes, the
+// nodes which advertise sentries are replaced by their sentries.
+func ConsensusDialNodes(state StateDB, nodes []*discover.Node) []*discover.Node {
+	dial := make([]*discover.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if sentries := CandidateSentryNodes(state, n.ID); len(sentries) > 0 {
+			dial = append(dial, sentries...)
+		} else {
+			dial = append(dial, n)
+		}
+	}
+	return dial
 }
 
 // Candidate Application && Increase Quality Deposit
-func (c *CandidateContract) CandidateDeposit(nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string) ([]byte, error) {
+// sig is the signature of (owner, chain id, deposit nonce) made with the node private key,
+// it proves that the sender operates the node.
+// blsPubKey is the BLS public key the node signs block confirmations with and blsProof
+// the proof of possession of its secret key, see bls.SecretKey.Prove.
+func (c *CandidateContract) CandidateDeposit(nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string, sig, blsPubKey, blsProof []byte) ([]byte, error) {
+	height := c.Evm.Context.BlockNumber
+	nonce := c.candidateNonce(nodeId)
+	if signer, err := types.CandidateDepositSender(sig, owner, c.Evm.ChainConfig().ChainID, nonce); nil != err || signer != nodeId {
+		log.Error("Failed to CandidateDeposit", "blockNumber", height.String(), "nodeId", nodeId.String(), "nonce", nonce, "ErrNodeSigIllegal: ", ErrNodeSigIllegal.Error())
+		return nil, ErrNodeSigIllegal
+	}
+	if err := checkBlsKey(blsPubKey, blsProof); nil != err {
+		log.Error("Failed to CandidateDeposit", "blockNumber", height.String(), "nodeId", nodeId.String(), "ErrBlsKeyIllegal: ", err.Error())
+		return nil, err
+	}
+	if err := c.candidateDeposit(nodeId, owner, fee, host, port, extra); nil != err {
+		return nil, err
+	}
+	c.Evm.StateDB.SetState(common.CandidatePoolAddr, CandidateNonceKey(nodeId), byteutil.Uint64ToBytes(nonce+1))
+	c.Evm.StateDB.SetState(common.CandidatePoolAddr, CandidateBlsKey(nodeId), blsPubKey)
+	return nil, nil
+}
+
+// candidateDepositV2 is CandidateDeposit between the node signature fork and the
+// BLS fork, the deposits carry the proof of the node but no BLS key.
+func (c *CandidateContract) candidateDepositV2(nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string, sig []byte) ([]byte, error) {
+	height := c.Evm.Context.BlockNumber
+	nonce := c.candidateNonce(nodeId)
+	if signer, err := types.CandidateDepositSender(sig, owner, c.Evm.ChainConfig().ChainID, nonce); nil != err || signer != nodeId {
+		log.Error("Failed to CandidateDeposit", "blockNumber", height.String(), "nodeId", nodeId.String(), "nonce", nonce, "ErrNodeSigIllegal: ", ErrNodeSigIllegal.Error())
+		return nil, ErrNodeSigIllegal
+	}
+	if err := c.candidateDeposit(nodeId, owner, fee, host, port, extra); nil != err {
+		return nil, err
+	}
+	c.Evm.StateDB.SetState(common.CandidatePoolAddr, CandidateNonceKey(nodeId), byteutil.Uint64ToBytes(nonce+1))
+	return nil, nil
+}
+
+// checkBlsKey checks the BLS public key and the proof of possession of its secret key.
+func checkBlsKey(blsPubKey, blsProof []byte) error {
+	pk, err := bls.UnmarshalPublicKey(blsPubKey)
+	if nil != err {
+		return ErrBlsKeyIllegal
+	}
+	if proof, err := bls.UnmarshalSignature(blsProof); nil != err || !bls.VerifyProof(pk, proof) {
+		return ErrBlsKeyIllegal
+	}
+	return nil
+}
+
+// candidateDepositV1 is CandidateDeposit before the node signature fork, the
+// deposits of the blocks before it are replayed without the proof of the node.
+func (c *CandidateContract) candidateDepositV1(nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string) ([]byte, error) {
+	return nil, c.candidateDeposit(nodeId, owner, fee, host, port, extra)
+}
+
+// candidateDeposit applies a checked deposit of the node.
+func (c *CandidateContract) candidateDeposit(nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string) error {
 	deposit := c.Contract.value
 	txHash := c.Evm.StateDB.TxHash()
 	txIdx := c.Evm.StateDB.TxIdx()
@@ -88,17 +298,17 @@ func (c *CandidateContract) CandidateDeposit(nodeId discover.NodeID, owner commo
 		"  fee: ", fee, " txhash: ", txHash.Hex(), " txIdx: ", txIdx, " height: ", height, " host: ", host, " port: ", port, " extra: ", extra)
 	if fee > 10000 {
 		log.Error("Failed to CandidateDeposit", "blockNumber", height.String(), "ErrFeeIllegal: ", ErrFeeIllegal.Error())
-		return nil, ErrFeeIllegal
+		return ErrFeeIllegal
 	}
 	if deposit.Cmp(big.NewInt(0)) < 1 {
 		log.Error("Failed to CandidateDeposit", "blockNumber", height.String(), "ErrDepositEmpty: ", ErrDepositEmpty.Error())
-		return nil, ErrDepositEmpty
+		return ErrDepositEmpty
 	}
 	addr := c.Evm.CandidatePoolContext.GetOwner(c.Evm.StateDB, nodeId, height)
 	if common.ZeroAddr != addr {
 		if ok := bytes.Equal(addr.Bytes(), owner.Bytes()); !ok {
 			log.Error("Failed to CandidateDeposit==> ", "blockNumber", height.String(), "old owner", addr.Hex(), "new owner", owner, "ErrOwnerNotOnly: ", ErrOwnerNotOnly.Error())
-			return nil, ErrOwnerNotOnly
+			return ErrOwnerNotOnly
 		}
 	}
 	//var alldeposit *big.Int
@@ -107,7 +317,7 @@ func (c *CandidateContract) CandidateDeposit(nodeId discover.NodeID, owner commo
 	can := c.Evm.CandidatePoolContext.GetCandidate(c.Evm.StateDB, nodeId, height)
 	if nil != can {
 		log.Error("Failed to CandidateDeposit, the candidate is already exist", "blockNumber", height.String(), "nodeId", nodeId.String())
-		return nil, ErrCandidateAlreadyExist
+		return ErrCandidateAlreadyExist
 	}
 	//if nil != can {
 	//	alldeposit = new(big.Int).Add(can.Deposit, deposit)
@@ -134,13 +344,11 @@ func (c *CandidateContract) CandidateDeposit(nodeId discover.NodeID, owner commo
 	log.Info("CandidateDeposit", "blockNumber", height.String(), "canDeposit: ", canDeposit)
 	if err := c.Evm.CandidatePoolContext.SetCandidate(c.Evm.StateDB, nodeId, &canDeposit); nil != err {
 		log.Error("Failed to CandidateDeposit", "blockNumber", height.String(), "SetCandidate return err: ", err.Error())
-		return nil, err
+		return err
 	}
-	r := ResultCommon{true, "", "success"}
-	event, _ := json.Marshal(r)
-	c.addLog(CandidateDepositEvent, string(event))
-	log.Info("Result of CandidateDeposit", "blockNumber", height.String(), "json: ", string(event))
-	return nil, nil
+	c.addLog(CandidateDepositEvent, nodeId, owner, deposit, fee)
+	log.Info("Result of CandidateDeposit", "blockNumber", height.String(), "nodeId: ", nodeId.String())
+	return nil
 }
 
 // Apply for a refund of the deposit
@@ -170,10 +378,8 @@ func (c *CandidateContract) CandidateApplyWithdraw(nodeId discover.NodeID, withd
 		log.Error("Failed to CandidateApplyWithdraw on WithdrawCandidate", "blockNumber", height.String(), "WithdrawCandidate return err: ", err.Error())
 		return nil, err
 	}
-	r := ResultCommon{true, "", "success"}
-	event, _ := json.Marshal(r)
-	c.addLog(CandidateApplyWithdrawEvent, string(event))
-	log.Info("Result of CandidateApplyWithdraw on WithdrawCandidate", "blockNumber", height.String(), "json: ", string(event))
+	c.addLog(CandidateApplyWithdrawEvent, nodeId, from, withdraw)
+	log.Info("Result of CandidateApplyWithdraw on WithdrawCandidate", "blockNumber", height.String(), "nodeId: ", nodeId.String(), "withdraw: ", withdraw)
 	return nil, nil
 }
 
@@ -182,14 +388,15 @@ func (c *CandidateContract) CandidateWithdraw(nodeId discover.NodeID) ([]byte, e
 	txHash := c.Evm.StateDB.TxHash()
 	height := c.Evm.Context.BlockNumber
 	log.Info("Input to CandidateWithdraw to RefundBalance", "nodeId: ", nodeId.String(), " height: ", height, " txHash: ", txHash.Hex())
+	owner := c.Evm.CandidatePoolContext.GetOwner(c.Evm.StateDB, nodeId, height)
+	poolBalance := new(big.Int).Set(c.Evm.StateDB.GetBalance(common.CandidatePoolAddr))
 	if err := c.Evm.CandidatePoolContext.RefundBalance(c.Evm.StateDB, nodeId, height); nil != err {
 		log.Error("Failed to CandidateWithdraw to RefundBalance", "blockNumber", height.String(), "RefundBalance return err: ", err.Error())
 		return nil, err
 	}
-	r := ResultCommon{true, "", "success"}
-	event, _ := json.Marshal(r)
-	c.addLog(CandidateWithdrawEvent, string(event))
-	log.Info("Result of CandidateWithdraw to RefundBalance", "blockNumber", height.String(), "json: ", string(event))
+	amount := new(big.Int).Sub(poolBalance, c.Evm.StateDB.GetBalance(common.CandidatePoolAddr))
+	c.addLog(CandidateWithdrawEvent, nodeId, owner, amount)
+	log.Info("Result of CandidateWithdraw to RefundBalance", "blockNumber", height.String(), "nodeId: ", nodeId.String(), "amount: ", amount)
 	return nil, nil
 }
 
@@ -208,19 +415,192 @@ func (c *CandidateContract) SetCandidateExtra(nodeId discover.NodeID, extra stri
 		log.Error("Failed to SetCandidateExtra", "blockNumber", height.String(), "SetCandidateExtra return err: ", err.Error())
 		return nil, err
 	}
-	r := ResultCommon{true, "", "success"}
-	event, _ := json.Marshal(r)
-	c.addLog(SetCandidateExtraEvent, string(event))
-	log.Info("Result of SetCandidateExtra", "blockNumber", height.String(), "json: ", string(event))
+	c.addLog(SetCandidateExtraEvent, nodeId, extra)
+	log.Info("Result of SetCandidateExtra", "blockNumber", height.String(), "nodeId: ", nodeId.String())
+	return nil, nil
+}
+
+// Set the sentries advertised by the candidate, sentries are comma separated enode URLs
+// which the other consensus nodes dial instead of the candidate. An empty list makes the
+// candidate reached directly again.
+func (c *CandidateContract) SetCandidateSentries(nodeId discover.NodeID, sentries string) ([]byte, error) {
+	txHash := c.Evm.StateDB.TxHash()
+	from := c.Contract.caller.Address()
+	height := c.Evm.Context.BlockNumber
+	log.Info("Input to SetCandidateSentries", "blockNumber", height.String(), "nodeId: ", nodeId.String(), " sentries: ", sentries, " from: ", from.Hex(), " txHash: ", txHash.Hex())
+	owner := c.Evm.CandidatePoolContext.GetOwner(c.Evm.StateDB, nodeId, height)
+	if ok := bytes.Equal(owner.Bytes(), from.Bytes()); !ok {
+		log.Error("Failed to SetCandidateSentries", "blockNumber", height.String(), "ErrPermissionDenied: ", ErrPermissionDenied.Error())
+		return nil, ErrPermissionDenied
+	}
+	if _, err := parseSentries(sentries); nil != err {
+		log.Error("Failed to SetCandidateSentries", "blockNumber", height.String(), "ErrSentriesIllegal: ", err.Error())
+		return nil, err
+	}
+	c.Evm.StateDB.SetState(common.CandidatePoolAddr, CandidateSentriesKey(nodeId), []byte(sentries))
+	c.addLog(SetCandidateSentriesEvent, nodeId, sentries)
+	log.Info("Result of SetCandidateSentries", "blockNumber", height.String(), "nodeId: ", nodeId.String())
+	return nil, nil
+}
+
+// Set the BLS public key the candidate signs block confirmations with, blsProof is the
+// proof of possession of its secret key. It registers a key for the candidates which
+// deposited before the BLS fork and rotates the key of the others.
+func (c *CandidateContract) SetCandidateBlsKey(nodeId discover.NodeID, blsPubKey, blsProof []byte) ([]byte, error) {
+	txHash := c.Evm.StateDB.TxHash()
+	from := c.Contract.caller.Address()
+	height := c.Evm.Context.BlockNumber
+	log.Info("Input to SetCandidateBlsKey", "blockNumber", height.String(), "nodeId: ", nodeId.String(), " from: ", from.Hex(), " txHash: ", txHash.Hex())
+	owner := c.Evm.CandidatePoolContext.GetOwner(c.Evm.StateDB, nodeId, height)
+	if ok := bytes.Equal(owner.Bytes(), from.Bytes()); !ok {
+		log.Error("Failed to SetCandidateBlsKey", "blockNumber", height.String(), "ErrPermissionDenied: ", ErrPermissionDenied.Error())
+		return nil, ErrPermissionDenied
+	}
+	if err := checkBlsKey(blsPubKey, blsProof); nil != err {
+		log.Error("Failed to SetCandidateBlsKey", "blockNumber", height.String(), "ErrBlsKeyIllegal: ", err.Error())
+		return nil, err
+	}
+	c.Evm.StateDB.SetState(common.CandidatePoolAddr, CandidateBlsKey(nodeId), blsPubKey)
+	c.addLog(SetCandidateBlsKeyEvent, nodeId, blsPubKey)
+	log.Info("Result of SetCandidateBlsKey", "blockNumber", height.String(), "nodeId: ", nodeId.String())
+	return nil, nil
+}
+
+// GetCandidateSentries returns the sentries advertised by the node, empty if it is
+// reached directly.
+func (c *CandidateContract) GetCandidateSentries(nodeId discover.NodeID) ([]byte, error) {
+	height := c.Evm.Context.BlockNumber
+	sentries := string(c.Evm.StateDB.GetState(common.CandidatePoolAddr, CandidateSentriesKey(nodeId)))
+	data, _ := json.Marshal(sentries)
+	sdata := DecodeResultStr(string(data))
+	log.Info("Result of GetCandidateSentries", "blockNumber", height.String(), "nodeId: ", nodeId.String(), "sentries: ", sentries)
+	return sdata, nil
+}
+
+// Increase the deposit of the candidate by the value of the transaction, the candidate
+// keeps its tickets and is re-sorted in its queue
+func (c *CandidateContract) IncreaseDeposit(nodeId discover.NodeID) ([]byte, error) {
+	deposit := c.Contract.value
+	txHash := c.Evm.StateDB.TxHash()
+	from := c.Contract.caller.Address()
+	height := c.Evm.Context.BlockNumber
+	log.Info("Input to IncreaseDeposit", "blockNumber", height.String(), "nodeId: ", nodeId.String(), " deposit: ", deposit, " from: ", from.Hex(), " txHash: ", txHash.Hex())
+	if deposit.Cmp(big.NewInt(0)) < 1 {
+		log.Error("Failed to IncreaseDeposit", "blockNumber", height.String(), "ErrDepositEmpty: ", ErrDepositEmpty.Error())
+		return nil, ErrDepositEmpty
+	}
+	owner := c.Evm.CandidatePoolContext.GetOwner(c.Evm.StateDB, nodeId, height)
+	if ok := bytes.Equal(owner.Bytes(), from.Bytes()); !ok {
+		log.Error("Failed to IncreaseDeposit", "blockNumber", height.String(), "ErrPermissionDenied: ", ErrPermissionDenied.Error())
+		return nil, ErrPermissionDenied
+	}
+	if err := c.Evm.CandidatePoolContext.IncreaseDeposit(c.Evm.StateDB, nodeId, deposit, height); nil != err {
+		log.Error("Failed to IncreaseDeposit", "blockNumber", height.String(), "IncreaseDeposit return err: ", err.Error())
+		return nil, err
+	}
+	c.addLog(IncreaseDepositEvent, nodeId, from, deposit)
+	log.Info("Result of IncreaseDeposit", "blockNumber", height.String(), "nodeId: ", nodeId.String(), "deposit: ", deposit)
+	return nil, nil
+}
+
+// Update the host, port and fee of the candidate, the new fee takes effect after a delay
+// A candidate behind sentries may leave its host and port empty
+func (c *CandidateContract) UpdateCandidateInfo(nodeId discover.NodeID, host, port string, fee uint32) ([]byte, error) {
+	txHash := c.Evm.StateDB.TxHash()
+	from := c.Contract.caller.Address()
+	height := c.Evm.Context.BlockNumber
+	log.Info("Input to UpdateCandidateInfo", "blockNumber", height.String(), "nodeId: ", nodeId.String(), " host: ", host, " port: ", port, " fee: ", fee, " from: ", from.Hex(), " txHash: ", txHash.Hex())
+	if fee > 10000 {
+		log.Error("Failed to UpdateCandidateInfo", "blockNumber", height.String(), "ErrFeeIllegal: ", ErrFeeIllegal.Error())
+		return nil, ErrFeeIllegal
+	}
+	owner := c.Evm.CandidatePoolContext.GetOwner(c.Evm.StateDB, nodeId, height)
+	if ok := bytes.Equal(owner.Bytes(), from.Bytes()); !ok {
+		log.Error("Failed to UpdateCandidateInfo", "blockNumber", height.String(), "ErrPermissionDenied: ", ErrPermissionDenied.Error())
+		return nil, ErrPermissionDenied
+	}
+	if err := c.Evm.CandidatePoolContext.UpdateCandidateInfo(c.Evm.StateDB, nodeId, host, port, fee, height); nil != err {
+		log.Error("Failed to UpdateCandidateInfo", "blockNumber", height.String(), "UpdateCandidateInfo return err: ", err.Error())
+		return nil, err
+	}
+	c.addLog(UpdateCandidateInfoEvent, nodeId, host, port, fee)
+	log.Info("Result of UpdateCandidateInfo", "blockNumber", height.String(), "nodeId: ", nodeId.String())
 	return nil, nil
 }
 
+// GetCandidatePendingFee returns the fee change of the candidate waiting to take effect.
+func (c *CandidateContract) GetCandidatePendingFee(nodeId discover.NodeID) ([]byte, error) {
+	height := c.Evm.Context.BlockNumber
+	pending := c.Evm.CandidatePoolContext.GetPendingFee(c.Evm.StateDB, nodeId)
+	data, _ := json.Marshal(pending)
+	sdata := DecodeResultStr(string(data))
+	log.Info("Result of GetCandidatePendingFee", "blockNumber", height.String(), "nodeId: ", nodeId.String(), "json: ", string(data))
+	return sdata, nil
+}
+
+// Release the jailed candidate, the deposit forfeited for the jailing is not returned
+func (c *CandidateContract) Unjail(nodeId discover.NodeID) ([]byte, error) {
+	txHash := c.Evm.StateDB.TxHash()
+	from := c.Contract.caller.Address()
+	height := c.Evm.Context.BlockNumber
+	log.Info("Input to Unjail", "blockNumber", height.String(), "nodeId: ", nodeId.String(), " from: ", from.Hex(), " txHash: ", txHash.Hex())
+	owner := c.Evm.CandidatePoolContext.GetOwner(c.Evm.StateDB, nodeId, height)
+	if ok := bytes.Equal(owner.Bytes(), from.Bytes()); !ok {
+		log.Error("Failed to Unjail", "blockNumber", height.String(), "ErrPermissionDenied: ", ErrPermissionDenied.Error())
+		return nil, ErrPermissionDenied
+	}
+	if err := c.Evm.CandidatePoolContext.Unjail(c.Evm.StateDB, nodeId, height); nil != err {
+		log.Error("Failed to Unjail", "blockNumber", height.String(), "Unjail return err: ", err.Error())
+		return nil, err
+	}
+	c.addLog(UnjailEvent, nodeId, from)
+	log.Info("Result of Unjail", "blockNumber", height.String(), "nodeId: ", nodeId.String())
+	return nil, nil
+}
+
+// GetCandidateLiveness returns the missed slots of the candidate in the current round and whether it is jailed.
+func (c *CandidateContract) GetCandidateLiveness(nodeId discover.NodeID) ([]byte, error) {
+	height := c.Evm.Context.BlockNumber
+	liveness := c.Evm.CandidatePoolContext.GetLiveness(c.Evm.StateDB, nodeId)
+	data, _ := json.Marshal(liveness)
+	sdata := DecodeResultStr(string(data))
+	log.Info("Result of GetCandidateLiveness", "blockNumber", height.String(), "nodeId: ", nodeId.String(), "json: ", string(data))
+	return sdata, nil
+}
+
+// GetCandidateNonce returns the deposit nonce the node key must sign for the next deposit.
+func (c *CandidateContract) GetCandidateNonce(nodeId discover.NodeID) ([]byte, error) {
+	height := c.Evm.Context.BlockNumber
+	nonce := c.candidateNonce(nodeId)
+	data, _ := json.Marshal(nonce)
+	sdata := DecodeResultStr(string(data))
+	log.Info("Result of GetCandidateNonce", "blockNumber", height.String(), "nodeId: ", nodeId.String(), "nonce: ", nonce)
+	return sdata, nil
+}
+
+// GetCandidateBlsKey returns the BLS public key registered by the node, empty if it has none.
+func (c *CandidateContract) GetCandidateBlsKey(nodeId discover.NodeID) ([]byte, error) {
+	height := c.Evm.Context.BlockNumber
+	var key string
+	if pk := c.Evm.StateDB.GetState(common.CandidatePoolAddr, CandidateBlsKey(nodeId)); len(pk) > 0 {
+		key = hexutil.Encode(pk)
+	}
+	data, _ := json.Marshal(key)
+	sdata := DecodeResultStr(string(data))
+	log.Info("Result of GetCandidateBlsKey", "blockNumber", height.String(), "nodeId: ", nodeId.String(), "key: ", key)
+	return sdata, nil
+}
+
 // Get the refund history you have applied for
 func (c *CandidateContract) GetCandidateWithdrawInfos(nodeId discover.NodeID) ([]byte, error) {
 	height := c.Evm.Context.BlockNumber
 	log.Info("Input to CandidateWithdrawInfos", "blockNumber", height.String(), "nodeId: ", nodeId.String())
 
 	refunds := c.Evm.CandidatePoolContext.GetDefeat(c.Evm.StateDB, nodeId, height)
+	if err := c.useQueryGas(len(refunds)); nil != err {
+		log.Error("Failed to CandidateWithdrawInfos", "blockNumber", height.String(), "err: ", err.Error())
+		return nil, err
+	}
 	type WithdrawInfo struct {
 		Balance        *big.Int
 		LockNumber     *big.Int
@@ -228,7 +608,7 @@ func (c *CandidateContract) GetCandidateWithdrawInfos(nodeId discover.NodeID) ([
 	}
 	r := make([]WithdrawInfo, len(refunds))
 	for i, v := range refunds {
-		refundBlockNumber := c.Evm.CandidatePoolContext.GetRefundInterval(height)
+		refundBlockNumber := c.Evm.CandidatePoolContext.GetRefundInterval(c.Evm.StateDB, height)
 		log.Debug("Call CandidateWithdrawInfos", "Deposit", v.Deposit, "BlockNumber", v.BlockNumber.String(), "RefundBlockNumber", refundBlockNumber)
 		r[i] = WithdrawInfo{v.Deposit, v.BlockNumber, refundBlockNumber}
 	}
@@ -256,6 +636,10 @@ func (c *CandidateContract) GetCandidateList() ([]byte, error) {
 
 	height := c.Evm.Context.BlockNumber
 	candidates := c.Evm.CandidatePoolContext.GetChosens(c.Evm.StateDB, 0, height)
+	if err := c.useQueryGas(len(candidates[0]) + len(candidates[1])); nil != err {
+		log.Error("Failed to GetCandidateList", "blockNumber", height.String(), "err: ", err.Error())
+		return nil, err
+	}
 	data, _ := json.Marshal(candidates)
 	sdata := DecodeResultStr(string(data))
 	log.Info("Result of GetCandidateList", "blockNumber", height.String(), "len(candidates): ", len(candidates[0])+len(candidates[1]), "json: ", string(data))
@@ -266,25 +650,23 @@ func (c *CandidateContract) GetCandidateList() ([]byte, error) {
 func (c *CandidateContract) GetVerifiersList() ([]byte, error) {
 	height := c.Evm.Context.BlockNumber
 	verifiers := c.Evm.CandidatePoolContext.GetChairpersons(c.Evm.StateDB, height)
+	if err := c.useQueryGas(len(verifiers)); nil != err {
+		log.Error("Failed to GetVerifiersList", "blockNumber", height.String(), "err: ", err.Error())
+		return nil, err
+	}
 	data, _ := json.Marshal(verifiers)
 	sdata := DecodeResultStr(string(data))
 	log.Info("Result of GetVerifiersList", "blockNumber", height.String(), "len(verifiers): ", len(verifiers), "json: ", string(data))
 	return sdata, nil
 }
 
-// addLog let the result add to event.
-func (c *CandidateContract) addLog(event, data string) {
-	var logdata [][]byte
-	logdata = make([][]byte, 0)
-	logdata = append(logdata, []byte(data))
-	buf := new(bytes.Buffer)
-	if err := rlp.Encode(buf, logdata); nil != err {
-		log.Error("Failed to CandidateContract addlog", "rlp encode fail: ", err.Error())
-	}
-	c.Evm.StateDB.AddLog(&types.Log{
-		Address:     common.CandidatePoolAddr,
-		Topics:      []common.Hash{common.BytesToHash(crypto.Keccak256([]byte(event)))},
-		Data:        buf.Bytes(),
-		BlockNumber: c.Evm.Context.BlockNumber.Uint64(),
-	})
+// addLog adds a log of the typed event, args are the values of the event inputs.
+// Before the PPOS events fork the log keeps the legacy format.
+func (c *CandidateContract) addLog(event string, args ...interface{}) {
+	blockNumber := c.Evm.Context.BlockNumber
+	if !c.Evm.ChainConfig().IsPposEvents(blockNumber) {
+		addLegacyPposLog(c.Evm.StateDB, common.CandidatePoolAddr, blockNumber, event, ResultCommon{true, "", "success"})
+		return
+	}
+	AddPposLog(c.Evm.StateDB, common.CandidatePoolAddr, blockNumber, event, args...)
 }
@@ -11,14 +11,17 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
 	"github.com/PlatONnetwork/PlatON-Go/core"
 	"github.com/PlatONnetwork/PlatON-Go/core/ppos"
-	"github.com/PlatONnetwork/PlatON-Go/core/ppos_storage"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"math/big"
+	"net"
 	"testing"
 	"time"
 )
@@ -29,14 +32,14 @@ import (
 		newContract(),
 		newEvm(),
 	}
-	nodeId := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
@@ -105,9 +108,8 @@ func newChainState() (*state.StateDB, error) {
 		genesis = new(core.Genesis).MustCommit(db)
 	)
 	fmt.Println("genesis", genesis)
-	ppos_storage.NewPPosTemp(db)
 	// Initialize a fresh chain with only a genesis block
-	blockchain, _ := core.NewBlockChain(db, nil, params.AllEthashProtocolChanges, nil, vm.Config{}, nil)
+	blockchain, _ := core.NewBlockChain(db, nil, nil, params.AllEthashProtocolChanges, nil, vm.Config{}, nil)
 
 	var state *state.StateDB
 	if statedb, err := blockchain.State(); nil != err {
@@ -126,6 +128,10 @@ func newPool() (*pposm.CandidatePoolContext, *pposm.TicketPoolContext) {
 			MaxChair:          1,
 			MaxCount:          3,
 			RefundBlockNumber: 1,
+			FeeChangeDelay:    2,
+			LivenessThreshold: 3,
+			LivenessPenalty:   "300",
+			JailPeriod:        5,
 		},
 		TicketConfig: &params.TicketConfig{
 			TicketPrice:       "1",
@@ -133,27 +139,53 @@ func newPool() (*pposm.CandidatePoolContext, *pposm.TicketPoolContext) {
 			ExpireBlockNumber: 100,
 		},
 	}
-	return pposm.NewCandidatePoolContext(configs), pposm.NewTicketPoolContext(configs)
+	candidatePoolContext, ticketPoolContext := pposm.NewCandidatePoolContext(configs), pposm.NewTicketPoolContext(configs)
+	candidatePoolContext.SetTicketPoolContext(ticketPoolContext)
+	ticketPoolContext.SetCandidatePoolContext(candidatePoolContext)
+	return candidatePoolContext, ticketPoolContext
 }
 
 func newEvm() *vm.EVM {
 	state, _ := newChainState()
 	candidatePoolContext, ticketPoolContext := newPool()
-	evm := &vm.EVM{
-		StateDB:              state,
+	context := vm.Context{
+		BlockNumber:          big.NewInt(7),
 		CandidatePoolContext: candidatePoolContext,
 		TicketPoolContext:    ticketPoolContext,
 	}
-	context := vm.Context{
-		BlockNumber: big.NewInt(7),
+	return vm.NewEVM(context, state, params.TestChainConfig, vm.Config{})
+}
+
+var (
+	testNodeKey1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testNodeKey2, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	testNodeId1     = discover.PubkeyID(&testNodeKey1.PublicKey)
+	testNodeId2     = discover.PubkeyID(&testNodeKey2.PublicKey)
+)
+
+// nodeSig signs the first deposit of the test node for owner.
+func nodeSig(nodeId discover.NodeID, owner common.Address) []byte {
+	key := testNodeKey1
+	if nodeId == testNodeId2 {
+		key = testNodeKey2
 	}
-	evm.Context = context
-	return evm
+	sig, _ := types.SignCandidateDeposit(key, owner, params.TestChainConfig.ChainID, 0)
+	return sig
+}
+
+// blsPubKey returns the BLS public key of the test node, derived from its id.
+func blsPubKey(nodeId discover.NodeID) []byte {
+	return bls.SecretKeyFromSeed(nodeId.Bytes()).PublicKey().Marshal()
+}
+
+// blsProof returns the proof of possession of the BLS key of the test node.
+func blsProof(nodeId discover.NodeID) []byte {
+	return bls.SecretKeyFromSeed(nodeId.Bytes()).Prove().Marshal()
 }
 
 func newContract() *vm.Contract {
 	callerAddress := vm.AccountRef(common.HexToAddress("0x12"))
-	contract := vm.NewContract(callerAddress, callerAddress, big.NewInt(1000), uint64(1))
+	contract := vm.NewContract(callerAddress, callerAddress, big.NewInt(1000), uint64(1000000))
 	return contract
 }
 
@@ -163,41 +195,409 @@ func TestCandidateDeposit(t *testing.T) {
 		newEvm(),
 	}
 
-	// CandidateDeposit(nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string) ([]byte, error)
-	nodeId := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	// CandidateDeposit(nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string, sig, blsPubKey, blsProof []byte) ([]byte, error)
+	nodeId := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
 	fmt.Println("CandidateDeposit success")
 }
 
+func TestCandidateDepositNodeSigFork(t *testing.T) {
+	state, _ := newChainState()
+	candidatePoolContext, ticketPoolContext := newPool()
+	config := *params.TestChainConfig
+	config.NodeSigBlock = big.NewInt(10)
+	config.BlsBlock = big.NewInt(12)
+	evm := vm.NewEVM(vm.Context{
+		BlockNumber:          big.NewInt(7),
+		CandidatePoolContext: candidatePoolContext,
+		TicketPoolContext:    ticketPoolContext,
+	}, state, &config, vm.Config{})
+	candidateContract := vm.CandidateContract{newContract(), evm}
+
+	owner := common.HexToAddress("0x12")
+	legacy, _ := vm.EncodeInput("CandidateDeposit", testNodeId1, owner, uint32(7000), "192.168.9.184", "16789", "")
+	// Deposits without the node signature are accepted before the fork
+	if _, err := candidateContract.Run(legacy); nil != err {
+		t.Fatalf("legacy CandidateDeposit fail: %v", err)
+	}
+	if can := candidatePoolContext.GetCandidate(state, testNodeId1, evm.BlockNumber); nil == can {
+		t.Fatalf("legacy deposit not applied")
+	}
+	evm.BlockNumber = big.NewInt(10)
+	legacy, _ = vm.EncodeInput("CandidateDeposit", testNodeId2, owner, uint32(7000), "192.168.9.185", "16789", "")
+	if _, err := candidateContract.Run(legacy); err != vm.ErrParamsLen {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrParamsLen)
+	}
+	// Deposits carry the node signature but no BLS key before the BLS fork
+	input, _ := vm.EncodeInput("CandidateDeposit", testNodeId2, owner, uint32(7000), "192.168.9.185", "16789", "", nodeSig(testNodeId2, owner), blsPubKey(testNodeId2), blsProof(testNodeId2))
+	if _, err := candidateContract.Run(input); err != vm.ErrParamsLen {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrParamsLen)
+	}
+	signed, _ := vm.EncodeInput("CandidateDeposit", testNodeId2, owner, uint32(7000), "192.168.9.185", "16789", "", nodeSig(testNodeId2, owner))
+	if _, err := candidateContract.Run(signed); nil != err {
+		t.Fatalf("CandidateDeposit fail: %v", err)
+	}
+	setKey, _ := vm.EncodeInput("SetCandidateBlsKey", testNodeId2, blsPubKey(testNodeId2), blsProof(testNodeId2))
+	if _, err := candidateContract.Run(setKey); err != vm.ErrUndefFunction {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrUndefFunction)
+	}
+
+	// The candidates deposited before the BLS fork register their key after it
+	evm.BlockNumber = big.NewInt(12)
+	if _, err := candidateContract.Run(setKey); nil != err {
+		t.Fatalf("SetCandidateBlsKey fail: %v", err)
+	}
+	if key := state.GetState(common.CandidatePoolAddr, vm.CandidateBlsKey(testNodeId2)); !bytes.Equal(key, blsPubKey(testNodeId2)) {
+		t.Fatalf("BLS key mismatch: have %x, want %x", key, blsPubKey(testNodeId2))
+	}
+	wrongProof, _ := vm.EncodeInput("SetCandidateBlsKey", testNodeId2, blsPubKey(testNodeId2), blsProof(testNodeId1))
+	if _, err := candidateContract.Run(wrongProof); err != vm.ErrBlsKeyIllegal {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrBlsKeyIllegal)
+	}
+}
+
+// Tests that the commands added by a fork are unknown before it.
+func TestCandidateCommandForks(t *testing.T) {
+	state, _ := newChainState()
+	candidatePoolContext, ticketPoolContext := newPool()
+	config := *params.TestChainConfig
+	config.CandidateUpdateBlock = big.NewInt(10)
+	config.LivenessBlock = big.NewInt(20)
+	config.SentryBlock = big.NewInt(30)
+	evm := vm.NewEVM(vm.Context{
+		CandidatePoolContext: candidatePoolContext,
+		TicketPoolContext:    ticketPoolContext,
+	}, state, &config, vm.Config{})
+	candidateContract := vm.CandidateContract{newContract(), evm}
+
+	tests := []struct {
+		fork *big.Int
+		name string
+		args []interface{}
+	}{
+		{config.CandidateUpdateBlock, "IncreaseDeposit", []interface{}{testNodeId1}},
+		{config.CandidateUpdateBlock, "UpdateCandidateInfo", []interface{}{testNodeId1, "192.168.9.184", "16789", uint32(7000)}},
+		{config.CandidateUpdateBlock, "GetCandidatePendingFee", []interface{}{testNodeId1}},
+		{config.LivenessBlock, "Unjail", []interface{}{testNodeId1}},
+		{config.LivenessBlock, "GetCandidateLiveness", []interface{}{testNodeId1}},
+		{config.SentryBlock, "SetCandidateSentries", []interface{}{testNodeId1, "[]"}},
+		{config.SentryBlock, "GetCandidateSentries", []interface{}{testNodeId1}},
+	}
+	for _, tt := range tests {
+		input, err := vm.EncodeInput(tt.name, tt.args...)
+		if err != nil {
+			t.Fatalf("%s: failed to encode input: %v", tt.name, err)
+		}
+		evm.BlockNumber = new(big.Int).Sub(tt.fork, common.Big1)
+		if _, err := candidateContract.Run(input); err != vm.ErrUndefFunction {
+			t.Errorf("%s: error mismatch before the fork: have %v, want %v", tt.name, err, vm.ErrUndefFunction)
+		}
+		evm.BlockNumber = tt.fork
+		if _, err := candidateContract.Run(input); err == vm.ErrUndefFunction {
+			t.Errorf("%s: command unknown after the fork", tt.name)
+		}
+	}
+}
+
+func TestCandidateDepositNodeSig(t *testing.T) {
+	candidateContract := vm.CandidateContract{
+		newContract(),
+		newEvm(),
+	}
+	owner := common.HexToAddress("0x12")
+	host, port, extra := "192.168.9.184", "16789", "{}"
+
+	// signed by another node key
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, host, port, extra, nodeSig(testNodeId2, owner), blsPubKey(testNodeId2), blsProof(testNodeId2)); err != vm.ErrNodeSigIllegal {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrNodeSigIllegal)
+	}
+	// signed for another owner
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, host, port, extra, nodeSig(testNodeId1, common.HexToAddress("0x13")), blsPubKey(testNodeId1), blsProof(testNodeId1)); err != vm.ErrNodeSigIllegal {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrNodeSigIllegal)
+	}
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, host, port, extra, nil, blsPubKey(testNodeId1), blsProof(testNodeId1)); err != vm.ErrNodeSigIllegal {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrNodeSigIllegal)
+	}
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, host, port, extra, nodeSig(testNodeId1, owner), blsPubKey(testNodeId1), blsProof(testNodeId1)); err != nil {
+		t.Fatalf("CandidateDeposit fail: %v", err)
+	}
+	// the nonce moved on, the signature can't be replayed
+	ret, err := candidateContract.GetCandidateNonce(testNodeId1)
+	if err != nil {
+		t.Fatalf("GetCandidateNonce fail: %v", err)
+	}
+	var nonce uint64
+	if err := json.Unmarshal(bytes.TrimRight(ret[64:], "\x00"), &nonce); err != nil || nonce != 1 {
+		t.Fatalf("nonce mismatch: have %d, want 1, err %v", nonce, err)
+	}
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, host, port, extra, nodeSig(testNodeId1, owner), blsPubKey(testNodeId1), blsProof(testNodeId1)); err != vm.ErrNodeSigIllegal {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrNodeSigIllegal)
+	}
+}
+
+func TestCandidateDepositBlsKey(t *testing.T) {
+	candidateContract := vm.CandidateContract{
+		newContract(),
+		newEvm(),
+	}
+	owner := common.HexToAddress("0x12")
+	host, port, extra := "192.168.9.184", "16789", "{}"
+
+	// the proof of another key
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, host, port, extra, nodeSig(testNodeId1, owner), blsPubKey(testNodeId1), blsProof(testNodeId2)); err != vm.ErrBlsKeyIllegal {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrBlsKeyIllegal)
+	}
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, host, port, extra, nodeSig(testNodeId1, owner), nil, blsProof(testNodeId1)); err != vm.ErrBlsKeyIllegal {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrBlsKeyIllegal)
+	}
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, host, port, extra, nodeSig(testNodeId1, owner), blsPubKey(testNodeId1), blsProof(testNodeId1)); err != nil {
+		t.Fatalf("CandidateDeposit fail: %v", err)
+	}
+	ret, err := candidateContract.GetCandidateBlsKey(testNodeId1)
+	if err != nil {
+		t.Fatalf("GetCandidateBlsKey fail: %v", err)
+	}
+	var key hexutil.Bytes
+	if err := json.Unmarshal(bytes.TrimRight(ret[64:], "\x00"), &key); err != nil || !bytes.Equal(key, blsPubKey(testNodeId1)) {
+		t.Fatalf("BLS key mismatch: have %x, want %x, err %v", key, blsPubKey(testNodeId1), err)
+	}
+}
+
+func TestSetCandidateSentries(t *testing.T) {
+	evm := newEvm()
+	candidateContract := vm.CandidateContract{
+		newContract(),
+		evm,
+	}
+	owner := common.HexToAddress("0x12")
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, "192.168.9.184", "16789", "{}", nodeSig(testNodeId1, owner), blsPubKey(testNodeId1), blsProof(testNodeId1)); err != nil {
+		t.Fatalf("CandidateDeposit fail: %v", err)
+	}
+	sentries := "enode://" + testNodeId2.String() + "@10.0.0.1:16789"
+
+	if _, err := candidateContract.SetCandidateSentries(testNodeId1, "enode://"+testNodeId2.String()); err != vm.ErrSentriesIllegal {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrSentriesIllegal)
+	}
+	other := vm.CandidateContract{
+		vm.NewContract(vm.AccountRef(common.HexToAddress("0x13")), vm.AccountRef(common.HexToAddress("0x13")), big.NewInt(1000), uint64(1)),
+		evm,
+	}
+	if _, err := other.SetCandidateSentries(testNodeId1, sentries); err != vm.ErrPermissionDenied {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrPermissionDenied)
+	}
+	if _, err := candidateContract.SetCandidateSentries(testNodeId1, sentries); err != nil {
+		t.Fatalf("SetCandidateSentries fail: %v", err)
+	}
+	ret, err := candidateContract.GetCandidateSentries(testNodeId1)
+	if err != nil {
+		t.Fatalf("GetCandidateSentries fail: %v", err)
+	}
+	var have string
+	if err := json.Unmarshal(bytes.TrimRight(ret[64:], "\x00"), &have); err != nil || have != sentries {
+		t.Fatalf("sentries mismatch: have %q, want %q, err %v", have, sentries, err)
+	}
+
+	// the candidate is dialed through its sentries
+	direct := discover.NodeID{3}
+	nodes := []*discover.Node{discover.NewNode(testNodeId1, net.ParseIP("192.168.9.184"), 16789, 16789), discover.NewNode(direct, net.ParseIP("192.168.9.185"), 16789, 16789)}
+	dial := vm.ConsensusDialNodes(evm.StateDB, nodes)
+	if len(dial) != 2 || dial[0].ID != testNodeId2 || dial[1].ID != direct {
+		t.Fatalf("dial nodes mismatch: %v", dial)
+	}
+}
+
+func TestCandidateIncreaseDeposit(t *testing.T) {
+	evm := newEvm()
+	candidateContract := vm.CandidateContract{
+		newContract(),
+		evm,
+	}
+	owner := common.HexToAddress("0x12")
+	for _, nodeId := range []discover.NodeID{testNodeId1, testNodeId2} {
+		if _, err := candidateContract.CandidateDeposit(nodeId, owner, 7000, "192.168.9.184", "16789", "{}", nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId)); err != nil {
+			t.Fatalf("CandidateDeposit fail: %v", err)
+		}
+	}
+	if _, err := candidateContract.IncreaseDeposit(testNodeId2); err != nil {
+		t.Fatalf("IncreaseDeposit fail: %v", err)
+	}
+	can := evm.CandidatePoolContext.GetCandidate(evm.StateDB, testNodeId2, evm.BlockNumber)
+	if can == nil || can.Deposit.Cmp(big.NewInt(2000)) != 0 || can.BlockNumber.Cmp(big.NewInt(7)) != 0 {
+		t.Fatalf("candidate mismatch after IncreaseDeposit: %+v", can)
+	}
+	// the larger deposit moves to the front of the queue
+	if queue := evm.CandidatePoolContext.GetChosens(evm.StateDB, 0, evm.BlockNumber)[0]; len(queue) != 2 || queue[0].CandidateId != testNodeId2 {
+		t.Fatalf("queue not re-sorted after IncreaseDeposit")
+	}
+
+	other := vm.CandidateContract{
+		vm.NewContract(vm.AccountRef(common.HexToAddress("0x13")), vm.AccountRef(common.HexToAddress("0x13")), big.NewInt(1000), uint64(1)),
+		evm,
+	}
+	if _, err := other.IncreaseDeposit(testNodeId2); err != vm.ErrPermissionDenied {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrPermissionDenied)
+	}
+}
+
+func TestUpdateCandidateInfo(t *testing.T) {
+	evm := newEvm()
+	candidateContract := vm.CandidateContract{
+		newContract(),
+		evm,
+	}
+	owner := common.HexToAddress("0x12")
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, "192.168.9.184", "16789", "{}", nodeSig(testNodeId1, owner), blsPubKey(testNodeId1), blsProof(testNodeId1)); err != nil {
+		t.Fatalf("CandidateDeposit fail: %v", err)
+	}
+	if _, err := candidateContract.UpdateCandidateInfo(testNodeId1, "192.168.9.185", "16790", 10001); err != vm.ErrFeeIllegal {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrFeeIllegal)
+	}
+	if _, err := candidateContract.UpdateCandidateInfo(testNodeId1, "192.168.9.185", "16790", 5000); err != nil {
+		t.Fatalf("UpdateCandidateInfo fail: %v", err)
+	}
+	// host and port change at once, the fee after the delay
+	can := evm.CandidatePoolContext.GetCandidate(evm.StateDB, testNodeId1, evm.BlockNumber)
+	if can.Host != "192.168.9.185" || can.Port != "16790" || can.Fee != 7000 {
+		t.Fatalf("candidate mismatch after UpdateCandidateInfo: %+v", can)
+	}
+	pending := evm.CandidatePoolContext.GetPendingFee(evm.StateDB, testNodeId1)
+	if pending == nil || pending.Fee != 5000 || pending.BlockNumber.Cmp(big.NewInt(9)) != 0 {
+		t.Fatalf("pending fee mismatch: %+v", pending)
+	}
+
+	pool := evm.CandidatePoolContext.(*pposm.CandidatePoolContext)
+	if err := pool.Notify(evm.StateDB, big.NewInt(8)); err != nil {
+		t.Fatalf("Notify fail: %v", err)
+	}
+	if can := pool.GetCandidate(evm.StateDB, testNodeId1, big.NewInt(8)); can.Fee != 7000 {
+		t.Fatalf("fee applied before the delay: %d", can.Fee)
+	}
+	if err := pool.Notify(evm.StateDB, big.NewInt(9)); err != nil {
+		t.Fatalf("Notify fail: %v", err)
+	}
+	if can := pool.GetCandidate(evm.StateDB, testNodeId1, big.NewInt(9)); can.Fee != 5000 {
+		t.Fatalf("fee mismatch after the delay: have %d, want 5000", can.Fee)
+	}
+	if pending := pool.GetPendingFee(evm.StateDB, testNodeId1); pending != nil {
+		t.Fatalf("pending fee not cleared: %+v", pending)
+	}
+}
+
+func TestCandidateLiveness(t *testing.T) {
+	evm := newEvm()
+	stateDB := evm.StateDB.(*state.StateDB)
+	candidateContract := vm.CandidateContract{
+		newContract(),
+		evm,
+	}
+	owner := common.HexToAddress("0x12")
+	for _, nodeId := range []discover.NodeID{testNodeId1, testNodeId2} {
+		if _, err := candidateContract.CandidateDeposit(nodeId, owner, 7000, "192.168.9.184", "16789", "{}", nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId)); err != nil {
+			t.Fatalf("CandidateDeposit fail: %v", err)
+		}
+	}
+	if _, err := candidateContract.IncreaseDeposit(testNodeId1); err != nil {
+		t.Fatalf("IncreaseDeposit fail: %v", err)
+	}
+	stateDB.AddBalance(common.CandidatePoolAddr, big.NewInt(3000))
+
+	pool := evm.CandidatePoolContext.(*pposm.CandidatePoolContext)
+	if _, err := candidateContract.Unjail(testNodeId1); err != pposm.CandidateNotJailedErr {
+		t.Fatalf("error mismatch: have %v, want %v", err, pposm.CandidateNotJailedErr)
+	}
+	// the slots missed before the fork aren't recorded
+	config := *params.TestChainConfig
+	config.LivenessBlock = big.NewInt(8)
+	evm.TicketPoolContext.(*pposm.TicketPoolContext).SetChainConfig(&config)
+	if err := pool.MissSlots(stateDB, testNodeId1, 3, big.NewInt(7)); err != nil {
+		t.Fatalf("MissSlots fail: %v", err)
+	}
+	if liveness := pool.GetLiveness(stateDB, testNodeId1); liveness != nil {
+		t.Fatalf("liveness recorded before the fork: %+v", liveness)
+	}
+	if err := pool.MissSlots(stateDB, testNodeId1, 2, big.NewInt(8)); err != nil {
+		t.Fatalf("MissSlots fail: %v", err)
+	}
+	if liveness := pool.GetLiveness(stateDB, testNodeId1); liveness == nil || liveness.MissedSlots != 2 || liveness.Jailed {
+		t.Fatalf("liveness mismatch below the threshold: %+v", liveness)
+	}
+	if err := pool.MissSlots(stateDB, testNodeId1, 1, big.NewInt(9)); err != nil {
+		t.Fatalf("MissSlots fail: %v", err)
+	}
+	liveness := pool.GetLiveness(stateDB, testNodeId1)
+	if liveness == nil || !liveness.Jailed || liveness.JailedBlock.Cmp(big.NewInt(9)) != 0 || liveness.Penalty.Cmp(big.NewInt(300)) != 0 {
+		t.Fatalf("liveness mismatch at the threshold: %+v", liveness)
+	}
+	if can := pool.GetCandidate(stateDB, testNodeId1, big.NewInt(9)); can.Deposit.Cmp(big.NewInt(1700)) != 0 {
+		t.Fatalf("deposit mismatch after jailing: have %v, want 1700", can.Deposit)
+	}
+	if balance := stateDB.GetBalance(common.RewardPoolAddr); balance.Cmp(big.NewInt(300)) != 0 {
+		t.Fatalf("reward pool balance mismatch: have %v, want 300", balance)
+	}
+
+	// the jailed candidate is left out although it has the largest deposit
+	nodes, err := pool.Election(stateDB, common.Hash{}, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("Election fail: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].ID != testNodeId2 {
+		t.Fatalf("election mismatch: %v", nodes)
+	}
+
+	other := vm.CandidateContract{
+		vm.NewContract(vm.AccountRef(common.HexToAddress("0x13")), vm.AccountRef(common.HexToAddress("0x13")), big.NewInt(0), uint64(1)),
+		evm,
+	}
+	if _, err := other.Unjail(testNodeId1); err != vm.ErrPermissionDenied {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrPermissionDenied)
+	}
+	// the candidate stays jailed for the jail period
+	evm.Context.BlockNumber = big.NewInt(13)
+	if _, err := candidateContract.Unjail(testNodeId1); err != pposm.JailPeriodErr {
+		t.Fatalf("error mismatch: have %v, want %v", err, pposm.JailPeriodErr)
+	}
+	evm.Context.BlockNumber = big.NewInt(14)
+	if _, err := candidateContract.Unjail(testNodeId1); err != nil {
+		t.Fatalf("Unjail fail: %v", err)
+	}
+	ret, err := candidateContract.GetCandidateLiveness(testNodeId1)
+	if err != nil {
+		t.Fatalf("GetCandidateLiveness fail: %v", err)
+	}
+	if data := bytes.TrimRight(ret[64:], "\x00"); string(data) != "null" {
+		t.Fatalf("liveness not cleared by Unjail: %s", data)
+	}
+}
+
 func TestCandidateDetails(t *testing.T) {
 	candidateContract := vm.CandidateContract{
 		newContract(),
 		newEvm(),
 	}
-	nodeId := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
 	fmt.Println("CandidateDeposit success")
 
 	// GetCandidateDetails(nodeIds []discover.NodeID) ([]byte, error)
-	// nodeId = discover.MustHexID("0x11234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	// nodeId = testNodeId2
 	var nodeIds []discover.NodeID
 	nodeIds = append(nodeIds, nodeId)
 	fmt.Println("GetCandidateDetails input==>", "nodeIds: ", nodeId.String())
@@ -218,34 +618,34 @@ func TestGetBatchCandidateDetail(t *testing.T) {
 		newContract(),
 		newEvm(),
 	}
-	nodeId := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
 	fmt.Println("CandidateDeposit1 success")
 
-	nodeId = discover.MustHexID("0x11234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId = testNodeId2
 	owner = common.HexToAddress("0x12")
 	fee = uint32(8000)
 	host = "192.168.9.185"
 	port = "16789"
 	extra = "{\"nodeName\": \"Platon-Shenzhen\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Cosmic wave\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/sz\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err = candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err = candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
 	fmt.Println("CandidateDeposit2 success")
 
 	// GetCandidateDetails(nodeIds []discover.NodeID) ([]byte, error)
-	nodeIds := []discover.NodeID{discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345"), discover.MustHexID("0x11234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")}
+	nodeIds := []discover.NodeID{testNodeId1, testNodeId2}
 	input, _ := json.Marshal(nodeIds)
 	fmt.Println("GetBatchCandidateDetail input==>", "nodeIds: ", string(input))
 	resByte, err := candidateContract.GetCandidateDetails(nodeIds)
@@ -264,27 +664,27 @@ func TestCandidateList(t *testing.T) {
 		newContract(),
 		newEvm(),
 	}
-	nodeId := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
 	fmt.Println("CandidateDeposit1 success")
 
-	nodeId = discover.MustHexID("0x11234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId = testNodeId2
 	owner = common.HexToAddress("0x12")
 	fee = uint32(8000)
 	host = "192.168.9.185"
 	port = "16789"
 	extra = "{\"nodeName\": \"Platon-Shenzhen\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Cosmic wave\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/sz\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err = candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err = candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
@@ -307,14 +707,14 @@ func TestSetCandidateExtra(t *testing.T) {
 		newContract(),
 		newEvm(),
 	}
-	nodeId := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
@@ -350,14 +750,14 @@ func TestCandidateApplyWithdraw(t *testing.T) {
 		newContract(),
 		newEvm(),
 	}
-	nodeId := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
@@ -378,14 +778,14 @@ func TestCandidateWithdraw(t *testing.T) {
 		newContract(),
 		newEvm(),
 	}
-	nodeId := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
@@ -429,14 +829,14 @@ func TestCandidateWithdrawInfos(t *testing.T) {
 		newContract(),
 		newEvm(),
 	}
-	nodeId := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
@@ -482,10 +882,11 @@ func TestCandidatePoolEncode(t *testing.T) {
 	//"enode://751f4f62fccee84fc290d0c68d673e4b0cc6975a5747d2baccb20f954d59ba3315d7bfb6d831523624d003c8c2d33451129e67c3eef3098f711ef3b3e268fd3c@192.168.9.182:16789",
 	//"enode://b6c8c9f99bfebfa4fb174df720b9385dbd398de699ec36750af3f38f8e310d4f0b90447acbef64bdf924c4b59280f3d42bb256e6123b53e9a7e99e4c432549d6@192.168.9.183:16789",
 	//"enode://97e424be5e58bfd4533303f8f515211599fd4ffe208646f7bfdf27885e50b6dd85d957587180988e76ae77b4b6563820a27b16885419e5ba6f575f19f6cb36b0@192.168.9.184:16789"
-	nodeId := []byte("1f3a8672348ff6b789e416762ad53e69063138b8eb4d8780101658f24b2369f1a8e09499226b467d8bc0c4e03e1dc903df857eeb3c67733d21b6aaee2840e429")
+	nodeId := []byte(testNodeId1.String())
 	owner := []byte("0x740ce31b3fac20dac379db243021a51e80ad00d7")
+	sig := nodeSig(testNodeId1, common.HexToAddress(string(owner)))
 	extra := "{\"nodeName\": \"Platon-Shanghai\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-eastern area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/sz\",\"time\":1546503651100}"
-	// CandidateDeposit(nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string)
+	// CandidateDeposit(nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string, sig, blsPubKey, blsProof []byte)
 	var CandidateDeposit [][]byte
 	CandidateDeposit = make([][]byte, 0)
 	CandidateDeposit = append(CandidateDeposit, byteutil.Uint64ToBytes(1001))
@@ -497,6 +898,9 @@ func TestCandidatePoolEncode(t *testing.T) {
 	CandidateDeposit = append(CandidateDeposit, []byte("0.0.0.0"))
 	CandidateDeposit = append(CandidateDeposit, []byte("30303"))
 	CandidateDeposit = append(CandidateDeposit, []byte(extra))
+	CandidateDeposit = append(CandidateDeposit, sig)
+	CandidateDeposit = append(CandidateDeposit, blsPubKey(testNodeId1))
+	CandidateDeposit = append(CandidateDeposit, blsProof(testNodeId1))
 	bufDeposit := new(bytes.Buffer)
 	err := rlp.Encode(bufDeposit, CandidateDeposit)
 	if err != nil {
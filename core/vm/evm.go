@@ -53,7 +53,7 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 			return RunPrecompiledContract(p, input, contract)
 		}
 		// ppos
-		if p := PrecompiledContractsPpos[*contract.CodeAddr]; p != nil {
+		if p := pposContract(evm.ChainConfig(), evm.BlockNumber, *contract.CodeAddr); p != nil {
 			log.Info("IN PPOS PrecompiledContractsPpos ... ")
 			switch r := p.(type) {
 			case *CandidateContract:
@@ -66,6 +66,16 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 				r.Contract = contract
 				r.Evm = evm
 				return RunPrecompiledContract(r, input, contract)
+			case *VCVerifierContract:
+				r = &VCVerifierContract{}
+				r.Contract = contract
+				r.Evm = evm
+				return RunPrecompiledContract(r, input, contract)
+			case *GovernanceContract:
+				r = &GovernanceContract{}
+				r.Contract = contract
+				r.Evm = evm
+				return RunPrecompiledContract(r, input, contract)
 			default:
 				log.Error("error type","contract.CodeAddr",*contract.CodeAddr)
 			}
@@ -109,6 +119,10 @@ type Context struct {
 	BlockNumber *big.Int       // Provides information for NUMBER
 	Time        *big.Int       // Provides information for TIME
 	Difficulty  *big.Int       // Provides information for DIFFICULTY
+
+	//ppos add
+	CandidatePoolContext candidatePoolContext // Provides the candidate pool for the ppos precompiles
+	TicketPoolContext    ticketPoolContext    // Provides the ticket pool for the ppos precompiles
 }
 
 // EVM is the Ethereum Virtual Machine base object and provides
@@ -146,10 +160,6 @@ type EVM struct {
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
-
-	//ppos add
-	CandidatePoolContext candidatePoolContext
-	TicketPoolContext    ticketPoolContext
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -214,7 +224,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		if evm.ChainConfig().IsByzantium(evm.BlockNumber) {
 			precompiles = PrecompiledContractsByzantium
 		}
-		if precompiles[addr] == nil && PrecompiledContractsPpos[addr] == nil && evm.ChainConfig().IsEIP158(evm.BlockNumber) && value.Sign() == 0 {
+		if precompiles[addr] == nil && pposContract(evm.ChainConfig(), evm.BlockNumber, addr) == nil && evm.ChainConfig().IsEIP158(evm.BlockNumber) && value.Sign() == 0 {
 			// Calling a non existing account, don't do anything, but ping the tracer
 			if evm.vmConfig.Debug && evm.depth == 0 {
 				evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
@@ -0,0 +1,650 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/hashicorp/golang-lru"
+)
+
+var (
+	ErrNotVerifier        = errors.New("The node is not a current verifier")
+	ErrParamUnknown       = errors.New("The parameter is not governable")
+	ErrParamValueIllegal  = errors.New("The parameter value is illegal")
+	ErrActiveBlockIllegal = errors.New("The activation block must be after the voting period")
+	ErrProposalExist      = errors.New("The proposal is already exist")
+	ErrProposalNotExist   = errors.New("The proposal is not exist")
+	ErrProposalClosed     = errors.New("The proposal is not in voting period")
+	ErrProposalVoted      = errors.New("The verifier has already voted for the proposal")
+	ErrParamConflict      = errors.New("The parameter value conflicts with the other parameters")
+)
+
+const (
+	SubmitProposalEvent = "SubmitProposalEvent"
+	VoteProposalEvent   = "VoteProposalEvent"
+)
+
+const (
+	// ProposalVotingPeriod is the number of blocks a proposal accepts votes after its submission.
+	ProposalVotingPeriod = 1024
+	// ProposalQuorum is the percentage of the current verifiers which must vote for a proposal.
+	ProposalQuorum = 67
+)
+
+var (
+	proposalIdsKey     = []byte("ParamProposalIds")
+	votingIdsKey       = []byte("ParamVotingIds")
+	pendingVersionsKey = []byte("ParamPendingVersions")
+	paramVersionsKey   = []byte("ParamVersions")
+	// paramVersionsHashKey holds the hash of the activated versions, so that the
+	// governed configs can be looked up in governedCache without decoding them.
+	paramVersionsHashKey = []byte("ParamVersionsHash")
+)
+
+// governedCache caches the governed configs by the base configs and the hash
+// of the activated versions.
+var governedCache, _ = lru.New(64)
+
+type governedKey struct {
+	base interface{}
+	hash common.Hash
+}
+
+// ParamProposalKey returns the state key of a parameter proposal.
+func ParamProposalKey(proposalId common.Hash) []byte {
+	return append([]byte("ParamProposal"), proposalId.Bytes()...)
+}
+
+type paramSetter func(cfg *params.PposConfig, value string) error
+
+// uint32Param sets a positive integer parameter.
+func uint32Param(field func(cfg *params.PposConfig) *uint32) paramSetter {
+	return func(cfg *params.PposConfig, value string) error {
+		v, err := strconv.ParseUint(value, 10, 32)
+		if nil != err || v == 0 {
+			return ErrParamValueIllegal
+		}
+		*field(cfg) = uint32(v)
+		return nil
+	}
+}
+
+// amountParam sets a positive amount parameter, in decimal.
+func amountParam(field func(cfg *params.PposConfig) *string) paramSetter {
+	return func(cfg *params.PposConfig, value string) error {
+		amount, ok := new(big.Int).SetString(value, 10)
+		if !ok || amount.Sign() <= 0 {
+			return ErrParamValueIllegal
+		}
+		*field(cfg) = amount.String()
+		return nil
+	}
+}
+
+// governedParams are the parameters which can be changed by proposals.
+var governedParams = map[string]paramSetter{
+	"Candidate.Threshold":           amountParam(func(cfg *params.PposConfig) *string { return &cfg.CandidateConfig.Threshold }),
+	"Candidate.DepositLimit":        uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.CandidateConfig.DepositLimit }),
+	"Candidate.Allowed":             uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.CandidateConfig.Allowed }),
+	"Candidate.MaxCount":            uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.CandidateConfig.MaxCount }),
+	"Candidate.MaxChair":            uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.CandidateConfig.MaxChair }),
+	"Candidate.RefundBlockNumber":   uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.CandidateConfig.RefundBlockNumber }),
+	"Candidate.FeeChangeDelay":      uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.CandidateConfig.FeeChangeDelay }),
+	"Candidate.LivenessThreshold":   uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.CandidateConfig.LivenessThreshold }),
+	"Candidate.LivenessPenalty":     amountParam(func(cfg *params.PposConfig) *string { return &cfg.CandidateConfig.LivenessPenalty }),
+	"Candidate.JailPeriod":          uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.CandidateConfig.JailPeriod }),
+	"Ticket.TicketPrice":            amountParam(func(cfg *params.PposConfig) *string { return &cfg.TicketConfig.TicketPrice }),
+	"Ticket.MaxCount":               uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.TicketConfig.MaxCount }),
+	"Ticket.ExpireBlockNumber":      uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.TicketConfig.ExpireBlockNumber }),
+	"Ticket.PriceEpoch":             uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.TicketConfig.PriceEpoch }),
+	"Ticket.TargetUtilization":      uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.TicketConfig.TargetUtilization }),
+	"Ticket.PriceAdjustDenominator": uint32Param(func(cfg *params.PposConfig) *uint32 { return &cfg.TicketConfig.PriceAdjustDenominator }),
+	"Ticket.MinTicketPrice":         amountParam(func(cfg *params.PposConfig) *string { return &cfg.TicketConfig.MinTicketPrice }),
+	"Ticket.MaxTicketPrice":         amountParam(func(cfg *params.PposConfig) *string { return &cfg.TicketConfig.MaxTicketPrice }),
+}
+
+type cbftParamSetter func(cfg *params.CbftConfig, value string) error
+
+// int64Param sets an integer parameter which is at least min.
+func int64Param(field func(cfg *params.CbftConfig) *int64, min int64) cbftParamSetter {
+	return func(cfg *params.CbftConfig, value string) error {
+		v, err := strconv.ParseInt(value, 10, 64)
+		if nil != err || v < min {
+			return ErrParamValueIllegal
+		}
+		*field(cfg) = v
+		return nil
+	}
+}
+
+// governedCbftParams are the consensus parameters which can be changed by proposals.
+var governedCbftParams = map[string]cbftParamSetter{
+	"Cbft.Duration":   int64Param(func(cfg *params.CbftConfig) *int64 { return &cfg.Duration }, 2),
+	"Cbft.MaxLatency": int64Param(func(cfg *params.CbftConfig) *int64 { return &cfg.MaxLatency }, 1),
+}
+
+func copyPposConfig(cfg *params.PposConfig) *params.PposConfig {
+	cpy := &params.PposConfig{
+		CandidateConfig: new(params.CandidateConfig),
+		TicketConfig:    new(params.TicketConfig),
+	}
+	if nil == cfg {
+		return cpy
+	}
+	if nil != cfg.CandidateConfig {
+		*cpy.CandidateConfig = *cfg.CandidateConfig
+	}
+	if nil != cfg.TicketConfig {
+		*cpy.TicketConfig = *cfg.TicketConfig
+	}
+	return cpy
+}
+
+func copyCbftConfig(cfg *params.CbftConfig) *params.CbftConfig {
+	cpy := new(params.CbftConfig)
+	if nil != cfg {
+		*cpy = *cfg
+	}
+	return cpy
+}
+
+// checkPposConfig checks the relations between the ppos parameters.
+func checkPposConfig(cfg *params.PposConfig) error {
+	if can := cfg.CandidateConfig; nil != can && can.MaxChair > can.MaxCount {
+		return ErrParamConflict
+	}
+	if ticket := cfg.TicketConfig; nil != ticket {
+		min, minOk := new(big.Int).SetString(ticket.MinTicketPrice, 10)
+		max, maxOk := new(big.Int).SetString(ticket.MaxTicketPrice, 10)
+		if minOk && maxOk && min.Cmp(max) > 0 {
+			return ErrParamConflict
+		}
+	}
+	return nil
+}
+
+// checkCbftConfig checks the relations between the consensus parameters, a
+// block must reach the other nodes within the slot of its producer.
+func checkCbftConfig(cfg *params.CbftConfig) error {
+	if cfg.MaxLatency > 0 && cfg.Duration > 0 && cfg.MaxLatency >= cfg.Duration*1000 {
+		return ErrParamConflict
+	}
+	return nil
+}
+
+// applyParamVersion sets the parameter name to value in the ppos or the consensus configs.
+func applyParamVersion(ppos *params.PposConfig, cbft *params.CbftConfig, name, value string) error {
+	if setter, ok := governedParams[name]; ok {
+		return setter(ppos, value)
+	}
+	if setter, ok := governedCbftParams[name]; ok {
+		return setter(cbft, value)
+	}
+	return ErrParamUnknown
+}
+
+// GovernedPposConfig returns the ppos configs in effect, the base configs with
+// every activated parameter version applied in activation order. A version
+// which conflicts with the versions before it is skipped.
+// base is returned as is when no version was activated yet.
+func GovernedPposConfig(state StateDB, base *params.PposConfig) *params.PposConfig {
+	hash := common.BytesToHash(state.GetState(common.GovernanceAddr, paramVersionsHashKey))
+	if nil == base || hash == (common.Hash{}) {
+		return base
+	}
+	key := governedKey{base, hash}
+	if cached, ok := governedCache.Get(key); ok {
+		return cached.(*params.PposConfig)
+	}
+	cfg := copyPposConfig(base)
+	for _, v := range getParamVersions(state, paramVersionsKey) {
+		setter, ok := governedParams[v.Name]
+		if !ok {
+			continue
+		}
+		next := copyPposConfig(cfg)
+		if err := setter(next, v.Value); nil != err {
+			log.Error("Failed to apply parameter version", "name", v.Name, "value", v.Value, "err", err)
+			continue
+		}
+		if err := checkPposConfig(next); nil != err {
+			log.Error("Failed to apply parameter version", "name", v.Name, "value", v.Value, "err", err)
+			continue
+		}
+		cfg = next
+	}
+	governedCache.Add(key, cfg)
+	return cfg
+}
+
+// GovernedCbftConfig returns the consensus configs in effect, the same way as
+// GovernedPposConfig.
+func GovernedCbftConfig(state StateDB, base *params.CbftConfig) *params.CbftConfig {
+	hash := common.BytesToHash(state.GetState(common.GovernanceAddr, paramVersionsHashKey))
+	if nil == base || hash == (common.Hash{}) {
+		return base
+	}
+	key := governedKey{base, hash}
+	if cached, ok := governedCache.Get(key); ok {
+		return cached.(*params.CbftConfig)
+	}
+	cfg := copyCbftConfig(base)
+	for _, v := range getParamVersions(state, paramVersionsKey) {
+		setter, ok := governedCbftParams[v.Name]
+		if !ok {
+			continue
+		}
+		next := copyCbftConfig(cfg)
+		if err := setter(next, v.Value); nil != err {
+			log.Error("Failed to apply parameter version", "name", v.Name, "value", v.Value, "err", err)
+			continue
+		}
+		if err := checkCbftConfig(next); nil != err {
+			log.Error("Failed to apply parameter version", "name", v.Name, "value", v.Value, "err", err)
+			continue
+		}
+		cfg = next
+	}
+	governedCache.Add(key, cfg)
+	return cfg
+}
+
+// GetParamVersions returns the activated parameter versions in activation order.
+func GetParamVersions(state StateDB) []*types.ParamVersion {
+	return getParamVersions(state, paramVersionsKey)
+}
+
+// NotifyGovernance closes the proposals whose voting period ended and activates
+// the approved versions which apply from the next block.
+func NotifyGovernance(state StateDB, blockNumber *big.Int) error {
+	height := blockNumber.Uint64()
+	votingIds := getHashList(state, votingIdsKey)
+	open := make([]common.Hash, 0, len(votingIds))
+	for _, id := range votingIds {
+		proposal, err := getParamProposal(state, id)
+		if nil != err {
+			return err
+		}
+		if proposal.EndVotingBlock >= height {
+			open = append(open, id)
+			continue
+		}
+		proposal.Status = types.ProposalRejected
+		if err := setParamProposal(state, proposal); nil != err {
+			return err
+		}
+		log.Info("Parameter proposal rejected", "blockNumber", height, "proposalId", id.Hex(), "yeas", len(proposal.Yeas))
+	}
+	if len(open) != len(votingIds) {
+		if err := setHashList(state, votingIdsKey, open); nil != err {
+			return err
+		}
+	}
+
+	pending := getParamVersions(state, pendingVersionsKey)
+	if len(pending) == 0 {
+		return nil
+	}
+	versions := getParamVersions(state, paramVersionsKey)
+	remain := make([]*types.ParamVersion, 0, len(pending))
+	for _, v := range pending {
+		if v.ActiveBlock > height+1 {
+			remain = append(remain, v)
+			continue
+		}
+		versions = append(versions, v)
+		if proposal, err := getParamProposal(state, v.ProposalId); nil == err {
+			proposal.Status = types.ProposalActive
+			if err := setParamProposal(state, proposal); nil != err {
+				return err
+			}
+		}
+		log.Info("Parameter version activated", "blockNumber", height, "name", v.Name, "value", v.Value, "activeBlock", v.ActiveBlock)
+	}
+	if len(remain) == len(pending) {
+		return nil
+	}
+	if err := setParamVersions(state, pendingVersionsKey, remain); nil != err {
+		return err
+	}
+	return setParamVersions(state, paramVersionsKey, versions)
+}
+
+// GovernanceContract lets the current verifiers change the ppos parameters
+// by submitting and voting for proposals.
+type GovernanceContract struct {
+	Contract *Contract
+	Evm      *EVM
+}
+
+func (g *GovernanceContract) RequiredGas(input []byte) uint64 {
+	return g.Evm.ChainConfig().PposGasTable(g.Evm.BlockNumber).Call
+}
+
+// useGas charges the proposal updates of the command.
+func (g *GovernanceContract) useGas(name string, args []reflect.Value) error {
+	var gas uint64
+	switch name {
+	case "SubmitProposal", "VoteProposal":
+		gas = g.Evm.ChainConfig().PposGasTable(g.Evm.BlockNumber).QueueUpdate
+	}
+	return usePposGas(g.Contract, gas)
+}
+
+func (g *GovernanceContract) Run(input []byte) ([]byte, error) {
+	if nil == g.Evm.CandidatePoolContext {
+		log.Error("Failed to GovernanceContract Run", "ErrCandidatePoolEmpty: ", ErrCandidatePoolEmpty.Error())
+		return nil, ErrCandidatePoolEmpty
+	}
+	return execute(input, g.commands(), g.useGas)
+}
+
+// commands returns the command table of the contract.
+func (g *GovernanceContract) commands() map[string]interface{} {
+	return map[string]interface{}{
+		"SubmitProposal":   g.SubmitProposal,
+		"VoteProposal":     g.VoteProposal,
+		"GetProposal":      g.GetProposal,
+		"GetProposalList":  g.GetProposalList,
+		"GetParamVersions": g.GetParamVersions,
+	}
+}
+
+// checkVerifier checks that nodeId is a current verifier operated by the sender,
+// it returns the current verifiers.
+func (g *GovernanceContract) checkVerifier(nodeId discover.NodeID) (types.CandidateQueue, error) {
+	from := g.Contract.caller.Address()
+	verifiers := g.Evm.CandidatePoolContext.GetChairpersons(g.Evm.StateDB, g.Evm.Context.BlockNumber)
+	for _, can := range verifiers {
+		if can.CandidateId != nodeId {
+			continue
+		}
+		if !bytes.Equal(can.Owner.Bytes(), from.Bytes()) {
+			return nil, ErrPermissionDenied
+		}
+		return verifiers, nil
+	}
+	return nil, ErrNotVerifier
+}
+
+// SubmitProposal proposes value for the parameter name from activeBlock on,
+// the proposer votes for its own proposal.
+func (g *GovernanceContract) SubmitProposal(nodeId discover.NodeID, name, value string, activeBlock uint64) ([]byte, error) {
+	txHash := g.Evm.StateDB.TxHash()
+	from := g.Contract.caller.Address()
+	height := g.Evm.Context.BlockNumber
+	log.Info("Input to SubmitProposal", "blockNumber", height.String(), "nodeId: ", nodeId.String(), " from: ", from.Hex(), " txHash: ", txHash.Hex(),
+		" name: ", name, " value: ", value, " activeBlock: ", activeBlock)
+	verifiers, err := g.checkVerifier(nodeId)
+	if nil != err {
+		log.Error("Failed to SubmitProposal", "blockNumber", height.String(), "err: ", err.Error())
+		return nil, err
+	}
+	if err := g.checkProposal(name, value); nil != err {
+		log.Error("Failed to SubmitProposal", "blockNumber", height.String(), "err: ", err.Error())
+		return nil, err
+	}
+	endVotingBlock := height.Uint64() + ProposalVotingPeriod
+	if activeBlock <= endVotingBlock {
+		log.Error("Failed to SubmitProposal", "blockNumber", height.String(), "endVotingBlock", endVotingBlock, "ErrActiveBlockIllegal: ", ErrActiveBlockIllegal.Error())
+		return nil, ErrActiveBlockIllegal
+	}
+	if len(g.Evm.StateDB.GetState(common.GovernanceAddr, ParamProposalKey(txHash))) != 0 {
+		log.Error("Failed to SubmitProposal", "blockNumber", height.String(), "ErrProposalExist: ", ErrProposalExist.Error())
+		return nil, ErrProposalExist
+	}
+	proposal := &types.ParamProposal{
+		ProposalId:     txHash,
+		Proposer:       nodeId,
+		Name:           name,
+		Value:          value,
+		SubmitBlock:    height.Uint64(),
+		EndVotingBlock: endVotingBlock,
+		ActiveBlock:    activeBlock,
+		Yeas:           []discover.NodeID{nodeId},
+		Status:         types.ProposalVoting,
+	}
+	if err := setHashList(g.Evm.StateDB, proposalIdsKey, append(getHashList(g.Evm.StateDB, proposalIdsKey), txHash)); nil != err {
+		return nil, err
+	}
+	if err := setHashList(g.Evm.StateDB, votingIdsKey, append(getHashList(g.Evm.StateDB, votingIdsKey), txHash)); nil != err {
+		return nil, err
+	}
+	if err := g.tally(proposal, verifiers); nil != err {
+		log.Error("Failed to SubmitProposal", "blockNumber", height.String(), "err: ", err.Error())
+		return nil, err
+	}
+	data := txHash.Hex()
+	r := ResultCommon{true, data, "success"}
+	event, _ := json.Marshal(r)
+	g.addLog(SubmitProposalEvent, string(event))
+	log.Info("Result of SubmitProposal", "blockNumber", height.String(), "json: ", string(event))
+	return DecodeResultStr(data), nil
+}
+
+// VoteProposal votes for the proposal on behalf of the verifier nodeId.
+func (g *GovernanceContract) VoteProposal(nodeId discover.NodeID, proposalId common.Hash) ([]byte, error) {
+	from := g.Contract.caller.Address()
+	height := g.Evm.Context.BlockNumber
+	log.Info("Input to VoteProposal", "blockNumber", height.String(), "nodeId: ", nodeId.String(), " from: ", from.Hex(), " proposalId: ", proposalId.Hex())
+	verifiers, err := g.checkVerifier(nodeId)
+	if nil != err {
+		log.Error("Failed to VoteProposal", "blockNumber", height.String(), "err: ", err.Error())
+		return nil, err
+	}
+	proposal, err := getParamProposal(g.Evm.StateDB, proposalId)
+	if nil != err {
+		log.Error("Failed to VoteProposal", "blockNumber", height.String(), "err: ", err.Error())
+		return nil, err
+	}
+	if proposal.Status != types.ProposalVoting || proposal.EndVotingBlock < height.Uint64() {
+		log.Error("Failed to VoteProposal", "blockNumber", height.String(), "status", proposal.Status, "ErrProposalClosed: ", ErrProposalClosed.Error())
+		return nil, ErrProposalClosed
+	}
+	for _, yea := range proposal.Yeas {
+		if yea == nodeId {
+			log.Error("Failed to VoteProposal", "blockNumber", height.String(), "ErrProposalVoted: ", ErrProposalVoted.Error())
+			return nil, ErrProposalVoted
+		}
+	}
+	proposal.Yeas = append(proposal.Yeas, nodeId)
+	if err := g.tally(proposal, verifiers); nil != err {
+		log.Error("Failed to VoteProposal", "blockNumber", height.String(), "err: ", err.Error())
+		return nil, err
+	}
+	r := ResultCommon{true, proposalId.Hex(), "success"}
+	event, _ := json.Marshal(r)
+	g.addLog(VoteProposalEvent, string(event))
+	log.Info("Result of VoteProposal", "blockNumber", height.String(), "json: ", string(event))
+	return nil, nil
+}
+
+// checkProposal checks value for the parameter name, and that it doesn't
+// conflict with the configs in effect once the pending versions are activated.
+func (g *GovernanceContract) checkProposal(name, value string) error {
+	ppos := copyPposConfig(g.Evm.CandidatePoolContext.PposConfig(g.Evm.StateDB))
+	cbft := copyCbftConfig(GovernedCbftConfig(g.Evm.StateDB, g.Evm.ChainConfig().Cbft))
+	for _, v := range getParamVersions(g.Evm.StateDB, pendingVersionsKey) {
+		applyParamVersion(ppos, cbft, v.Name, v.Value)
+	}
+	if err := applyParamVersion(ppos, cbft, name, value); nil != err {
+		return err
+	}
+	if err := checkPposConfig(ppos); nil != err {
+		return err
+	}
+	return checkCbftConfig(cbft)
+}
+
+// tally approves the proposal once the yeas of the current verifiers reach the
+// quorum of them, and stores it. The yeas of the nodes which left the verifiers
+// don't count. A proposal which conflicts with the proposals approved since its
+// submission is rejected.
+func (g *GovernanceContract) tally(proposal *types.ParamProposal, verifiers types.CandidateQueue) error {
+	current := make(map[discover.NodeID]bool, len(verifiers))
+	for _, can := range verifiers {
+		current[can.CandidateId] = true
+	}
+	yeas := 0
+	for _, yea := range proposal.Yeas {
+		if current[yea] {
+			yeas++
+		}
+	}
+	if yeas*100 >= ProposalQuorum*len(verifiers) {
+		proposal.Status = types.ProposalApproved
+		if err := g.checkProposal(proposal.Name, proposal.Value); nil != err {
+			proposal.Status = types.ProposalRejected
+			log.Info("Parameter proposal rejected", "proposalId", proposal.ProposalId.Hex(), "err", err)
+		}
+		votingIds := getHashList(g.Evm.StateDB, votingIdsKey)
+		for i, id := range votingIds {
+			if id == proposal.ProposalId {
+				votingIds = append(votingIds[:i], votingIds[i+1:]...)
+				break
+			}
+		}
+		if err := setHashList(g.Evm.StateDB, votingIdsKey, votingIds); nil != err {
+			return err
+		}
+		if proposal.Status == types.ProposalRejected {
+			return setParamProposal(g.Evm.StateDB, proposal)
+		}
+		pending := append(getParamVersions(g.Evm.StateDB, pendingVersionsKey), &types.ParamVersion{
+			Name:        proposal.Name,
+			Value:       proposal.Value,
+			ActiveBlock: proposal.ActiveBlock,
+			ProposalId:  proposal.ProposalId,
+		})
+		if err := setParamVersions(g.Evm.StateDB, pendingVersionsKey, pending); nil != err {
+			return err
+		}
+		log.Info("Parameter proposal approved", "proposalId", proposal.ProposalId.Hex(), "yeas", yeas, "verifiers", len(verifiers))
+	}
+	return setParamProposal(g.Evm.StateDB, proposal)
+}
+
+// GetProposal returns the proposal by proposalId.
+func (g *GovernanceContract) GetProposal(proposalId common.Hash) ([]byte, error) {
+	proposal, err := getParamProposal(g.Evm.StateDB, proposalId)
+	if nil != err {
+		log.Error("Failed to GetProposal", "proposalId", proposalId.Hex(), "err: ", err.Error())
+		return nil, err
+	}
+	data, _ := json.Marshal(proposal)
+	return DecodeResultStr(string(data)), nil
+}
+
+// GetProposalList returns every proposal in submission order.
+func (g *GovernanceContract) GetProposalList() ([]byte, error) {
+	ids := getHashList(g.Evm.StateDB, proposalIdsKey)
+	if err := usePposGas(g.Contract, uint64(len(ids))*g.Evm.ChainConfig().PposGasTable(g.Evm.BlockNumber).QueryItem); nil != err {
+		return nil, err
+	}
+	proposals := make([]*types.ParamProposal, 0, len(ids))
+	for _, id := range ids {
+		proposal, err := getParamProposal(g.Evm.StateDB, id)
+		if nil != err {
+			return nil, err
+		}
+		proposals = append(proposals, proposal)
+	}
+	data, _ := json.Marshal(proposals)
+	return DecodeResultStr(string(data)), nil
+}
+
+// GetParamVersions returns the activated parameter versions.
+func (g *GovernanceContract) GetParamVersions() ([]byte, error) {
+	data, _ := json.Marshal(GetParamVersions(g.Evm.StateDB))
+	return DecodeResultStr(string(data)), nil
+}
+
+// transaction add event
+func (g *GovernanceContract) addLog(event, data string) {
+	var logdata [][]byte
+	logdata = make([][]byte, 0)
+	logdata = append(logdata, []byte(data))
+	buf := new(bytes.Buffer)
+	if err := rlp.Encode(buf, logdata); nil != err {
+		log.Error("Failed to GovernanceContract addlog", "rlp encode fail: ", err.Error())
+	}
+	g.Evm.StateDB.AddLog(&types.Log{
+		Address:     common.GovernanceAddr,
+		Topics:      []common.Hash{common.BytesToHash(crypto.Keccak256([]byte(event)))},
+		Data:        buf.Bytes(),
+		BlockNumber: g.Evm.Context.BlockNumber.Uint64(),
+	})
+}
+
+func getParamProposal(state StateDB, proposalId common.Hash) (*types.ParamProposal, error) {
+	blob := state.GetState(common.GovernanceAddr, ParamProposalKey(proposalId))
+	if len(blob) == 0 {
+		return nil, ErrProposalNotExist
+	}
+	proposal := new(types.ParamProposal)
+	if err := rlp.DecodeBytes(blob, proposal); nil != err {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+func setParamProposal(state StateDB, proposal *types.ParamProposal) error {
+	blob, err := rlp.EncodeToBytes(proposal)
+	if nil != err {
+		return err
+	}
+	state.SetState(common.GovernanceAddr, ParamProposalKey(proposal.ProposalId), blob)
+	return nil
+}
+
+func getHashList(state StateDB, key []byte) []common.Hash {
+	var ids []common.Hash
+	if blob := state.GetState(common.GovernanceAddr, key); len(blob) != 0 {
+		if err := rlp.DecodeBytes(blob, &ids); nil != err {
+			log.Error("Failed to decode proposal ids", "key", string(key), "err", err)
+		}
+	}
+	return ids
+}
+
+func setHashList(state StateDB, key []byte, ids []common.Hash) error {
+	blob, err := rlp.EncodeToBytes(ids)
+	if nil != err {
+		return err
+	}
+	state.SetState(common.GovernanceAddr, key, blob)
+	return nil
+}
+
+func getParamVersions(state StateDB, key []byte) []*types.ParamVersion {
+	var versions []*types.ParamVersion
+	if blob := state.GetState(common.GovernanceAddr, key); len(blob) != 0 {
+		if err := rlp.DecodeBytes(blob, &versions); nil != err {
+			log.Error("Failed to decode parameter versions", "key", string(key), "err", err)
+		}
+	}
+	return versions
+}
+
+func setParamVersions(state StateDB, key []byte, versions []*types.ParamVersion) error {
+	blob, err := rlp.EncodeToBytes(versions)
+	if nil != err {
+		return err
+	}
+	state.SetState(common.GovernanceAddr, key, blob)
+	if bytes.Equal(key, paramVersionsKey) {
+		state.SetState(common.GovernanceAddr, paramVersionsHashKey, crypto.Keccak256(blob))
+	}
+	return nil
+}
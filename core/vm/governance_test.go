@@ -0,0 +1,278 @@
+package vm_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/ppos"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+)
+
+// chairContext serves a fixed set of verifiers.
+type chairContext struct {
+	*pposm.CandidatePoolContext
+	chairs types.CandidateQueue
+}
+
+func (c *chairContext) GetChairpersons(state vm.StateDB, blockNumber *big.Int) types.CandidateQueue {
+	return c.chairs
+}
+
+var testVerifiers = types.CandidateQueue{
+	{CandidateId: discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012341"), Owner: common.HexToAddress("0x12")},
+	{CandidateId: discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012342"), Owner: common.HexToAddress("0x13")},
+	{CandidateId: discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012343"), Owner: common.HexToAddress("0x14")},
+}
+
+func newGovernance() (*vm.EVM, *chairContext) {
+	evm := newEvm()
+	candidatePoolContext := &chairContext{evm.CandidatePoolContext.(*pposm.CandidatePoolContext), testVerifiers}
+	evm.CandidatePoolContext = candidatePoolContext
+	return evm, candidatePoolContext
+}
+
+func governanceFrom(evm *vm.EVM, from common.Address) *vm.GovernanceContract {
+	caller := vm.AccountRef(from)
+	return &vm.GovernanceContract{vm.NewContract(caller, caller, big.NewInt(0), uint64(1)), evm}
+}
+
+func getProposal(t *testing.T, g *vm.GovernanceContract, proposalId common.Hash) *types.ParamProposal {
+	ret, err := g.GetProposal(proposalId)
+	if err != nil {
+		t.Fatalf("GetProposal fail: %v", err)
+	}
+	proposal := new(types.ParamProposal)
+	if err := json.Unmarshal(bytes.TrimRight(ret[64:], "\x00"), proposal); err != nil {
+		t.Fatalf("GetProposal decode fail: %v", err)
+	}
+	return proposal
+}
+
+func TestSubmitProposal(t *testing.T) {
+	evm, _ := newGovernance()
+	activeBlock := evm.Context.BlockNumber.Uint64() + vm.ProposalVotingPeriod + 1
+
+	tests := []struct {
+		from        common.Address
+		nodeId      discover.NodeID
+		name, value string
+		activeBlock uint64
+		err         error
+	}{
+		{common.HexToAddress("0x12"), testNodeId1, "Candidate.MaxCount", "5", activeBlock, vm.ErrNotVerifier},
+		{common.HexToAddress("0x13"), testVerifiers[0].CandidateId, "Candidate.MaxCount", "5", activeBlock, vm.ErrPermissionDenied},
+		{common.HexToAddress("0x12"), testVerifiers[0].CandidateId, "Candidate.Unknown", "5", activeBlock, vm.ErrParamUnknown},
+		{common.HexToAddress("0x12"), testVerifiers[0].CandidateId, "Candidate.MaxCount", "0", activeBlock, vm.ErrParamValueIllegal},
+		{common.HexToAddress("0x12"), testVerifiers[0].CandidateId, "Ticket.TicketPrice", "-1", activeBlock, vm.ErrParamValueIllegal},
+		{common.HexToAddress("0x12"), testVerifiers[0].CandidateId, "Candidate.MaxCount", "5", activeBlock - 1, vm.ErrActiveBlockIllegal},
+		{common.HexToAddress("0x12"), testVerifiers[0].CandidateId, "Candidate.MaxChair", "4", activeBlock, vm.ErrParamConflict},
+		{common.HexToAddress("0x12"), testVerifiers[0].CandidateId, "Cbft.Duration", "1", activeBlock, vm.ErrParamValueIllegal},
+		{common.HexToAddress("0x12"), testVerifiers[0].CandidateId, "Candidate.MaxCount", "5", activeBlock, nil},
+	}
+	for i, tt := range tests {
+		evm.StateDB.(*state.StateDB).Prepare(common.BigToHash(big.NewInt(int64(i+1))), common.Hash{}, i)
+		if _, err := governanceFrom(evm, tt.from).SubmitProposal(tt.nodeId, tt.name, tt.value, tt.activeBlock); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	proposal := getProposal(t, governanceFrom(evm, common.HexToAddress("0x12")), common.BigToHash(big.NewInt(int64(len(tests)))))
+	if proposal.Status != types.ProposalVoting || len(proposal.Yeas) != 1 || proposal.Yeas[0] != testVerifiers[0].CandidateId {
+		t.Fatalf("proposal mismatch: status %d, yeas %v", proposal.Status, proposal.Yeas)
+	}
+}
+
+func TestVoteProposal(t *testing.T) {
+	evm, candidatePoolContext := newGovernance()
+	stateDB := evm.StateDB.(*state.StateDB)
+	activeBlock := evm.Context.BlockNumber.Uint64() + vm.ProposalVotingPeriod + 10
+	proposalId := common.HexToHash("0x01")
+	stateDB.Prepare(proposalId, common.Hash{}, 0)
+
+	if _, err := governanceFrom(evm, common.HexToAddress("0x12")).SubmitProposal(testVerifiers[0].CandidateId, "Candidate.MaxCount", "5", activeBlock); err != nil {
+		t.Fatalf("SubmitProposal fail: %v", err)
+	}
+	if _, err := governanceFrom(evm, common.HexToAddress("0x13")).VoteProposal(testVerifiers[1].CandidateId, proposalId); err != nil {
+		t.Fatalf("VoteProposal fail: %v", err)
+	}
+	if _, err := governanceFrom(evm, common.HexToAddress("0x13")).VoteProposal(testVerifiers[1].CandidateId, proposalId); err != vm.ErrProposalVoted {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrProposalVoted)
+	}
+	if _, err := governanceFrom(evm, common.HexToAddress("0x13")).VoteProposal(testVerifiers[1].CandidateId, common.HexToHash("0x02")); err != vm.ErrProposalNotExist {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrProposalNotExist)
+	}
+	// two of three verifiers are below the quorum
+	g := governanceFrom(evm, common.HexToAddress("0x14"))
+	if proposal := getProposal(t, g, proposalId); proposal.Status != types.ProposalVoting {
+		t.Fatalf("status mismatch: have %d, want %d", proposal.Status, types.ProposalVoting)
+	}
+	if _, err := g.VoteProposal(testVerifiers[2].CandidateId, proposalId); err != nil {
+		t.Fatalf("VoteProposal fail: %v", err)
+	}
+	if proposal := getProposal(t, g, proposalId); proposal.Status != types.ProposalApproved {
+		t.Fatalf("status mismatch: have %d, want %d", proposal.Status, types.ProposalApproved)
+	}
+
+	// the approved value applies from the activation block
+	if err := vm.NotifyGovernance(stateDB, new(big.Int).SetUint64(activeBlock-2)); err != nil {
+		t.Fatalf("NotifyGovernance fail: %v", err)
+	}
+	if maxCount := candidatePoolContext.MaxCount(stateDB); maxCount != 3 {
+		t.Fatalf("MaxCount mismatch before activation: have %d, want 3", maxCount)
+	}
+	if err := vm.NotifyGovernance(stateDB, new(big.Int).SetUint64(activeBlock-1)); err != nil {
+		t.Fatalf("NotifyGovernance fail: %v", err)
+	}
+	if maxCount := candidatePoolContext.MaxCount(stateDB); maxCount != 5 {
+		t.Fatalf("MaxCount mismatch after activation: have %d, want 5", maxCount)
+	}
+	if proposal := getProposal(t, g, proposalId); proposal.Status != types.ProposalActive {
+		t.Fatalf("status mismatch: have %d, want %d", proposal.Status, types.ProposalActive)
+	}
+	versions := vm.GetParamVersions(stateDB)
+	if len(versions) != 1 || versions[0].Name != "Candidate.MaxCount" || versions[0].Value != "5" || versions[0].ProposalId != proposalId {
+		t.Fatalf("versions mismatch: %v", versions)
+	}
+	// the genesis configs are left untouched
+	base := &params.PposConfig{CandidateConfig: &params.CandidateConfig{MaxCount: 3}, TicketConfig: &params.TicketConfig{}}
+	if cfg := vm.GovernedPposConfig(stateDB, base); cfg.CandidateConfig.MaxCount != 5 || base.CandidateConfig.MaxCount != 3 {
+		t.Fatalf("configs mismatch: have %d, base %d", cfg.CandidateConfig.MaxCount, base.CandidateConfig.MaxCount)
+	}
+}
+
+func TestVoteVerifierLeft(t *testing.T) {
+	evm, candidatePoolContext := newGovernance()
+	stateDB := evm.StateDB.(*state.StateDB)
+	activeBlock := evm.Context.BlockNumber.Uint64() + vm.ProposalVotingPeriod + 10
+	proposalId := common.HexToHash("0x01")
+	stateDB.Prepare(proposalId, common.Hash{}, 0)
+
+	if _, err := governanceFrom(evm, common.HexToAddress("0x12")).SubmitProposal(testVerifiers[0].CandidateId, "Candidate.MaxCount", "5", activeBlock); err != nil {
+		t.Fatalf("SubmitProposal fail: %v", err)
+	}
+	if _, err := governanceFrom(evm, common.HexToAddress("0x13")).VoteProposal(testVerifiers[1].CandidateId, proposalId); err != nil {
+		t.Fatalf("VoteProposal fail: %v", err)
+	}
+	// the submitter leaves the verifiers, its yea no longer counts
+	joined := &types.Candidate{CandidateId: discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012344"), Owner: common.HexToAddress("0x15")}
+	candidatePoolContext.chairs = types.CandidateQueue{testVerifiers[1], testVerifiers[2], joined}
+	g := governanceFrom(evm, common.HexToAddress("0x14"))
+	if _, err := g.VoteProposal(testVerifiers[2].CandidateId, proposalId); err != nil {
+		t.Fatalf("VoteProposal fail: %v", err)
+	}
+	if proposal := getProposal(t, g, proposalId); proposal.Status != types.ProposalVoting {
+		t.Fatalf("status mismatch: have %d, want %d", proposal.Status, types.ProposalVoting)
+	}
+	if _, err := governanceFrom(evm, joined.Owner).VoteProposal(joined.CandidateId, proposalId); err != nil {
+		t.Fatalf("VoteProposal fail: %v", err)
+	}
+	if proposal := getProposal(t, g, proposalId); proposal.Status != types.ProposalApproved {
+		t.Fatalf("status mismatch: have %d, want %d", proposal.Status, types.ProposalApproved)
+	}
+}
+
+func TestProposalExpire(t *testing.T) {
+	evm, _ := newGovernance()
+	stateDB := evm.StateDB.(*state.StateDB)
+	proposalId := common.HexToHash("0x01")
+	stateDB.Prepare(proposalId, common.Hash{}, 0)
+
+	g := governanceFrom(evm, common.HexToAddress("0x12"))
+	if _, err := g.SubmitProposal(testVerifiers[0].CandidateId, "Ticket.TicketPrice", "2", evm.Context.BlockNumber.Uint64()+vm.ProposalVotingPeriod+1); err != nil {
+		t.Fatalf("SubmitProposal fail: %v", err)
+	}
+	endVotingBlock := getProposal(t, g, proposalId).EndVotingBlock
+	if err := vm.NotifyGovernance(stateDB, new(big.Int).SetUint64(endVotingBlock)); err != nil {
+		t.Fatalf("NotifyGovernance fail: %v", err)
+	}
+	if proposal := getProposal(t, g, proposalId); proposal.Status != types.ProposalVoting {
+		t.Fatalf("status mismatch: have %d, want %d", proposal.Status, types.ProposalVoting)
+	}
+	if err := vm.NotifyGovernance(stateDB, new(big.Int).SetUint64(endVotingBlock+1)); err != nil {
+		t.Fatalf("NotifyGovernance fail: %v", err)
+	}
+	if proposal := getProposal(t, g, proposalId); proposal.Status != types.ProposalRejected {
+		t.Fatalf("status mismatch: have %d, want %d", proposal.Status, types.ProposalRejected)
+	}
+	if _, err := governanceFrom(evm, common.HexToAddress("0x13")).VoteProposal(testVerifiers[1].CandidateId, proposalId); err != vm.ErrProposalClosed {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrProposalClosed)
+	}
+	if versions := vm.GetParamVersions(stateDB); len(versions) != 0 {
+		t.Fatalf("versions mismatch: %v", versions)
+	}
+}
+
+// submit submits the proposal with id on behalf of the first verifier.
+func submit(t *testing.T, evm *vm.EVM, id common.Hash, name, value string, activeBlock uint64) {
+	evm.StateDB.(*state.StateDB).Prepare(id, common.Hash{}, 0)
+	if _, err := governanceFrom(evm, common.HexToAddress("0x12")).SubmitProposal(testVerifiers[0].CandidateId, name, value, activeBlock); err != nil {
+		t.Fatalf("SubmitProposal fail: %v", err)
+	}
+}
+
+// vote votes for the proposal by the other verifiers.
+func vote(t *testing.T, evm *vm.EVM, id common.Hash) {
+	for i, from := range []string{"0x13", "0x14"} {
+		if _, err := governanceFrom(evm, common.HexToAddress(from)).VoteProposal(testVerifiers[i+1].CandidateId, id); err != nil {
+			t.Fatalf("VoteProposal fail: %v", err)
+		}
+	}
+}
+
+func TestProposalConflict(t *testing.T) {
+	evm, _ := newGovernance()
+	activeBlock := evm.Context.BlockNumber.Uint64() + vm.ProposalVotingPeriod + 1
+	g := governanceFrom(evm, common.HexToAddress("0x12"))
+
+	// each proposal is legal on its own, the second conflicts with the first once approved
+	submit(t, evm, common.HexToHash("0x01"), "Candidate.MaxChair", "3", activeBlock)
+	submit(t, evm, common.HexToHash("0x02"), "Candidate.MaxCount", "2", activeBlock)
+	vote(t, evm, common.HexToHash("0x01"))
+	vote(t, evm, common.HexToHash("0x02"))
+	if proposal := getProposal(t, g, common.HexToHash("0x01")); proposal.Status != types.ProposalApproved {
+		t.Fatalf("status mismatch: have %d, want %d", proposal.Status, types.ProposalApproved)
+	}
+	if proposal := getProposal(t, g, common.HexToHash("0x02")); proposal.Status != types.ProposalRejected {
+		t.Fatalf("status mismatch: have %d, want %d", proposal.Status, types.ProposalRejected)
+	}
+	// and new conflicting proposals are refused
+	evm.StateDB.(*state.StateDB).Prepare(common.HexToHash("0x03"), common.Hash{}, 0)
+	if _, err := g.SubmitProposal(testVerifiers[0].CandidateId, "Candidate.MaxCount", "2", activeBlock); err != vm.ErrParamConflict {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrParamConflict)
+	}
+}
+
+func TestGovernedCbftConfig(t *testing.T) {
+	evm, _ := newGovernance()
+	stateDB := evm.StateDB.(*state.StateDB)
+	activeBlock := evm.Context.BlockNumber.Uint64() + vm.ProposalVotingPeriod + 1
+	base := &params.CbftConfig{Duration: 10, MaxLatency: 600}
+
+	if cfg := vm.GovernedCbftConfig(stateDB, base); cfg != base {
+		t.Fatalf("configs mismatch before activation: have %v, want %v", cfg, base)
+	}
+	submit(t, evm, common.HexToHash("0x01"), "Cbft.Duration", "20", activeBlock)
+	vote(t, evm, common.HexToHash("0x01"))
+	if err := vm.NotifyGovernance(stateDB, new(big.Int).SetUint64(activeBlock-1)); err != nil {
+		t.Fatalf("NotifyGovernance fail: %v", err)
+	}
+	cfg := vm.GovernedCbftConfig(stateDB, base)
+	if cfg.Duration != 20 || cfg.MaxLatency != 600 || base.Duration != 10 {
+		t.Fatalf("configs mismatch: have %d, base %d", cfg.Duration, base.Duration)
+	}
+	// the configs are cached until the versions change
+	if cached := vm.GovernedCbftConfig(stateDB, base); cached != cfg {
+		t.Fatalf("configs not cached")
+	}
+	// the consensus parameters don't change the ppos configs
+	ppos := &params.PposConfig{CandidateConfig: &params.CandidateConfig{MaxCount: 3}, TicketConfig: &params.TicketConfig{}}
+	if governed := vm.GovernedPposConfig(stateDB, ppos); governed.CandidateConfig.MaxCount != 3 {
+		t.Fatalf("ppos configs mismatch: %v", governed.CandidateConfig)
+	}
+}
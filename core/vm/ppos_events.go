@@ -0,0 +1,138 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	ethabi "github.com/PlatONnetwork/PlatON-Go/accounts/abi"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+// Events emitted outside of transactions, they become system logs of the block.
+const (
+	WitnessElectedEvent = "WitnessElectedEvent"
+	TicketExpireEvent   = "TicketExpireEvent"
+	RewardEvent         = "RewardEvent"
+	JailEvent           = "JailEvent"
+)
+
+// PposEventsABI describes the events of the PPOS precompiled contracts. The first
+// topic of a log is the id of the event, the indexed inputs follow in order and
+// the other inputs are ABI-encoded into the data. A node id is indexed by the
+// keccak256 hash of its 64 bytes.
+const PposEventsABI = `[
+	{"type":"event","name":"CandidateDepositEvent","inputs":[{"name":"nodeId","type":"bytes","indexed":true},{"name":"owner","type":"address","indexed":true},{"name":"deposit","type":"uint256"},{"name":"fee","type":"uint32"}]},
+	{"type":"event","name":"CandidateApplyWithdrawEvent","inputs":[{"name":"nodeId","type":"bytes","indexed":true},{"name":"owner","type":"address","indexed":true},{"name":"amount","type":"uint256"}]},
+	{"type":"event","name":"CandidateWithdrawEvent","inputs":[{"name":"nodeId","type":"bytes","indexed":true},{"name":"owner","type":"address","indexed":true},{"name":"amount","type":"uint256"}]},
+	{"type":"event","name":"SetCandidateExtraEvent","inputs":[{"name":"nodeId","type":"bytes","indexed":true},{"name":"extra","type":"string"}]},
+	{"type":"event","name":"IncreaseDepositEvent","inputs":[{"name":"nodeId","type":"bytes","indexed":true},{"name":"owner","type":"address","indexed":true},{"name":"amount","type":"uint256"}]},
+	{"type":"event","name":"UpdateCandidateInfoEvent","inputs":[{"name":"nodeId","type":"bytes","indexed":true},{"name":"host","type":"string"},{"name":"port","type":"string"},{"name":"fee","type":"uint32"}]},
+	{"type":"event","name":"UnjailEvent","inputs":[{"name":"nodeId","type":"bytes","indexed":true},{"name":"owner","type":"address","indexed":true}]},
+	{"type":"event","name":"SetCandidateSentriesEvent","inputs":[{"name":"nodeId","type":"bytes","indexed":true},{"name":"sentries","type":"string"}]},
+	{"type":"event","name":"SetCandidateBlsKeyEvent","inputs":[{"name":"nodeId","type":"bytes","indexed":true},{"name":"blsPubKey","type":"bytes"}]},
+	{"type":"event","name":"VoteTicketEvent","inputs":[{"name":"nodeId","type":"bytes","indexed":true},{"name":"owner","type":"address","indexed":true},{"name":"ticketId","type":"bytes32","indexed":true},{"name":"count","type":"uint32"},{"name":"price","type":"uint256"}]},
+	{"type":"event","name":"TransferTicketEvent","inputs":[{"name":"ticketId","type":"bytes32","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"newTicketId","type":"bytes32"},{"name":"count","type":"uint32"}]},
+	{"type":"event","name":"RedelegateTicketEvent","inputs":[{"name":"ticketId","type":"bytes32","indexed":true},{"name":"nodeId","type":"bytes","indexed":true},{"name":"owner","type":"address","indexed":true},{"name":"newTicketId","type":"bytes32"},{"name":"count","type":"uint32"}]},
+	{"type":"event","name":"WitnessElectedEvent","inputs":[{"name":"nodeId","type":"bytes","indexed":true},{"name":"owner","type":"address","indexed":true},{"name":"ticketId","type":"bytes32","indexed":true},{"name":"deposit","type":"uint256"}]},
+	{"type":"event","name":"TicketExpireEvent","inputs":[{"name":"ticketId","type":"bytes32","indexed":true},{"name":"nodeId","type":"bytes","indexed":true},{"name":"owner","type":"address","indexed":true},{"name":"count","type":"uint32"}]},
+	{"type":"event","name":"RewardEvent","inputs":[{"name":"to","type":"address","indexed":true},{"name":"nodeId","type":"bytes","indexed":true},{"name":"ticketId","type":"bytes32","indexed":true},{"name":"amount","type":"uint256"}]},
+	{"type":"event","name":"JailEvent","inputs":[{"name":"nodeId","type":"bytes","indexed":true},{"name":"missedSlots","type":"uint32"},{"name":"penalty","type":"uint256"}]}
+]`
+
+var pposEvents ethabi.ABI
+
+func init() {
+	var err error
+	if pposEvents, err = ethabi.JSON(strings.NewReader(PposEventsABI)); err != nil {
+		panic("invalid ppos events abi: " + err.Error())
+	}
+}
+
+// AddPposLog adds a log of the PPOS event name emitted at address, args are the
+// values of the event inputs in order. Outside of a transaction the log is a system
+// log of the block.
+func AddPposLog(state StateDB, address common.Address, blockNumber *big.Int, name string, args ...interface{}) {
+	event, ok := pposEvents.Events[name]
+	if !ok || len(args) != len(event.Inputs) {
+		log.Error("Failed to add ppos log, the event doesn't match", "event", name, "args", len(args))
+		return
+	}
+	topics := []common.Hash{event.Id()}
+	data := make([]interface{}, 0, len(args))
+	for i, input := range event.Inputs {
+		arg := args[i]
+		if nodeId, ok := arg.(discover.NodeID); ok {
+			arg = nodeId.Bytes()
+		}
+		if input.Indexed {
+			topics = append(topics, pposEventTopic(arg))
+		} else {
+			data = append(data, arg)
+		}
+	}
+	packed, err := event.Inputs.NonIndexed().Pack(data...)
+	if nil != err {
+		log.Error("Failed to add ppos log, abi encode fail", "event", name, "err", err)
+		return
+	}
+	state.AddLog(&types.Log{
+		Address:     address,
+		Topics:      topics,
+		Data:        packed,
+		BlockNumber: blockNumber.Uint64(),
+	})
+}
+
+// AddPposSystemLog adds a system log of the PPOS event name, system logs are only
+// recorded from the PPOS events fork block on.
+func AddPposSystemLog(config *params.ChainConfig, state StateDB, address common.Address, blockNumber *big.Int, name string, args ...interface{}) {
+	if nil == config || !config.IsPposEvents(blockNumber) {
+		return
+	}
+	AddPposLog(state, address, blockNumber, name, args...)
+}
+
+// addLegacyPposLog adds a log in the format used before the PPOS events fork: one
+// topic hashed from the event name and the RLP encoded JSON result as the data.
+func addLegacyPposLog(state StateDB, address common.Address, blockNumber *big.Int, name string, result ResultCommon) {
+	event, _ := json.Marshal(result)
+	buf := new(bytes.Buffer)
+	if err := rlp.Encode(buf, [][]byte{event}); nil != err {
+		log.Error("Failed to add legacy ppos log", "rlp encode fail: ", err.Error())
+	}
+	state.AddLog(&types.Log{
+		Address:     address,
+		Topics:      []common.Hash{common.BytesToHash(crypto.Keccak256([]byte(name)))},
+		Data:        buf.Bytes(),
+		BlockNumber: blockNumber.Uint64(),
+	})
+}
+
+// pposEventTopic returns the topic of an indexed event input, values longer
+// than a topic are hashed.
+func pposEventTopic(arg interface{}) common.Hash {
+	switch v := arg.(type) {
+	case common.Hash:
+		return v
+	case common.Address:
+		return common.BytesToHash(v.Bytes())
+	case []byte:
+		return crypto.Keccak256Hash(v)
+	case string:
+		return crypto.Keccak256Hash([]byte(v))
+	case *big.Int:
+		return common.BigToHash(v)
+	case uint32:
+		return common.BigToHash(new(big.Int).SetUint64(uint64(v)))
+	}
+	log.Error("Unsupported indexed ppos event input", "arg", arg)
+	return common.Hash{}
+}
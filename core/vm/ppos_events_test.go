@@ -0,0 +1,121 @@
+package vm_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+func TestCandidateDepositEvent(t *testing.T) {
+	evm := newEvm()
+	stateDB := evm.StateDB.(*state.StateDB)
+	txHash := common.HexToHash("0x01")
+	stateDB.Prepare(txHash, common.Hash{}, 0)
+	candidateContract := vm.CandidateContract{
+		newContract(),
+		evm,
+	}
+	owner := common.HexToAddress("0x12")
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, "192.168.9.184", "16789", "{}", nodeSig(testNodeId1, owner), blsPubKey(testNodeId1), blsProof(testNodeId1)); err != nil {
+		t.Fatalf("CandidateDeposit fail: %v", err)
+	}
+
+	logs := stateDB.GetLogs(txHash)
+	if len(logs) != 1 {
+		t.Fatalf("logs count mismatch: have %d, want 1", len(logs))
+	}
+	events, err := abi.JSON(strings.NewReader(vm.PposEventsABI))
+	if err != nil {
+		t.Fatalf("invalid events abi: %v", err)
+	}
+	event := events.Events[vm.CandidateDepositEvent]
+	l := logs[0]
+	if l.Address != common.CandidatePoolAddr || len(l.Topics) != 3 {
+		t.Fatalf("log mismatch: %+v", l)
+	}
+	if l.Topics[0] != event.Id() || l.Topics[1] != crypto.Keccak256Hash(testNodeId1.Bytes()) || l.Topics[2] != common.BytesToHash(owner.Bytes()) {
+		t.Fatalf("topics mismatch: %v", l.Topics)
+	}
+	var data struct {
+		Deposit *big.Int
+		Fee     uint32
+	}
+	if err := events.Unpack(&data, vm.CandidateDepositEvent, l.Data); err != nil {
+		t.Fatalf("failed to decode the log data: %v", err)
+	}
+	if data.Deposit.Cmp(big.NewInt(1000)) != 0 || data.Fee != 7000 {
+		t.Fatalf("data mismatch: deposit %v, fee %d", data.Deposit, data.Fee)
+	}
+}
+
+func TestSystemLog(t *testing.T) {
+	evm := newEvm()
+	stateDB := evm.StateDB.(*state.StateDB)
+	stateDB.Prepare(types.SystemLogTxHash, common.Hash{}, 3)
+
+	ticketId := common.HexToHash("0x02")
+	vm.AddPposLog(stateDB, common.TicketPoolAddr, big.NewInt(9), vm.TicketExpireEvent, ticketId, testNodeId1, common.HexToAddress("0x12"), uint32(5))
+	// a mismatched event is dropped
+	vm.AddPposLog(stateDB, common.TicketPoolAddr, big.NewInt(9), vm.TicketExpireEvent, ticketId)
+
+	logs := stateDB.GetLogs(types.SystemLogTxHash)
+	if len(logs) != 1 {
+		t.Fatalf("system logs count mismatch: have %d, want 1", len(logs))
+	}
+	if l := logs[0]; l.TxIndex != 3 || l.BlockNumber != 9 || len(l.Topics) != 4 || l.Topics[1] != ticketId {
+		t.Fatalf("system log mismatch: %+v", l)
+	}
+	bloom := types.CreateBlockBloom(nil, logs)
+	if !types.BloomLookup(bloom, common.TicketPoolAddr) || !types.BloomLookup(bloom, ticketId) {
+		t.Fatalf("system log missing in the block bloom")
+	}
+}
+
+func TestPposEventsFork(t *testing.T) {
+	config := *params.TestChainConfig
+	config.PposEventsBlock = big.NewInt(8)
+	evm := newEvm()
+	evm = vm.NewEVM(evm.Context, evm.StateDB, &config, vm.Config{})
+	stateDB := evm.StateDB.(*state.StateDB)
+	txHash := common.HexToHash("0x01")
+	stateDB.Prepare(txHash, common.Hash{}, 0)
+	candidateContract := vm.CandidateContract{
+		newContract(),
+		evm,
+	}
+	owner := common.HexToAddress("0x12")
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, "192.168.9.184", "16789", "{}", nodeSig(testNodeId1, owner), blsPubKey(testNodeId1), blsProof(testNodeId1)); err != nil {
+		t.Fatalf("CandidateDeposit fail: %v", err)
+	}
+
+	// before the fork the log keeps the legacy format
+	logs := stateDB.GetLogs(txHash)
+	if len(logs) != 1 || len(logs[0].Topics) != 1 || logs[0].Topics[0] != crypto.Keccak256Hash([]byte(vm.CandidateDepositEvent)) {
+		t.Fatalf("legacy log mismatch: %v", logs)
+	}
+	var data [][]byte
+	if err := rlp.DecodeBytes(logs[0].Data, &data); err != nil || len(data) != 1 || string(data[0]) != `{"Ret":true,"Data":"","ErrMsg":"success"}` {
+		t.Fatalf("legacy log data mismatch: %x, %v", logs[0].Data, err)
+	}
+
+	// and no system log is recorded
+	stateDB.Prepare(types.SystemLogTxHash, common.Hash{}, 1)
+	ticketId := common.HexToHash("0x02")
+	vm.AddPposSystemLog(&config, stateDB, common.TicketPoolAddr, big.NewInt(7), vm.TicketExpireEvent, ticketId, testNodeId1, owner, uint32(5))
+	if logs := stateDB.GetLogs(types.SystemLogTxHash); len(logs) != 0 {
+		t.Fatalf("system log before the fork: %v", logs)
+	}
+	vm.AddPposSystemLog(&config, stateDB, common.TicketPoolAddr, big.NewInt(8), vm.TicketExpireEvent, ticketId, testNodeId1, owner, uint32(5))
+	if logs := stateDB.GetLogs(types.SystemLogTxHash); len(logs) != 1 {
+		t.Fatalf("system logs count mismatch: have %d, want 1", len(logs))
+	}
+}
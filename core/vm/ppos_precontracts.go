@@ -8,15 +8,33 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/byteutil"
 	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	gerr "github.com/go-errors/errors"
+	"math/big"
 	"reflect"
+	"strings"
 )
 
 // Ppos pre-compiled contract address
 var PrecompiledContractsPpos = map[common.Address]PrecompiledContract{
 	common.CandidatePoolAddr: &CandidateContract{},
 	common.TicketPoolAddr:    &TicketContract{},
+	common.VCVerifierAddr:    &VCVerifierContract{},
+	common.GovernanceAddr:    &GovernanceContract{},
+}
+
+// pposContract returns the ppos contract at addr if it's active at the block
+// number num, the contracts added after the launch are gated by their forks.
+func pposContract(config *params.ChainConfig, num *big.Int, addr common.Address) PrecompiledContract {
+	if addr == common.VCVerifierAddr && !config.IsVCVerifier(num) {
+		return nil
+	}
+	if addr == common.GovernanceAddr && !config.IsGovernance(num) {
+		return nil
+	}
+	return PrecompiledContractsPpos[addr]
 }
 
 var (
@@ -25,10 +43,36 @@ var (
 	ErrParamsLen       = errors.New("Params length does not match")
 	ErrUndefFunction   = errors.New("Undefined function")
 	ErrTxType          = errors.New("Transaction type does not match the function")
+	ErrResultLen       = errors.New("Result length does not match")
 )
 
-// execute decode input data and call the function.
-func execute(input []byte, command map[string]interface{}) ([]byte, error) {
+// txTypeMap is the transaction type of every command which changes the state,
+// it must be the first element of the input.
+var txTypeMap = map[string]uint64{
+	"VoteTicket":             1000,
+	"CandidateDeposit":       1001,
+	"CandidateApplyWithdraw": 1002,
+	"CandidateWithdraw":      1003,
+	"SetCandidateExtra":      1004,
+	"RegisterVerifyingKey":   1005,
+	"IncreaseDeposit":        1006,
+	"UpdateCandidateInfo":    1007,
+	"SubmitProposal":         1008,
+	"VoteProposal":           1009,
+	"TransferTicket":         1010,
+	"RedelegateTicket":       1011,
+	"Unjail":                 1012,
+	"SetCandidateSentries":   1013,
+	"SetCandidateBlsKey":     1014,
+}
+
+// pposGasFunc charges the gas of the command name beyond the base cost of the call,
+// args are the decoded arguments of the command.
+type pposGasFunc func(name string, args []reflect.Value) error
+
+// execute decode input data and call the function, useGas is called with the
+// decoded command before the call if it is not nil.
+func execute(input []byte, command map[string]interface{}, useGas pposGasFunc) ([]byte, error) {
 	log.Info("Input to execute==> ", "input: ", hex.EncodeToString(input))
 	defer func() {
 		if err := recover(); nil != err {
@@ -37,54 +81,202 @@ func execute(input []byte, command map[string]interface{}) ([]byte, error) {
 			log.Error("Failed to execute==> ", "err: ", fmt.Sprint(err), "\nstack: ", msg)
 		}
 	}()
+	name, funcValue, params, err := decodeInput(input, command)
+	if nil != err {
+		log.Error("Failed to execute==> ", "err: ", err.Error())
+		return nil, err
+	}
+	if nil != useGas {
+		if err := useGas(name, params); nil != err {
+			log.Error("Failed to execute==> ", "err: ", err.Error())
+			return nil, err
+		}
+	}
+	result := reflect.ValueOf(funcValue).Call(params)
+	log.Info("Result of execute==> ", "result[0]: ", result[0].Bytes())
+	if _, err := result[1].Interface().(error); !err {
+		return result[0].Bytes(), nil
+	}
+	log.Error("Result of execute==> ", "result[1]: err ", result[1].Interface().(error).Error())
+	return result[0].Bytes(), result[1].Interface().(error)
+}
+
+// decodeInput decodes the input of a call into the command of the table and its
+// arguments converted by byteutil.Command.
+func decodeInput(input []byte, command map[string]interface{}) (string, interface{}, []reflect.Value, error) {
 	var source [][]byte
 	if err := rlp.Decode(bytes.NewReader(input), &source); nil != err {
-		log.Error("Failed to execute==> ", err.Error())
-		return nil, ErrParamsRlpDecode
+		return "", nil, nil, ErrParamsRlpDecode
 	}
 	if len(source) < 2 {
-		log.Error("Failed to execute==> ", "ErrParamsBaselen: ", ErrParamsBaselen.Error())
-		return nil, ErrParamsBaselen
+		return "", nil, nil, ErrParamsBaselen
 	}
 	// get func and param list
-	if _, ok := command[byteutil.BytesToString(source[1])]; !ok {
-		log.Error("Failed to execute==> ", "ErrUndefFunction: ", ErrUndefFunction.Error())
-		return nil, ErrUndefFunction
+	name := byteutil.BytesToString(source[1])
+	funcValue, ok := command[name]
+	if !ok {
+		return "", nil, nil, ErrUndefFunction
 	}
-	funcValue := command[byteutil.BytesToString(source[1])]
 	// validate transaction type
-	var txTypeMap = map[string]uint64{
-		"VoteTicket":             1000,
-		"CandidateDeposit":       1001,
-		"CandidateApplyWithdraw": 1002,
-		"CandidateWithdraw":      1003,
-		"SetCandidateExtra":      1004,
-	}
-	if txType, ok := txTypeMap[byteutil.BytesToString(source[1])]; ok {
+	if txType, ok := txTypeMap[name]; ok {
 		if txType != byteutil.BytesTouint64(source[0]) {
-			log.Error("Failed to execute==> ", "ErrTxType: ", ErrTxType.Error())
-			return nil, ErrTxType
+			return "", nil, nil, ErrTxType
 		}
 	}
 	paramList := reflect.TypeOf(funcValue)
 	paramNum := paramList.NumIn()
-	params := make([]reflect.Value, paramNum)
 	if paramNum != len(source)-2 {
-		log.Error("Failed to execute==> ", "ErrParamsLen: ", ErrParamsLen.Error())
-		return nil, ErrParamsLen
+		return "", nil, nil, ErrParamsLen
 	}
+	params := make([]reflect.Value, paramNum)
 	for i := 0; i < paramNum; i++ {
 		targetType := paramList.In(i).String()
 		originByte := []reflect.Value{reflect.ValueOf(source[i+2])}
 		params[i] = reflect.ValueOf(byteutil.Command[targetType]).Call(originByte)[0]
 	}
-	result := reflect.ValueOf(funcValue).Call(params)
-	log.Info("Result of execute==> ", "result[0]: ", result[0].Bytes())
-	if _, err := result[1].Interface().(error); !err {
-		return result[0].Bytes(), nil
+	return name, funcValue, params, nil
+}
+
+// PposCall is a call of a ppos contract command decoded from its input.
+type PposCall struct {
+	TxType uint64        // Transaction type of the command, 0 for the queries
+	Name   string        // Name of the command
+	Types  []string      // Types of the arguments, the keys of byteutil.Command
+	Args   []interface{} // Arguments of the command
+}
+
+// DecodePposInput decodes the input of a call to the ppos contract at addr
+// against the command table of the contract.
+func DecodePposInput(addr common.Address, input []byte) (call *PposCall, err error) {
+	contract, ok := PrecompiledContractsPpos[addr].(interface {
+		commands() map[string]interface{}
+	})
+	if !ok {
+		return nil, fmt.Errorf("no ppos contract at %x", addr)
 	}
-	log.Error("Result of execute==> ", "result[1]: err ", result[1].Interface().(error).Error())
-	return result[0].Bytes(), result[1].Interface().(error)
+	// The converters panic on some malformed arguments
+	defer func() {
+		if r := recover(); r != nil {
+			call, err = nil, fmt.Errorf("invalid arguments: %v", r)
+		}
+	}()
+	name, funcValue, params, err := decodeInput(input, contract.commands())
+	if err != nil {
+		return nil, err
+	}
+	call = &PposCall{TxType: txTypeMap[name], Name: name}
+	paramList := reflect.TypeOf(funcValue)
+	for i, param := range params {
+		call.Types = append(call.Types, paramList.In(i).String())
+		call.Args = append(call.Args, param.Interface())
+	}
+	return call, nil
+}
+
+// pposCommands returns the command table of the ppos contract at addr in the
+// block number num, nil if there's no such contract.
+func pposCommands(config *params.ChainConfig, num *big.Int, addr common.Address) map[string]interface{} {
+	if pposContract(config, num, addr) == nil {
+		return nil
+	}
+	evm := &EVM{chainConfig: config}
+	switch addr {
+	case common.CandidatePoolAddr:
+		return (&CandidateContract{Evm: evm}).commandsAt(num)
+	case common.TicketPoolAddr:
+		return (&TicketContract{Evm: evm}).commandsAt(num)
+	case common.VCVerifierAddr:
+		return (&VCVerifierContract{Evm: evm}).commands()
+	case common.GovernanceAddr:
+		return (&GovernanceContract{Evm: evm}).commands()
+	}
+	return nil
+}
+
+// IsPposCommand reports whether input calls a command of the ppos contract at addr
+// in the block number num which changes the state, one of txTypeMap with its
+// transaction type.
+func IsPposCommand(config *params.ChainConfig, num *big.Int, addr common.Address, input []byte) bool {
+	commands := pposCommands(config, num, addr)
+	if commands == nil {
+		return false
+	}
+	var source [][]byte
+	if err := rlp.DecodeBytes(input, &source); nil != err || len(source) < 2 || len(source[0]) > 8 {
+		return false
+	}
+	name := byteutil.BytesToString(source[1])
+	if _, ok := commands[name]; !ok {
+		return false
+	}
+	txType, ok := txTypeMap[name]
+	return ok && txType == byteutil.BytesTouint64(source[0])
+}
+
+// usePposGas deducts gas from the contract, ErrOutOfGas is returned if the
+// contract doesn't have enough gas left.
+func usePposGas(contract *Contract, gas uint64) error {
+	if !contract.UseGas(gas) {
+		return ErrOutOfGas
+	}
+	return nil
+}
+
+// EncodeInput encodes the call of the command name with args into the input
+// parsed by execute, the arguments are encoded in the form expected by the
+// converters of byteutil.Command.
+func EncodeInput(name string, args ...interface{}) ([]byte, error) {
+	source := make([][]byte, 0, len(args)+2)
+	source = append(source, byteutil.Uint64ToBytes(txTypeMap[name]), []byte(name))
+	for i, arg := range args {
+		var b []byte
+		switch v := arg.(type) {
+		case string:
+			b = []byte(v)
+		case []byte:
+			b = v
+		case uint32:
+			b = byteutil.Uint32ToBytes(v)
+		case uint64:
+			b = byteutil.Uint64ToBytes(v)
+		case *big.Int:
+			b = v.Bytes()
+		case discover.NodeID:
+			b = []byte(v.String())
+		case []discover.NodeID:
+			ids := make([]string, len(v))
+			for j, id := range v {
+				ids[j] = id.String()
+			}
+			b = []byte(strings.Join(ids, ":"))
+		case common.Hash:
+			b = []byte(v.Hex())
+		case []common.Hash:
+			hashes := make([]string, len(v))
+			for j, hash := range v {
+				hashes[j] = hash.Hex()
+			}
+			b = []byte(strings.Join(hashes, ":"))
+		case common.Address:
+			b = []byte(v.Hex())
+		default:
+			return nil, fmt.Errorf("unsupported type %T of argument %d", arg, i)
+		}
+		source = append(source, b)
+	}
+	return rlp.EncodeToBytes(source)
+}
+
+// DecodeResult returns the string encoded into result by DecodeResultStr.
+func DecodeResult(result []byte) (string, error) {
+	if len(result) < 64 {
+		return "", ErrResultLen
+	}
+	size := new(big.Int).SetBytes(result[32:64])
+	if !size.IsUint64() || size.Uint64() > uint64(len(result)-64) {
+		return "", ErrResultLen
+	}
+	return string(result[64 : 64+size.Uint64()]), nil
 }
 
 // ResultCommon is the struct of transaction event.
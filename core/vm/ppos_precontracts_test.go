@@ -7,6 +7,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"math/big"
 	"testing"
 )
@@ -16,7 +17,7 @@ func TestExecute(t *testing.T) {
 		"recalled": recalled,
 	}
 	input, _ := hex.DecodeString("f8c28800000000000000f188726563616c6c6564b88230783166336138363732333438666636623738396534313637363261643533653639303633313338623865623464383738303130313635386632346232333639663161386530393439393232366234363764386263306334653033653164633930336466383537656562336336373733336432316236616165653238343065343239aa30786632313664366534633137303937613630656532623865356338383934316364396630373236336201")
-	_, err := execute(input, command)
+	_, err := execute(input, command, nil)
 	if nil != err {
 		fmt.Println("execute fail", "err", err)
 	}
@@ -36,6 +37,38 @@ func TestDecodeResultStr(t *testing.T) {
 	fmt.Println("origin: ", string(data), "[]byte: ", sdata, "json: ", json)
 }
 
+func TestEncodeInput(t *testing.T) {
+	nodeIds := []discover.NodeID{discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")}
+	ticketId := common.HexToHash("0x02")
+	var called bool
+	var command = map[string]interface{}{
+		"RedelegateTicket": func(id common.Hash, count uint32, ids []discover.NodeID, price *big.Int, extra string) ([]byte, error) {
+			called = true
+			if id != ticketId || count != 7 || len(ids) != 1 || ids[0] != nodeIds[0] || price.Cmp(big.NewInt(300)) != 0 || extra != "{}" {
+				t.Errorf("arguments mismatch: %v %d %v %v %s", id, count, ids, price, extra)
+			}
+			return DecodeResultStr("ok"), nil
+		},
+	}
+	input, err := EncodeInput("RedelegateTicket", ticketId, uint32(7), nodeIds, big.NewInt(300), "{}")
+	if err != nil {
+		t.Fatalf("failed to encode input: %v", err)
+	}
+	result, err := execute(input, command, nil)
+	if err != nil || !called {
+		t.Fatalf("execute fail: %v", err)
+	}
+	if data, err := DecodeResult(result); err != nil || data != "ok" {
+		t.Fatalf("result mismatch: have %q (%v), want %q", data, err, "ok")
+	}
+	if _, err := EncodeInput("GetTicketPrice", 1.5); err == nil {
+		t.Fatalf("unsupported argument encoded")
+	}
+	if _, err := DecodeResult(result[:65]); err != ErrResultLen {
+		t.Fatalf("short result error mismatch: have %v, want %v", err, ErrResultLen)
+	}
+}
+
 func recalled(nodeId discover.NodeID, owner common.Address, deposit *big.Int) ([]byte, error) {
 	fmt.Println("nodeId:", nodeId, "owner:", owner, "deposit:", deposit)
 	return nil, nil
@@ -45,3 +78,24 @@ func ResultByte2Json(origin []byte) string {
 	resultByte := origin[64:]
 	return string(resultByte)
 }
+
+func TestPposContractFork(t *testing.T) {
+	config := &params.ChainConfig{VCVerifierBlock: big.NewInt(10), GovernanceBlock: big.NewInt(20)}
+	tests := []struct {
+		addr   common.Address
+		num    int64
+		active bool
+	}{
+		{common.VCVerifierAddr, 9, false},
+		{common.VCVerifierAddr, 10, true},
+		{common.GovernanceAddr, 19, false},
+		{common.GovernanceAddr, 20, true},
+		{common.CandidatePoolAddr, 0, true},
+		{common.TicketPoolAddr, 0, true},
+	}
+	for i, tt := range tests {
+		if p := pposContract(config, big.NewInt(tt.num), tt.addr); (p != nil) != tt.active {
+			t.Errorf("test %d: %s at %d active %v, want %v", i, tt.addr.Hex(), tt.num, p != nil, tt.active)
+		}
+	}
+}
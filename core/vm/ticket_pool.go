@@ -1,17 +1,14 @@
 package vm
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
-	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
-	"github.com/PlatONnetwork/PlatON-Go/params"
-	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"math/big"
+	"reflect"
 	"strconv"
 )
 
@@ -22,25 +19,53 @@ var (
 )
 
 const (
-	VoteTicketEvent = "VoteTicketEvent"
+	VoteTicketEvent       = "VoteTicketEvent"
+	TransferTicketEvent   = "TransferTicketEvent"
+	RedelegateTicketEvent = "RedelegateTicketEvent"
 )
 
 type ticketPoolContext interface {
 	VoteTicket(stateDB StateDB, owner common.Address, voteNumber uint32, deposit *big.Int, nodeId discover.NodeID, blockNumber *big.Int) (uint32, error)
+	TransferTicket(stateDB StateDB, from common.Address, ticketId common.Hash, count uint32, to common.Address, blockNumber *big.Int) (common.Hash, error)
+	RedelegateTicket(stateDB StateDB, from common.Address, ticketId common.Hash, count uint32, nodeId discover.NodeID, blockNumber *big.Int) (common.Hash, error)
 	GetCandidatesTicketCount(stateDB StateDB, nodeIds []discover.NodeID) map[discover.NodeID]uint32
 	GetBatchTicketRemaining(stateDB StateDB, ticketIds []common.Hash) map[common.Hash]uint32
 	GetCandidateEpoch(stateDB StateDB, nodeId discover.NodeID) uint64
 	GetPoolNumber(stateDB StateDB) uint32
 	GetTicketPrice(stateDB StateDB) *big.Int
+	GetTicketPriceHistory(stateDB StateDB, start, count uint32) []*types.TicketPriceRecord
 }
 
+// MaxTicketPriceHistoryPage is the maximum number of price records returned by
+// a GetTicketPriceHistory call.
+const MaxTicketPriceHistoryPage = 100
+
 type TicketContract struct {
 	Contract *Contract
 	Evm      *EVM
 }
 
 func (t *TicketContract) RequiredGas(input []byte) uint64 {
-	return params.EcrecoverGas
+	return t.Evm.ChainConfig().PposGasTable(t.Evm.BlockNumber).Call
+}
+
+// useGas charges the tickets handled by the command, the candidate queue updates
+// they cause and the items requested by the batch queries.
+func (t *TicketContract) useGas(name string, args []reflect.Value) error {
+	gt := t.Evm.ChainConfig().PposGasTable(t.Evm.BlockNumber)
+	var gas uint64
+	switch name {
+	case "VoteTicket":
+		gas = args[0].Uint()*gt.Ticket + gt.QueueUpdate
+	case "TransferTicket":
+		gas = args[1].Uint() * gt.Ticket
+	case "RedelegateTicket":
+		// the tickets leave one candidate and join another
+		gas = args[1].Uint()*gt.Ticket + 2*gt.QueueUpdate
+	case "GetCandidateTicketCount", "GetTicketCountByTxHash":
+		gas = uint64(args[0].Len()) * gt.QueryItem
+	}
+	return usePposGas(t.Contract, gas)
 }
 
 func (t *TicketContract) Run(input []byte) ([]byte, error) {
@@ -48,15 +73,33 @@ func (t *TicketContract) Run(input []byte) ([]byte, error) {
 		log.Error("Failed to TicketContract Run", "ErrTicketPoolEmpty: ", ErrTicketPoolEmpty.Error())
 		return nil, ErrTicketPoolEmpty
 	}
-	var command = map[string]interface{}{
+	return execute(input, t.commandsAt(t.Evm.BlockNumber), t.useGas)
+}
+
+// commands returns the command table of the contract.
+func (t *TicketContract) commands() map[string]interface{} {
+	return map[string]interface{}{
 		"VoteTicket":              t.VoteTicket,
+		"TransferTicket":          t.TransferTicket,
+		"RedelegateTicket":        t.RedelegateTicket,
 		"GetCandidateTicketCount": t.GetCandidateTicketCount,
 		"GetTicketCountByTxHash":  t.GetTicketCountByTxHash,
 		"GetCandidateEpoch":       t.GetCandidateEpoch,
 		"GetPoolRemainder":        t.GetPoolRemainder,
 		"GetTicketPrice":          t.GetTicketPrice,
+		"GetTicketPriceHistory":   t.GetTicketPriceHistory,
+	}
+}
+
+// commandsAt returns the command table of the contract in the block number,
+// the commands added by a fork are unknown before it.
+func (t *TicketContract) commandsAt(number *big.Int) map[string]interface{} {
+	commands := t.commands()
+	if !t.Evm.ChainConfig().IsTicketTransfer(number) {
+		delete(commands, "TransferTicket")
+		delete(commands, "RedelegateTicket")
 	}
-	return execute(input, command)
+	return commands
 }
 
 // VoteTicket let a account buy tickets and vote to the chosen candidate.
@@ -107,19 +150,53 @@ func (t *TicketContract) VoteTicket(count uint32, price *big.Int, nodeId discove
 	data := strconv.FormatUint(uint64(successCount), 10) + ":" + ticketPrice.String()
 	sdata := DecodeResultStr(data)
 	log.Info("Result of VoteTicket", "successCount: ", successCount, "dealTPrice: ", ticketPrice, "json: ", data)
+	result := ResultCommon{true, data, "success"}
 	if nil != err {
 		log.Warn("Failed to VoteTicket", "VoteTicket return err: ", err.Error())
-		r := ResultCommon{true, data, err.Error()}
-		event, _ := json.Marshal(r)
-		t.addLog(VoteTicketEvent, string(event))
-		return sdata, nil
-	}
-	r := ResultCommon{true, data, "success"}
-	event, _ := json.Marshal(r)
-	t.addLog(VoteTicketEvent, string(event))
+		result.ErrMsg = err.Error()
+	}
+	t.addLog(VoteTicketEvent, result, nodeId, from, txHash, successCount, ticketPrice)
 	return sdata, nil
 }
 
+// TransferTicket moves count tickets of ticketId to the account to, the moved
+// tickets become a new ticket identified by an id derived from the transaction.
+func (t *TicketContract) TransferTicket(ticketId common.Hash, count uint32, to common.Address) ([]byte, error) {
+	txHash := t.Evm.StateDB.TxHash()
+	blockNumber := t.Evm.Context.BlockNumber
+	from := t.Contract.caller.Address()
+	log.Info("Input to TransferTicket", " ticketId: ", ticketId.Hex(), " from: ", from.Hex(), " to: ", to.Hex(), " count: ", count,
+		" txhash: ", txHash.Hex(), " blockNumber: ", blockNumber)
+	newTicketId, err := t.Evm.TicketPoolContext.TransferTicket(t.Evm.StateDB, from, ticketId, count, to, blockNumber)
+	if nil != err {
+		log.Error("Failed to TransferTicket", "err: ", err.Error())
+		return nil, err
+	}
+	data := newTicketId.Hex()
+	t.addLog(TransferTicketEvent, ResultCommon{true, data, "success"}, ticketId, from, to, newTicketId, count)
+	log.Info("Result of TransferTicket", "newTicketId: ", data)
+	return DecodeResultStr(data), nil
+}
+
+// RedelegateTicket moves count tickets of ticketId to the candidate nodeId, the
+// moved tickets become a new ticket identified by an id derived from the transaction.
+func (t *TicketContract) RedelegateTicket(ticketId common.Hash, count uint32, nodeId discover.NodeID) ([]byte, error) {
+	txHash := t.Evm.StateDB.TxHash()
+	blockNumber := t.Evm.Context.BlockNumber
+	from := t.Contract.caller.Address()
+	log.Info("Input to RedelegateTicket", " ticketId: ", ticketId.Hex(), " from: ", from.Hex(), " nodeId: ", nodeId.String(), " count: ", count,
+		" txhash: ", txHash.Hex(), " blockNumber: ", blockNumber)
+	newTicketId, err := t.Evm.TicketPoolContext.RedelegateTicket(t.Evm.StateDB, from, ticketId, count, nodeId, blockNumber)
+	if nil != err {
+		log.Error("Failed to RedelegateTicket", "err: ", err.Error())
+		return nil, err
+	}
+	data := newTicketId.Hex()
+	t.addLog(RedelegateTicketEvent, ResultCommon{true, data, "success"}, ticketId, nodeId, from, newTicketId, count)
+	log.Info("Result of RedelegateTicket", "newTicketId: ", data)
+	return DecodeResultStr(data), nil
+}
+
 // GetCandidateTicketCount returns the number of candidate's ticket.
 func (t *TicketContract) GetCandidateTicketCount(nodeIds []discover.NodeID) ([]byte, error) {
 	input, _ := json.Marshal(nodeIds)
@@ -170,19 +247,31 @@ func (t *TicketContract) GetTicketPrice() ([]byte, error) {
 	return sdata, nil
 }
 
-// addLog let the result add to event.
-func (t *TicketContract) addLog(event, data string) {
-	var logdata [][]byte
-	logdata = make([][]byte, 0)
-	logdata = append(logdata, []byte(data))
-	buf := new(bytes.Buffer)
-	if err := rlp.Encode(buf, logdata); nil != err {
-		log.Error("Failed to addlog", "rlp encode fail: ", err.Error())
-	}
-	t.Evm.StateDB.AddLog(&types.Log{
-		Address:     common.TicketPoolAddr,
-		Topics:      []common.Hash{common.BytesToHash(crypto.Keccak256([]byte(event)))},
-		Data:        buf.Bytes(),
-		BlockNumber: t.Evm.Context.BlockNumber.Uint64(),
-	})
+// GetTicketPriceHistory returns count of the latest ticket prices set by the
+// pricing epochs from the index start, oldest first. count is capped to
+// MaxTicketPriceHistoryPage.
+func (t *TicketContract) GetTicketPriceHistory(start, count uint32) ([]byte, error) {
+	if count > MaxTicketPriceHistoryPage {
+		count = MaxTicketPriceHistoryPage
+	}
+	records := t.Evm.TicketPoolContext.GetTicketPriceHistory(t.Evm.StateDB, start, count)
+	if err := usePposGas(t.Contract, uint64(len(records))*t.Evm.ChainConfig().PposGasTable(t.Evm.BlockNumber).QueryItem); nil != err {
+		log.Error("Failed to GetTicketPriceHistory", "err: ", err.Error())
+		return nil, err
+	}
+	data, _ := json.Marshal(records)
+	sdata := DecodeResultStr(string(data))
+	log.Info("Result of GetTicketPriceHistory", "len: ", len(records))
+	return sdata, nil
+}
+
+// addLog adds a log of the typed event, args are the values of the event inputs.
+// Before the PPOS events fork the log keeps the legacy format with result.
+func (t *TicketContract) addLog(event string, result ResultCommon, args ...interface{}) {
+	blockNumber := t.Evm.Context.BlockNumber
+	if !t.Evm.ChainConfig().IsPposEvents(blockNumber) {
+		addLegacyPposLog(t.Evm.StateDB, common.TicketPoolAddr, blockNumber, event, result)
+		return
+	}
+	AddPposLog(t.Evm.StateDB, common.TicketPoolAddr, blockNumber, event, args...)
 }
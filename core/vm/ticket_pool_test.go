@@ -6,8 +6,11 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/byteutil"
 	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/core/ppos"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"math/big"
 	"testing"
@@ -26,15 +29,15 @@ func TestTicketPoolOverAll(t *testing.T) {
 		evm,
 	}
 
-	// CandidateDeposit(nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string) ([]byte, error)
-	nodeId := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	// CandidateDeposit(nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string, sig, blsPubKey, blsProof []byte) ([]byte, error)
+	nodeId := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
@@ -63,14 +66,14 @@ func TestVoteTicket(t *testing.T) {
 		contract,
 		evm,
 	}
-	nodeId := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
@@ -98,27 +101,27 @@ func TestGetCandidateTicketCount(t *testing.T) {
 		contract,
 		evm,
 	}
-	nodeId1 := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId1 := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId1: ", nodeId1.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId1, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId1, owner, fee, host, port, extra, nodeSig(nodeId1, owner), blsPubKey(nodeId1), blsProof(nodeId1))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
 	fmt.Println("CandidateDeposit1 success")
 
-	nodeId2 := discover.MustHexID("0x11234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId2 := testNodeId2
 	owner = common.HexToAddress("0x12")
 	fee = uint32(8000)
 	host = "192.168.9.185"
 	port = "16789"
 	extra = "{\"nodeName\": \"Platon-Shenzhen\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Cosmic wave\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/sz\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId2: ", nodeId2.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err = candidateContract.CandidateDeposit(nodeId2, owner, fee, host, port, extra)
+	_, err = candidateContract.CandidateDeposit(nodeId2, owner, fee, host, port, extra, nodeSig(nodeId2, owner), blsPubKey(nodeId2), blsProof(nodeId2))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
@@ -158,8 +161,8 @@ func TestGetCandidateTicketCount(t *testing.T) {
 	// GetBatchCandidateTicketCount(nodeIds []discover.NodeID) ([]byte, error)
 	fmt.Println("GetBatchCandidateTicketCount input==>", "nodeIds: ", nodeId1.String(), nodeId2.String())
 	var nodeIds []discover.NodeID
-	nodeId1 = discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
-	nodeId2 = discover.MustHexID("0x11234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId1 = testNodeId1
+	nodeId2 = testNodeId2
 	nodeIds = append(append(nodeIds, nodeId1), nodeId2)
 	resByte, err = ticketContract.GetCandidateTicketCount(nodeIds)
 	if nil != err {
@@ -184,14 +187,14 @@ func TestGetCandidateEpoch(t *testing.T) {
 		contract,
 		evm,
 	}
-	nodeId := discover.MustHexID("0x01234567890121345678901123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345")
+	nodeId := testNodeId1
 	owner := common.HexToAddress("0x12")
 	fee := uint32(7000)
 	host := "192.168.9.184"
 	port := "16789"
 	extra := "{\"nodeName\": \"Platon-Beijing\", \"nodePortrait\": \"\",\"nodeDiscription\": \"PlatON-Gravitational area\",\"nodeDepartment\": \"JUZIX\",\"officialWebsite\": \"https://www.platon.network/\",\"time\":1546503651190}"
 	fmt.Println("CandidateDeposit input==>", "nodeId: ", nodeId.String(), "owner: ", owner.Hex(), "fee: ", fee, "host: ", host, "port: ", port, "extra: ", extra)
-	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra)
+	_, err := candidateContract.CandidateDeposit(nodeId, owner, fee, host, port, extra, nodeSig(nodeId, owner), blsPubKey(nodeId), blsProof(nodeId))
 	if nil != err {
 		fmt.Println("CandidateDeposit fail", "err", err)
 	}
@@ -227,6 +230,181 @@ func TestGetTicketPrice(t *testing.T) {
 	fmt.Println("The ticket price is: ", vm.ResultByte2Json(resByte))
 }
 
+func TestTicketPriceAdjust(t *testing.T) {
+	evm := newEvm()
+	ticketPoolContext := evm.TicketPoolContext.(*pposm.TicketPoolContext)
+	configs := ticketPoolContext.Configs.TicketConfig
+	configs.TicketPrice = "100"
+	configs.PriceEpoch = 10
+	configs.TargetUtilization = 50
+	configs.PriceAdjustDenominator = 8
+	configs.MaxTicketPrice = "90"
+	stateDB := evm.StateDB
+
+	// nothing sold, the price drops by 1/8
+	if err := ticketPoolContext.Notify(stateDB, big.NewInt(10)); err != nil {
+		t.Fatalf("Notify fail: %v", err)
+	}
+	if price := ticketPoolContext.GetTicketPrice(stateDB); price.Cmp(big.NewInt(88)) != 0 {
+		t.Fatalf("price mismatch: have %v, want 88", price)
+	}
+	// no adjustment within the epoch
+	if err := ticketPoolContext.Notify(stateDB, big.NewInt(11)); err != nil {
+		t.Fatalf("Notify fail: %v", err)
+	}
+	if price := ticketPoolContext.GetTicketPrice(stateDB); price.Cmp(big.NewInt(88)) != 0 {
+		t.Fatalf("price mismatch: have %v, want 88", price)
+	}
+
+	candidateContract := vm.CandidateContract{newContract(), evm}
+	owner := common.HexToAddress("0x12")
+	if _, err := candidateContract.CandidateDeposit(testNodeId1, owner, 7000, "192.168.9.184", "16789", "{}", nodeSig(testNodeId1, owner), blsPubKey(testNodeId1), blsProof(testNodeId1)); err != nil {
+		t.Fatalf("CandidateDeposit fail: %v", err)
+	}
+	caller := vm.AccountRef(owner)
+	ticketContract := vm.TicketContract{vm.NewContract(caller, caller, big.NewInt(8000*88), uint64(1)), evm}
+	if _, err := ticketContract.VoteTicket(8000, big.NewInt(88), testNodeId1); err != nil {
+		t.Fatalf("VoteTicket fail: %v", err)
+	}
+	// 80% sold, the price rises up to the max price
+	if err := ticketPoolContext.Notify(stateDB, big.NewInt(20)); err != nil {
+		t.Fatalf("Notify fail: %v", err)
+	}
+	if price := ticketPoolContext.GetTicketPrice(stateDB); price.Cmp(big.NewInt(90)) != 0 {
+		t.Fatalf("price mismatch: have %v, want 90", price)
+	}
+
+	if price, base := stateDB.GetPPOSCache().GetTicketPrice(); price.Cmp(big.NewInt(90)) != 0 || base.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("stored price mismatch: have %v from %v, want 90 from 100", price, base)
+	}
+
+	history := ticketPoolContext.GetTicketPriceHistory(stateDB, 0, 10)
+	if len(history) != 2 {
+		t.Fatalf("history length mismatch: have %d, want 2", len(history))
+	}
+	want := []struct {
+		block, price int64
+		remaining    uint32
+	}{{10, 88, 10000}, {20, 90, 2000}}
+	for i, record := range history {
+		if record.BlockNumber.Int64() != want[i].block || record.Price.Int64() != want[i].price || record.Remaining != want[i].remaining {
+			t.Errorf("record %d mismatch: have %v %v %d, want %+v", i, record.BlockNumber, record.Price, record.Remaining, want[i])
+		}
+	}
+	if page := ticketPoolContext.GetTicketPriceHistory(stateDB, 1, 1); len(page) != 1 || page[0].BlockNumber.Int64() != 20 {
+		t.Fatalf("page mismatch: %v", page)
+	}
+	if page := ticketPoolContext.GetTicketPriceHistory(stateDB, 2, 1); len(page) != 0 {
+		t.Fatalf("page mismatch: %v", page)
+	}
+
+	// a new configured price replaces the adjusted one
+	configs.TicketPrice = "50"
+	if price := ticketPoolContext.GetTicketPrice(stateDB); price.Cmp(big.NewInt(50)) != 0 {
+		t.Fatalf("price mismatch: have %v, want 50", price)
+	}
+}
+
+func TestTransferAndRedelegateTicket(t *testing.T) {
+	evm := newEvm()
+	stateDB := evm.StateDB.(*state.StateDB)
+	ticketPoolContext := evm.TicketPoolContext.(*pposm.TicketPoolContext)
+	owner1, owner2, holder := common.HexToAddress("0x12"), common.HexToAddress("0x13"), common.HexToAddress("0x99")
+	for _, can := range []struct {
+		nodeId discover.NodeID
+		owner  common.Address
+	}{{testNodeId1, owner1}, {testNodeId2, owner2}} {
+		candidateContract := vm.CandidateContract{vm.NewContract(vm.AccountRef(can.owner), vm.AccountRef(can.owner), big.NewInt(1000), uint64(1)), evm}
+		if _, err := candidateContract.CandidateDeposit(can.nodeId, can.owner, 7000, "192.168.9.184", "16789", "{}", nodeSig(can.nodeId, can.owner), blsPubKey(can.nodeId), blsProof(can.nodeId)); err != nil {
+			t.Fatalf("CandidateDeposit fail: %v", err)
+		}
+	}
+	ticketFrom := func(from common.Address, value int64) *vm.TicketContract {
+		return &vm.TicketContract{vm.NewContract(vm.AccountRef(from), vm.AccountRef(from), big.NewInt(value), uint64(1)), evm}
+	}
+	ticketId := common.HexToHash("0x01")
+	stateDB.Prepare(ticketId, common.Hash{}, 0)
+	if _, err := ticketFrom(owner1, 10).VoteTicket(10, big.NewInt(1), testNodeId1); err != nil {
+		t.Fatalf("VoteTicket fail: %v", err)
+	}
+
+	stateDB.Prepare(common.HexToHash("0x02"), common.Hash{}, 1)
+	if _, err := ticketFrom(owner2, 0).TransferTicket(ticketId, 3, holder); err != pposm.TicketOwnerErr {
+		t.Fatalf("error mismatch: have %v, want %v", err, pposm.TicketOwnerErr)
+	}
+	if _, err := ticketFrom(owner1, 0).TransferTicket(ticketId, 11, holder); err != pposm.TicketCountErr {
+		t.Fatalf("error mismatch: have %v, want %v", err, pposm.TicketCountErr)
+	}
+	res, err := ticketFrom(owner1, 0).TransferTicket(ticketId, 3, holder)
+	if err != nil {
+		t.Fatalf("TransferTicket fail: %v", err)
+	}
+	transferId := resultTicketId(res)
+	if transferId == stateDB.TxHash() {
+		t.Fatalf("transferred ticket reuses the transaction hash")
+	}
+	if ticket := ticketPoolContext.GetTicket(stateDB, transferId); ticket == nil || ticket.Owner != holder || ticket.CandidateId != testNodeId1 || ticket.Deposit.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("transferred ticket mismatch: %v", ticket)
+	}
+
+	stateDB.Prepare(common.HexToHash("0x03"), common.Hash{}, 2)
+	if _, err := ticketFrom(holder, 0).RedelegateTicket(transferId, 2, testNodeId1); err != pposm.RedelegateSelfErr {
+		t.Fatalf("error mismatch: have %v, want %v", err, pposm.RedelegateSelfErr)
+	}
+	res, err = ticketFrom(holder, 0).RedelegateTicket(transferId, 2, testNodeId2)
+	if err != nil {
+		t.Fatalf("RedelegateTicket fail: %v", err)
+	}
+	redelegateId := resultTicketId(res)
+	ticket := ticketPoolContext.GetTicket(stateDB, redelegateId)
+	if ticket == nil || ticket.Owner != holder || ticket.CandidateId != testNodeId2 || ticket.BlockNumber.Cmp(evm.Context.BlockNumber) != 0 {
+		t.Fatalf("re-delegated ticket mismatch: %v", ticket)
+	}
+
+	counts := ticketPoolContext.GetCandidatesTicketCount(stateDB, []discover.NodeID{testNodeId1, testNodeId2})
+	if counts[testNodeId1] != 8 || counts[testNodeId2] != 2 {
+		t.Fatalf("ticket count mismatch: have %d %d, want 8 2", counts[testNodeId1], counts[testNodeId2])
+	}
+	remaining := ticketPoolContext.GetBatchTicketRemaining(stateDB, []common.Hash{ticketId, transferId, redelegateId})
+	if remaining[ticketId] != 7 || remaining[transferId] != 1 || remaining[redelegateId] != 2 {
+		t.Fatalf("remaining mismatch: %v", remaining)
+	}
+
+	// no moves right before the election
+	ticketPoolContext.Configs.TicketConfig.TransferCooldown = 10
+	evm.Context.BlockNumber = big.NewInt(common.BaseElection - 5)
+	if _, err := ticketFrom(owner1, 0).TransferTicket(ticketId, 1, holder); err != pposm.TicketCooldownErr {
+		t.Fatalf("error mismatch: have %v, want %v", err, pposm.TicketCooldownErr)
+	}
+	evm.Context.BlockNumber = big.NewInt(common.BaseElection + 1)
+	if _, err := ticketFrom(owner1, 0).TransferTicket(ticketId, 1, holder); err != nil {
+		t.Fatalf("TransferTicket fail: %v", err)
+	}
+
+	// several moves of the same ticket within one transaction get their own ids
+	res, err = ticketFrom(owner1, 0).TransferTicket(ticketId, 1, holder)
+	if err != nil {
+		t.Fatalf("TransferTicket fail: %v", err)
+	}
+	first := resultTicketId(res)
+	if res, err = ticketFrom(owner1, 0).TransferTicket(ticketId, 2, holder); err != nil {
+		t.Fatalf("TransferTicket fail: %v", err)
+	}
+	second := resultTicketId(res)
+	if first == second {
+		t.Fatalf("split tickets share the id %x", first)
+	}
+	remaining = ticketPoolContext.GetBatchTicketRemaining(stateDB, []common.Hash{ticketId, first, second})
+	if remaining[ticketId] != 3 || remaining[first] != 1 || remaining[second] != 2 {
+		t.Fatalf("remaining mismatch: %v", remaining)
+	}
+}
+
+// resultTicketId extracts the new ticket id from the result of a ticket move.
+func resultTicketId(res []byte) common.Hash {
+	return common.HexToHash(string(res[64:130]))
+}
+
 func TestTicketPoolEncode(t *testing.T) {
 	nodeId := []byte("1f3a8672348ff6b789e416762ad53e69063138b8eb4d8780101658f24b2369f1a8e09499226b467d8bc0c4e03e1dc903df857eeb3c67733d21b6aaee2840e429")
 	// VoteTicket(count uint32, price *big.Int, nodeId discover.NodeID)
@@ -327,3 +505,86 @@ func TestTicketPoolEncode(t *testing.T) {
 	}
 
 }
+
+func TestTicketGasMetering(t *testing.T) {
+	state, _ := newChainState()
+	candidatePoolContext, ticketPoolContext := newPool()
+	config := *params.TestChainConfig
+	config.PposGasBlock = big.NewInt(0)
+	evm := vm.NewEVM(vm.Context{
+		BlockNumber:          big.NewInt(7),
+		CandidatePoolContext: candidatePoolContext,
+		TicketPoolContext:    ticketPoolContext,
+	}, state, &config, vm.Config{})
+	gt := params.PposGasTableMetered
+	caller := vm.AccountRef(common.HexToAddress("0x12"))
+
+	ticketContract := vm.TicketContract{
+		vm.NewContract(caller, caller, big.NewInt(0), 10000),
+		evm,
+	}
+	if gas := ticketContract.RequiredGas(nil); gas != gt.Call {
+		t.Fatalf("base gas mismatch: have %d, want %d", gas, gt.Call)
+	}
+	input, _ := rlp.EncodeToBytes([][]byte{byteutil.Uint64ToBytes(0), []byte("GetTicketCountByTxHash"), []byte("0x01:0x02:0x03")})
+	if _, err := ticketContract.Run(input); nil != err {
+		t.Fatalf("GetTicketCountByTxHash fail: %v", err)
+	}
+	if want := 10000 - 3*gt.QueryItem; ticketContract.Contract.Gas != want {
+		t.Fatalf("gas left mismatch: have %d, want %d", ticketContract.Contract.Gas, want)
+	}
+
+	// 100 tickets cost more than the gas left, none of them is created
+	remainder := ticketPoolContext.GetPoolNumber(state)
+	ticketContract.Contract = vm.NewContract(caller, caller, big.NewInt(100), 100*gt.Ticket)
+	input, _ = rlp.EncodeToBytes([][]byte{byteutil.Uint64ToBytes(1000), []byte("VoteTicket"), byteutil.Uint32ToBytes(100), big.NewInt(1).Bytes(), []byte(testNodeId1.String())})
+	if _, err := ticketContract.Run(input); err != vm.ErrOutOfGas {
+		t.Fatalf("VoteTicket error mismatch: have %v, want %v", err, vm.ErrOutOfGas)
+	}
+	if left := ticketPoolContext.GetPoolNumber(state); left != remainder {
+		t.Fatalf("ticket pool remainder changed: have %d, want %d", left, remainder)
+	}
+	// the candidate updates are charged as well
+	candidateContract := vm.CandidateContract{
+		vm.NewContract(caller, caller, big.NewInt(0), gt.QueueUpdate-1),
+		evm,
+	}
+	input, _ = rlp.EncodeToBytes([][]byte{byteutil.Uint64ToBytes(1012), []byte("Unjail"), []byte(testNodeId1.String())})
+	if _, err := candidateContract.Run(input); err != vm.ErrOutOfGas {
+		t.Fatalf("Unjail error mismatch: have %v, want %v", err, vm.ErrOutOfGas)
+	}
+}
+
+func TestTicketCommandForks(t *testing.T) {
+	state, _ := newChainState()
+	candidatePoolContext, ticketPoolContext := newPool()
+	config := *params.TestChainConfig
+	config.TicketTransferBlock = big.NewInt(10)
+	evm := vm.NewEVM(vm.Context{
+		CandidatePoolContext: candidatePoolContext,
+		TicketPoolContext:    ticketPoolContext,
+	}, state, &config, vm.Config{})
+	ticketContract := vm.TicketContract{newContract(), evm}
+
+	tests := []struct {
+		name string
+		args []interface{}
+	}{
+		{"TransferTicket", []interface{}{common.HexToHash("0x01"), uint32(1), common.HexToAddress("0x99")}},
+		{"RedelegateTicket", []interface{}{common.HexToHash("0x01"), uint32(1), testNodeId2}},
+	}
+	for _, tt := range tests {
+		input, err := vm.EncodeInput(tt.name, tt.args...)
+		if err != nil {
+			t.Fatalf("%s: failed to encode input: %v", tt.name, err)
+		}
+		evm.BlockNumber = big.NewInt(9)
+		if _, err := ticketContract.Run(input); err != vm.ErrUndefFunction {
+			t.Errorf("%s: error mismatch before the fork: have %v, want %v", tt.name, err, vm.ErrUndefFunction)
+		}
+		evm.BlockNumber = config.TicketTransferBlock
+		if _, err := ticketContract.Run(input); err == vm.ErrUndefFunction {
+			t.Errorf("%s: command unknown after the fork", tt.name)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bn256"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+var (
+	ErrVerifyingKeyLen      = errors.New("Verifying key length is illegal")
+	ErrVerifyingKeyNotExist = errors.New("Verifying key does not exist")
+	ErrProofLen             = errors.New("Proof length is illegal")
+	ErrProofInputsLen       = errors.New("Public inputs do not match the verifying key")
+	ErrProofInputRange      = errors.New("Public input exceeds the scalar field")
+)
+
+const (
+	RegisterVerifyingKeyEvent = "RegisterVerifyingKeyEvent"
+)
+
+const (
+	g1PointLen = 64  // uncompressed bn256 G1 point, as the bn256 precompiles
+	g2PointLen = 128 // uncompressed bn256 G2 point, as the bn256 precompiles
+
+	// alpha(G1) | beta(G2) | gamma(G2) | delta(G2), followed by the IC points
+	verifyingKeyBaseLen = g1PointLen + 3*g2PointLen
+	// A(G1) | B(G2) | C(G1)
+	proofLen = 2*g1PointLen + g2PointLen
+)
+
+const (
+	// pairing check of the four point pairs of the Groth16 equation
+	vcPairingGas = params.Bn256PairingBaseGas + 4*params.Bn256PairingPerPointGas
+	// scalar multiplication and addition of an IC point per public input
+	vcPerInputGas = params.Bn256ScalarMulGas + params.Bn256AddGas
+	// storage of a 32 bytes word of a registered verifying key
+	vcPerWordGas = params.SstoreSetGas
+)
+
+// groth16ScalarOrder is the order of the bn256 groups, public inputs are
+// elements of the scalar field.
+var groth16ScalarOrder, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+// VerifyingKeyKey returns the state key of a registered verifying key.
+func VerifyingKeyKey(keyId common.Hash) []byte {
+	return append([]byte("VerifyingKey"), keyId.Bytes()...)
+}
+
+// groth16VerifyingKey is the verifying key of a Groth16 circuit over bn256.
+type groth16VerifyingKey struct {
+	alpha *bn256.G1
+	beta  *bn256.G2
+	gamma *bn256.G2
+	delta *bn256.G2
+	ic    []*bn256.G1 // ic[0] is the constant term, one more point per public input
+}
+
+// groth16Proof is a Groth16 proof over bn256.
+type groth16Proof struct {
+	a *bn256.G1
+	b *bn256.G2
+	c *bn256.G1
+}
+
+// parseVerifyingKey decodes a verifying key encoded as
+// alpha(G1) | beta(G2) | gamma(G2) | delta(G2) | IC[0..n](G1).
+func parseVerifyingKey(blob []byte) (*groth16VerifyingKey, error) {
+	if len(blob) < verifyingKeyBaseLen+g1PointLen || (len(blob)-verifyingKeyBaseLen)%g1PointLen != 0 {
+		return nil, ErrVerifyingKeyLen
+	}
+	var (
+		vk  = new(groth16VerifyingKey)
+		err error
+	)
+	if vk.alpha, err = newCurvePoint(blob[:g1PointLen]); err != nil {
+		return nil, err
+	}
+	blob = blob[g1PointLen:]
+	for _, p := range []**bn256.G2{&vk.beta, &vk.gamma, &vk.delta} {
+		if *p, err = newTwistPoint(blob[:g2PointLen]); err != nil {
+			return nil, err
+		}
+		blob = blob[g2PointLen:]
+	}
+	for ; len(blob) > 0; blob = blob[g1PointLen:] {
+		point, err := newCurvePoint(blob[:g1PointLen])
+		if err != nil {
+			return nil, err
+		}
+		vk.ic = append(vk.ic, point)
+	}
+	return vk, nil
+}
+
+// parseProof decodes a proof encoded as A(G1) | B(G2) | C(G1).
+func parseProof(blob []byte) (*groth16Proof, error) {
+	if len(blob) != proofLen {
+		return nil, ErrProofLen
+	}
+	var (
+		proof = new(groth16Proof)
+		err   error
+	)
+	if proof.a, err = newCurvePoint(blob[:g1PointLen]); err != nil {
+		return nil, err
+	}
+	if proof.b, err = newTwistPoint(blob[g1PointLen : g1PointLen+g2PointLen]); err != nil {
+		return nil, err
+	}
+	if proof.c, err = newCurvePoint(blob[g1PointLen+g2PointLen:]); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// parseInputs decodes the public inputs, each a 32 bytes big endian scalar.
+func parseInputs(blob []byte) ([]*big.Int, error) {
+	if len(blob)%32 != 0 {
+		return nil, ErrProofInputsLen
+	}
+	inputs := make([]*big.Int, 0, len(blob)/32)
+	for i := 0; i < len(blob); i += 32 {
+		input := new(big.Int).SetBytes(blob[i : i+32])
+		if input.Cmp(groth16ScalarOrder) >= 0 {
+			return nil, ErrProofInputRange
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs, nil
+}
+
+// verify checks e(A, B) == e(alpha, beta) * e(vk_x, gamma) * e(C, delta),
+// where vk_x = IC[0] + sum(inputs[i] * IC[i+1]).
+func (vk *groth16VerifyingKey) verify(proof *groth16Proof, inputs []*big.Int) (bool, error) {
+	if len(inputs)+1 != len(vk.ic) {
+		return false, ErrProofInputsLen
+	}
+	vkx := new(bn256.G1).ScalarMult(vk.ic[0], big.NewInt(1))
+	for i, input := range inputs {
+		vkx.Add(vkx, new(bn256.G1).ScalarMult(vk.ic[i+1], input))
+	}
+	return bn256.PairingCheck(
+		[]*bn256.G1{new(bn256.G1).Neg(proof.a), vk.alpha, vkx, proof.c},
+		[]*bn256.G2{proof.b, vk.beta, vk.gamma, vk.delta},
+	), nil
+}
+
+// VCVerifierContract verifies the Groth16 proofs of verifiable computation
+// results against the verifying keys registered on chain.
+type VCVerifierContract struct {
+	Contract *Contract
+	Evm      *EVM
+}
+
+// RequiredGas charges the lookup of the verifying key every command does, the
+// work of the command is charged by useGas.
+func (v *VCVerifierContract) RequiredGas(input []byte) uint64 {
+	return params.SloadGas
+}
+
+// useGas charges RegisterVerifyingKey by the words of the stored key and
+// VerifyProof by the number of public inputs on top of the pairing check.
+func (v *VCVerifierContract) useGas(name string, args []reflect.Value) error {
+	words := func(arg reflect.Value) uint64 {
+		return uint64(arg.Len()+31) / 32
+	}
+	var gas uint64
+	switch name {
+	case "RegisterVerifyingKey":
+		gas = params.SstoreSetGas + words(args[0])*vcPerWordGas
+	case "VerifyProof":
+		gas = vcPairingGas + words(args[2])*vcPerInputGas
+	}
+	return usePposGas(v.Contract, gas)
+}
+
+func (v *VCVerifierContract) Run(input []byte) ([]byte, error) {
+	return execute(input, v.commands(), v.useGas)
+}
+
+// commands returns the command table of the contract.
+func (v *VCVerifierContract) commands() map[string]interface{} {
+	return map[string]interface{}{
+		"RegisterVerifyingKey": v.RegisterVerifyingKey,
+		"GetVerifyingKey":      v.GetVerifyingKey,
+		"VerifyProof":          v.VerifyProof,
+	}
+}
+
+// RegisterVerifyingKey stores the verifying key of a circuit, the id of the key is its hash.
+func (v *VCVerifierContract) RegisterVerifyingKey(vk []byte) ([]byte, error) {
+	from := v.Contract.caller.Address()
+	log.Info("Input to RegisterVerifyingKey", "from: ", from.Hex(), "len(vk): ", len(vk))
+	if _, err := parseVerifyingKey(vk); err != nil {
+		log.Error("Failed to RegisterVerifyingKey", "err: ", err.Error())
+		return nil, err
+	}
+	keyId := common.BytesToHash(crypto.Keccak256(vk))
+	if len(v.Evm.StateDB.GetState(common.VCVerifierAddr, VerifyingKeyKey(keyId))) == 0 {
+		v.Evm.StateDB.SetState(common.VCVerifierAddr, VerifyingKeyKey(keyId), vk)
+	}
+	data := keyId.Hex()
+	sdata := DecodeResultStr(data)
+	r := ResultCommon{true, data, "success"}
+	event, _ := json.Marshal(r)
+	v.addLog(RegisterVerifyingKeyEvent, string(event))
+	log.Info("Result of RegisterVerifyingKey", "keyId: ", data)
+	return sdata, nil
+}
+
+// GetVerifyingKey returns the verifying key registered by keyId.
+func (v *VCVerifierContract) GetVerifyingKey(keyId common.Hash) ([]byte, error) {
+	vk := v.Evm.StateDB.GetState(common.VCVerifierAddr, VerifyingKeyKey(keyId))
+	if len(vk) == 0 {
+		log.Error("Failed to GetVerifyingKey", "keyId: ", keyId.Hex(), "err: ", ErrVerifyingKeyNotExist.Error())
+		return nil, ErrVerifyingKeyNotExist
+	}
+	return DecodeResultStr(common.ToHex(vk)), nil
+}
+
+// VerifyProof checks the proof of a verifiable computation result against the
+// verifying key keyId, inputs are the public inputs as 32 bytes big endian words.
+func (v *VCVerifierContract) VerifyProof(keyId common.Hash, proof []byte, inputs []byte) ([]byte, error) {
+	log.Info("Input to VerifyProof", "keyId: ", keyId.Hex(), "len(proof): ", len(proof), "len(inputs): ", len(inputs))
+	blob := v.Evm.StateDB.GetState(common.VCVerifierAddr, VerifyingKeyKey(keyId))
+	if len(blob) == 0 {
+		log.Error("Failed to VerifyProof", "keyId: ", keyId.Hex(), "err: ", ErrVerifyingKeyNotExist.Error())
+		return nil, ErrVerifyingKeyNotExist
+	}
+	ok, err := VerifyGroth16(blob, proof, inputs)
+	if err != nil {
+		log.Error("Failed to VerifyProof", "keyId: ", keyId.Hex(), "err: ", err.Error())
+		return nil, err
+	}
+	data, _ := json.Marshal(ok)
+	log.Info("Result of VerifyProof", "keyId: ", keyId.Hex(), "valid: ", ok)
+	return DecodeResultStr(string(data)), nil
+}
+
+// VerifyGroth16 checks a Groth16 proof over bn256 in pure Go, the encodings are
+// the ones accepted by the VCVerifierContract.
+func VerifyGroth16(verifyingKey, proof, inputs []byte) (bool, error) {
+	vk, err := parseVerifyingKey(verifyingKey)
+	if err != nil {
+		return false, err
+	}
+	p, err := parseProof(proof)
+	if err != nil {
+		return false, err
+	}
+	in, err := parseInputs(inputs)
+	if err != nil {
+		return false, err
+	}
+	return vk.verify(p, in)
+}
+
+// addLog let the result add to event.
+func (v *VCVerifierContract) addLog(event, data string) {
+	var logdata [][]byte
+	logdata = make([][]byte, 0)
+	logdata = append(logdata, []byte(data))
+	buf := new(bytes.Buffer)
+	if err := rlp.Encode(buf, logdata); nil != err {
+		log.Error("Failed to addlog", "rlp encode fail: ", err.Error())
+	}
+	v.Evm.StateDB.AddLog(&types.Log{
+		Address:     common.VCVerifierAddr,
+		Topics:      []common.Hash{common.BytesToHash(crypto.Keccak256([]byte(event)))},
+		Data:        buf.Bytes(),
+		BlockNumber: v.Evm.Context.BlockNumber.Uint64(),
+	})
+}
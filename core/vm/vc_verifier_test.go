@@ -0,0 +1,155 @@
+package vm_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bn256"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+)
+
+var bn256Order, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+// groth16Fixture builds a verifying key and a proof satisfying the Groth16
+// verification equation for the given inputs, from known discrete logs.
+func groth16Fixture(inputs []*big.Int) (vk, proof, in []byte) {
+	scalar := func(v int64) *big.Int { return big.NewInt(v) }
+	var (
+		alpha, beta, gamma, delta = scalar(3), scalar(5), scalar(7), scalar(11)
+		ic                        = []*big.Int{scalar(13)}
+		a, b                      = scalar(17), scalar(19)
+	)
+	for i := range inputs {
+		ic = append(ic, scalar(int64(23+i)))
+	}
+	// vk_x = ic[0] + sum(inputs[i] * ic[i+1])
+	vkx := new(big.Int).Set(ic[0])
+	for i, input := range inputs {
+		vkx.Add(vkx, new(big.Int).Mul(input, ic[i+1]))
+	}
+	// a*b = alpha*beta + vkx*gamma + c*delta
+	c := new(big.Int).Mul(a, b)
+	c.Sub(c, new(big.Int).Mul(alpha, beta))
+	c.Sub(c, new(big.Int).Mul(vkx, gamma))
+	c.Mul(c, new(big.Int).ModInverse(delta, bn256Order))
+	c.Mod(c, bn256Order)
+
+	g1 := func(k *big.Int) []byte { return new(bn256.G1).ScalarBaseMult(k).Marshal() }
+	g2 := func(k *big.Int) []byte { return new(bn256.G2).ScalarBaseMult(k).Marshal() }
+
+	vk = append(vk, g1(alpha)...)
+	vk = append(vk, g2(beta)...)
+	vk = append(vk, g2(gamma)...)
+	vk = append(vk, g2(delta)...)
+	for _, k := range ic {
+		vk = append(vk, g1(k)...)
+	}
+	proof = append(proof, g1(a)...)
+	proof = append(proof, g2(b)...)
+	proof = append(proof, g1(c)...)
+	for _, input := range inputs {
+		in = append(in, common.LeftPadBytes(input.Bytes(), 32)...)
+	}
+	return vk, proof, in
+}
+
+func TestVerifyGroth16(t *testing.T) {
+	inputs := []*big.Int{big.NewInt(42), big.NewInt(7)}
+	vk, proof, in := groth16Fixture(inputs)
+
+	if ok, err := vm.VerifyGroth16(vk, proof, in); err != nil || !ok {
+		t.Fatalf("valid proof rejected: ok %v, err %v", ok, err)
+	}
+	// A different public input must not verify
+	_, _, other := groth16Fixture([]*big.Int{big.NewInt(43), big.NewInt(7)})
+	if ok, err := vm.VerifyGroth16(vk, proof, other); err != nil || ok {
+		t.Fatalf("proof accepted for wrong inputs: ok %v, err %v", ok, err)
+	}
+	// A tampered proof must not verify
+	bad := append([]byte{}, proof...)
+	copy(bad[len(bad)-64:], new(bn256.G1).ScalarBaseMult(big.NewInt(1)).Marshal())
+	if ok, err := vm.VerifyGroth16(vk, bad, in); err != nil || ok {
+		t.Fatalf("tampered proof accepted: ok %v, err %v", ok, err)
+	}
+	// Malformed encodings
+	if _, err := vm.VerifyGroth16(vk, proof, in[:32]); err != vm.ErrProofInputsLen {
+		t.Errorf("error mismatch: have %v, want %v", err, vm.ErrProofInputsLen)
+	}
+	if _, err := vm.VerifyGroth16(vk, proof[:128], in); err != vm.ErrProofLen {
+		t.Errorf("error mismatch: have %v, want %v", err, vm.ErrProofLen)
+	}
+	if _, err := vm.VerifyGroth16(vk[:100], proof, in); err != vm.ErrVerifyingKeyLen {
+		t.Errorf("error mismatch: have %v, want %v", err, vm.ErrVerifyingKeyLen)
+	}
+	overflow := append(common.LeftPadBytes(bn256Order.Bytes(), 32), in[32:]...)
+	if _, err := vm.VerifyGroth16(vk, proof, overflow); err != vm.ErrProofInputRange {
+		t.Errorf("error mismatch: have %v, want %v", err, vm.ErrProofInputRange)
+	}
+}
+
+func TestVCVerifierContract(t *testing.T) {
+	contract := vm.VCVerifierContract{
+		newContract(),
+		newEvm(),
+	}
+	vk, proof, in := groth16Fixture([]*big.Int{big.NewInt(1)})
+
+	if _, err := contract.RegisterVerifyingKey(vk); err != nil {
+		t.Fatalf("RegisterVerifyingKey fail: %v", err)
+	}
+	keyId := common.BytesToHash(crypto.Keccak256(vk))
+	if _, err := contract.GetVerifyingKey(keyId); err != nil {
+		t.Fatalf("GetVerifyingKey fail: %v", err)
+	}
+
+	ret, err := contract.VerifyProof(keyId, proof, in)
+	if err != nil {
+		t.Fatalf("VerifyProof fail: %v", err)
+	}
+	var valid bool
+	if err := json.Unmarshal(bytes.TrimRight(ret[64:], "\x00"), &valid); err != nil || !valid {
+		t.Fatalf("valid proof rejected: %s", ret[64:])
+	}
+	if _, err := contract.VerifyProof(common.Hash{0x01}, proof, in); err != vm.ErrVerifyingKeyNotExist {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrVerifyingKeyNotExist)
+	}
+	if _, err := contract.RegisterVerifyingKey(vk[:200]); err != vm.ErrVerifyingKeyLen {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrVerifyingKeyLen)
+	}
+}
+
+func TestVCVerifierGas(t *testing.T) {
+	vk, proof, in := groth16Fixture([]*big.Int{big.NewInt(1), big.NewInt(2)})
+	evm := newEvm()
+	// run returns the gas used by the command on top of the required gas
+	run := func(name string, args ...interface{}) uint64 {
+		caller := vm.AccountRef(common.HexToAddress("0x12"))
+		contract := vm.VCVerifierContract{vm.NewContract(caller, caller, big.NewInt(0), 10000000), evm}
+		input, _ := vm.EncodeInput(name, args...)
+		if have, want := contract.RequiredGas(input), params.SloadGas; have != want {
+			t.Errorf("%s required gas mismatch: have %d, want %d", name, have, want)
+		}
+		if _, err := contract.Run(input); err != nil {
+			t.Fatalf("%s fail: %v", name, err)
+		}
+		return 10000000 - contract.Contract.Gas
+	}
+
+	if have, want := run("RegisterVerifyingKey", vk), params.SstoreSetGas*uint64(1+len(vk)/32); have != want {
+		t.Errorf("RegisterVerifyingKey gas mismatch: have %d, want %d", have, want)
+	}
+	base := params.Bn256PairingBaseGas + 4*params.Bn256PairingPerPointGas
+	perInput := params.Bn256ScalarMulGas + params.Bn256AddGas
+	keyId := common.BytesToHash(crypto.Keccak256(vk))
+	if have, want := run("VerifyProof", keyId, proof, in), base+2*perInput; have != want {
+		t.Errorf("VerifyProof gas mismatch: have %d, want %d", have, want)
+	}
+	if have, want := run("GetVerifyingKey", keyId), uint64(0); have != want {
+		t.Errorf("GetVerifyingKey gas mismatch: have %d, want %d", have, want)
+	}
+}
@@ -0,0 +1,33 @@
+package bls
+
+// Bitmap marks the signers of an aggregated signature, bit i is set if the
+// signer at index i of the list of signers contributed to the aggregate.
+type Bitmap []byte
+
+// NewBitmap returns a bitmap of n signers without any bit set.
+func NewBitmap(n int) Bitmap {
+	return make(Bitmap, (n+7)/8)
+}
+
+// Set marks the signer i, it is ignored if i is out of the bitmap.
+func (b Bitmap) Set(i int) {
+	if i >= 0 && i/8 < len(b) {
+		b[i/8] |= 1 << uint(i%8)
+	}
+}
+
+// Has reports whether the signer i is marked.
+func (b Bitmap) Has(i int) bool {
+	return i >= 0 && i/8 < len(b) && b[i/8]&(1<<uint(i%8)) != 0
+}
+
+// Count returns the number of the marked signers.
+func (b Bitmap) Count() int {
+	n := 0
+	for _, c := range b {
+		for ; c != 0; c &= c - 1 {
+			n++
+		}
+	}
+	return n
+}
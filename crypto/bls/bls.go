@@ -0,0 +1,222 @@
+// Package bls implements BLS signatures on the bn256 curve. Signatures are points
+// of G1 and public keys are points of G2, so that the signatures of a message by
+// several keys can be aggregated into a single signature which is verified with
+// the sum of the public keys.
+//
+// Aggregating public keys is only safe for keys whose owners proved the possession
+// of the secret key, see Prove and VerifyProof.
+package bls
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/PlatONnetwork/PlatON-Go/common/math"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bn256"
+)
+
+const (
+	// PublicKeyLength is the length of a marshalled public key.
+	PublicKeyLength = 128
+	// SignatureLength is the length of a marshalled signature.
+	SignatureLength = 64
+)
+
+var (
+	// domains separating the messages of signatures from the proofs of possession
+	signDomain  = []byte("PlatON-BLS-SIGN")
+	proofDomain = []byte("PlatON-BLS-POP")
+
+	g2 = new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+)
+
+var (
+	ErrInvalidPublicKey = errors.New("bls: invalid public key")
+	ErrInvalidSignature = errors.New("bls: invalid signature")
+)
+
+// SecretKey is a BLS secret key.
+type SecretKey struct {
+	x *big.Int
+}
+
+// PublicKey is a BLS public key.
+type PublicKey struct {
+	p *bn256.G2
+}
+
+// Signature is a BLS signature, or the aggregate of several signatures.
+type Signature struct {
+	s *bn256.G1
+}
+
+// GenerateKey generates a secret key reading randomness from r, crypto/rand
+// is used if r is nil.
+func GenerateKey(r io.Reader) (*SecretKey, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	for {
+		x, err := rand.Int(r, bn256.Order)
+		if err != nil {
+			return nil, err
+		}
+		if x.Sign() > 0 {
+			return &SecretKey{x}, nil
+		}
+	}
+}
+
+// SecretKeyFromSeed derives a secret key from seed. The same seed always gives the
+// same key, so a node can derive its BLS key from its node key.
+func SecretKeyFromSeed(seed []byte) *SecretKey {
+	h := crypto.Keccak256(signDomain, seed)
+	for {
+		x := new(big.Int).Mod(new(big.Int).SetBytes(h), bn256.Order)
+		if x.Sign() > 0 {
+			return &SecretKey{x}
+		}
+		h = crypto.Keccak256(h)
+	}
+}
+
+// NodeSecretKey returns the BLS secret key of the node whose node key is prv.
+func NodeSecretKey(prv *ecdsa.PrivateKey) *SecretKey {
+	return SecretKeyFromSeed(crypto.FromECDSA(prv))
+}
+
+// PublicKey returns the public key of sk.
+func (sk *SecretKey) PublicKey() *PublicKey {
+	return &PublicKey{new(bn256.G2).ScalarMult(g2, sk.x)}
+}
+
+// Sign signs msg with sk.
+func (sk *SecretKey) Sign(msg []byte) *Signature {
+	return &Signature{new(bn256.G1).ScalarMult(hashToG1(signDomain, msg), sk.x)}
+}
+
+// Prove returns the proof of possession of sk, the signature of its public key
+// in a domain of its own.
+func (sk *SecretKey) Prove() *Signature {
+	return &Signature{new(bn256.G1).ScalarMult(hashToG1(proofDomain, sk.PublicKey().Marshal()), sk.x)}
+}
+
+// Verify reports whether sig is the signature of msg by pk. With an aggregated
+// signature, pk is the aggregate of the public keys of the signers.
+func Verify(pk *PublicKey, msg []byte, sig *Signature) bool {
+	return verify(pk, hashToG1(signDomain, msg), sig)
+}
+
+// VerifyProof reports whether proof proves the possession of the secret key of pk.
+func VerifyProof(pk *PublicKey, proof *Signature) bool {
+	return verify(pk, hashToG1(proofDomain, pk.Marshal()), proof)
+}
+
+// verify checks e(sig, g2) == e(h, pk).
+func verify(pk *PublicKey, h *bn256.G1, sig *Signature) bool {
+	if pk == nil || sig == nil {
+		return false
+	}
+	return bn256.PairingCheck([]*bn256.G1{sig.s, new(bn256.G1).Neg(h)}, []*bn256.G2{g2, pk.p})
+}
+
+// AggregateSignatures returns the sum of sigs, nil if sigs is empty.
+func AggregateSignatures(sigs ...*Signature) *Signature {
+	if len(sigs) == 0 {
+		return nil
+	}
+	s := sigs[0].s
+	for _, sig := range sigs[1:] {
+		s = new(bn256.G1).Add(s, sig.s)
+	}
+	return &Signature{s}
+}
+
+// AggregatePublicKeys returns the sum of pks, nil if pks is empty.
+func AggregatePublicKeys(pks ...*PublicKey) *PublicKey {
+	if len(pks) == 0 {
+		return nil
+	}
+	p := pks[0].p
+	for _, pk := range pks[1:] {
+		p = new(bn256.G2).Add(p, pk.p)
+	}
+	return &PublicKey{p}
+}
+
+// Marshal returns the encoding of pk.
+func (pk *PublicKey) Marshal() []byte {
+	return pk.p.Marshal()
+}
+
+// UnmarshalPublicKey decodes a public key, the point at infinity and points
+// outside of the group of the generator are rejected.
+func UnmarshalPublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != PublicKeyLength {
+		return nil, ErrInvalidPublicKey
+	}
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	if isZero(p.Marshal()) || !isZero(new(bn256.G2).ScalarMult(p, bn256.Order).Marshal()) {
+		return nil, ErrInvalidPublicKey
+	}
+	return &PublicKey{p}, nil
+}
+
+// Marshal returns the encoding of sig.
+func (sig *Signature) Marshal() []byte {
+	return sig.s.Marshal()
+}
+
+// UnmarshalSignature decodes a signature, the point at infinity is rejected.
+func UnmarshalSignature(b []byte) (*Signature, error) {
+	if len(b) != SignatureLength {
+		return nil, ErrInvalidSignature
+	}
+	s := new(bn256.G1)
+	if _, err := s.Unmarshal(b); err != nil || isZero(b) {
+		return nil, ErrInvalidSignature
+	}
+	return &Signature{s}, nil
+}
+
+// hashToG1 maps msg in domain to a point of G1 by try-and-increment: the first
+// hash of (domain, counter, msg) which is the x coordinate of a point of the
+// curve y² = x³ + 3 gives the point. G1 has a cofactor of 1, every point of the
+// curve belongs to it.
+func hashToG1(domain, msg []byte) *bn256.G1 {
+	three := big.NewInt(3)
+	for ctr := 0; ; ctr++ {
+		h := crypto.Keccak256(domain, []byte{byte(ctr >> 8), byte(ctr)}, msg)
+		x := new(big.Int).Mod(new(big.Int).SetBytes(h), bn256.P)
+		rhs := new(big.Int).Exp(x, three, bn256.P)
+		rhs.Add(rhs, three).Mod(rhs, bn256.P)
+		y := new(big.Int).ModSqrt(rhs, bn256.P)
+		if y == nil {
+			continue
+		}
+		enc := make([]byte, 64)
+		math.ReadBits(x, enc[:32])
+		math.ReadBits(y, enc[32:])
+		p := new(bn256.G1)
+		if _, err := p.Unmarshal(enc); err != nil {
+			continue
+		}
+		return p
+	}
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package bls
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testKeys(t *testing.T, n int) []*SecretKey {
+	keys := make([]*SecretKey, n)
+	for i := range keys {
+		key, err := GenerateKey(nil)
+		if err != nil {
+			t.Fatalf("GenerateKey fail: %v", err)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+func TestSignVerify(t *testing.T) {
+	keys := testKeys(t, 2)
+	msg := []byte("block seal hash")
+
+	sig := keys[0].Sign(msg)
+	if !Verify(keys[0].PublicKey(), msg, sig) {
+		t.Fatalf("valid signature rejected")
+	}
+	if Verify(keys[1].PublicKey(), msg, sig) {
+		t.Fatalf("signature accepted for another key")
+	}
+	if Verify(keys[0].PublicKey(), []byte("another message"), sig) {
+		t.Fatalf("signature accepted for another message")
+	}
+
+	dec, err := UnmarshalSignature(sig.Marshal())
+	if err != nil || !Verify(keys[0].PublicKey(), msg, dec) {
+		t.Fatalf("signature encoding round trip fail: %v", err)
+	}
+	pk, err := UnmarshalPublicKey(keys[0].PublicKey().Marshal())
+	if err != nil || !Verify(pk, msg, sig) {
+		t.Fatalf("public key encoding round trip fail: %v", err)
+	}
+	if _, err := UnmarshalPublicKey(make([]byte, PublicKeyLength)); err != ErrInvalidPublicKey {
+		t.Fatalf("infinity public key error mismatch: have %v, want %v", err, ErrInvalidPublicKey)
+	}
+	if _, err := UnmarshalSignature(make([]byte, SignatureLength)); err != ErrInvalidSignature {
+		t.Fatalf("infinity signature error mismatch: have %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	keys := testKeys(t, 4)
+	msg := []byte("block seal hash")
+
+	var (
+		sigs []*Signature
+		pks  []*PublicKey
+	)
+	for _, key := range keys[:3] {
+		sigs = append(sigs, key.Sign(msg))
+		pks = append(pks, key.PublicKey())
+	}
+	agg := AggregateSignatures(sigs...)
+	if !Verify(AggregatePublicKeys(pks...), msg, agg) {
+		t.Fatalf("valid aggregate rejected")
+	}
+	if Verify(AggregatePublicKeys(pks[:2]...), msg, agg) {
+		t.Fatalf("aggregate accepted with a missing signer")
+	}
+	if Verify(AggregatePublicKeys(append(pks, keys[3].PublicKey())...), msg, agg) {
+		t.Fatalf("aggregate accepted with an extra signer")
+	}
+}
+
+func TestProof(t *testing.T) {
+	keys := testKeys(t, 2)
+	proof := keys[0].Prove()
+	if !VerifyProof(keys[0].PublicKey(), proof) {
+		t.Fatalf("valid proof rejected")
+	}
+	if VerifyProof(keys[1].PublicKey(), proof) {
+		t.Fatalf("proof accepted for another key")
+	}
+	// a proof is not a signature of the public key
+	if Verify(keys[0].PublicKey(), keys[0].PublicKey().Marshal(), proof) {
+		t.Fatalf("proof accepted as a signature")
+	}
+}
+
+func TestSecretKeyFromSeed(t *testing.T) {
+	a, b := SecretKeyFromSeed([]byte("seed")), SecretKeyFromSeed([]byte("seed"))
+	if !bytes.Equal(a.PublicKey().Marshal(), b.PublicKey().Marshal()) {
+		t.Fatalf("derived key is not reproducible")
+	}
+	if c := SecretKeyFromSeed([]byte("other")); bytes.Equal(a.PublicKey().Marshal(), c.PublicKey().Marshal()) {
+		t.Fatalf("derived key doesn't depend on the seed")
+	}
+}
+
+func TestBitmap(t *testing.T) {
+	b := NewBitmap(10)
+	if len(b) != 2 {
+		t.Fatalf("bitmap length mismatch: have %d, want 2", len(b))
+	}
+	b.Set(0)
+	b.Set(9)
+	b.Set(16)
+	if !b.Has(0) || !b.Has(9) || b.Has(1) || b.Has(16) || b.Has(-1) {
+		t.Fatalf("bitmap bits mismatch: %08b", b)
+	}
+	if b.Count() != 2 {
+		t.Fatalf("bitmap count mismatch: have %d, want 2", b.Count())
+	}
+}
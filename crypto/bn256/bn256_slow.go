@@ -17,6 +17,12 @@ type G1 = bn256.G1
 // output of an operation, but cannot be used as an input.
 type G2 = bn256.G2
 
+// Order is the number of elements in both G₁ and G₂.
+var Order = bn256.Order
+
+// P is the prime over which the curve is defined.
+var P = bn256.P
+
 // PairingCheck calculates the Optimal Ate pairing for a set of points.
 func PairingCheck(a []*G1, b []*G2) bool {
 	return bn256.PairingCheck(a, b)
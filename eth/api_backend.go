@@ -126,6 +126,10 @@ func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*typ
 	for i, receipt := range receipts {
 		logs[i] = receipt.Logs
 	}
+	// the system logs follow the logs of the last transaction
+	if systemLogs := rawdb.ReadSystemLogs(b.eth.chainDb, hash, *number); len(systemLogs) > 0 {
+		logs = append(logs, systemLogs)
+	}
 	return logs, nil
 }
 
@@ -0,0 +1,79 @@
+package eth
+
+import (
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/core"
+)
+
+// computeTaskPool is implemented by the mpc and vc pools.
+type computeTaskPool interface {
+	Task(taskId string) *core.ComputeTask
+	Tasks() []*core.ComputeTask
+}
+
+// RPCComputeTask is the json representation of a mpc/vc compute task.
+type RPCComputeTask struct {
+	TaskId      string         `json:"taskId"`
+	TxHash      common.Hash    `json:"txHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	Status      string         `json:"status"`
+	Attempts    hexutil.Uint64 `json:"attempts"`
+	Error       string         `json:"error,omitempty"`
+	Created     hexutil.Uint64 `json:"created"`
+	Updated     hexutil.Uint64 `json:"updated"`
+}
+
+func newRPCComputeTask(task *core.ComputeTask) *RPCComputeTask {
+	return &RPCComputeTask{
+		TaskId:      task.TaskId,
+		TxHash:      task.TxHash,
+		BlockNumber: hexutil.Uint64(task.BlockNumber),
+		BlockHash:   task.BlockHash,
+		Status:      task.Status.String(),
+		Attempts:    hexutil.Uint64(task.Attempts),
+		Error:       task.Error,
+		Created:     hexutil.Uint64(task.Created),
+		Updated:     hexutil.Uint64(task.Updated),
+	}
+}
+
+// PublicComputeTaskAPI offers the state of the mpc or vc compute tasks,
+// it's served in the mpc and the vc namespace.
+type PublicComputeTaskAPI struct {
+	pool computeTaskPool
+}
+
+// NewPublicComputeTaskAPI creates a new API for the tasks of pool.
+func NewPublicComputeTaskAPI(pool computeTaskPool) *PublicComputeTaskAPI {
+	return &PublicComputeTaskAPI{pool}
+}
+
+// Tasks returns all known tasks ordered by the number of their start-calc block.
+func (api *PublicComputeTaskAPI) Tasks() []*RPCComputeTask {
+	tasks := api.pool.Tasks()
+	result := make([]*RPCComputeTask, len(tasks))
+	for i, task := range tasks {
+		result[i] = newRPCComputeTask(task)
+	}
+	return result
+}
+
+// Task returns the task with the given id.
+func (api *PublicComputeTaskAPI) Task(taskId string) (*RPCComputeTask, error) {
+	task := api.pool.Task(taskId)
+	if task == nil {
+		return nil, core.ErrTaskNotFound
+	}
+	return newRPCComputeTask(task), nil
+}
+
+// Status returns the status of the task with the given id.
+func (api *PublicComputeTaskAPI) Status(taskId string) (string, error) {
+	task := api.pool.Task(taskId)
+	if task == nil {
+		return "", core.ErrTaskNotFound
+	}
+	return task.Status.String(), nil
+}
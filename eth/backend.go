@@ -30,6 +30,7 @@ import (
 	"sync/atomic"
 
 	"github.com/PlatONnetwork/PlatON-Go/accounts"
+	"github.com/PlatONnetwork/PlatON-Go/accounts/keystore"
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
 	"github.com/PlatONnetwork/PlatON-Go/consensus"
@@ -129,9 +130,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
-	if nil == ppos_storage.GetPPosTempPtr() {
-		ppos_storage.NewPPosTemp(pposDB)
-	}
+	pposTemp := ppos_storage.NewPPosTemp(pposDB)
 
 	chainConfig, genesisHash, genesisErr := core.SetupGenesisBlock(chainDb, config.Genesis)
 	if _, ok := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !ok {
@@ -177,7 +176,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		cacheConfig = &core.CacheConfig{Disabled: config.NoPruning, TrieNodeLimit: config.TrieCache, TrieTimeLimit: config.TrieTimeout}
 	)
 
-	eth.blockchain, err = core.NewBlockChain(chainDb, cacheConfig, eth.chainConfig, eth.engine, vmConfig, eth.shouldPreserve)
+	eth.blockchain, err = core.NewBlockChain(chainDb, pposTemp, cacheConfig, eth.chainConfig, eth.engine, vmConfig, eth.shouldPreserve)
 	if err != nil {
 		return nil, err
 	}
@@ -212,23 +211,34 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if config.MPCPool.Lifetime == 0 {
 		config.MPCPool.Lifetime = core.DefaultMPCPoolConfig.Lifetime
 	}
-	eth.mpcPool = core.NewMPCPool(config.MPCPool, eth.chainConfig, eth.blockchain)
-	eth.vcPool = core.NewVCPool(config.VCPool, eth.chainConfig, eth.blockchain)
+	if config.MPCPool.TaskTimeout == 0 {
+		config.MPCPool.TaskTimeout = core.DefaultMPCPoolConfig.TaskTimeout
+	}
+	if eth.mpcPool, err = core.NewMPCPool(config.MPCPool, eth.chainConfig, eth.blockchain, chainDb); err != nil {
+		return nil, err
+	}
+	// vc results are signed by the actor key in the node's keystore
+	var ks *keystore.KeyStore
+	if backends := ctx.AccountManager.Backends(keystore.KeyStoreType); len(backends) > 0 {
+		ks = backends[0].(*keystore.KeyStore)
+	}
+	eth.vcPool = core.NewVCPool(config.VCPool, eth.chainConfig, eth.blockchain, chainDb, eth.txPool, ks)
 
 	if eth.protocolManager, err = NewProtocolManager(eth.chainConfig, config.SyncMode, config.NetworkId, eth.eventMux, eth.txPool, eth.engine, eth.blockchain, chainDb); err != nil {
 		return nil, err
 	}
-	if _, ok := eth.engine.(consensus.Bft); ok {
-		cbft.SetPposOption(eth.blockchain)
+	if cbftEngine, ok := eth.engine.(*cbft.Cbft); ok {
+		cbftEngine.SetPposOption(eth.blockchain)
 	}
 
 	//var consensusCache *cbft.Cache = cbft.NewCache(eth.blockchain)
 	eth.miner = miner.New(eth, eth.chainConfig, eth.EventMux(), eth.engine, config.MinerRecommit, config.MinerGasFloor, config.MinerGasCeil, eth.isLocalBlock, blockSignatureCh, cbftResultCh, highestLogicalBlockCh, blockChainCache)
+	eth.miner.SetPposGasShare(config.MinerPposGasShare)
 	eth.miner.SetExtra(makeExtraData(config.MinerExtraData))
 
-	if _, ok := eth.engine.(consensus.Bft); ok {
-		cbft.SetBlockChainCache(blockChainCache)
-		cbft.SetBackend(eth.blockchain, eth.txPool)
+	if cbftEngine, ok := eth.engine.(*cbft.Cbft); ok {
+		cbftEngine.SetBlockChainCache(blockChainCache)
+		cbftEngine.SetBackend(eth.blockchain, eth.txPool)
 
 		shouldElection := func(blockNumber *big.Int) bool {
 			return eth.miner.ShouldElection(blockNumber)
@@ -367,6 +377,16 @@ func (s *Ethereum) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   s.netRPCService,
 			Public:    true,
+		}, {
+			Namespace: "mpc",
+			Version:   "1.0",
+			Service:   NewPublicComputeTaskAPI(s.mpcPool),
+			Public:    true,
+		}, {
+			Namespace: "vc",
+			Version:   "1.0",
+			Service:   NewPublicComputeTaskAPI(s.vcPool),
+			Public:    true,
 		},
 	}...)
 }
@@ -519,6 +539,8 @@ func (s *Ethereum) Miner() *miner.Miner { return s.miner }
 func (s *Ethereum) AccountManager() *accounts.Manager  { return s.accountManager }
 func (s *Ethereum) BlockChain() *core.BlockChain       { return s.blockchain }
 func (s *Ethereum) TxPool() *core.TxPool               { return s.txPool }
+func (s *Ethereum) MPCPool() *core.MPCPool             { return s.mpcPool }
+func (s *Ethereum) VCPool() *core.VCPool               { return s.vcPool }
 func (s *Ethereum) EventMux() *event.TypeMux           { return s.eventMux }
 func (s *Ethereum) Engine() consensus.Engine           { return s.engine }
 func (s *Ethereum) ChainDb() ethdb.Database            { return s.chainDb }
@@ -578,6 +600,10 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 		}
 		if cbftEngine.IsCurrentNode(parentNumber, parentHash, blockNumber) {
 			currentNodes := cbftEngine.CurrentNodes(parentNumber, parentHash, blockNumber)
+			// The consensus nodes behind sentries are reached through them
+			if state, err := s.blockchain.State(); err == nil {
+				currentNodes = vm.ConsensusDialNodes(state, currentNodes)
+			}
 			for _, n := range currentNodes {
 				srvr.AddConsensusPeer(discover.NewNode(n.ID, n.IP, n.UDP, n.TCP))
 			}
@@ -619,11 +645,22 @@ func setPposConfig(pposConfig *PposConfig) *params.PposConfig {
 			MaxChair:          pposConfig.Candidate.MaxChair,
 			MaxCount:          pposConfig.Candidate.MaxCount,
 			RefundBlockNumber: pposConfig.Candidate.RefundBlockNumber,
+			FeeChangeDelay:    pposConfig.Candidate.FeeChangeDelay,
+			LivenessThreshold: pposConfig.Candidate.LivenessThreshold,
+			LivenessPenalty:   pposConfig.Candidate.LivenessPenalty,
+			JailPeriod:        pposConfig.Candidate.JailPeriod,
+			RandomElection:    pposConfig.Candidate.RandomElection,
 		},
 		TicketConfig: &params.TicketConfig{
-			TicketPrice:       pposConfig.Ticket.TicketPrice,
-			MaxCount:          pposConfig.Ticket.MaxCount,
-			ExpireBlockNumber: pposConfig.Ticket.ExpireBlockNumber,
+			TicketPrice:            pposConfig.Ticket.TicketPrice,
+			MaxCount:               pposConfig.Ticket.MaxCount,
+			ExpireBlockNumber:      pposConfig.Ticket.ExpireBlockNumber,
+			PriceEpoch:             pposConfig.Ticket.PriceEpoch,
+			TargetUtilization:      pposConfig.Ticket.TargetUtilization,
+			PriceAdjustDenominator: pposConfig.Ticket.PriceAdjustDenominator,
+			MinTicketPrice:         pposConfig.Ticket.MinTicketPrice,
+			MaxTicketPrice:         pposConfig.Ticket.MaxTicketPrice,
+			TransferCooldown:       pposConfig.Ticket.TransferCooldown,
 		},
 	}
 }
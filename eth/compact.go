@@ -0,0 +1,379 @@
+package eth
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/hashicorp/golang-lru"
+)
+
+const (
+	// maxPendingCompactBlocks is the maximum number of compact blocks waiting for
+	// their missing transactions.
+	maxPendingCompactBlocks = 64
+
+	// compactBlockTimeout is the time a compact block waits for its missing
+	// transactions before it is dropped.
+	compactBlockTimeout = 5 * time.Second
+
+	// maxRecentBlocks is the number of propagated blocks kept to serve the missing
+	// transactions of their compact blocks.
+	maxRecentBlocks = 64
+)
+
+var (
+	errCompactTxIndex    = errors.New("prefilled transaction index out of range")
+	errCompactTxID       = errors.New("prefilled transaction doesn't match its short id")
+	errCompactTxMismatch = errors.New("compact block transactions don't match the header")
+)
+
+// prefilledTx is a transaction sent in full with a compact block.
+type prefilledTx struct {
+	Index uint64
+	Tx    *types.Transaction
+}
+
+// compactBlock is a block whose transactions are replaced by their short ids, the
+// transactions the peer is not known to have are sent in full.
+type compactBlock struct {
+	Header     *types.Header
+	TxIDs      []uint64
+	Prefilled  []prefilledTx
+	Uncles     []*types.Header
+	Signatures []*common.BlockConfirmSign
+	Aggregate  []*types.BlockConfirmAggregate `rlp:"tail"`
+}
+
+// shortTxID returns the short id of a transaction in the compact block of the
+// header hash. As in BIP 152 the ids are salted per block, the SipHash-2-4 of the
+// transaction hash keyed by the header hash, so that transactions colliding in
+// every block can't be crafted.
+func shortTxID(header, hash common.Hash) uint64 {
+	return sipHash24(binary.LittleEndian.Uint64(header[:8]), binary.LittleEndian.Uint64(header[8:16]), hash[:])
+}
+
+// sipHash24 returns the SipHash-2-4 of msg keyed by k0 and k1.
+func sipHash24(k0, k1 uint64, msg []byte) uint64 {
+	v0 := k0 ^ 0x736f6d6570736575
+	v1 := k1 ^ 0x646f72616e646f6d
+	v2 := k0 ^ 0x6c7967656e657261
+	v3 := k1 ^ 0x7465646279746573
+	round := func() {
+		v0 += v1
+		v1 = v1<<13 | v1>>51
+		v1 ^= v0
+		v0 = v0<<32 | v0>>32
+		v2 += v3
+		v3 = v3<<16 | v3>>48
+		v3 ^= v2
+		v0 += v3
+		v3 = v3<<21 | v3>>43
+		v3 ^= v0
+		v2 += v1
+		v1 = v1<<17 | v1>>47
+		v1 ^= v2
+		v2 = v2<<32 | v2>>32
+	}
+	compress := func(m uint64) {
+		v3 ^= m
+		round()
+		round()
+		v0 ^= m
+	}
+	n := len(msg)
+	for ; len(msg) >= 8; msg = msg[8:] {
+		compress(binary.LittleEndian.Uint64(msg))
+	}
+	// the last block holds the remaining bytes and the length of msg
+	var last [8]byte
+	copy(last[:], msg)
+	last[7] = byte(n)
+	compress(binary.LittleEndian.Uint64(last[:]))
+
+	v2 ^= 0xff
+	round()
+	round()
+	round()
+	round()
+	return v0 ^ v1 ^ v2 ^ v3
+}
+
+// newCompactBlock encodes block compactly, the transactions for which known
+// returns false are sent in full.
+func newCompactBlock(block *types.Block, known func(hash common.Hash) bool) *compactBlock {
+	body := block.Body()
+	cb := &compactBlock{
+		Header:     block.Header(),
+		TxIDs:      make([]uint64, len(body.Transactions)),
+		Uncles:     body.Uncles,
+		Signatures: body.Signatures,
+		Aggregate:  body.Aggregate,
+	}
+	salt := block.Hash()
+	for i, tx := range body.Transactions {
+		hash := tx.Hash()
+		cb.TxIDs[i] = shortTxID(salt, hash)
+		if !known(hash) {
+			cb.Prefilled = append(cb.Prefilled, prefilledTx{Index: uint64(i), Tx: tx})
+		}
+	}
+	return cb
+}
+
+// fill returns the transactions of the compact block, the prefilled ones first and
+// then the ones of pool. It returns the indexes of the missing transactions too.
+func (cb *compactBlock) fill(pool txPool) ([]*types.Transaction, []uint64, error) {
+	salt := cb.Header.Hash()
+	txs := make([]*types.Transaction, len(cb.TxIDs))
+	for _, prefilled := range cb.Prefilled {
+		if prefilled.Index >= uint64(len(txs)) || prefilled.Tx == nil {
+			return nil, nil, errCompactTxIndex
+		}
+		if shortTxID(salt, prefilled.Tx.Hash()) != cb.TxIDs[prefilled.Index] {
+			return nil, nil, errCompactTxID
+		}
+		txs[prefilled.Index] = prefilled.Tx
+	}
+	if len(cb.Prefilled) < len(txs) {
+		index := txPoolIndex(pool, salt)
+		for i, id := range cb.TxIDs {
+			if txs[i] == nil {
+				txs[i] = index[id]
+			}
+		}
+	}
+	return txs, cb.missing(txs), nil
+}
+
+// missing returns the indexes of the transactions which are still nil.
+func (cb *compactBlock) missing(txs []*types.Transaction) []uint64 {
+	var missing []uint64
+	for i, tx := range txs {
+		if tx == nil {
+			missing = append(missing, uint64(i))
+		}
+	}
+	return missing
+}
+
+// notPrefilled returns the indexes of the transactions which were not sent in full.
+func (cb *compactBlock) notPrefilled() []uint64 {
+	prefilled := make(map[uint64]bool, len(cb.Prefilled))
+	for _, tx := range cb.Prefilled {
+		prefilled[tx.Index] = true
+	}
+	var indexes []uint64
+	for i := range cb.TxIDs {
+		if !prefilled[uint64(i)] {
+			indexes = append(indexes, uint64(i))
+		}
+	}
+	return indexes
+}
+
+// assemble rebuilds the block from its transactions, which must match the
+// transaction root of the header.
+func (cb *compactBlock) assemble(txs []*types.Transaction) (*types.Block, error) {
+	if types.DeriveSha(types.Transactions(txs)) != cb.Header.TxHash {
+		return nil, errCompactTxMismatch
+	}
+	body := &types.Body{Transactions: txs, Uncles: cb.Uncles, Signatures: cb.Signatures, Aggregate: cb.Aggregate}
+	return types.NewBlockWithHeader(cb.Header).WithFullBody(body), nil
+}
+
+// txPoolIndex indexes the pending transactions of pool by their short ids in the
+// compact block of the header hash salt, the ids shared by several transactions
+// are left out.
+func txPoolIndex(pool txPool, salt common.Hash) map[uint64]*types.Transaction {
+	pending, err := pool.Pending()
+	if err != nil {
+		log.Warn("Failed to index the pending transactions", "err", err)
+		return nil
+	}
+	index := make(map[uint64]*types.Transaction)
+	shared := make(map[uint64]bool)
+	for _, txs := range pending {
+		for _, tx := range txs {
+			id := shortTxID(salt, tx.Hash())
+			if _, ok := index[id]; ok {
+				shared[id] = true
+			}
+			index[id] = tx
+		}
+	}
+	for id := range shared {
+		delete(index, id)
+	}
+	return index
+}
+
+// pendingCompact is a compact block waiting for its missing transactions.
+type pendingCompact struct {
+	block     *compactBlock
+	txs       []*types.Transaction
+	requested []uint64  // Indexes of the requested transactions
+	code      uint64    // Message code of the full block, PrepareBlockMsg or NewBlockMsg
+	hops      uint8     // Hops left to relay a prepare block
+	peer      string    // Peer the transactions are requested from
+	time      time.Time // Time the compact block was received
+	retried   bool      // Whether all the transactions were requested after a mismatch
+}
+
+// compactBlocks tracks the compact blocks waiting for their missing transactions
+// and the blocks propagated compactly.
+type compactBlocks struct {
+	lock    sync.Mutex
+	pending map[common.Hash]*pendingCompact
+	recent  *lru.Cache // Recently propagated blocks, serving their missing transactions
+}
+
+func newCompactBlocks() *compactBlocks {
+	recent, _ := lru.New(maxRecentBlocks)
+	return &compactBlocks{
+		pending: make(map[common.Hash]*pendingCompact),
+		recent:  recent,
+	}
+}
+
+// remember keeps a propagated block to serve the transactions its peers miss.
+func (c *compactBlocks) remember(block *types.Block) {
+	c.recent.Add(block.Hash(), block)
+}
+
+// block returns a recently propagated block.
+func (c *compactBlocks) block(hash common.Hash) *types.Block {
+	if block, ok := c.recent.Get(hash); ok {
+		return block.(*types.Block)
+	}
+	return nil
+}
+
+// add tracks a compact block waiting for its missing transactions, the expired
+// ones are dropped and the oldest one too if too many are waiting.
+func (c *compactBlocks) add(pending *pendingCompact) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var (
+		oldest     common.Hash
+		oldestTime time.Time
+	)
+	for hash, p := range c.pending {
+		if time.Since(p.time) > compactBlockTimeout {
+			delete(c.pending, hash)
+		} else if oldestTime.IsZero() || p.time.Before(oldestTime) {
+			oldest, oldestTime = hash, p.time
+		}
+	}
+	if len(c.pending) >= maxPendingCompactBlocks {
+		delete(c.pending, oldest)
+	}
+	c.pending[pending.block.Header.Hash()] = pending
+}
+
+// take removes the compact block whose transactions were requested from peer.
+func (c *compactBlocks) take(hash common.Hash, peer string) *pendingCompact {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	pending := c.pending[hash]
+	if pending == nil || pending.peer != peer {
+		return nil
+	}
+	delete(c.pending, hash)
+	return pending
+}
+
+// handleCompactBlock rebuilds a compact block received from p, the missing
+// transactions are requested from p. code is the message code of the full block.
+func (pm *ProtocolManager) handleCompactBlock(p *peer, cb *compactBlock, code uint64, hops uint8, receivedAt time.Time) error {
+	txs, missing, err := cb.fill(pm.txpool)
+	if err != nil {
+		return errResp(ErrDecode, "compact block %x: %v", cb.Header.Hash(), err)
+	}
+	retried := false
+	if len(missing) == 0 {
+		block, err := cb.assemble(txs)
+		if err == nil {
+			return pm.deliverCompactBlock(p, block, code, hops, receivedAt)
+		}
+		// A transaction of the pool shares the short id of another one of the
+		// block, request all the transactions which were not sent in full
+		log.Debug("Compact block doesn't match the pool transactions", "hash", cb.Header.Hash(), "err", err)
+		missing, retried = cb.notPrefilled(), true
+	}
+	pm.compact.add(&pendingCompact{
+		block:     cb,
+		txs:       txs,
+		requested: missing,
+		code:      code,
+		hops:      hops,
+		peer:      p.id,
+		time:      receivedAt,
+		retried:   retried,
+	})
+	p.Log().Debug("Requesting the missing transactions of a compact block", "hash", cb.Header.Hash(), "missing", len(missing))
+	return p.RequestBlockTxs(cb.Header.Hash(), missing)
+}
+
+// handleBlockTxs completes the compact block waiting for txs.
+func (pm *ProtocolManager) handleBlockTxs(p *peer, hash common.Hash, txs []*types.Transaction) error {
+	pending := pm.compact.take(hash, p.id)
+	if pending == nil {
+		p.Log().Debug("Unrequested compact block transactions", "hash", hash)
+		return nil
+	}
+	if len(txs) != len(pending.requested) {
+		p.Log().Debug("Compact block transactions don't match the request", "hash", hash, "have", len(txs), "want", len(pending.requested))
+		return nil
+	}
+	for i, index := range pending.requested {
+		pending.txs[index] = txs[i]
+	}
+	block, err := pending.block.assemble(pending.txs)
+	if err != nil {
+		if pending.retried {
+			p.Log().Debug("Failed to rebuild compact block", "hash", hash, "err", err)
+			return nil
+		}
+		// Some transaction of the pool shares its short id with the one of the block
+		pending.requested, pending.retried = pending.block.notPrefilled(), true
+		pm.compact.add(pending)
+		return p.RequestBlockTxs(hash, pending.requested)
+	}
+	return pm.deliverCompactBlock(p, block, pending.code, pending.hops, pending.time)
+}
+
+// deliverCompactBlock handles a rebuilt compact block like its full block.
+func (pm *ProtocolManager) deliverCompactBlock(p *peer, block *types.Block, code uint64, hops uint8, receivedAt time.Time) error {
+	block.ReceivedAt = receivedAt
+	block.ReceivedFrom = p
+	if code == PrepareBlockMsg {
+		return pm.handlePrepareBlock(p, block, hops)
+	}
+	return pm.handleNewBlock(p, block)
+}
+
+// blockTxs returns the transactions of a propagated block at indexes.
+func (pm *ProtocolManager) blockTxs(hash common.Hash, indexes []uint64) ([]*types.Transaction, error) {
+	block := pm.compact.block(hash)
+	if block == nil {
+		block = pm.blockchain.GetBlockByHash(hash)
+	}
+	if block == nil {
+		return nil, nil
+	}
+	txs := make([]*types.Transaction, 0, len(indexes))
+	for _, index := range indexes {
+		if index >= uint64(len(block.Transactions())) {
+			return nil, errCompactTxIndex
+		}
+		txs = append(txs, block.Transactions()[index])
+	}
+	return txs, nil
+}
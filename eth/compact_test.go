@@ -0,0 +1,158 @@
+package eth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/p2p"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+func TestCompactBlockRoundTrip(t *testing.T) {
+	txs := make([]*types.Transaction, 4)
+	for i := range txs {
+		txs[i] = newTestTransaction(testBankKey, uint64(i), 10)
+	}
+	block := testPrepareBlock(txs)
+
+	// the peer is known to have the first two transactions only
+	known := map[common.Hash]bool{txs[0].Hash(): true, txs[1].Hash(): true}
+	cb := newCompactBlock(block, func(hash common.Hash) bool { return known[hash] })
+	if len(cb.TxIDs) != 4 || len(cb.Prefilled) != 2 {
+		t.Fatalf("compact block mismatch: %d ids, %d prefilled", len(cb.TxIDs), len(cb.Prefilled))
+	}
+	enc, err := rlp.EncodeToBytes(cb)
+	if err != nil {
+		t.Fatalf("failed to encode compact block: %v", err)
+	}
+	var dec compactBlock
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("failed to decode compact block: %v", err)
+	}
+
+	// the pool misses the second transaction
+	pool := &testTxPool{pool: []*types.Transaction{txs[0]}}
+	have, missing, err := dec.fill(pool)
+	if err != nil {
+		t.Fatalf("failed to fill compact block: %v", err)
+	}
+	if len(missing) != 1 || missing[0] != 1 {
+		t.Fatalf("missing transactions mismatch: have %v, want [1]", missing)
+	}
+	have[1] = txs[2]
+	if _, err := dec.assemble(have); err != errCompactTxMismatch {
+		t.Fatalf("error mismatch: have %v, want %v", err, errCompactTxMismatch)
+	}
+	have[1] = txs[1]
+	rebuilt, err := dec.assemble(have)
+	if err != nil {
+		t.Fatalf("failed to assemble compact block: %v", err)
+	}
+	if rebuilt.Hash() != block.Hash() || len(rebuilt.Transactions()) != 4 {
+		t.Fatalf("rebuilt block mismatch: have %x, want %x", rebuilt.Hash(), block.Hash())
+	}
+}
+
+func TestSipHash24(t *testing.T) {
+	// the test vectors of the SipHash paper, keyed by 00..0f
+	tests := []struct {
+		len  int
+		want uint64
+	}{
+		{0, 0x726fdb47dd0e0e31},
+		{8, 0x93f5f5799a932462},
+		{15, 0xa129ca6149be45e5},
+	}
+	var msg [15]byte
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+	for _, tt := range tests {
+		if have := sipHash24(0x0706050403020100, 0x0f0e0d0c0b0a0908, msg[:tt.len]); have != tt.want {
+			t.Errorf("length %d: hash mismatch: have %#x, want %#x", tt.len, have, tt.want)
+		}
+	}
+}
+
+func TestShortTxIDSalted(t *testing.T) {
+	hash := common.HexToHash("0x01")
+	if shortTxID(common.Hash{2}, hash) == shortTxID(common.Hash{3}, hash) {
+		t.Fatalf("short id doesn't depend on the block")
+	}
+}
+
+func TestCompactBlockPrefilled(t *testing.T) {
+	txs := []*types.Transaction{newTestTransaction(testBankKey, 0, 10), newTestTransaction(testBankKey, 1, 10)}
+	cb := newCompactBlock(testPrepareBlock(txs), func(common.Hash) bool { return false })
+
+	cb.Prefilled[1].Index = 2
+	if _, _, err := cb.fill(&testTxPool{}); err != errCompactTxIndex {
+		t.Fatalf("error mismatch: have %v, want %v", err, errCompactTxIndex)
+	}
+	cb.Prefilled[1].Index = 0
+	if _, _, err := cb.fill(&testTxPool{}); err != errCompactTxID {
+		t.Fatalf("error mismatch: have %v, want %v", err, errCompactTxID)
+	}
+}
+
+func TestCompactBlocksPending(t *testing.T) {
+	c := newCompactBlocks()
+	cb := newCompactBlock(testPrepareBlock(nil), func(common.Hash) bool { return true })
+	hash := cb.Header.Hash()
+
+	c.add(&pendingCompact{block: cb, peer: "a", time: time.Now()})
+	if c.take(hash, "b") != nil {
+		t.Fatalf("compact block taken by another peer")
+	}
+	if c.take(hash, "a") == nil {
+		t.Fatalf("compact block not taken")
+	}
+	if c.take(hash, "a") != nil {
+		t.Fatalf("compact block taken twice")
+	}
+
+	// the expired compact blocks are dropped
+	c.add(&pendingCompact{block: cb, peer: "a", time: time.Now().Add(-2 * compactBlockTimeout)})
+	other := newCompactBlock(testPrepareBlock([]*types.Transaction{newTestTransaction(testBankKey, 0, 10)}), func(common.Hash) bool { return true })
+	c.add(&pendingCompact{block: other, peer: "a", time: time.Now()})
+	if c.take(hash, "a") != nil {
+		t.Fatalf("expired compact block not dropped")
+	}
+}
+
+func TestSendCompactPrepareBlock(t *testing.T) {
+	txs := []*types.Transaction{newTestTransaction(testBankKey, 0, 10)}
+	block := testPrepareBlock(txs)
+
+	for _, version := range []uint{eth63, eth64} {
+		app, net := p2p.MsgPipe()
+		p := newPeer(int(version), p2p.NewPeer(discover.NodeID{}, "peer", nil), app)
+		go p.SendPrepareBlock(block, 2)
+
+		msg, err := net.ReadMsg()
+		if err != nil {
+			t.Fatalf("eth/%d: failed to read message: %v", version, err)
+		}
+		want := uint64(PrepareBlockMsg)
+		if version >= eth64 {
+			want = CompactPrepareBlockMsg
+		}
+		if msg.Code != want {
+			t.Errorf("eth/%d: message code mismatch: have %d, want %d", version, msg.Code, want)
+		}
+		if version < eth64 {
+			// The peers below eth/64 only know the block
+			var request legacyPrepareBlockData
+			if err := msg.Decode(&request); err != nil {
+				t.Errorf("eth/%d: failed to decode legacy prepare block: %v", version, err)
+			} else if request.Block.Hash() != block.Hash() {
+				t.Errorf("eth/%d: block mismatch: have %x, want %x", version, request.Block.Hash(), block.Hash())
+			}
+		}
+		msg.Discard()
+		app.Close()
+	}
+}
@@ -56,6 +56,8 @@ var DefaultConfig = Config{
 				MaxChair:          	10,
 				MaxCount:          	100,
 				RefundBlockNumber: 	512,
+				FeeChangeDelay: 	512,
+				JailPeriod: 		512,
 			},
 			Ticket: &TicketConfig{
 				TicketPrice: 		"100000000000000000000",
@@ -74,6 +76,8 @@ var DefaultConfig = Config{
 	MinerGasPrice: big.NewInt(params.GWei),
 	MinerRecommit: 3 * time.Second,
 
+	MinerPposGasShare: 10,
+
 	TxPool: core.DefaultTxPoolConfig,
 	GPO: gasprice.Config{
 		Blocks:     20,
@@ -133,6 +137,9 @@ type Config struct {
 	MinerRecommit  time.Duration
 	MinerNoverify  bool
 
+	// Percentage of the block gas reserved for the PPOS transactions
+	MinerPposGasShare uint64
+
 	// Transaction pool options
 	TxPool core.TxPoolConfig
 
@@ -185,6 +192,16 @@ type CandidateConfig struct {
 	MaxChair				uint32					`json:"maxChair"`
 	// allow block interval for refunds
 	RefundBlockNumber 		uint32 					`json:"refundBlockNumber"`
+	// block interval before a fee change takes effect
+	FeeChangeDelay 			uint32 					`json:"feeChangeDelay"`
+	// missed block slots in an election round before a witness is jailed, zero disables jailing
+	LivenessThreshold		uint32					`json:"livenessThreshold"`
+	// part of the deposit forfeited to the reward pool when a witness is jailed
+	LivenessPenalty			string					`json:"livenessPenalty"`
+	// blocks a jailed witness stays jailed before it can be unjailed
+	JailPeriod				uint32					`json:"jailPeriod"`
+	// sample the witnesses weighted by stake instead of electing the top maxChair
+	RandomElection			bool					`json:"randomElection"`
 
 }
 type TicketConfig struct {
@@ -193,6 +210,17 @@ type TicketConfig struct {
 	MaxCount				uint32					`json:"maxCount"`
 	// Reach expired quantity
 	ExpireBlockNumber		uint32					`json:"expireBlockNumber"`
+	// block interval between ticket price adjustments, zero keeps the price fixed
+	PriceEpoch				uint32					`json:"priceEpoch"`
+	// percentage of sold tickets the price adjustments steer to
+	TargetUtilization		uint32					`json:"targetUtilization"`
+	// the price changes at most by 1/PriceAdjustDenominator per epoch
+	PriceAdjustDenominator	uint32					`json:"priceAdjustDenominator"`
+	// bounds of the adjusted price
+	MinTicketPrice 			string 					`json:"minTicketPrice"`
+	MaxTicketPrice 			string 					`json:"maxTicketPrice"`
+	// blocks before an election in which tickets can't be transferred or re-delegated
+	TransferCooldown		uint32					`json:"transferCooldown"`
 }
 
 type configMarshaling struct {
@@ -126,6 +126,7 @@ func (p *FakePeer) RequestBodies(hashes []common.Hash) error {
 		txs    [][]*types.Transaction
 		uncles [][]*types.Header
 		signatures [][]*common.BlockConfirmSign
+		aggregates []*types.BlockConfirmAggregate
 	)
 	for _, hash := range hashes {
 		block := rawdb.ReadBlock(p.db, hash, *p.hc.GetBlockNumber(hash))
@@ -133,8 +134,9 @@ func (p *FakePeer) RequestBodies(hashes []common.Hash) error {
 		txs = append(txs, block.Transactions())
 		uncles = append(uncles, block.Uncles())
 		signatures = append(signatures, block.Signatures())
+		aggregates = append(aggregates, block.ConfirmAggregate)
 	}
-	p.dl.DeliverBodies(p.id, txs, uncles, signatures)
+	p.dl.DeliverBodies(p.id, txs, uncles, signatures, aggregates)
 	return nil
 }
 
@@ -61,6 +61,7 @@ type fetchResult struct {
 	Transactions types.Transactions
 	Receipts     types.Receipts
 	Signatures   []*common.BlockConfirmSign
+	Aggregate    *types.BlockConfirmAggregate
 }
 
 // queue represents hashes that are either need fetching or are being fetched
@@ -767,7 +768,7 @@ func (q *queue) DeliverHeaders(id string, headers []*types.Header, headerProcCh
 // DeliverBodies injects a block body retrieval response into the results queue.
 // The method returns the number of blocks bodies accepted from the delivery and
 // also wakes any threads waiting for data delivery.
-func (q *queue) DeliverBodies(id string, txLists [][]*types.Transaction, uncleLists [][]*types.Header, signatureLists [][]*common.BlockConfirmSign) (int, error) {
+func (q *queue) DeliverBodies(id string, txLists [][]*types.Transaction, uncleLists [][]*types.Header, signatureLists [][]*common.BlockConfirmSign, aggregates []*types.BlockConfirmAggregate) (int, error) {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -778,6 +779,9 @@ func (q *queue) DeliverBodies(id string, txLists [][]*types.Transaction, uncleLi
 		result.Transactions = txLists[index]
 		result.Uncles = uncleLists[index]
 		result.Signatures = signatureLists[index]
+		if index < len(aggregates) {
+			result.Aggregate = aggregates[index]
+		}
 		return nil
 	}
 	return q.deliver(id, q.blockTaskPool, q.blockTaskQueue, q.blockPendPool, q.blockDonePool, bodyReqTimer, len(txLists), reconstruct)
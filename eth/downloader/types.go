@@ -48,6 +48,7 @@ type bodyPack struct {
 	transactions [][]*types.Transaction
 	uncles       [][]*types.Header
 	signatures 	 [][]*common.BlockConfirmSign
+	aggregates   []*types.BlockConfirmAggregate
 }
 
 func (p *bodyPack) PeerId() string { return p.peerID }
@@ -95,6 +95,7 @@ type bodyFilterTask struct {
 	transactions [][]*types.Transaction // Collection of transactions per block bodies
 	uncles       [][]*types.Header      // Collection of uncles per block bodies
 	signatures	 [][]*common.BlockConfirmSign
+	aggregates   []*types.BlockConfirmAggregate // Aggregated confirmation per block bodies
 	time         time.Time              // Arrival time of the blocks' contents
 }
 
@@ -254,7 +255,7 @@ func (f *Fetcher) FilterHeaders(peer string, headers []*types.Header, time time.
 
 // FilterBodies extracts all the block bodies that were explicitly requested by
 // the fetcher, returning those that should be handled differently.
-func (f *Fetcher) FilterBodies(peer string, transactions [][]*types.Transaction, uncles [][]*types.Header, signatures [][]*common.BlockConfirmSign, time time.Time) ([][]*types.Transaction, [][]*types.Header, [][]*common.BlockConfirmSign) {
+func (f *Fetcher) FilterBodies(peer string, transactions [][]*types.Transaction, uncles [][]*types.Header, signatures [][]*common.BlockConfirmSign, aggregates []*types.BlockConfirmAggregate, time time.Time) ([][]*types.Transaction, [][]*types.Header, [][]*common.BlockConfirmSign, []*types.BlockConfirmAggregate) {
 	log.Trace("Filtering bodies", "peer", peer, "txs", len(transactions), "uncles", len(uncles), "signatures", len(signatures))
 
 	// Send the filter channel to the fetcher
@@ -263,20 +264,20 @@ func (f *Fetcher) FilterBodies(peer string, transactions [][]*types.Transaction,
 	select {
 	case f.bodyFilter <- filter:
 	case <-f.quit:
-		return nil, nil, nil
+		return nil, nil, nil, nil
 	}
 	// Request the filtering of the body list
 	select {
-	case filter <- &bodyFilterTask{peer: peer, transactions: transactions, uncles: uncles, signatures: signatures, time: time}:
+	case filter <- &bodyFilterTask{peer: peer, transactions: transactions, uncles: uncles, signatures: signatures, aggregates: aggregates, time: time}:
 	case <-f.quit:
-		return nil, nil, nil
+		return nil, nil, nil, nil
 	}
 	// Retrieve the bodies remaining after filtering
 	select {
 	case task := <-filter:
-		return task.transactions, task.uncles, task.signatures
+		return task.transactions, task.uncles, task.signatures, task.aggregates
 	case <-f.quit:
-		return nil, nil, nil
+		return nil, nil, nil, nil
 	}
 }
 
@@ -523,7 +524,7 @@ func (f *Fetcher) loop() {
 			bodyFilterInMeter.Mark(int64(len(task.transactions)))
 
 			blocks := []*types.Block{}
-			for i := 0; i < len(task.transactions) && i < len(task.uncles) && i < len(task.signatures); i++ {
+			for i := 0; i < len(task.transactions) && i < len(task.uncles) && i < len(task.signatures) && i < len(task.aggregates); i++ {
 				// Match up a body to any possible completion request
 				matched := false
 
@@ -538,6 +539,7 @@ func (f *Fetcher) loop() {
 
 							if f.getBlock(hash) == nil {
 								block := types.NewBlockWithHeader(announce.header).WithBody(task.transactions[i], task.uncles[i], task.signatures[i])
+								block.ConfirmAggregate = task.aggregates[i]
 								block.ReceivedAt = task.time
 
 								blocks = append(blocks, block)
@@ -550,6 +552,8 @@ func (f *Fetcher) loop() {
 				if matched {
 					task.transactions = append(task.transactions[:i], task.transactions[i+1:]...)
 					task.uncles = append(task.uncles[:i], task.uncles[i+1:]...)
+					task.signatures = append(task.signatures[:i], task.signatures[i+1:]...)
+					task.aggregates = append(task.aggregates[:i], task.aggregates[i+1:]...)
 					i--
 					continue
 				}
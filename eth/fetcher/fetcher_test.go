@@ -19,7 +19,6 @@ package fetcher
 import (
 	"errors"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft"
-	"github.com/PlatONnetwork/PlatON-Go/core/ppos_storage"
 	"math/big"
 	"sync"
 	"sync/atomic"
@@ -47,8 +46,6 @@ var (
 // contains a transaction and every 5th an uncle to allow testing correct block
 // reassembly.
 func makeChain(n int, seed byte, parent *types.Block) ([]common.Hash, map[common.Hash]*types.Block) {
-	db := ethdb.NewMemDatabase()
-	ppos_storage.NewPPosTemp(db)
 	blocks, _ := core.GenerateChain(params.TestChainConfig, parent, cbft.New(params.GrapeChainConfig.Cbft, nil, nil, nil), testdb, n, func(i int, block *core.BlockGen) {
 		block.SetCoinbase(common.Address{seed})
 
@@ -190,16 +187,18 @@ func (f *fetcherTester) makeBodyFetcher(peer string, blocks map[common.Hash]*typ
 		uncles := make([][]*types.Header, 0, len(hashes))
 
 		signs := make([][]*common.BlockConfirmSign, 0, len(hashes))
+		aggregates := make([]*types.BlockConfirmAggregate, 0, len(hashes))
 
 		for _, hash := range hashes {
 			if block, ok := closure[hash]; ok {
 				transactions = append(transactions, block.Transactions())
 				uncles = append(uncles, block.Uncles())
 				signs = append(signs, block.Signatures())
+				aggregates = append(aggregates, block.ConfirmAggregate)
 			}
 		}
 		// Return on a new thread
-		go f.fetcher.FilterBodies(peer, transactions, uncles, signs, time.Now().Add(drift))
+		go f.fetcher.FilterBodies(peer, transactions, uncles, signs, aggregates, time.Now().Add(drift))
 
 		return nil
 	}
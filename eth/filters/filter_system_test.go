@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft"
-	"github.com/PlatONnetwork/PlatON-Go/core/ppos_storage"
 	"math/big"
 	"math/rand"
 	"reflect"
@@ -160,7 +159,6 @@ func (b *testBackend) ServiceFilter(ctx context.Context, session *bloombits.Matc
 func TestBlockSubscription(t *testing.T) {
 	t.Parallel()
 	db := ethdb.NewMemDatabase()
-	ppos_storage.NewPPosTemp(db)
 	var (
 		mux         = new(event.TypeMux)
 		txFeed      = new(event.Feed)
@@ -19,7 +19,6 @@ package filters
 import (
 	"context"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft"
-	"github.com/PlatONnetwork/PlatON-Go/core/ppos_storage"
 	"io/ioutil"
 	"math/big"
 	"os"
@@ -128,7 +127,6 @@ func TestFilters(t *testing.T) {
 	)
 	defer db.Close()
 
-	ppos_storage.NewPPosTemp(db)
 	genesis := core.GenesisBlockForTesting(db, addr, big.NewInt(1000000))
 	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, cbft.New(params.GrapeChainConfig.Cbft, nil, nil, nil) , db, 1000, func(i int, gen *core.BlockGen) {
 		switch i {
@@ -21,7 +21,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/PlatONnetwork/PlatON-Go/core/ppos_storage"
 	"math"
 	"math/big"
 	"math/rand"
@@ -95,6 +94,9 @@ type ProtocolManager struct {
 
 	prepareMinedBlockSub *event.TypeMuxSubscription
 	blockSignatureSub    *event.TypeMuxSubscription
+	relay                *consensusRelay // Deduplication of the relayed consensus messages
+	compact              *compactBlocks  // Compact blocks waiting for their missing transactions
+	scores               *peerScores     // Consensus scores of the peers
 
 	// channels for fetcher, syncer, txsyncLoop
 	newPeerCh   chan *peer
@@ -125,6 +127,9 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		txsyncCh:    make(chan *txsync),
 		quitSync:    make(chan struct{}),
 		engine:      engine,
+		relay:       newConsensusRelay(),
+		compact:     newCompactBlocks(),
+		scores:      newPeerScores(),
 	}
 	// Temporarily remove by niuxiaojie
 	// Assume that the test network is not under attack
@@ -267,7 +272,9 @@ func (pm *ProtocolManager) Stop() {
 }
 
 func (pm *ProtocolManager) newPeer(pv int, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
-	return newPeer(pv, p, newMeteredMsgWriter(rw))
+	peer := newPeer(pv, p, newMeteredMsgWriter(rw))
+	peer.score = pm.scores.get(peer.id)
+	return peer
 }
 
 // handle is the callback invoked to manage the life cycle of an eth peer. When
@@ -277,6 +284,10 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	if pm.peers.Len() >= pm.maxPeers && !p.Peer.Info().Network.Trusted && !p.Peer.Info().Network.Consensus {
 		return p2p.DiscTooManyPeers
 	}
+	if p.score.banned(time.Now()) {
+		p.Log().Debug("Banned Ethereum peer refused", "score", p.score.Info().Score)
+		return p2p.DiscUselessPeer
+	}
 	p.Log().Debug("Ethereum peer connected", "name", p.Name())
 
 	// Execute the Ethereum handshake
@@ -363,7 +374,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 	case p.version >= eth63 && msg.Code == GetPposStorageMsg:
 		// deal the retrieval message
 		p.Log().Debug("Received a broadcast message[GetPposStorageMsg]")
-		if pivotHash, data, err := ppos_storage.GetPPosTempPtr().GetPPosStorageProto(); err == nil {
+		if pivotHash, data, err := pm.blockchain.PPosTemp().GetPPosStorageProto(); err == nil {
 			latest := pm.blockchain.CurrentHeader()
 			var pivot *types.Header
 			if pivotHash != (common.Hash{}) {
@@ -571,19 +582,23 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		transactions := make([][]*types.Transaction, len(request))
 		uncles := make([][]*types.Header, len(request))
 		signatures := make([][]*common.BlockConfirmSign, len(request))
+		aggregates := make([]*types.BlockConfirmAggregate, len(request))
 
 		for i, body := range request {
 			transactions[i] = body.Transactions
 			uncles[i] = body.Uncles
 			signatures[i] = body.Signatures
+			if len(body.Aggregate) > 0 {
+				aggregates[i] = body.Aggregate[0]
+			}
 		}
 		// Filter out any explicitly requested bodies, deliver the rest to the downloader
 		filter := len(transactions) > 0 || len(uncles) > 0 || len(signatures) > 0
 		if filter {
-			transactions, uncles, signatures = pm.fetcher.FilterBodies(p.id, transactions, uncles, signatures, time.Now())
+			transactions, uncles, signatures, aggregates = pm.fetcher.FilterBodies(p.id, transactions, uncles, signatures, aggregates, time.Now())
 		}
 		if len(transactions) > 0 || len(uncles) > 0 || len(signatures) > 0 || !filter {
-			err := pm.downloader.DeliverBodies(p.id, transactions, uncles, signatures)
+			err := pm.downloader.DeliverBodies(p.id, transactions, uncles, signatures, aggregates)
 			if err != nil {
 				log.Debug("Failed to deliver bodies", "err", err)
 			}
@@ -707,29 +722,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 		log.Debug("Received a message[NewBlockMsg]", "GoRoutineID", common.CurrentGoRoutineID(), "receiveAt", request.Block.ReceivedAt.Unix(), "peerId", p.id, "hash", request.Block.Hash(), "number", request.Block.NumberU64())
 
-		// Mark the peer as owning the block and schedule it for import
-		p.MarkBlock(request.Block.Hash())
-		pm.fetcher.Enqueue(p.id, request.Block)
-
-		// Assuming the block is importable by the peer, but possibly not yet done so,
-		// calculate the head hash and block number that the peer truly must have.
-		var (
-			trueHead = request.Block.ParentHash()
-			trueBn   = new(big.Int).Sub(request.Block.Number(), big.NewInt(1))
-		)
-		// Update the peers block number if better than the previous
-
-		if _, bn := p.Head(); trueBn.Cmp(bn) > 0 {
-			p.SetHead(trueHead, trueBn)
-
-			// Schedule a sync if above ours. Note, this will not fire a sync for a gap of
-			// a singe block (as the true TD is below the propagated block), however this
-			// scenario should easily be covered by the fetcher.
-			currentBlock := pm.blockchain.CurrentBlock()
-			if trueBn.Cmp(currentBlock.Number()) > 0 {
-				go pm.synchronise(p)
-			}
-		}
+		return pm.handleNewBlock(p, request.Block)
 
 	case msg.Code == TxMsg:
 		log.Debug("Received a broadcast message[TxMsg]", "acceptTxs", pm.acceptTxs)
@@ -755,53 +748,102 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 	case msg.Code == PrepareBlockMsg:
 		// Retrieve and decode the propagated block
 		var request prepareBlockData
-		if err := msg.Decode(&request); err != nil {
+		if p.version < eth64 {
+			var legacy legacyPrepareBlockData
+			if err := msg.Decode(&legacy); err != nil {
+				return errResp(ErrDecode, "%v: %v", msg, err)
+			}
+			request = prepareBlockData{Block: legacy.Block}
+		} else if err := msg.Decode(&request); err != nil {
 			return errResp(ErrDecode, "%v: %v", msg, err)
 		}
-		log.Debug("Received a broadcast message[PrepareBlockMsg]", "GoRoutineID", common.CurrentGoRoutineID(), "peerId", p.id, "hash", request.Block.Hash(), "number", request.Block.NumberU64())
+		log.Debug("Received a broadcast message[PrepareBlockMsg]", "GoRoutineID", common.CurrentGoRoutineID(), "peerId", p.id, "hash", request.Block.Hash(), "number", request.Block.NumberU64(), "hops", request.Hops)
+		if ok, err := pm.admitConsensusMsg(p, consensusMsgKey{code: PrepareBlockMsg, hash: request.Block.Hash()}, request.Block.NumberU64()); !ok {
+			return err
+		}
 
 		request.Block.ReceivedAt = msg.ReceivedAt
 		request.Block.ReceivedFrom = p
+		return pm.handlePrepareBlock(p, request.Block, request.Hops)
 
-		// Preliminary check block
-		if err := pm.engine.VerifyHeader(pm.blockchain, request.Block.Header(), true); err != nil {
-			log.Error("Failed to VerifyHeader in PrepareBlockMsg,discard this msg", "err", err)
-			return nil
+	case p.version >= eth64 && msg.Code == CompactPrepareBlockMsg:
+		var request compactPrepareBlockData
+		if err := msg.Decode(&request); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		hash := request.Block.Header.Hash()
+		log.Debug("Received a broadcast message[CompactPrepareBlockMsg]", "peerId", p.id, "hash", hash, "number", request.Block.Header.Number, "txs", len(request.Block.TxIDs), "prefilled", len(request.Block.Prefilled), "hops", request.Hops)
+		if ok, err := pm.admitConsensusMsg(p, consensusMsgKey{code: PrepareBlockMsg, hash: hash}, request.Block.Header.Number.Uint64()); !ok {
+			return err
 		}
-		if pm.blockchain.HasBlock(request.Block.Hash(), request.Block.NumberU64()) {
-			log.Warn("Block already in blockchain,discard this msg", "err", err)
+		if pm.relay.seen(consensusMsgKey{code: PrepareBlockMsg, hash: hash}) {
+			log.Trace("Prepare block already handled,discard this msg", "hash", hash)
 			return nil
 		}
-		if cbftEngine, ok := pm.engine.(consensus.Bft); ok {
-			//if pm.downloader.IsRunning() {
-			//	log.Warn("downloader is running,discard this msg")
-			//}
-			/*
-			if flag, err := cbftEngine.IsConsensusNode(); !flag || err != nil {
-				log.Warn("local node is not consensus node,discard this msg")
-			} else if flag, err := cbftEngine.CheckConsensusNode(p.Peer.ID()); !flag || err != nil {
-				log.Warn("remote node is not consensus node,discard this msg")
-			} else if err := cbftEngine.OnNewBlock(pm.blockchain, request.Block); err != nil {
-				log.Error("deliver prepareBlockMsg data to cbft engine failed", "err", err)
-			}
-			*/
-			if err := cbftEngine.OnNewBlock(pm.blockchain, request.Block); err != nil {
-				log.Error("deliver prepareBlockMsg data to cbft engine failed", "err", err)
+		return pm.handleCompactBlock(p, request.Block, PrepareBlockMsg, request.Hops, msg.ReceivedAt)
+
+	case p.version >= eth64 && msg.Code == CompactNewBlockMsg:
+		var request compactNewBlockData
+		if err := msg.Decode(&request); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		log.Debug("Received a message[CompactNewBlockMsg]", "peerId", p.id, "hash", request.Block.Header.Hash(), "number", request.Block.Header.Number, "txs", len(request.Block.TxIDs), "prefilled", len(request.Block.Prefilled))
+		return pm.handleCompactBlock(p, request.Block, NewBlockMsg, 0, msg.ReceivedAt)
+
+	case p.version >= eth64 && msg.Code == GetBlockTxsMsg:
+		var request getBlockTxsData
+		if err := msg.Decode(&request); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		txs, err := pm.blockTxs(request.Hash, request.Indexes)
+		if err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		return p.SendBlockTxs(request.Hash, txs)
+
+	case p.version >= eth64 && msg.Code == BlockTxsMsg:
+		var request blockTxsData
+		if err := msg.Decode(&request); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		for i, tx := range request.Txs {
+			if tx == nil {
+				return errResp(ErrDecode, "transaction %d is nil", i)
 			}
-			return nil
-		} else {
-			log.Warn("Consensus engine is not cbft", "GoRoutineID", common.CurrentGoRoutineID(), "peerId", p.id, "hash", request.Block.Hash(), "number", request.Block.NumberU64())
+			p.MarkTransaction(tx.Hash())
 		}
+		return pm.handleBlockTxs(p, request.Hash, request.Txs)
 
 	case msg.Code == BlockSignatureMsg:
-		// Retrieve and decode the propagated block
+		// Retrieve and decode the propagated block, the legacy signatures aren't relayed
 		var request blockSignature
-		if err := msg.Decode(&request); err != nil {
+		if p.version < eth64 {
+			var legacy legacyBlockSignature
+			if err := msg.Decode(&legacy); err != nil {
+				return errResp(ErrDecode, "%v: %v", msg, err)
+			}
+			request = blockSignature{SignHash: legacy.SignHash, Hash: legacy.Hash, Number: legacy.Number, Signature: legacy.Signature}
+		} else if err := msg.Decode(&request); err != nil {
 			return errResp(ErrDecode, "%v: %v", msg, err)
 		}
 
-		log.Debug("Received a broadcast message[BlockSignatureMsg]", "GoRoutineID", common.CurrentGoRoutineID(), "peerId", p.id, "SignHash", request.SignHash, "Hash", request.Hash, "Number", request.Number, "Signature", request.Signature.String())
-		engineBlockSignature := &cbfttypes.BlockSignature{SignHash: request.SignHash, Hash: request.Hash, Number: request.Number, Signature: request.Signature}
+		log.Debug("Received a broadcast message[BlockSignatureMsg]", "GoRoutineID", common.CurrentGoRoutineID(), "peerId", p.id, "SignHash", request.SignHash, "Hash", request.Hash, "Number", request.Number, "Signature", request.Signature.String(), "hops", request.Hops)
+		engineBlockSignature := &cbfttypes.BlockSignature{SignHash: request.SignHash, Hash: request.Hash, Number: request.Number, Signature: request.Signature, ParentHash: request.ParentHash, BlsSignature: request.BlsSignature}
+
+		// The signature may be relayed, it is delivered on behalf of its signer
+		signer, err := consensusSigner(request.SignHash, request.Signature)
+		if err != nil {
+			log.Error("Failed to recover the signer of BlockSignatureMsg,discard this msg", "hash", request.Hash, "err", err)
+			return p.score.invalidSignature()
+		}
+		key := consensusMsgKey{code: BlockSignatureMsg, hash: request.Hash, signer: signer}
+		if ok, err := pm.admitConsensusMsg(p, key, request.Number.Uint64()); !ok {
+			return err
+		}
+		if pm.relay.seen(key) {
+			log.Trace("Block signature already handled,discard this msg", "hash", request.Hash, "signer", signer.TerminalString())
+			return nil
+		}
 
 		if cbftEngine, ok := pm.engine.(consensus.Bft); ok {
 			//if pm.downloader.IsRunning() {
@@ -816,7 +858,26 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				log.Error("deliver blockSignatureMsg data to cbft engine failed", "blockHash", request.Hash, "err", err)
 			}
 			*/
-			if err := cbftEngine.OnBlockSignature(pm.blockchain, p.Peer.ID(), engineBlockSignature); err != nil {
+			// Only the signatures of the consensus nodes are relayed, a signature whose
+			// consensus nodes are unknown yet is accepted from its signer only
+			parentNumber := new(big.Int).Sub(request.Number, common.Big1)
+			consensusNodes := cbftEngine.ConsensusNodes(parentNumber, request.ParentHash, request.Number)
+			fromConsensus := isConsensusNode(consensusNodes, signer)
+			if !fromConsensus && signer != p.Peer.ID() {
+				log.Warn("Relayed signature is not signed by a consensus node,discard this msg", "hash", request.Hash, "signer", signer.TerminalString())
+				if len(consensusNodes) == 0 {
+					// The consensus nodes are unknown yet, the peer may know them
+					return nil
+				}
+				return p.score.invalidSignature()
+			}
+			if !pm.relay.mark(key) {
+				return nil
+			}
+			if fromConsensus {
+				pm.relaySignature(engineBlockSignature, request.Hops, p)
+			}
+			if err := cbftEngine.OnBlockSignature(pm.blockchain, signer, engineBlockSignature); err != nil {
 				log.Error("deliver blockSignatureMsg data to cbft engine failed", "blockHash", request.Hash, "err", err)
 			}
 			return nil
@@ -882,6 +943,7 @@ func (pm *ProtocolManager) BroadcastBlock(block *types.Block, propagate bool) {
 			log.Error("Propagating dangling block", "number", block.Number(), "hash", hash)
 			return
 		}
+		pm.compact.remember(block)
 		// Send the block to a subset of our peers
 		transfer := peers[:int(math.Sqrt(float64(len(peers))))]
 		for _, peer := range transfer {
@@ -899,24 +961,131 @@ func (pm *ProtocolManager) BroadcastBlock(block *types.Block, propagate bool) {
 	}
 }
 
+// handleNewBlock schedules the import of a block propagated by p.
+func (pm *ProtocolManager) handleNewBlock(p *peer, block *types.Block) error {
+	// Mark the peer as owning the block and schedule it for import
+	p.MarkBlock(block.Hash())
+	pm.fetcher.Enqueue(p.id, block)
+
+	// Assuming the block is importable by the peer, but possibly not yet done so,
+	// calculate the head hash and block number that the peer truly must have.
+	var (
+		trueHead = block.ParentHash()
+		trueBn   = new(big.Int).Sub(block.Number(), big.NewInt(1))
+	)
+	// Update the peers block number if better than the previous
+
+	if _, bn := p.Head(); trueBn.Cmp(bn) > 0 {
+		p.SetHead(trueHead, trueBn)
+
+		// Schedule a sync if above ours. Note, this will not fire a sync for a gap of
+		// a singe block (as the true TD is below the propagated block), however this
+		// scenario should easily be covered by the fetcher.
+		currentBlock := pm.blockchain.CurrentBlock()
+		if trueBn.Cmp(currentBlock.Number()) > 0 {
+			go pm.synchronise(p)
+		}
+	}
+	return nil
+}
+
+// handlePrepareBlock delivers a prepare block received from p to the consensus
+// engine and relays it, hops is the number of hops left to relay it.
+func (pm *ProtocolManager) handlePrepareBlock(p *peer, block *types.Block, hops uint8) error {
+	key := consensusMsgKey{code: PrepareBlockMsg, hash: block.Hash()}
+	if pm.relay.seen(key) {
+		log.Trace("Prepare block already handled,discard this msg", "hash", block.Hash())
+		return nil
+	}
+
+	// Preliminary check block
+	if err := pm.engine.VerifyHeader(pm.blockchain, block.Header(), true); err != nil {
+		log.Error("Failed to VerifyHeader in PrepareBlockMsg,discard this msg", "err", err)
+		return p.score.invalidSignature()
+	}
+	if ok, err := pm.checkBlockProducer(p, block); !ok {
+		return err
+	}
+	if pm.blockchain.HasBlock(block.Hash(), block.NumberU64()) {
+		log.Warn("Block already in blockchain,discard this msg", "hash", block.Hash())
+		return nil
+	}
+	if err := verifyPrepareBody(block); err != nil {
+		return errResp(ErrDecode, "prepare block %x: %v", block.Hash(), err)
+	}
+	if !pm.relay.mark(key) {
+		return nil
+	}
+	pm.relayPrepareBlock(block, hops, p)
+	if cbftEngine, ok := pm.engine.(consensus.Bft); ok {
+		//if pm.downloader.IsRunning() {
+		//	log.Warn("downloader is running,discard this msg")
+		//}
+		/*
+		if flag, err := cbftEngine.IsConsensusNode(); !flag || err != nil {
+			log.Warn("local node is not consensus node,discard this msg")
+		} else if flag, err := cbftEngine.CheckConsensusNode(p.Peer.ID()); !flag || err != nil {
+			log.Warn("remote node is not consensus node,discard this msg")
+		} else if err := cbftEngine.OnNewBlock(pm.blockchain, block); err != nil {
+			log.Error("deliver prepareBlockMsg data to cbft engine failed", "err", err)
+		}
+		*/
+		if err := cbftEngine.OnNewBlock(pm.blockchain, block); err != nil {
+			log.Error("deliver prepareBlockMsg data to cbft engine failed", "err", err)
+		}
+		return nil
+	} else {
+		log.Warn("Consensus engine is not cbft", "GoRoutineID", common.CurrentGoRoutineID(), "peerId", p.id, "hash", block.Hash(), "number", block.NumberU64())
+	}
+	return nil
+}
+
+// verifyPrepareBody checks that the body of a prepare block matches its header,
+// the hash of a block doesn't cover its body so a relayed body could be altered.
+// A prepare block isn't confirmed yet, it carries no confirmations.
+func verifyPrepareBody(block *types.Block) error {
+	if hash := types.DeriveSha(block.Transactions()); hash != block.TxHash() {
+		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, block.TxHash())
+	}
+	if hash := types.CalcUncleHash(block.Uncles()); hash != block.UncleHash() {
+		return fmt.Errorf("uncle root hash mismatch: have %x, want %x", hash, block.UncleHash())
+	}
+	if len(block.ConfirmSigns) != 0 || block.ConfirmAggregate != nil {
+		return errors.New("confirmations in a prepare block")
+	}
+	return nil
+}
+
 func (pm *ProtocolManager) MulticastConsensus(a interface{}, consensusNodes []discover.NodeID) {
 	// Consensus node peer
 	peers := pm.peers.PeersWithConsensus(consensusNodes)
-	if peers == nil || len(peers) <= 0 {
+	hops := uint8(1)
+	if len(peers) < pm.remoteConsensusNodes(consensusNodes) {
+		// Some consensus nodes are not connected directly, gossip the message to
+		// all peers and let them relay it
+		log.Debug("consensus peers are incomplete, gossip the message", "consensusPeers", len(peers), "consensusNodes", len(consensusNodes))
+		peers, hops = pm.peers.PeersWithout(""), maxConsensusHops
+	}
+	if len(peers) <= 0 {
 		log.Warn("consensus peers is empty")
 	}
 
 	if block, ok := a.(*types.Block); ok {
+		pm.relay.mark(consensusMsgKey{code: PrepareBlockMsg, hash: block.Hash()})
+		pm.compact.remember(block)
 		for _, peer := range peers {
 			log.Debug("Send a broadcast message[PrepareBlockMsg]",
-				"peerId", peer.id, "Hash", block.Hash(), "Number", block.Number())
-			peer.AsyncSendPrepareBlock(block)
+				"peerId", peer.id, "Hash", block.Hash(), "Number", block.Number(), "hops", hops)
+			peer.AsyncSendPrepareBlock(block, hops)
 		}
 	} else if signature, ok := a.(*cbfttypes.BlockSignature); ok {
+		if signer, err := consensusSigner(signature.SignHash, signature.Signature); err == nil {
+			pm.relay.mark(consensusMsgKey{code: BlockSignatureMsg, hash: signature.Hash, signer: signer})
+		}
 		for _, peer := range peers {
 			log.Debug("Send a broadcast message[BlockSignatureMsg]",
-				"peerId", peer.id, "SignHash", signature.SignHash, "Hash", signature.Hash, "Number", signature.Number, "SignHash", signature.SignHash)
-			peer.AsyncSendSignature(signature)
+				"peerId", peer.id, "SignHash", signature.SignHash, "Hash", signature.Hash, "Number", signature.Number, "SignHash", signature.SignHash, "hops", hops)
+			peer.AsyncSendSignature(signature, hops)
 		}
 	}
 }
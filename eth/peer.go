@@ -70,6 +70,7 @@ type PeerInfo struct {
 	Version    int      `json:"version"`    // Ethereum protocol version negotiated
 	BN         *big.Int `json:"number"`     // The block number of the peer's blockchain
 	Head       string   `json:"head"`       // SHA3 hash of the peer's best owned block
+	Score      *PeerScoreInfo `json:"score"` // Consensus score of the peer
 }
 
 // propEvent is a block propagation, waiting for its turn in the broadcast queue.
@@ -93,6 +94,7 @@ type peer struct {
 	knownTxs           mapset.Set                // Set of transaction hashes known to be known by this peer
 	knownBlocks        mapset.Set                // Set of block hashes known to be known by this peer
 	knownPrepareBlocks mapset.Set                // Set of prepareblock hashes known to be known by this peer
+	score              *peerScore                // Consensus score, kept across the connections of the peer
 	queuedTxs          chan []*types.Transaction // Queue of transactions to broadcast to the peer
 	queuedProps        chan *propEvent           // Queue of blocks to broadcast to the peer
 	queuedAnns         chan *types.Block         // Queue of blocks to announce to the peer
@@ -109,6 +111,7 @@ func newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
 		id:              fmt.Sprintf("%x", p.ID().Bytes()[:8]),
 		knownTxs:        mapset.NewSet(),
 		knownBlocks:     mapset.NewSet(),
+		score:           newPeerScore(),
 		queuedTxs:       make(chan []*types.Transaction, maxQueuedTxs),
 		queuedProps:     make(chan *propEvent, maxQueuedProps),
 		queuedAnns:      make(chan *types.Block, maxQueuedAnns),
@@ -138,14 +141,14 @@ func (p *peer) broadcast() {
 				p.Log().Trace("Announced block", "number", block.Number(), "hash", block.Hash())
 
 			case prop := <-p.queuedPreBlock:
-				if err := p.SendPrepareBlock(prop.block); err != nil {
+				if err := p.SendPrepareBlock(prop.block, prop.hops); err != nil {
 					return
 				}
 				p.Log().Trace("Propagated prepare block", "number", prop.block.Number(), "hash", prop.block.Hash())
 
 			case prop := <-p.queuedSignature:
-				signature := &cbfttypes.BlockSignature{SignHash: prop.SignHash, Hash: prop.Hash, Number: prop.Number, Signature: prop.Signature}
-				if err := p.SendSignature(signature); err != nil {
+				signature := &cbfttypes.BlockSignature{SignHash: prop.SignHash, Hash: prop.Hash, Number: prop.Number, Signature: prop.Signature, ParentHash: prop.ParentHash, BlsSignature: prop.BlsSignature}
+				if err := p.SendSignature(signature, prop.Hops); err != nil {
 					return
 				}
 				p.Log().Trace("Propagated block signature", "hash", signature.Hash)
@@ -186,6 +189,7 @@ func (p *peer) Info() *PeerInfo {
 		Version:    p.version,
 		BN: bn,
 		Head:       hash.Hex(),
+		Score:      p.score.Info(),
 	}
 }
 
@@ -228,6 +232,11 @@ func (p *peer) MarkTransaction(hash common.Hash) {
 	p.knownTxs.Add(hash)
 }
 
+// knowsTransaction reports whether the peer is known to have the transaction.
+func (p *peer) knowsTransaction(hash common.Hash) bool {
+	return p.knownTxs.Contains(hash)
+}
+
 // SendTransactions sends transactions to the peer and includes the hashes
 // in its transaction hash set for future reference.
 func (p *peer) SendTransactions(txs types.Transactions) error {
@@ -276,9 +285,13 @@ func (p *peer) AsyncSendNewBlockHash(block *types.Block) {
 	}
 }
 
-// SendNewBlock propagates an entire block to a remote peer.
+// SendNewBlock propagates an entire block to a remote peer, compactly if the
+// peer supports it.
 func (p *peer) SendNewBlock(block *types.Block) error {
 	p.knownBlocks.Add(block.Hash())
+	if p.version >= eth64 {
+		return p2p.Send(p.rw, CompactNewBlockMsg, &compactNewBlockData{Block: newCompactBlock(block, p.knowsTransaction)})
+	}
 	return p2p.Send(p.rw, NewBlockMsg, []interface{}{block})
 }
 
@@ -606,6 +619,20 @@ func (ps *peerSet) PeersWithConsensus(consensusNodes []discover.NodeID) []*peer
 	return list
 }
 
+// PeersWithout retrieves a list of peers except the one with the given id.
+func (ps *peerSet) PeersWithout(id string) []*peer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*peer, 0, len(ps.peers))
+	for pid, p := range ps.peers {
+		if pid != id {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
 //func (ps *peerSet) PeersWithoutConsensus(engine consensus.Engine) []*peer {
 //	ps.lock.RLock()
 //	defer ps.lock.RUnlock()
@@ -632,6 +659,7 @@ func (ps *peerSet) PeersWithConsensus(consensusNodes []discover.NodeID) []*peer
 
 type preBlockEvent struct {
 	block *types.Block
+	hops  uint8
 }
 
 type signatureEvent struct {
@@ -639,29 +667,53 @@ type signatureEvent struct {
 	Hash      common.Hash // Block hash，header[:]
 	Number    *big.Int
 	Signature *common.BlockConfirmSign
+	// BLS signature of SignHash, empty if the signer has no BLS key
+	BlsSignature []byte
+	ParentHash   common.Hash
+	Hops         uint8
+}
+
+// SendPrepareBlock propagates an entire block to a remote peer, compactly if the
+// peer supports it. hops is the number of hops left to relay it. The peers below
+// eth/64 get the legacy encoding.
+func (p *peer) SendPrepareBlock(block *types.Block, hops uint8) error {
+	if p.version < eth64 {
+		return p2p.Send(p.rw, PrepareBlockMsg, &legacyPrepareBlockData{Block: block})
+	}
+	return p2p.Send(p.rw, CompactPrepareBlockMsg, &compactPrepareBlockData{Block: newCompactBlock(block, p.knowsTransaction), Hops: hops})
+}
+
+// RequestBlockTxs fetches the transactions of a compact block at indexes.
+func (p *peer) RequestBlockTxs(hash common.Hash, indexes []uint64) error {
+	return p2p.Send(p.rw, GetBlockTxsMsg, &getBlockTxsData{Hash: hash, Indexes: indexes})
 }
 
-// SendPrepareBlock propagates an entire block to a remote peer.
-func (p *peer) SendPrepareBlock(block *types.Block) error {
-	return p2p.Send(p.rw, PrepareBlockMsg, []interface{}{block})
+// SendBlockTxs sends the requested transactions of a compact block.
+func (p *peer) SendBlockTxs(hash common.Hash, txs []*types.Transaction) error {
+	return p2p.Send(p.rw, BlockTxsMsg, &blockTxsData{Hash: hash, Txs: txs})
 }
 
-func (p *peer) AsyncSendPrepareBlock(block *types.Block) {
+func (p *peer) AsyncSendPrepareBlock(block *types.Block, hops uint8) {
 	select {
-	case p.queuedPreBlock <- &preBlockEvent{block: block}:
+	case p.queuedPreBlock <- &preBlockEvent{block: block, hops: hops}:
 		p.Log().Debug("Send prepare block propagation", "number", block.NumberU64(), "hash", block.Hash())
 	default:
 		p.Log().Debug("Dropping prepare block propagation", "number", block.NumberU64(), "hash", block.Hash())
 	}
 }
 
-func (p *peer) SendSignature(signature *cbfttypes.BlockSignature) error {
-	return p2p.Send(p.rw, BlockSignatureMsg, []interface{}{signature.SignHash, signature.Hash, signature.Number, signature.Signature})
+// SendSignature propagates a block signature to a remote peer, hops is the
+// number of hops left to relay it. The peers below eth/64 get the legacy encoding.
+func (p *peer) SendSignature(signature *cbfttypes.BlockSignature, hops uint8) error {
+	if p.version < eth64 {
+		return p2p.Send(p.rw, BlockSignatureMsg, &legacyBlockSignature{SignHash: signature.SignHash, Hash: signature.Hash, Number: signature.Number, Signature: signature.Signature})
+	}
+	return p2p.Send(p.rw, BlockSignatureMsg, []interface{}{signature.SignHash, signature.Hash, signature.Number, signature.Signature, signature.BlsSignature, signature.ParentHash, hops})
 }
 
-func (p *peer) AsyncSendSignature(signature *cbfttypes.BlockSignature) {
+func (p *peer) AsyncSendSignature(signature *cbfttypes.BlockSignature, hops uint8) {
 	select {
-	case p.queuedSignature <- &signatureEvent{SignHash: signature.SignHash, Hash: signature.Hash, Number: signature.Number, Signature: signature.Signature}:
+	case p.queuedSignature <- &signatureEvent{SignHash: signature.SignHash, Hash: signature.Hash, Number: signature.Number, Signature: signature.Signature, ParentHash: signature.ParentHash, BlsSignature: signature.BlsSignature, Hops: hops}:
 	default:
 		p.Log().Debug("Dropping block Signature", "Hash", signature.Hash)
 	}
@@ -32,16 +32,17 @@ import (
 const (
 	eth62 = 62
 	eth63 = 63
+	eth64 = 64
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 var ProtocolName = "eth"
 
 // ProtocolVersions are the upported versions of the eth protocol (first is primary).
-var ProtocolVersions = []uint{eth63, eth62}
+var ProtocolVersions = []uint{eth64, eth63, eth62}
 
 // ProtocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []uint64{19, 8}
+var ProtocolLengths = []uint64{23, 19, 8}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -68,6 +69,12 @@ const (
 	ReceiptsMsg    = 0x10
 	GetPposStorageMsg = 0x11
 	PposStorageMsg    = 0x12
+
+	// Protocol messages belonging to eth/64
+	CompactPrepareBlockMsg = 0x13
+	CompactNewBlockMsg     = 0x14
+	GetBlockTxsMsg         = 0x15
+	BlockTxsMsg            = 0x16
 )
 
 type errCode int
@@ -83,6 +90,7 @@ const (
 	ErrNoStatusMsg
 	ErrExtraStatusMsg
 	ErrSuspendedPeer
+	ErrTooManyMisbehaviours
 )
 
 func (e errCode) String() string {
@@ -100,6 +108,7 @@ var errorToString = map[int]string{
 	ErrNoStatusMsg:             "No status message",
 	ErrExtraStatusMsg:          "Extra status message",
 	ErrSuspendedPeer:           "Suspended peer",
+	ErrTooManyMisbehaviours:    "Too many consensus misbehaviours",
 }
 
 type txPool interface {
@@ -181,6 +190,38 @@ type newBlockData struct {
 
 type prepareBlockData struct {
 	Block *types.Block
+	Hops  uint8 // Hops left to relay the block
+}
+
+// legacyPrepareBlockData is the encoding of prepareBlockData exchanged with the
+// peers below eth/64, which don't relay the prepare blocks.
+type legacyPrepareBlockData struct {
+	Block *types.Block
+}
+
+// compactNewBlockData is the network packet for the compact block propagation message.
+type compactNewBlockData struct {
+	Block *compactBlock
+}
+
+// compactPrepareBlockData is the network packet for the compact prepare block message.
+type compactPrepareBlockData struct {
+	Block *compactBlock
+	Hops  uint8 // Hops left to relay the block
+}
+
+// getBlockTxsData is the network packet requesting the missing transactions of
+// a compact block.
+type getBlockTxsData struct {
+	Hash    common.Hash // Hash of the block
+	Indexes []uint64    // Indexes of the transactions in the block
+}
+
+// blockTxsData is the network packet for the missing transactions of a compact
+// block, in the order of the request.
+type blockTxsData struct {
+	Hash common.Hash
+	Txs  []*types.Transaction
 }
 
 type blockSignature struct {
@@ -188,6 +229,19 @@ type blockSignature struct {
 	Hash      common.Hash // blokc hash，header[:]
 	Number    *big.Int
 	Signature *common.BlockConfirmSign
+	// BLS signature of SignHash, empty if the signer has no BLS key
+	BlsSignature []byte
+	ParentHash   common.Hash
+	Hops         uint8 // Hops left to relay the signature
+}
+
+// legacyBlockSignature is the encoding of blockSignature exchanged with the peers
+// below eth/64, which know neither the BLS signatures nor the relay.
+type legacyBlockSignature struct {
+	SignHash  common.Hash
+	Hash      common.Hash
+	Number    *big.Int
+	Signature *common.BlockConfirmSign
 }
 
 // blockBody represents the data content of a single block.
@@ -195,6 +249,7 @@ type blockBody struct {
 	Transactions []*types.Transaction // Transactions contained within a block
 	Uncles       []*types.Header      // Uncles contained within a block
 	Signatures	 []*common.BlockConfirmSign	// Signatures contained within a block
+	Aggregate    []*types.BlockConfirmAggregate `rlp:"tail"` // Aggregated confirmation of a block, if any
 }
 
 // blockBodiesData is the network packet for block content distribution.
@@ -0,0 +1,121 @@
+package eth
+
+import (
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/consensus"
+	"github.com/PlatONnetwork/PlatON-Go/core/cbfttypes"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/hashicorp/golang-lru"
+)
+
+const (
+	// maxConsensusHops is the number of hops a consensus message travels at most,
+	// the origin sends it with maxConsensusHops and every relay decrements it.
+	maxConsensusHops = 3
+
+	// maxKnownConsensusMsgs is the number of handled consensus messages to remember
+	// for deduplication.
+	maxKnownConsensusMsgs = 4096
+)
+
+// consensusMsgKey identifies a consensus message, the signer is empty for the
+// prepare blocks which are identified by their hash.
+type consensusMsgKey struct {
+	code   uint64
+	hash   common.Hash
+	signer discover.NodeID
+}
+
+// consensusRelay remembers the consensus messages already handled, so that every
+// message is delivered to the engine and relayed only once.
+type consensusRelay struct {
+	known *lru.Cache
+}
+
+func newConsensusRelay() *consensusRelay {
+	known, _ := lru.New(maxKnownConsensusMsgs)
+	return &consensusRelay{known: known}
+}
+
+// seen reports whether the message was handled already.
+func (r *consensusRelay) seen(key consensusMsgKey) bool {
+	return r.known.Contains(key)
+}
+
+// mark marks the message as handled, it returns false if it was handled already.
+func (r *consensusRelay) mark(key consensusMsgKey) bool {
+	ok, _ := r.known.ContainsOrAdd(key, struct{}{})
+	return !ok
+}
+
+// consensusSigner recovers the node which signed a block signature.
+func consensusSigner(signHash common.Hash, sign *common.BlockConfirmSign) (discover.NodeID, error) {
+	pubkey, err := crypto.SigToPub(signHash.Bytes(), sign[:])
+	if err != nil {
+		return discover.NodeID{}, err
+	}
+	return discover.PubkeyID(pubkey), nil
+}
+
+// relayHops returns the hops left to relay a message received with hops.
+func relayHops(hops uint8) uint8 {
+	if hops > maxConsensusHops {
+		hops = maxConsensusHops
+	}
+	if hops == 0 {
+		return 0
+	}
+	return hops - 1
+}
+
+// relayPrepareBlock forwards a received prepare block to the other peers.
+func (pm *ProtocolManager) relayPrepareBlock(block *types.Block, hops uint8, from *peer) {
+	if hops = relayHops(hops); hops == 0 {
+		return
+	}
+	pm.compact.remember(block)
+	for _, peer := range pm.peers.PeersWithout(from.id) {
+		log.Trace("Relay a broadcast message[PrepareBlockMsg]", "peerId", peer.id, "Hash", block.Hash(), "Number", block.Number(), "hops", hops)
+		peer.AsyncSendPrepareBlock(block, hops)
+	}
+}
+
+// relaySignature forwards a received block signature to the other peers.
+func (pm *ProtocolManager) relaySignature(signature *cbfttypes.BlockSignature, hops uint8, from *peer) {
+	if hops = relayHops(hops); hops == 0 {
+		return
+	}
+	for _, peer := range pm.peers.PeersWithout(from.id) {
+		log.Trace("Relay a broadcast message[BlockSignatureMsg]", "peerId", peer.id, "Hash", signature.Hash, "Number", signature.Number, "hops", hops)
+		peer.AsyncSendSignature(signature, hops)
+	}
+}
+
+// remoteConsensusNodes returns the number of the consensus nodes other than the
+// local node.
+func (pm *ProtocolManager) remoteConsensusNodes(consensusNodes []discover.NodeID) int {
+	bft, ok := pm.engine.(consensus.Bft)
+	if !ok {
+		return len(consensusNodes)
+	}
+	self, n := bft.GetOwnNodeID(), 0
+	for _, id := range consensusNodes {
+		if id != self {
+			n++
+		}
+	}
+	return n
+}
+
+// isConsensusNode reports whether nodeID is one of the consensus nodes.
+func isConsensusNode(consensusNodes []discover.NodeID, nodeID discover.NodeID) bool {
+	for _, id := range consensusNodes {
+		if id == nodeID {
+			return true
+		}
+	}
+	return false
+}
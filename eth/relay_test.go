@@ -0,0 +1,144 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/cbfttypes"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/p2p"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+func TestConsensusRelayDedup(t *testing.T) {
+	relay := newConsensusRelay()
+	key := consensusMsgKey{code: BlockSignatureMsg, hash: common.HexToHash("0x01"), signer: discover.NodeID{1}}
+
+	if relay.seen(key) {
+		t.Fatalf("message seen before it was handled")
+	}
+	if !relay.mark(key) {
+		t.Fatalf("first mark rejected")
+	}
+	if !relay.seen(key) || relay.mark(key) {
+		t.Fatalf("message handled twice")
+	}
+	// the same block signed by another node is another message
+	other := key
+	other.signer = discover.NodeID{2}
+	if relay.seen(other) {
+		t.Fatalf("signature of another signer deduplicated")
+	}
+	other = key
+	other.code = PrepareBlockMsg
+	if relay.seen(other) {
+		t.Fatalf("message of another type deduplicated")
+	}
+}
+
+func TestRelayHops(t *testing.T) {
+	tests := []struct{ hops, want uint8 }{
+		{0, 0},
+		{1, 0},
+		{2, 1},
+		{maxConsensusHops, maxConsensusHops - 1},
+		{255, maxConsensusHops - 1},
+	}
+	for _, tt := range tests {
+		if have := relayHops(tt.hops); have != tt.want {
+			t.Errorf("hops %d: relay hops mismatch: have %d, want %d", tt.hops, have, tt.want)
+		}
+	}
+}
+
+func TestSignatureRelayEncoding(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signHash := common.HexToHash("0x0102")
+	sig, _ := crypto.Sign(signHash.Bytes(), key)
+	signature := &cbfttypes.BlockSignature{
+		SignHash:     signHash,
+		Hash:         common.HexToHash("0x03"),
+		Number:       big.NewInt(10),
+		Signature:    common.NewBlockConfirmSign(sig),
+		ParentHash:   common.HexToHash("0x04"),
+		BlsSignature: []byte{0x05},
+	}
+
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	p := newPeer(eth64, p2p.NewPeer(discover.NodeID{}, "peer", nil), app)
+	go p.SendSignature(signature, 2)
+
+	msg, err := net.ReadMsg()
+	if err != nil {
+		t.Fatalf("failed to read message: %v", err)
+	}
+	var request blockSignature
+	if err := msg.Decode(&request); err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+	if request.Hash != signature.Hash || request.ParentHash != signature.ParentHash || request.Hops != 2 || len(request.BlsSignature) != 1 {
+		t.Fatalf("signature mismatch: %+v", request)
+	}
+	signer, err := consensusSigner(request.SignHash, request.Signature)
+	if err != nil || signer != discover.PubkeyID(&key.PublicKey) {
+		t.Fatalf("signer mismatch: have %x, want %x (%v)", signer, discover.PubkeyID(&key.PublicKey), err)
+	}
+}
+
+func TestSendSignatureLegacy(t *testing.T) {
+	signature := &cbfttypes.BlockSignature{
+		SignHash:     common.HexToHash("0x01"),
+		Hash:         common.HexToHash("0x02"),
+		Number:       big.NewInt(3),
+		Signature:    &common.BlockConfirmSign{},
+		BlsSignature: []byte{0x04},
+		ParentHash:   common.HexToHash("0x05"),
+	}
+	for _, version := range []uint{eth63, eth64} {
+		app, net := p2p.MsgPipe()
+		p := newPeer(int(version), p2p.NewPeer(discover.NodeID{}, "peer", nil), app)
+		go p.SendSignature(signature, 2)
+
+		msg, err := net.ReadMsg()
+		if err != nil {
+			t.Fatalf("eth/%d: failed to read message: %v", version, err)
+		}
+		if version < eth64 {
+			var legacy legacyBlockSignature
+			if err := msg.Decode(&legacy); err != nil || legacy.Hash != signature.Hash {
+				t.Errorf("eth/%d: legacy signature mismatch: %v, %v", version, legacy, err)
+			}
+		} else {
+			var request blockSignature
+			if err := msg.Decode(&request); err != nil || request.ParentHash != signature.ParentHash || request.Hops != 2 {
+				t.Errorf("eth/%d: signature mismatch: %v, %v", version, request, err)
+			}
+		}
+		app.Close()
+	}
+}
+
+func testPrepareBlock(txs []*types.Transaction) *types.Block {
+	header := &types.Header{Number: common.Big1, TxHash: types.DeriveSha(types.Transactions(txs)), UncleHash: types.EmptyUncleHash}
+	return types.NewBlockWithHeader(header).WithBody(txs, nil, nil)
+}
+
+func TestVerifyPrepareBody(t *testing.T) {
+	txs := []*types.Transaction{newTestTransaction(testBankKey, 0, 10), newTestTransaction(testBankKey, 1, 10)}
+	block := testPrepareBlock(txs)
+	if err := verifyPrepareBody(block); err != nil {
+		t.Fatalf("valid prepare block rejected: %v", err)
+	}
+	if err := verifyPrepareBody(block.WithBody(txs[:1], nil, nil)); err == nil {
+		t.Errorf("prepare block with altered transactions accepted")
+	}
+	if err := verifyPrepareBody(block.WithBody(txs, []*types.Header{{Number: common.Big1}}, nil)); err == nil {
+		t.Errorf("prepare block with altered uncles accepted")
+	}
+	if err := verifyPrepareBody(block.WithBody(txs, nil, []*common.BlockConfirmSign{{}})); err == nil {
+		t.Errorf("prepare block with confirmations accepted")
+	}
+}
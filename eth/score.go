@@ -0,0 +1,270 @@
+package eth
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/consensus"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/hashicorp/golang-lru"
+)
+
+const (
+	// Penalties of the misbehaviours of a peer on the consensus messages
+	scoreInvalidSignature = 20 // Signature which can't be recovered or isn't from a consensus node
+	scoreOutOfTurnBlock   = 20 // Prepare block received out of the turn of its producer
+	scoreDuplicateMsg     = 2  // Consensus message sent twice by the same peer
+	scoreStaleMsg         = 5  // Consensus message of a block number too old or too far in the future
+	scoreRateLimited      = 1  // Consensus message over the rate limit
+
+	// scoreDisconnect is the score above which a peer is disconnected.
+	scoreDisconnect = 100
+
+	// scoreBan is the score above which a peer is banned for peerBanTime.
+	scoreBan = 200
+
+	// peerBanTime is the time a banned peer is refused.
+	peerBanTime = time.Hour
+
+	// scoreDecay is the time in which a score decreases by one point.
+	scoreDecay = time.Second
+
+	// maxConsensusMsgRate is the number of consensus messages accepted from a peer
+	// per second.
+	maxConsensusMsgRate = 256
+
+	// maxStaleBlocks is the number of blocks a consensus message may be below the
+	// current block.
+	maxStaleBlocks = 16
+
+	// maxFutureBlocks is the number of blocks a consensus message may be above the
+	// current block.
+	maxFutureBlocks = 64
+
+	// maxKnownPeerConsensusMsgs is the number of consensus messages of a peer to
+	// remember for the detection of duplicates.
+	maxKnownPeerConsensusMsgs = 1024
+
+	// maxPeerScores is the number of peers whose scores are kept.
+	maxPeerScores = 1024
+
+	// Lengths of the vanity and seal of the extra data of a header
+	extraVanity = 32
+	extraSeal   = 65
+)
+
+var errMissingSeal = errors.New("header has no seal")
+
+// PeerScoreInfo represents the consensus score of a peer.
+type PeerScoreInfo struct {
+	Score             int       `json:"score"`             // Current score, the peer is disconnected above scoreDisconnect
+	InvalidSignatures uint64    `json:"invalidSignatures"` // Number of invalid signatures sent
+	OutOfTurnBlocks   uint64    `json:"outOfTurnBlocks"`   // Number of prepare blocks received out of the turn of their producers
+	Duplicates        uint64    `json:"duplicates"`        // Number of duplicate consensus messages sent
+	StaleMsgs         uint64    `json:"staleMsgs"`         // Number of consensus messages with an out of range number
+	RateLimited       uint64    `json:"rateLimited"`       // Number of consensus messages dropped by the rate limit
+	BannedUntil       time.Time `json:"bannedUntil,omitempty"`
+}
+
+// peerScore tracks the misbehaviours of a peer on the consensus messages.
+type peerScore struct {
+	lock    sync.Mutex
+	info    PeerScoreInfo
+	updated time.Time // Time the score was decayed last
+
+	window time.Time // Start of the current rate limit window
+	msgs   int       // Number of consensus messages in the current window
+
+	known *lru.Cache // Consensus messages sent by the peer
+}
+
+func newPeerScore() *peerScore {
+	known, _ := lru.New(maxKnownPeerConsensusMsgs)
+	return &peerScore{known: known, updated: time.Now()}
+}
+
+// decay lowers the score by the time elapsed since the last update.
+func (s *peerScore) decay(now time.Time) {
+	if elapsed := int(now.Sub(s.updated) / scoreDecay); elapsed > 0 {
+		if s.info.Score -= elapsed; s.info.Score < 0 {
+			s.info.Score = 0
+		}
+		s.updated = s.updated.Add(time.Duration(elapsed) * scoreDecay)
+	}
+}
+
+// penalize adds points to the score, counter is the misbehaviour to count. It
+// returns an error if the peer has to be disconnected.
+func (s *peerScore) penalize(points int, counter *uint64) error {
+	now := time.Now()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.decay(now)
+	s.info.Score += points
+	*counter++
+	if s.info.Score >= scoreBan {
+		s.info.BannedUntil = now.Add(peerBanTime)
+	}
+	if s.info.Score >= scoreDisconnect {
+		return errResp(ErrTooManyMisbehaviours, "score %d", s.info.Score)
+	}
+	return nil
+}
+
+// allow reports whether a consensus message is within the rate limit.
+func (s *peerScore) allow(now time.Time) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if now.Sub(s.window) >= time.Second {
+		s.window, s.msgs = now, 0
+	}
+	s.msgs++
+	return s.msgs <= maxConsensusMsgRate
+}
+
+// mark marks a consensus message as sent by the peer, it returns false if the
+// peer sent it already.
+func (s *peerScore) mark(key consensusMsgKey) bool {
+	ok, _ := s.known.ContainsOrAdd(key, struct{}{})
+	return !ok
+}
+
+// banned reports whether the peer is banned.
+func (s *peerScore) banned(now time.Time) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return now.Before(s.info.BannedUntil)
+}
+
+// Info returns a snapshot of the score.
+func (s *peerScore) Info() *PeerScoreInfo {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.decay(time.Now())
+	info := s.info
+	return &info
+}
+
+// Misbehaviours of a peer on the consensus messages
+func (s *peerScore) invalidSignature() error {
+	return s.penalize(scoreInvalidSignature, &s.info.InvalidSignatures)
+}
+
+func (s *peerScore) outOfTurnBlock() error {
+	return s.penalize(scoreOutOfTurnBlock, &s.info.OutOfTurnBlocks)
+}
+
+func (s *peerScore) duplicate() error {
+	return s.penalize(scoreDuplicateMsg, &s.info.Duplicates)
+}
+
+func (s *peerScore) stale() error {
+	return s.penalize(scoreStaleMsg, &s.info.StaleMsgs)
+}
+
+func (s *peerScore) rateLimited() error {
+	return s.penalize(scoreRateLimited, &s.info.RateLimited)
+}
+
+// peerScores keeps the scores of the peers across their connections, so that a
+// reconnecting peer keeps its score and a banned one stays banned.
+type peerScores struct {
+	lock   sync.Mutex
+	scores *lru.Cache
+}
+
+func newPeerScores() *peerScores {
+	scores, _ := lru.New(maxPeerScores)
+	return &peerScores{scores: scores}
+}
+
+// get returns the score of the peer id, creating it if unknown.
+func (ps *peerScores) get(id string) *peerScore {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	if score, ok := ps.scores.Get(id); ok {
+		return score.(*peerScore)
+	}
+	score := newPeerScore()
+	ps.scores.Add(id, score)
+	return score
+}
+
+// blockProducer recovers the node which sealed the header.
+func blockProducer(header *types.Header) (discover.NodeID, error) {
+	if len(header.Extra) < extraVanity+extraSeal {
+		return discover.NodeID{}, errMissingSeal
+	}
+	pubkey, err := crypto.SigToPub(header.SealHash().Bytes(), header.Extra[extraVanity:extraVanity+extraSeal])
+	if err != nil {
+		return discover.NodeID{}, err
+	}
+	return discover.PubkeyID(pubkey), nil
+}
+
+// currentNumber returns the number of the current block of the chain.
+func (pm *ProtocolManager) currentNumber() uint64 {
+	return pm.blockchain.CurrentBlock().NumberU64()
+}
+
+// admitConsensusMsg applies the budgets of p to a consensus message of number.
+// It reports whether the message is to be handled, and returns an error if p
+// has to be disconnected.
+func (pm *ProtocolManager) admitConsensusMsg(p *peer, key consensusMsgKey, number uint64) (bool, error) {
+	if !p.score.allow(time.Now()) {
+		p.Log().Trace("Consensus message over the rate limit,discard this msg", "hash", key.hash)
+		return false, p.score.rateLimited()
+	}
+	if !p.score.mark(key) {
+		p.Log().Debug("Duplicate consensus message,discard this msg", "hash", key.hash)
+		return false, p.score.duplicate()
+	}
+	current := pm.currentNumber()
+	if number+maxStaleBlocks <= current {
+		p.Log().Debug("Stale consensus message,discard this msg", "hash", key.hash, "number", number, "current", current)
+		return false, p.score.stale()
+	}
+	if number > current+maxFutureBlocks {
+		// The local chain may be behind, the message is far in the future only
+		// if it is far above the head of the peer too
+		if _, head := p.Head(); head != nil && number <= head.Uint64()+maxFutureBlocks {
+			return false, nil
+		}
+		p.Log().Debug("Future consensus message,discard this msg", "hash", key.hash, "number", number, "current", current)
+		return false, p.score.stale()
+	}
+	return true, nil
+}
+
+// checkBlockProducer checks that a prepare block of p was received in the turn of
+// its producer, as the consensus engine does. The turns of the blocks whose parent
+// is unknown yet can't be checked. It returns an error if p has to be disconnected.
+func (pm *ProtocolManager) checkBlockProducer(p *peer, block *types.Block) (bool, error) {
+	bft, ok := pm.engine.(consensus.Bft)
+	if !ok {
+		return true, nil
+	}
+	producer, err := blockProducer(block.Header())
+	if err != nil {
+		p.Log().Debug("Failed to recover the producer of a prepare block", "hash", block.Hash(), "err", err)
+		return false, p.score.invalidSignature()
+	}
+	parentNumber := new(big.Int).Sub(block.Number(), common.Big1)
+	consensusNodes := bft.ConsensusNodes(parentNumber, block.ParentHash(), block.Number())
+	if len(consensusNodes) > 0 && !bft.InTurn(parentNumber, block.ParentHash(), block.Number(), block.ReceivedAt.UnixNano()/1e6, producer) {
+		p.Log().Debug("Prepare block out of turn", "hash", block.Hash(), "producer", producer.TerminalString(), "receivedAt", block.ReceivedAt)
+		return false, p.score.outOfTurnBlock()
+	}
+	return true, nil
+}
@@ -0,0 +1,97 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+func TestPeerScorePenalties(t *testing.T) {
+	score := newPeerScore()
+	for i := 0; i < scoreDisconnect/scoreInvalidSignature-1; i++ {
+		if err := score.invalidSignature(); err != nil {
+			t.Fatalf("peer disconnected at score %d: %v", score.Info().Score, err)
+		}
+	}
+	if err := score.invalidSignature(); err == nil {
+		t.Fatalf("peer not disconnected at score %d", score.Info().Score)
+	}
+	if score.banned(time.Now()) {
+		t.Fatalf("peer banned at score %d", score.Info().Score)
+	}
+	for score.Info().Score < scoreBan {
+		score.outOfTurnBlock()
+	}
+	if !score.banned(time.Now()) || score.banned(time.Now().Add(peerBanTime)) {
+		t.Fatalf("ban mismatch: %+v", score.Info())
+	}
+	info := score.Info()
+	if info.InvalidSignatures != scoreDisconnect/scoreInvalidSignature || info.OutOfTurnBlocks == 0 {
+		t.Fatalf("counters mismatch: %+v", info)
+	}
+}
+
+func TestPeerScoreDecay(t *testing.T) {
+	score := newPeerScore()
+	score.stale()
+	score.updated = score.updated.Add(-2 * scoreDecay)
+	if have, want := score.Info().Score, scoreStaleMsg-2; have != want {
+		t.Fatalf("decayed score mismatch: have %d, want %d", have, want)
+	}
+	score.updated = score.updated.Add(-10 * scoreDecay)
+	if have := score.Info().Score; have != 0 {
+		t.Fatalf("decayed score mismatch: have %d, want 0", have)
+	}
+}
+
+func TestPeerScoreRateLimit(t *testing.T) {
+	score := newPeerScore()
+	now := time.Now()
+	for i := 0; i < maxConsensusMsgRate; i++ {
+		if !score.allow(now) {
+			t.Fatalf("message %d over the rate limit", i)
+		}
+	}
+	if score.allow(now) {
+		t.Fatalf("message over the rate limit allowed")
+	}
+	if !score.allow(now.Add(time.Second)) {
+		t.Fatalf("message of the next window not allowed")
+	}
+}
+
+func TestPeerScoreDuplicates(t *testing.T) {
+	score := newPeerScore()
+	key := consensusMsgKey{code: BlockSignatureMsg, hash: common.HexToHash("0x01"), signer: discover.NodeID{1}}
+	if !score.mark(key) {
+		t.Fatalf("first message marked as duplicate")
+	}
+	if score.mark(key) {
+		t.Fatalf("duplicate message not detected")
+	}
+	// the scores are kept across the connections of a peer
+	scores := newPeerScores()
+	if scores.get("a") != scores.get("a") || scores.get("a") == scores.get("b") {
+		t.Fatalf("peer scores mismatch")
+	}
+}
+
+func TestBlockProducer(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	header := &types.Header{Number: big.NewInt(1), Extra: make([]byte, extraVanity+extraSeal)}
+	sig, _ := crypto.Sign(header.SealHash().Bytes(), key)
+	copy(header.Extra[extraVanity:], sig)
+
+	producer, err := blockProducer(header)
+	if err != nil || producer != discover.PubkeyID(&key.PublicKey) {
+		t.Fatalf("producer mismatch: have %x, want %x (%v)", producer, discover.PubkeyID(&key.PublicKey), err)
+	}
+	if _, err := blockProducer(&types.Header{Number: big.NewInt(1)}); err != errMissingSeal {
+		t.Fatalf("error mismatch: have %v, want %v", err, errMissingSeal)
+	}
+}
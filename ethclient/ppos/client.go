@@ -0,0 +1,306 @@
+// Package ppos provides a typed client for the PPOS system contracts, the
+// candidate pool and the ticket pool.
+package ppos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/PlatONnetwork/PlatON-Go"
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi/bind"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+var errNoSigner = errors.New("no signer to authorize the transaction with")
+
+// WithdrawInfo is a deposit refund of a candidate waiting for its lock to expire.
+type WithdrawInfo struct {
+	Balance        *big.Int
+	LockNumber     *big.Int
+	LockBlockCycle uint32
+}
+
+// Client calls the PPOS system contracts with typed arguments and decodes
+// their results. Transactions are signed with the signer of the TransactOpts,
+// their nonce, gas price and gas limit are filled from the backend if not set.
+type Client struct {
+	backend bind.ContractBackend
+	signer  types.Signer
+}
+
+// NewClient creates a client of the PPOS contracts using backend, transactions
+// are signed for the chain chainID, a nil chainID signs without replay protection.
+func NewClient(backend bind.ContractBackend, chainID *big.Int) *Client {
+	var signer types.Signer = types.HomesteadSigner{}
+	if chainID != nil {
+		signer = types.NewEIP155Signer(chainID)
+	}
+	return &Client{backend: backend, signer: signer}
+}
+
+// CandidateDeposit applies nodeId as a candidate or adds opts.Value to its deposit,
+// sig is the signature of the deposit made with the node key, blsPubKey and blsProof
+// the BLS public key of the node and its proof of possession.
+func (c *Client) CandidateDeposit(opts *bind.TransactOpts, nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string, sig, blsPubKey, blsProof []byte) (*types.Transaction, error) {
+	return c.transact(opts, common.CandidatePoolAddr, "CandidateDeposit", nodeId, owner, fee, host, port, extra, sig, blsPubKey, blsProof)
+}
+
+// CandidateApplyWithdraw applies for the refund of withdraw of the deposit of nodeId.
+func (c *Client) CandidateApplyWithdraw(opts *bind.TransactOpts, nodeId discover.NodeID, withdraw *big.Int) (*types.Transaction, error) {
+	return c.transact(opts, common.CandidatePoolAddr, "CandidateApplyWithdraw", nodeId, withdraw)
+}
+
+// CandidateWithdraw refunds the deposits of nodeId whose lock has expired.
+func (c *Client) CandidateWithdraw(opts *bind.TransactOpts, nodeId discover.NodeID) (*types.Transaction, error) {
+	return c.transact(opts, common.CandidatePoolAddr, "CandidateWithdraw", nodeId)
+}
+
+// SetCandidateExtra sets the additional information of nodeId.
+func (c *Client) SetCandidateExtra(opts *bind.TransactOpts, nodeId discover.NodeID, extra string) (*types.Transaction, error) {
+	return c.transact(opts, common.CandidatePoolAddr, "SetCandidateExtra", nodeId, extra)
+}
+
+// IncreaseDeposit adds opts.Value to the deposit of nodeId.
+func (c *Client) IncreaseDeposit(opts *bind.TransactOpts, nodeId discover.NodeID) (*types.Transaction, error) {
+	return c.transact(opts, common.CandidatePoolAddr, "IncreaseDeposit", nodeId)
+}
+
+// UpdateCandidateInfo updates the host, port and fee of nodeId.
+func (c *Client) UpdateCandidateInfo(opts *bind.TransactOpts, nodeId discover.NodeID, host, port string, fee uint32) (*types.Transaction, error) {
+	return c.transact(opts, common.CandidatePoolAddr, "UpdateCandidateInfo", nodeId, host, port, fee)
+}
+
+// SetCandidateSentries sets the sentries advertised by nodeId, sentries are comma
+// separated enode URLs.
+func (c *Client) SetCandidateSentries(opts *bind.TransactOpts, nodeId discover.NodeID, sentries string) (*types.Transaction, error) {
+	return c.transact(opts, common.CandidatePoolAddr, "SetCandidateSentries", nodeId, sentries)
+}
+
+// SetCandidateBlsKey sets the BLS public key of nodeId, blsProof is the proof of
+// possession of its secret key.
+func (c *Client) SetCandidateBlsKey(opts *bind.TransactOpts, nodeId discover.NodeID, blsPubKey, blsProof []byte) (*types.Transaction, error) {
+	return c.transact(opts, common.CandidatePoolAddr, "SetCandidateBlsKey", nodeId, blsPubKey, blsProof)
+}
+
+// Unjail releases the jailed candidate nodeId.
+func (c *Client) Unjail(opts *bind.TransactOpts, nodeId discover.NodeID) (*types.Transaction, error) {
+	return c.transact(opts, common.CandidatePoolAddr, "Unjail", nodeId)
+}
+
+// VoteTicket buys count tickets at price for the candidate nodeId.
+func (c *Client) VoteTicket(opts *bind.TransactOpts, count uint32, price *big.Int, nodeId discover.NodeID) (*types.Transaction, error) {
+	return c.transact(opts, common.TicketPoolAddr, "VoteTicket", count, price, nodeId)
+}
+
+// TransferTicket moves count tickets of ticketId to the account to.
+func (c *Client) TransferTicket(opts *bind.TransactOpts, ticketId common.Hash, count uint32, to common.Address) (*types.Transaction, error) {
+	return c.transact(opts, common.TicketPoolAddr, "TransferTicket", ticketId, count, to)
+}
+
+// RedelegateTicket moves count tickets of ticketId to the candidate nodeId.
+func (c *Client) RedelegateTicket(opts *bind.TransactOpts, ticketId common.Hash, count uint32, nodeId discover.NodeID) (*types.Transaction, error) {
+	return c.transact(opts, common.TicketPoolAddr, "RedelegateTicket", ticketId, count, nodeId)
+}
+
+// GetCandidateNonce returns the deposit nonce the node key must sign for the next deposit.
+func (c *Client) GetCandidateNonce(opts *bind.CallOpts, nodeId discover.NodeID) (uint64, error) {
+	var nonce uint64
+	err := c.call(opts, common.CandidatePoolAddr, &nonce, "GetCandidateNonce", nodeId)
+	return nonce, err
+}
+
+// GetCandidateBlsKey returns the BLS public key registered by nodeId, empty if it has none.
+func (c *Client) GetCandidateBlsKey(opts *bind.CallOpts, nodeId discover.NodeID) ([]byte, error) {
+	var key hexutil.Bytes
+	err := c.call(opts, common.CandidatePoolAddr, &key, "GetCandidateBlsKey", nodeId)
+	return key, err
+}
+
+// GetCandidateSentries returns the sentries advertised by nodeId, empty if it is reached directly.
+func (c *Client) GetCandidateSentries(opts *bind.CallOpts, nodeId discover.NodeID) (string, error) {
+	var sentries string
+	err := c.call(opts, common.CandidatePoolAddr, &sentries, "GetCandidateSentries", nodeId)
+	return sentries, err
+}
+
+// GetCandidatePendingFee returns the fee change of nodeId waiting to take effect.
+func (c *Client) GetCandidatePendingFee(opts *bind.CallOpts, nodeId discover.NodeID) (*types.CandidatePendingFee, error) {
+	var pending *types.CandidatePendingFee
+	err := c.call(opts, common.CandidatePoolAddr, &pending, "GetCandidatePendingFee", nodeId)
+	return pending, err
+}
+
+// GetCandidateLiveness returns the missed slots of nodeId and whether it is jailed.
+func (c *Client) GetCandidateLiveness(opts *bind.CallOpts, nodeId discover.NodeID) (*types.CandidateLiveness, error) {
+	var liveness *types.CandidateLiveness
+	err := c.call(opts, common.CandidatePoolAddr, &liveness, "GetCandidateLiveness", nodeId)
+	return liveness, err
+}
+
+// GetCandidateWithdrawInfos returns the deposit refunds of nodeId.
+func (c *Client) GetCandidateWithdrawInfos(opts *bind.CallOpts, nodeId discover.NodeID) ([]WithdrawInfo, error) {
+	var infos []WithdrawInfo
+	err := c.call(opts, common.CandidatePoolAddr, &infos, "GetCandidateWithdrawInfos", nodeId)
+	return infos, err
+}
+
+// GetCandidateDetails returns the candidates of nodeIds.
+func (c *Client) GetCandidateDetails(opts *bind.CallOpts, nodeIds []discover.NodeID) (types.CandidateQueue, error) {
+	var candidates types.CandidateQueue
+	err := c.call(opts, common.CandidatePoolAddr, &candidates, "GetCandidateDetails", nodeIds)
+	return candidates, err
+}
+
+// GetCandidateList returns the immediate and the reserve queue of candidates.
+func (c *Client) GetCandidateList(opts *bind.CallOpts) (types.KindCanQueue, error) {
+	var queues types.KindCanQueue
+	err := c.call(opts, common.CandidatePoolAddr, &queues, "GetCandidateList")
+	return queues, err
+}
+
+// GetVerifiersList returns the witnesses of the current round.
+func (c *Client) GetVerifiersList(opts *bind.CallOpts) (types.CandidateQueue, error) {
+	var verifiers types.CandidateQueue
+	err := c.call(opts, common.CandidatePoolAddr, &verifiers, "GetVerifiersList")
+	return verifiers, err
+}
+
+// GetCandidateTicketCount returns the number of tickets of each of nodeIds.
+func (c *Client) GetCandidateTicketCount(opts *bind.CallOpts, nodeIds []discover.NodeID) (map[discover.NodeID]uint32, error) {
+	var counts map[discover.NodeID]uint32
+	err := c.call(opts, common.TicketPoolAddr, &counts, "GetCandidateTicketCount", nodeIds)
+	return counts, err
+}
+
+// GetTicketCountByTxHash returns the number of remaining tickets of each of ticketIds.
+func (c *Client) GetTicketCountByTxHash(opts *bind.CallOpts, ticketIds []common.Hash) (map[common.Hash]uint32, error) {
+	var counts map[common.Hash]uint32
+	err := c.call(opts, common.TicketPoolAddr, &counts, "GetTicketCountByTxHash", ticketIds)
+	return counts, err
+}
+
+// GetCandidateEpoch returns the ticket age of nodeId.
+func (c *Client) GetCandidateEpoch(opts *bind.CallOpts, nodeId discover.NodeID) (uint64, error) {
+	var epoch uint64
+	err := c.call(opts, common.TicketPoolAddr, &epoch, "GetCandidateEpoch", nodeId)
+	return epoch, err
+}
+
+// GetPoolRemainder returns the number of tickets left in the ticket pool.
+func (c *Client) GetPoolRemainder(opts *bind.CallOpts) (uint32, error) {
+	var remainder uint32
+	err := c.call(opts, common.TicketPoolAddr, &remainder, "GetPoolRemainder")
+	return remainder, err
+}
+
+// GetTicketPrice returns the current ticket price.
+func (c *Client) GetTicketPrice(opts *bind.CallOpts) (*big.Int, error) {
+	var price *big.Int
+	err := c.call(opts, common.TicketPoolAddr, &price, "GetTicketPrice")
+	return price, err
+}
+
+// GetTicketPriceHistory returns count of the latest ticket prices set by the pricing
+// epochs from the index start, oldest first, at most vm.MaxTicketPriceHistoryPage.
+func (c *Client) GetTicketPriceHistory(opts *bind.CallOpts, start, count uint32) ([]*types.TicketPriceRecord, error) {
+	var records []*types.TicketPriceRecord
+	err := c.call(opts, common.TicketPoolAddr, &records, "GetTicketPriceHistory", start, count)
+	return records, err
+}
+
+// call calls the query name of the contract at address and decodes the JSON
+// encoded result into result.
+func (c *Client) call(opts *bind.CallOpts, address common.Address, result interface{}, name string, args ...interface{}) error {
+	if opts == nil {
+		opts = new(bind.CallOpts)
+	}
+	input, err := vm.EncodeInput(name, args...)
+	if err != nil {
+		return err
+	}
+	var (
+		msg    = ethereum.CallMsg{From: opts.From, To: &address, Data: input}
+		ctx    = ensureContext(opts.Context)
+		output []byte
+	)
+	if opts.Pending {
+		pb, ok := c.backend.(bind.PendingContractCaller)
+		if !ok {
+			return bind.ErrNoPendingState
+		}
+		output, err = pb.PendingCallContract(ctx, msg)
+	} else {
+		output, err = c.backend.CallContract(ctx, msg, nil)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := vm.DecodeResult(output)
+	if err != nil {
+		return fmt.Errorf("failed to decode the result of %s: %v", name, err)
+	}
+	return json.Unmarshal([]byte(data), result)
+}
+
+// transact sends a transaction calling the command name of the contract at address.
+func (c *Client) transact(opts *bind.TransactOpts, address common.Address, name string, args ...interface{}) (*types.Transaction, error) {
+	input, err := vm.EncodeInput(name, args...)
+	if err != nil {
+		return nil, err
+	}
+	ctx := ensureContext(opts.Context)
+	value := opts.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	var nonce uint64
+	if opts.Nonce == nil {
+		nonce, err = c.backend.PendingNonceAt(ctx, opts.From)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve account nonce: %v", err)
+		}
+	} else {
+		nonce = opts.Nonce.Uint64()
+	}
+	gasPrice := opts.GasPrice
+	if gasPrice == nil {
+		gasPrice, err = c.backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest gas price: %v", err)
+		}
+	}
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		// the contracts are precompiled, there is no code to check before estimating
+		msg := ethereum.CallMsg{From: opts.From, To: &address, Value: value, Data: input}
+		gasLimit, err = c.backend.EstimateGas(ctx, msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate gas needed: %v", err)
+		}
+	}
+	if opts.Signer == nil {
+		return nil, errNoSigner
+	}
+	signedTx, err := opts.Signer(c.signer, opts.From, types.NewTransaction(nonce, address, value, gasLimit, gasPrice, input))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.backend.SendTransaction(ctx, signedTx); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
+func ensureContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.TODO()
+	}
+	return ctx
+}
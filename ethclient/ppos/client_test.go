@@ -0,0 +1,112 @@
+package ppos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go"
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi/bind"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/event"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+)
+
+var (
+	testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testNodeId = discover.PubkeyID(&testKey.PublicKey)
+)
+
+// testBackend records the calls and transactions of the client, calls return result.
+type testBackend struct {
+	result string
+	call   ethereum.CallMsg
+	sent   *types.Transaction
+}
+
+func (b *testBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (b *testBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	b.call = call
+	return vm.DecodeResultStr(b.result), nil
+}
+
+func (b *testBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return nil, nil
+}
+
+func (b *testBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return 5, nil
+}
+
+func (b *testBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1000), nil
+}
+
+func (b *testBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	b.call = call
+	return 50000, nil
+}
+
+func (b *testBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.sent = tx
+	return nil
+}
+
+func (b *testBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return nil, nil
+}
+
+func (b *testBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error { <-quit; return nil }), nil
+}
+
+func TestCall(t *testing.T) {
+	data, _ := json.Marshal(map[discover.NodeID]uint32{testNodeId: 12})
+	backend := &testBackend{result: string(data)}
+	client := NewClient(backend, big.NewInt(1))
+
+	counts, err := client.GetCandidateTicketCount(nil, []discover.NodeID{testNodeId})
+	if err != nil {
+		t.Fatalf("GetCandidateTicketCount fail: %v", err)
+	}
+	if counts[testNodeId] != 12 {
+		t.Fatalf("ticket count mismatch: have %v, want 12", counts)
+	}
+	input, _ := vm.EncodeInput("GetCandidateTicketCount", []discover.NodeID{testNodeId})
+	if *backend.call.To != common.TicketPoolAddr || !bytes.Equal(backend.call.Data, input) {
+		t.Fatalf("call mismatch: to %x, data %x", backend.call.To, backend.call.Data)
+	}
+}
+
+func TestTransact(t *testing.T) {
+	backend := &testBackend{}
+	client := NewClient(backend, big.NewInt(1))
+	opts := bind.NewKeyedTransactor(testKey)
+	opts.Value = big.NewInt(3000)
+
+	tx, err := client.VoteTicket(opts, 3, big.NewInt(1000), testNodeId)
+	if err != nil {
+		t.Fatalf("VoteTicket fail: %v", err)
+	}
+	if tx != backend.sent {
+		t.Fatalf("the transaction is not sent")
+	}
+	input, _ := vm.EncodeInput("VoteTicket", uint32(3), big.NewInt(1000), testNodeId)
+	if *tx.To() != common.TicketPoolAddr || !bytes.Equal(tx.Data(), input) || tx.Value().Cmp(opts.Value) != 0 {
+		t.Fatalf("transaction mismatch: to %x, data %x, value %v", tx.To(), tx.Data(), tx.Value())
+	}
+	if tx.Nonce() != 5 || tx.Gas() != 50000 || tx.GasPrice().Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("defaults mismatch: nonce %d, gas %d, gas price %v", tx.Nonce(), tx.Gas(), tx.GasPrice())
+	}
+	if from, err := types.Sender(types.NewEIP155Signer(big.NewInt(1)), tx); err != nil || from != opts.From {
+		t.Fatalf("sender mismatch: have %x (%v), want %x", from, err, opts.From)
+	}
+}
@@ -112,6 +112,11 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPrivateAccountAPI(apiBackend, nonceLock),
 			Public:    false,
+		}, {
+			Namespace: "ppos",
+			Version:   "1.0",
+			Service:   NewPublicPposAPI(apiBackend, nonceLock),
+			Public:    true,
 		},
 	}
 }
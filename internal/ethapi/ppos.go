@@ -0,0 +1,205 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/rpc"
+)
+
+// PublicPposAPI provides an API to call the PPOS system contracts with typed
+// arguments, the queries return the decoded results of the contracts.
+type PublicPposAPI struct {
+	b      Backend
+	chain  *PublicBlockChainAPI
+	txPool *PublicTransactionPoolAPI
+}
+
+// NewPublicPposAPI creates a new RPC service of the PPOS system contracts.
+func NewPublicPposAPI(b Backend, nonceLock *AddrLocker) *PublicPposAPI {
+	return &PublicPposAPI{b, NewPublicBlockChainAPI(b), NewPublicTransactionPoolAPI(b, nonceLock)}
+}
+
+// CandidateDeposit sends a transaction applying nodeId as a candidate or adding
+// the value of the transaction to its deposit.
+func (s *PublicPposAPI) CandidateDeposit(ctx context.Context, args SendTxArgs, nodeId discover.NodeID, owner common.Address, fee uint32, host, port, extra string, sig, blsPubKey, blsProof hexutil.Bytes) (common.Hash, error) {
+	return s.sendTransaction(ctx, args, common.CandidatePoolAddr, "CandidateDeposit", nodeId, owner, fee, host, port, extra, []byte(sig), []byte(blsPubKey), []byte(blsProof))
+}
+
+// CandidateApplyWithdraw sends a transaction applying for the refund of withdraw of the deposit of nodeId.
+func (s *PublicPposAPI) CandidateApplyWithdraw(ctx context.Context, args SendTxArgs, nodeId discover.NodeID, withdraw hexutil.Big) (common.Hash, error) {
+	return s.sendTransaction(ctx, args, common.CandidatePoolAddr, "CandidateApplyWithdraw", nodeId, withdraw.ToInt())
+}
+
+// CandidateWithdraw sends a transaction refunding the deposits of nodeId whose lock has expired.
+func (s *PublicPposAPI) CandidateWithdraw(ctx context.Context, args SendTxArgs, nodeId discover.NodeID) (common.Hash, error) {
+	return s.sendTransaction(ctx, args, common.CandidatePoolAddr, "CandidateWithdraw", nodeId)
+}
+
+// SetCandidateExtra sends a transaction setting the additional information of nodeId.
+func (s *PublicPposAPI) SetCandidateExtra(ctx context.Context, args SendTxArgs, nodeId discover.NodeID, extra string) (common.Hash, error) {
+	return s.sendTransaction(ctx, args, common.CandidatePoolAddr, "SetCandidateExtra", nodeId, extra)
+}
+
+// SetCandidateSentries sends a transaction setting the sentries advertised by nodeId,
+// sentries are comma separated enode URLs.
+func (s *PublicPposAPI) SetCandidateSentries(ctx context.Context, args SendTxArgs, nodeId discover.NodeID, sentries string) (common.Hash, error) {
+	return s.sendTransaction(ctx, args, common.CandidatePoolAddr, "SetCandidateSentries", nodeId, sentries)
+}
+
+// IncreaseDeposit sends a transaction adding its value to the deposit of nodeId.
+func (s *PublicPposAPI) IncreaseDeposit(ctx context.Context, args SendTxArgs, nodeId discover.NodeID) (common.Hash, error) {
+	return s.sendTransaction(ctx, args, common.CandidatePoolAddr, "IncreaseDeposit", nodeId)
+}
+
+// UpdateCandidateInfo sends a transaction updating the host, port and fee of nodeId.
+func (s *PublicPposAPI) UpdateCandidateInfo(ctx context.Context, args SendTxArgs, nodeId discover.NodeID, host, port string, fee uint32) (common.Hash, error) {
+	return s.sendTransaction(ctx, args, common.CandidatePoolAddr, "UpdateCandidateInfo", nodeId, host, port, fee)
+}
+
+// SetCandidateBlsKey sends a transaction setting the BLS public key of nodeId,
+// blsProof is the proof of possession of its secret key.
+func (s *PublicPposAPI) SetCandidateBlsKey(ctx context.Context, args SendTxArgs, nodeId discover.NodeID, blsPubKey, blsProof hexutil.Bytes) (common.Hash, error) {
+	return s.sendTransaction(ctx, args, common.CandidatePoolAddr, "SetCandidateBlsKey", nodeId, []byte(blsPubKey), []byte(blsProof))
+}
+
+// Unjail sends a transaction releasing the jailed candidate nodeId.
+func (s *PublicPposAPI) Unjail(ctx context.Context, args SendTxArgs, nodeId discover.NodeID) (common.Hash, error) {
+	return s.sendTransaction(ctx, args, common.CandidatePoolAddr, "Unjail", nodeId)
+}
+
+// VoteTicket sends a transaction buying count tickets at price for the candidate nodeId.
+func (s *PublicPposAPI) VoteTicket(ctx context.Context, args SendTxArgs, count uint32, price hexutil.Big, nodeId discover.NodeID) (common.Hash, error) {
+	return s.sendTransaction(ctx, args, common.TicketPoolAddr, "VoteTicket", count, price.ToInt(), nodeId)
+}
+
+// TransferTicket sends a transaction moving count tickets of ticketId to the account to.
+func (s *PublicPposAPI) TransferTicket(ctx context.Context, args SendTxArgs, ticketId common.Hash, count uint32, to common.Address) (common.Hash, error) {
+	return s.sendTransaction(ctx, args, common.TicketPoolAddr, "TransferTicket", ticketId, count, to)
+}
+
+// RedelegateTicket sends a transaction moving count tickets of ticketId to the candidate nodeId.
+func (s *PublicPposAPI) RedelegateTicket(ctx context.Context, args SendTxArgs, ticketId common.Hash, count uint32, nodeId discover.NodeID) (common.Hash, error) {
+	return s.sendTransaction(ctx, args, common.TicketPoolAddr, "RedelegateTicket", ticketId, count, nodeId)
+}
+
+// GetCandidateNonce returns the deposit nonce the node key must sign for the next deposit.
+func (s *PublicPposAPI) GetCandidateNonce(ctx context.Context, nodeId discover.NodeID, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.CandidatePoolAddr, "GetCandidateNonce", nodeId)
+}
+
+// GetCandidateBlsKey returns the BLS public key registered by nodeId.
+func (s *PublicPposAPI) GetCandidateBlsKey(ctx context.Context, nodeId discover.NodeID, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.CandidatePoolAddr, "GetCandidateBlsKey", nodeId)
+}
+
+// GetCandidateSentries returns the sentries advertised by nodeId.
+func (s *PublicPposAPI) GetCandidateSentries(ctx context.Context, nodeId discover.NodeID, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.CandidatePoolAddr, "GetCandidateSentries", nodeId)
+}
+
+// GetCandidatePendingFee returns the fee change of nodeId waiting to take effect.
+func (s *PublicPposAPI) GetCandidatePendingFee(ctx context.Context, nodeId discover.NodeID, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.CandidatePoolAddr, "GetCandidatePendingFee", nodeId)
+}
+
+// GetCandidateLiveness returns the missed slots of nodeId and whether it is jailed.
+func (s *PublicPposAPI) GetCandidateLiveness(ctx context.Context, nodeId discover.NodeID, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.CandidatePoolAddr, "GetCandidateLiveness", nodeId)
+}
+
+// GetCandidateWithdrawInfos returns the deposit refunds of nodeId.
+func (s *PublicPposAPI) GetCandidateWithdrawInfos(ctx context.Context, nodeId discover.NodeID, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.CandidatePoolAddr, "GetCandidateWithdrawInfos", nodeId)
+}
+
+// GetCandidateDetails returns the candidates of nodeIds.
+func (s *PublicPposAPI) GetCandidateDetails(ctx context.Context, nodeIds []discover.NodeID, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.CandidatePoolAddr, "GetCandidateDetails", nodeIds)
+}
+
+// GetCandidateList returns the immediate and the reserve queue of candidates.
+func (s *PublicPposAPI) GetCandidateList(ctx context.Context, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.CandidatePoolAddr, "GetCandidateList")
+}
+
+// GetVerifiersList returns the witnesses of the current round.
+func (s *PublicPposAPI) GetVerifiersList(ctx context.Context, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.CandidatePoolAddr, "GetVerifiersList")
+}
+
+// GetCandidateTicketCount returns the number of tickets of each of nodeIds.
+func (s *PublicPposAPI) GetCandidateTicketCount(ctx context.Context, nodeIds []discover.NodeID, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.TicketPoolAddr, "GetCandidateTicketCount", nodeIds)
+}
+
+// GetTicketCountByTxHash returns the number of remaining tickets of each of ticketIds.
+func (s *PublicPposAPI) GetTicketCountByTxHash(ctx context.Context, ticketIds []common.Hash, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.TicketPoolAddr, "GetTicketCountByTxHash", ticketIds)
+}
+
+// GetCandidateEpoch returns the ticket age of nodeId.
+func (s *PublicPposAPI) GetCandidateEpoch(ctx context.Context, nodeId discover.NodeID, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.TicketPoolAddr, "GetCandidateEpoch", nodeId)
+}
+
+// GetPoolRemainder returns the number of tickets left in the ticket pool.
+func (s *PublicPposAPI) GetPoolRemainder(ctx context.Context, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.TicketPoolAddr, "GetPoolRemainder")
+}
+
+// GetTicketPrice returns the current ticket price.
+func (s *PublicPposAPI) GetTicketPrice(ctx context.Context, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.TicketPoolAddr, "GetTicketPrice")
+}
+
+// GetTicketPriceHistory returns count of the latest ticket prices set by the pricing
+// epochs from the index start, oldest first, at most vm.MaxTicketPriceHistoryPage.
+func (s *PublicPposAPI) GetTicketPriceHistory(ctx context.Context, start, count uint32, blockNr rpc.BlockNumber) (json.RawMessage, error) {
+	return s.call(ctx, blockNr, common.TicketPoolAddr, "GetTicketPriceHistory", start, count)
+}
+
+// call executes the query name of the contract at address on the state of the
+// block blockNr, the contract returns its result encoded in JSON.
+func (s *PublicPposAPI) call(ctx context.Context, blockNr rpc.BlockNumber, address common.Address, name string, args ...interface{}) (json.RawMessage, error) {
+	input, err := vm.EncodeInput(name, args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.chain.Call(ctx, CallArgs{To: &address, Data: input}, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	data, err := vm.DecodeResult(output)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(data), nil
+}
+
+// sendTransaction sends a transaction from args.From calling the command name of
+// the contract at address, the gas is estimated if args doesn't set it.
+func (s *PublicPposAPI) sendTransaction(ctx context.Context, args SendTxArgs, address common.Address, name string, params ...interface{}) (common.Hash, error) {
+	input, err := vm.EncodeInput(name, params...)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	data := hexutil.Bytes(input)
+	args.To, args.Data, args.Input = &address, &data, nil
+	if args.Gas == nil {
+		callArgs := CallArgs{From: args.From, To: &address, Data: data}
+		if args.Value != nil {
+			callArgs.Value = *args.Value
+		}
+		gas, err := s.chain.EstimateGas(ctx, callArgs)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		args.Gas = &gas
+	}
+	return s.txPool.SendTransaction(ctx, args)
+}
@@ -25,12 +25,15 @@ var Modules = map[string]string{
 	"debug":      Debug_JS,
 	"eth":        Eth_JS,
 	"miner":      Miner_JS,
+	"mpc":        MPC_JS,
 	"net":        Net_JS,
 	"personal":   Personal_JS,
+	"ppos":       PPOS_JS,
 	"rpc":        RPC_JS,
 	"shh":        Shh_JS,
 	"swarmfs":    SWARMFS_JS,
 	"txpool":     TxPool_JS,
+	"vc":         VC_JS,
 }
 
 const Chequebook_JS = `
@@ -549,6 +552,31 @@ web3._extend({
 });
 `
 
+const MPC_JS = `
+web3._extend({
+	property: 'mpc',
+	methods: [
+		new web3._extend.Method({
+			name: 'task',
+			call: 'mpc_task',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'status',
+			call: 'mpc_status',
+			params: 1
+		}),
+	],
+	properties:
+	[
+		new web3._extend.Property({
+			name: 'tasks',
+			getter: 'mpc_tasks'
+		}),
+	]
+});
+`
+
 const Net_JS = `
 web3._extend({
 	property: 'net',
@@ -691,3 +719,198 @@ web3._extend({
 	]
 });
 `
+
+const VC_JS = `
+web3._extend({
+	property: 'vc',
+	methods: [
+		new web3._extend.Method({
+			name: 'task',
+			call: 'vc_task',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'status',
+			call: 'vc_status',
+			params: 1
+		}),
+	],
+	properties:
+	[
+		new web3._extend.Property({
+			name: 'tasks',
+			getter: 'vc_tasks'
+		}),
+	]
+});
+`
+
+const PPOS_JS = `
+web3._extend({
+	property: 'ppos',
+	methods: [
+		new web3._extend.Method({
+			name: 'candidateDeposit',
+			call: 'ppos_candidateDeposit',
+			params: 10,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null, null, null, null, null, null, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'candidateApplyWithdraw',
+			call: 'ppos_candidateApplyWithdraw',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'candidateWithdraw',
+			call: 'ppos_candidateWithdraw',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'setCandidateExtra',
+			call: 'ppos_setCandidateExtra',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'increaseDeposit',
+			call: 'ppos_increaseDeposit',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'updateCandidateInfo',
+			call: 'ppos_updateCandidateInfo',
+			params: 5,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'setCandidateSentries',
+			call: 'ppos_setCandidateSentries',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'setCandidateBlsKey',
+			call: 'ppos_setCandidateBlsKey',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'unjail',
+			call: 'ppos_unjail',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'voteTicket',
+			call: 'ppos_voteTicket',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'transferTicket',
+			call: 'ppos_transferTicket',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'redelegateTicket',
+			call: 'ppos_redelegateTicket',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidateNonce',
+			call: 'ppos_getCandidateNonce',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidateBlsKey',
+			call: 'ppos_getCandidateBlsKey',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidateSentries',
+			call: 'ppos_getCandidateSentries',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidatePendingFee',
+			call: 'ppos_getCandidatePendingFee',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidateLiveness',
+			call: 'ppos_getCandidateLiveness',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidateWithdrawInfos',
+			call: 'ppos_getCandidateWithdrawInfos',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidateDetails',
+			call: 'ppos_getCandidateDetails',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidateList',
+			call: 'ppos_getCandidateList',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getVerifiersList',
+			call: 'ppos_getVerifiersList',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidateTicketCount',
+			call: 'ppos_getCandidateTicketCount',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTicketCountByTxHash',
+			call: 'ppos_getTicketCountByTxHash',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidateEpoch',
+			call: 'ppos_getCandidateEpoch',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getPoolRemainder',
+			call: 'ppos_getPoolRemainder',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTicketPrice',
+			call: 'ppos_getTicketPrice',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTicketPriceHistory',
+			call: 'ppos_getTicketPriceHistory',
+			params: 3,
+			inputFormatter: [null, null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+	]
+});
+`
@@ -102,6 +102,14 @@ func (b *LesApiBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*typ
 }
 
 func (b *LesApiBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmCfg vm.Config) (*vm.EVM, func() error, error) {
+	// The ppos contracts read the ppos storage, which the light state lacks
+	if to := msg.To(); to != nil && (*to == common.CandidatePoolAddr || *to == common.TicketPoolAddr) {
+		storage, err := light.GetPposStorage(ctx, b.eth.odr, header)
+		if err != nil {
+			return nil, nil, err
+		}
+		state.SetPPOSCache(storage)
+	}
 	state.SetBalance(msg.From(), math.MaxBig256)
 	context := core.NewEVMContext(msg, header, b.eth.blockchain, nil)
 	return vm.NewEVM(context, state, b.eth.chainConfig, vmCfg), state.Error, nil
@@ -30,6 +30,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common/mclock"
 	"github.com/PlatONnetwork/PlatON-Go/consensus"
 	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/ppos_storage"
 	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
@@ -39,10 +40,12 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/light"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/p2p"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discv5"
 	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"github.com/PlatONnetwork/PlatON-Go/trie"
+	"github.com/hashicorp/golang-lru"
 )
 
 const (
@@ -51,6 +54,9 @@ const (
 
 	ethVersion = 63 // equivalent eth version for the downloader
 
+	pposStorageUnit       = softResponseLimit / MaxPposStorageUnits // Size of encoded ppos storage charged as one request
+	pposStorageCacheLimit = 16                                      // Amount of encoded ppos storages kept for serving
+
 	MaxHeaderFetch           = 192 // Amount of block headers to be fetched per retrieval request
 	MaxBodyFetch             = 32  // Amount of block bodies to be fetched per retrieval request
 	MaxReceiptFetch          = 128 // Amount of transaction receipts to allow fetching per request
@@ -59,6 +65,10 @@ const (
 	MaxHelperTrieProofsFetch = 64  // Amount of merkle proofs to be fetched per retrieval request
 	MaxTxSend                = 64  // Amount of transactions to be send per request
 	MaxTxStatus              = 256 // Amount of transactions to queried per request
+	MaxValidatorsFetch       = 64  // Amount of validator sets to be fetched per retrieval request
+	MaxPposStorageFetch      = 4   // Amount of ppos storages to be fetched per retrieval request
+	MaxPposStorageUnits      = 32  // Amount of ppos storage units charged per retrieval request
+	MaxSystemLogsFetch       = 128 // Amount of block system logs to be fetched per retrieval request
 
 	disableClientRemovePeer = false
 )
@@ -100,6 +110,7 @@ type ProtocolManager struct {
 	server      *LesServer
 	serverPool  *serverPool
 	clientPool  *freeClientPool
+	engine      consensus.Engine
 	lesTopic    discv5.Topic
 	reqDist     *requestDistributor
 	retriever   *retrieveManager
@@ -109,6 +120,8 @@ type ProtocolManager struct {
 	peers      *peerSet
 	maxPeers   int
 
+	pposStorages *lru.Cache // Encoded ppos storages of the recently served blocks
+
 	eventMux *event.TypeMux
 
 	// channels for fetcher, syncer, txsyncLoop
@@ -137,12 +150,14 @@ func NewProtocolManager(chainConfig *params.ChainConfig, indexerConfig *light.In
 		txpool:      txpool,
 		txrelay:     txrelay,
 		serverPool:  serverPool,
+		engine:      engine,
 		peers:       peers,
 		newPeerCh:   make(chan *peer),
 		quitSync:    quitSync,
 		wg:          wg,
 		noMorePeers: make(chan struct{}),
 	}
+	manager.pposStorages, _ = lru.New(pposStorageCacheLimit)
 	if odr != nil {
 		manager.retriever = odr.retriever
 		manager.reqDist = odr.retriever.dist
@@ -323,7 +338,7 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	}
 }
 
-var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg}
+var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg, GetValidatorsMsg, GetPposStorageMsg, GetSystemLogsMsg}
 
 // handleMsg is invoked whenever an inbound message is received from a remote
 // peer. The remote connection is torn down upon returning any error.
@@ -682,6 +697,179 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
 		return p.SendReceiptsRLP(req.ReqID, bv, receipts)
 
+	case GetValidatorsMsg:
+		p.Log().Trace("Received validators request")
+		// Decode the retrieval message
+		var req struct {
+			ReqID  uint64
+			Hashes []common.Hash
+		}
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqCnt := len(req.Hashes)
+		if reject(uint64(reqCnt), MaxValidatorsFetch) {
+			return errResp(ErrRequestRejected, "")
+		}
+		// Gather the consensus nodes of the rounds following the switch blocks,
+		// an unknown round is answered by an empty set
+		validators := make([][]discover.NodeID, 0, reqCnt)
+		bft, _ := pm.engine.(consensus.Bft)
+		for _, hash := range req.Hashes {
+			var nodes []discover.NodeID
+			if number := rawdb.ReadHeaderNumber(pm.chainDb, hash); number != nil && bft != nil {
+				nodes = bft.ConsensusNodes(new(big.Int).SetUint64(*number), hash, new(big.Int).SetUint64(*number+1))
+			}
+			validators = append(validators, nodes)
+		}
+		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
+		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		return p.SendValidators(req.ReqID, bv, validators)
+
+	case ValidatorsMsg:
+		if pm.odr == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+
+		p.Log().Trace("Received validators response")
+		// A batch of validator sets arrived to one of our previous requests
+		var resp struct {
+			ReqID, BV uint64
+			Data      [][]discover.NodeID
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		deliverMsg = &Msg{
+			MsgType: MsgValidators,
+			ReqID:   resp.ReqID,
+			Obj:     resp.Data,
+		}
+
+	case GetPposStorageMsg:
+		p.Log().Trace("Received ppos storage request")
+		// Decode the retrieval message
+		var req struct {
+			ReqID  uint64
+			Hashes []common.Hash
+		}
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqCnt := len(req.Hashes)
+		if reqCnt > MaxPposStorageFetch {
+			return errResp(ErrRequestRejected, "")
+		}
+		// Gather the ppos storages until the fetch or network limits is reached,
+		// only the storages of the recent blocks are kept in the temp
+		var (
+			bytes    int
+			units    int
+			storages [][]byte
+		)
+		for _, hash := range req.Hashes {
+			if bytes >= softResponseLimit {
+				break
+			}
+			data := pm.pposStorage(hash)
+			storages = append(storages, data)
+			bytes += len(data)
+			units += (len(data) + pposStorageUnit - 1) / pposStorageUnit
+		}
+		// The storages are charged by their size, a missing one as a single unit
+		if units < reqCnt {
+			units = reqCnt
+		}
+		if units > MaxPposStorageUnits {
+			units = MaxPposStorageUnits
+		}
+		if reject(uint64(units), MaxPposStorageUnits) {
+			return errResp(ErrRequestRejected, "")
+		}
+		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(units)*costs.reqCost)
+		pm.server.fcCostStats.update(msg.Code, uint64(units), rcost)
+		return p.SendPposStorages(req.ReqID, bv, storages)
+
+	case PposStorageMsg:
+		if pm.odr == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+
+		p.Log().Trace("Received ppos storage response")
+		// A batch of ppos storages arrived to one of our previous requests
+		var resp struct {
+			ReqID, BV uint64
+			Data      [][]byte
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		deliverMsg = &Msg{
+			MsgType: MsgPposStorage,
+			ReqID:   resp.ReqID,
+			Obj:     resp.Data,
+		}
+
+	case GetSystemLogsMsg:
+		p.Log().Trace("Received system logs request")
+		// Decode the retrieval message
+		var req struct {
+			ReqID  uint64
+			Hashes []common.Hash
+		}
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqCnt := len(req.Hashes)
+		if reject(uint64(reqCnt), MaxSystemLogsFetch) {
+			return errResp(ErrRequestRejected, "")
+		}
+		// Gather the system logs until the fetch or network limits is reached,
+		// a block without system logs is answered with an empty list
+		var (
+			bytes int
+			logs  [][]*types.LogForStorage
+		)
+		for _, hash := range req.Hashes {
+			if bytes >= softResponseLimit {
+				break
+			}
+			var results []*types.LogForStorage
+			if number := rawdb.ReadHeaderNumber(pm.chainDb, hash); number != nil {
+				for _, l := range rawdb.ReadSystemLogs(pm.chainDb, hash, *number) {
+					results = append(results, (*types.LogForStorage)(l))
+					bytes += len(l.Data) + len(l.Topics)*common.HashLength
+				}
+			}
+			logs = append(logs, results)
+		}
+		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
+		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		return p.SendSystemLogs(req.ReqID, bv, logs)
+
+	case SystemLogsMsg:
+		if pm.odr == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+
+		p.Log().Trace("Received system logs response")
+		// A batch of system logs arrived to one of our previous requests
+		var resp struct {
+			ReqID, BV uint64
+			Logs      [][]*types.LogForStorage
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		deliverMsg = &Msg{
+			MsgType: MsgSystemLogs,
+			ReqID:   resp.ReqID,
+			Obj:     resp.Logs,
+		}
+
 	case ReceiptsMsg:
 		if pm.odr == nil {
 			return errResp(ErrUnexpectedResponse, "")
@@ -1157,6 +1345,27 @@ func (pm *ProtocolManager) getHelperTrieAuxData(req HelperTrieReq) []byte {
 	return nil
 }
 
+// pposStorage returns the encoded ppos storage of the given block, or nil if
+// the block is unknown or its storage isn't kept in the temp anymore
+func (pm *ProtocolManager) pposStorage(hash common.Hash) []byte {
+	if data, ok := pm.pposStorages.Get(hash); ok {
+		return data.([]byte)
+	}
+	bc, _ := pm.blockchain.(*core.BlockChain)
+	number := rawdb.ReadHeaderNumber(pm.chainDb, hash)
+	if number == nil || bc == nil || bc.PPosTemp() == nil {
+		return nil
+	}
+	storage := bc.PPosTemp().BuildPposCache(new(big.Int).SetUint64(*number), hash)
+	data, err := ppos_storage.EncodePposStorage(storage)
+	if err != nil {
+		log.Error("Failed to encode ppos storage", "number", *number, "hash", hash, "err", err)
+		return nil
+	}
+	pm.pposStorages.Add(hash, data)
+	return data
+}
+
 func (pm *ProtocolManager) txStatus(hashes []common.Hash) []txStatus {
 	stats := make([]txStatus, len(hashes))
 	for i, stat := range pm.txpool.Status(hashes) {
@@ -88,6 +88,9 @@ const (
 	MsgProofsV2
 	MsgHeaderProofs
 	MsgHelperTrieProofs
+	MsgValidators
+	MsgPposStorage
+	MsgSystemLogs
 )
 
 // Msg encodes a LES message that delivers reply data for a request
@@ -22,14 +22,17 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/ppos_storage"
 	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/light"
 	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"github.com/PlatONnetwork/PlatON-Go/trie"
 )
@@ -45,6 +48,9 @@ var (
 	errCHTHashMismatch     = errors.New("cht hash mismatch")
 	errCHTNumberMismatch   = errors.New("cht number mismatch")
 	errUselessNodes        = errors.New("useless nodes in merkle proof nodeset")
+	errNoValidators        = errors.New("no validators in reply")
+	errPposHashMismatch    = errors.New("ppos storage hash mismatch")
+	errSystemLogsMismatch  = errors.New("system logs don't match the header")
 )
 
 type LesOdrRequest interface {
@@ -68,6 +74,12 @@ func LesRequest(req light.OdrRequest) LesOdrRequest {
 		return (*ChtRequest)(r)
 	case *light.BloomRequest:
 		return (*BloomRequest)(r)
+	case *light.ValidatorsRequest:
+		return (*ValidatorsRequest)(r)
+	case *light.PposStorageRequest:
+		return (*PposStorageRequest)(r)
+	case *light.SystemLogsRequest:
+		return (*SystemLogsRequest)(r)
 	default:
 		return nil
 	}
@@ -178,6 +190,161 @@ func (r *ReceiptsRequest) Validate(db ethdb.Database, msg *Msg) error {
 	return nil
 }
 
+// ValidatorsRequest is the ODR request type for the consensus nodes of the round
+// following a switch block
+type ValidatorsRequest light.ValidatorsRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *ValidatorsRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetValidatorsMsg, 1)
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *ValidatorsRequest) CanSend(peer *peer) bool {
+	return peer.version >= lpv3 && peer.HasBlock(r.Hash, r.Number)
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *ValidatorsRequest) Request(reqID uint64, peer *peer) error {
+	peer.Log().Debug("Requesting validators", "hash", r.Hash)
+	return peer.RequestValidators(reqID, r.GetCost(peer), []common.Hash{r.Hash})
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *ValidatorsRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating validators", "hash", r.Hash)
+
+	// Ensure we have a correct message with a single validator set
+	if msg.MsgType != MsgValidators {
+		return errInvalidMessageType
+	}
+	validators := msg.Obj.([][]discover.NodeID)
+	if len(validators) != 1 {
+		return errInvalidEntryCount
+	}
+	if len(validators[0]) == 0 {
+		return errNoValidators
+	}
+	header := rawdb.ReadHeader(db, r.Hash, r.Number)
+	if header == nil {
+		return errHeaderUnavailable
+	}
+	// Before the witness commitment fork only the presence of the set is checked
+	if err := (*light.ValidatorsRequest)(r).Verify(header, validators[0]); err != nil {
+		return err
+	}
+	r.Validators = validators[0]
+	return nil
+}
+
+// PposStorageRequest is the ODR request type for the ppos storage of a block
+type PposStorageRequest light.PposStorageRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest). The storage is charged
+// by its size which isn't known in advance, so the highest charge is assumed.
+func (r *PposStorageRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetPposStorageMsg, MaxPposStorageUnits)
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *PposStorageRequest) CanSend(peer *peer) bool {
+	return peer.version >= lpv3 && peer.HasBlock(r.Hash, r.Number)
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *PposStorageRequest) Request(reqID uint64, peer *peer) error {
+	peer.Log().Debug("Requesting ppos storage", "hash", r.Hash)
+	return peer.RequestPposStorages(reqID, r.GetCost(peer), []common.Hash{r.Hash})
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *PposStorageRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating ppos storage", "hash", r.Hash)
+
+	// Ensure we have a correct message with a single ppos storage
+	if msg.MsgType != MsgPposStorage {
+		return errInvalidMessageType
+	}
+	storages := msg.Obj.([][]byte)
+	if len(storages) != 1 {
+		return errInvalidEntryCount
+	}
+	// Verify the storage against the hash committed in the state
+	storage, err := ppos_storage.DecodePposStorage(storages[0])
+	if err != nil {
+		return err
+	}
+	hash, err := storage.CalculateHash(new(big.Int).SetUint64(r.Number), r.Hash)
+	if err != nil {
+		return err
+	}
+	if hash != r.Root {
+		return errPposHashMismatch
+	}
+	r.Data = storages[0]
+	return nil
+}
+
+// SystemLogsRequest is the ODR request type for the system logs of a block
+type SystemLogsRequest light.SystemLogsRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *SystemLogsRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetSystemLogsMsg, 1)
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *SystemLogsRequest) CanSend(peer *peer) bool {
+	return peer.version >= lpv3 && peer.HasBlock(r.Hash, r.Number)
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *SystemLogsRequest) Request(reqID uint64, peer *peer) error {
+	peer.Log().Debug("Requesting system logs", "hash", r.Hash)
+	return peer.RequestSystemLogs(reqID, r.GetCost(peer), []common.Hash{r.Hash})
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *SystemLogsRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating system logs", "hash", r.Hash)
+
+	// Ensure we have a correct message with the system logs of a single block
+	if msg.MsgType != MsgSystemLogs {
+		return errInvalidMessageType
+	}
+	blockLogs := msg.Obj.([][]*types.LogForStorage)
+	if len(blockLogs) != 1 {
+		return errInvalidEntryCount
+	}
+	logs := make([]*types.Log, len(blockLogs[0]))
+	for i, l := range blockLogs[0] {
+		if l.BlockHash != r.Hash || l.BlockNumber != r.Number || l.TxHash != types.SystemLogTxHash {
+			return errSystemLogsMismatch
+		}
+		logs[i] = (*types.Log)(l)
+	}
+	// The system logs aren't part of a trie, the header bloom of the receipts
+	// and the system logs is checked instead
+	header := rawdb.ReadHeader(db, r.Hash, r.Number)
+	if header == nil {
+		return errHeaderUnavailable
+	}
+	if header.Bloom != types.CreateBlockBloom(r.Receipts, logs) {
+		return errSystemLogsMismatch
+	}
+	r.Logs = logs
+	return nil
+}
+
 type ProofReq struct {
 	BHash       common.Hash
 	AccKey, Key []byte
@@ -193,7 +360,7 @@ func (r *TrieRequest) GetCost(peer *peer) uint64 {
 	switch peer.version {
 	case lpv1:
 		return peer.GetRequestCost(GetProofsV1Msg, 1)
-	case lpv2:
+	case lpv2, lpv3:
 		return peer.GetRequestCost(GetProofsV2Msg, 1)
 	default:
 		panic(nil)
@@ -353,7 +520,7 @@ func (r *ChtRequest) GetCost(peer *peer) uint64 {
 	switch peer.version {
 	case lpv1:
 		return peer.GetRequestCost(GetHeaderProofsMsg, 1)
-	case lpv2:
+	case lpv2, lpv3:
 		return peer.GetRequestCost(GetHelperTrieProofsMsg, 1)
 	default:
 		panic(nil)
@@ -389,7 +556,7 @@ func (r *ChtRequest) Request(reqID uint64, peer *peer) error {
 		// convert HelperTrie request to old CHT request
 		reqsV1 = ChtReq{ChtNum: (req.TrieIdx + 1) * (r.Config.ChtSize / r.Config.PairChtSize), BlockNum: blockNum, FromLevel: req.FromLevel}
 		return peer.RequestHelperTrieProofs(reqID, r.GetCost(peer), []ChtReq{reqsV1})
-	case lpv2:
+	case lpv2, lpv3:
 		return peer.RequestHelperTrieProofs(reqID, r.GetCost(peer), []HelperTrieReq{req})
 	default:
 		panic(nil)
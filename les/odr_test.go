@@ -26,6 +26,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/math"
 	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/ppos_storage"
 	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
@@ -160,6 +161,50 @@ func odrContractCall(ctx context.Context, db ethdb.Database, config *params.Chai
 }
 
 // testOdr tests odr requests whose validation guaranteed by block headers.
+func TestOdrPposStorageValidate(t *testing.T) {
+	storage := ppos_storage.NewPPOS_storage()
+	storage.SetTicketPrice(big.NewInt(90), big.NewInt(100))
+	data, err := ppos_storage.EncodePposStorage(storage)
+	if err != nil {
+		t.Fatalf("failed to encode the ppos storage: %v", err)
+	}
+	hash := common.HexToHash("0x01")
+	root, err := storage.CalculateHash(big.NewInt(1), hash)
+	if err != nil {
+		t.Fatalf("failed to hash the ppos storage: %v", err)
+	}
+	// a storage differing in the price only
+	tampered := storage.Copy()
+	tampered.SetTicketPrice(big.NewInt(1), big.NewInt(100))
+	tamperedData, err := ppos_storage.EncodePposStorage(tampered)
+	if err != nil {
+		t.Fatalf("failed to encode the tampered ppos storage: %v", err)
+	}
+
+	tests := []struct {
+		root  common.Hash
+		reply [][]byte
+		err   error
+	}{
+		{root, [][]byte{data}, nil},
+		{root, [][]byte{tamperedData}, errPposHashMismatch},
+		{root, [][]byte{nil}, errPposHashMismatch},
+		{root, [][]byte{data, data}, errInvalidEntryCount},
+		{common.Hash{}, [][]byte{nil}, nil},
+		{common.Hash{}, [][]byte{data}, errPposHashMismatch},
+	}
+	for i, tt := range tests {
+		r := &PposStorageRequest{Hash: hash, Number: 1, Root: tt.root}
+		if err := r.Validate(ethdb.NewMemDatabase(), &Msg{MsgType: MsgPposStorage, Obj: tt.reply}); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+			continue
+		}
+		if tt.err == nil && !bytes.Equal(r.Data, tt.reply[0]) {
+			t.Errorf("test %d: data mismatch: have %x, want %x", i, r.Data, tt.reply[0])
+		}
+	}
+}
+
 func testOdr(t *testing.T, protocol int, expFail uint64, fn odrTestFn) {
 	// Assemble the test environment
 	server, client, tearDown := newClientServerEnv(t, 4, protocol, nil, true)
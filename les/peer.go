@@ -31,6 +31,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/les/flowcontrol"
 	"github.com/PlatONnetwork/PlatON-Go/light"
 	"github.com/PlatONnetwork/PlatON-Go/p2p"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 )
 
@@ -231,6 +232,24 @@ func (p *peer) SendHelperTrieProofs(reqID, bv uint64, resp HelperTrieResps) erro
 	return sendResponse(p.rw, HelperTrieProofsMsg, reqID, bv, resp)
 }
 
+// SendValidators sends the consensus nodes of the rounds following the requested
+// switch blocks.
+func (p *peer) SendValidators(reqID, bv uint64, validators [][]discover.NodeID) error {
+	return sendResponse(p.rw, ValidatorsMsg, reqID, bv, validators)
+}
+
+// SendPposStorages sends a batch of encoded ppos storages, corresponding to the
+// blocks requested.
+func (p *peer) SendPposStorages(reqID, bv uint64, storages [][]byte) error {
+	return sendResponse(p.rw, PposStorageMsg, reqID, bv, storages)
+}
+
+// SendSystemLogs sends a batch of block system logs, corresponding to the blocks
+// requested.
+func (p *peer) SendSystemLogs(reqID, bv uint64, logs [][]*types.LogForStorage) error {
+	return sendResponse(p.rw, SystemLogsMsg, reqID, bv, logs)
+}
+
 // SendTxStatus sends a batch of transaction status records, corresponding to the ones requested.
 func (p *peer) SendTxStatus(reqID, bv uint64, stats []txStatus) error {
 	return sendResponse(p.rw, TxStatusMsg, reqID, bv, stats)
@@ -270,13 +289,33 @@ func (p *peer) RequestReceipts(reqID, cost uint64, hashes []common.Hash) error {
 	return sendRequest(p.rw, GetReceiptsMsg, reqID, cost, hashes)
 }
 
+// RequestValidators fetches the consensus nodes of the rounds following a batch of
+// switch blocks from a remote node.
+func (p *peer) RequestValidators(reqID, cost uint64, hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of validators", "count", len(hashes))
+	return sendRequest(p.rw, GetValidatorsMsg, reqID, cost, hashes)
+}
+
+// RequestPposStorages fetches the ppos storages of a batch of blocks from a remote
+// node.
+func (p *peer) RequestPposStorages(reqID, cost uint64, hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of ppos storages", "count", len(hashes))
+	return sendRequest(p.rw, GetPposStorageMsg, reqID, cost, hashes)
+}
+
+// RequestSystemLogs fetches the system logs of a batch of blocks from a remote node.
+func (p *peer) RequestSystemLogs(reqID, cost uint64, hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of system logs", "count", len(hashes))
+	return sendRequest(p.rw, GetSystemLogsMsg, reqID, cost, hashes)
+}
+
 // RequestProofs fetches a batch of merkle proofs from a remote node.
 func (p *peer) RequestProofs(reqID, cost uint64, reqs []ProofReq) error {
 	p.Log().Debug("Fetching batch of proofs", "count", len(reqs))
 	switch p.version {
 	case lpv1:
 		return sendRequest(p.rw, GetProofsV1Msg, reqID, cost, reqs)
-	case lpv2:
+	case lpv2, lpv3:
 		return sendRequest(p.rw, GetProofsV2Msg, reqID, cost, reqs)
 	default:
 		panic(nil)
@@ -293,7 +332,7 @@ func (p *peer) RequestHelperTrieProofs(reqID, cost uint64, data interface{}) err
 		}
 		p.Log().Debug("Fetching batch of header proofs", "count", len(reqs))
 		return sendRequest(p.rw, GetHeaderProofsMsg, reqID, cost, reqs)
-	case lpv2:
+	case lpv2, lpv3:
 		reqs, ok := data.([]HelperTrieReq)
 		if !ok {
 			return errInvalidHelpTrieReq
@@ -317,7 +356,7 @@ func (p *peer) SendTxs(reqID, cost uint64, txs types.Transactions) error {
 	switch p.version {
 	case lpv1:
 		return p2p.Send(p.rw, SendTxMsg, txs) // old message format does not include reqID
-	case lpv2:
+	case lpv2, lpv3:
 		return sendRequest(p.rw, SendTxV2Msg, reqID, cost, txs)
 	default:
 		panic(nil)
@@ -0,0 +1,249 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/consensus"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+// cbftRequestTimeout is the time to wait for the confirmations and the next
+// validators of a switch block.
+const cbftRequestTimeout = 10 * time.Second
+
+var (
+	ErrUnknownValidators = errors.New("unknown validators of the round")
+	ErrNotValidator      = errors.New("header not sealed by a validator of its round")
+	ErrNotInTurn         = errors.New("header out of the turn of its producer")
+	ErrWitnessMismatch   = errors.New("validators don't match the witness hash of the switch block")
+
+	validatorsPrefix = []byte("cbftValidators-") // validatorsPrefix + switch block hash -> consensus nodes of the next round
+)
+
+// GetValidators reads the consensus nodes of the round following the switch
+// block hash from the database, nil if they are unknown.
+func GetValidators(db ethdb.Database, hash common.Hash) []discover.NodeID {
+	data, _ := db.Get(append(validatorsPrefix, hash.Bytes()...))
+	if len(data) == 0 {
+		return nil
+	}
+	var nodes []discover.NodeID
+	if err := rlp.DecodeBytes(data, &nodes); err != nil {
+		log.Error("Invalid validators RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return nodes
+}
+
+// StoreValidators writes the consensus nodes of the round following the switch
+// block hash into the database.
+func StoreValidators(db ethdb.Database, hash common.Hash, nodes []discover.NodeID) {
+	data, err := rlp.EncodeToBytes(nodes)
+	if err != nil {
+		log.Crit("Failed to RLP encode validators", "err", err)
+	}
+	db.Put(append(validatorsPrefix, hash.Bytes()...), data)
+}
+
+// isSwitchBlock reports whether the block of number is the last one of its round.
+func isSwitchBlock(number uint64) bool {
+	return number > 0 && number%common.BaseSwitchWitness == 0
+}
+
+// roundOf returns the round of the block of number, round k holds the blocks from
+// k*BaseSwitchWitness+1 to (k+1)*BaseSwitchWitness.
+func roundOf(number uint64) uint64 {
+	if number == 0 {
+		return 0
+	}
+	return (number - 1) / common.BaseSwitchWitness
+}
+
+// cbftVerifier does the checks of the CBFT headers which the engine leaves to the
+// full nodes: every header has to be sealed by a consensus node of its round in
+// its turn, and every switch block has to be confirmed by its round before the
+// chain moves on to the next one. Only the confirmations of the switch blocks are
+// checked, those of the other blocks are not served to the light clients.
+//
+// The consensus nodes of the next round are retrieved from a server once the switch
+// block is confirmed. Since the witness commitment fork they are checked against
+// the commitment of the switch block, before it they are trusted as far as the
+// server is. The first round is set by the genesis or by the trusted checkpoint.
+type cbftVerifier struct {
+	chain   *LightChain
+	config  *params.CbftConfig
+	genesis []discover.NodeID // Consensus nodes of the first round
+
+	cpRound uint64            // Round of the block following the trusted checkpoint
+	cpNodes []discover.NodeID // Consensus nodes of cpRound, nil without checkpoint
+}
+
+func newCbftVerifier(chain *LightChain, config *params.CbftConfig, cp *params.TrustedCheckpoint) *cbftVerifier {
+	v := &cbftVerifier{chain: chain, config: config}
+	for _, node := range config.InitialNodes {
+		v.genesis = append(v.genesis, node.ID)
+	}
+	if cp != nil && len(cp.Validators) > 0 {
+		v.cpRound = roundOf((cp.SectionIndex + 1) * chain.indexerConfig.ChtSize)
+		v.cpNodes = cp.Validators
+	}
+	return v
+}
+
+// segmentEnd returns the end of the segment of chain beginning at start, that is
+// the index following the first switch block or the length of chain. The headers
+// of a segment belong to the same round.
+func segmentEnd(chain []*types.Header, start int) int {
+	for i := start; i < len(chain); i++ {
+		if isSwitchBlock(chain[i].Number.Uint64()) {
+			return i + 1
+		}
+	}
+	return len(chain)
+}
+
+// validators returns the consensus nodes of the round of the block following
+// parent, verifying the switch block of the previous round if needed.
+func (v *cbftVerifier) validators(parent *types.Header) ([]discover.NodeID, error) {
+	round := roundOf(parent.Number.Uint64() + 1)
+	if v.cpNodes != nil && round == v.cpRound {
+		return v.cpNodes, nil
+	}
+	if round == 0 {
+		return v.genesis, nil
+	}
+	number, hash := round*common.BaseSwitchWitness, parent.Hash()
+	if parent.Number.Uint64() != number {
+		maxNonCanonical := uint64(common.BaseSwitchWitness)
+		hash, _ = v.chain.GetAncestor(hash, parent.Number.Uint64(), parent.Number.Uint64()-number, &maxNonCanonical)
+	}
+	header := v.chain.GetHeader(hash, number)
+	if header == nil {
+		return nil, ErrUnknownValidators
+	}
+	if nodes := GetValidators(v.chain.chainDb, hash); nodes != nil {
+		return nodes, nil
+	}
+	// The switch block was inserted without being verified, most likely because
+	// the verification was interrupted
+	return v.verifySwitch(header)
+}
+
+// verifyProducers checks that the headers of a segment are sealed by the consensus
+// nodes of their round in their turns. It returns the index of the first invalid
+// header.
+func (v *cbftVerifier) verifyProducers(chain []*types.Header) (int, error) {
+	parent := v.chain.GetHeader(chain[0].ParentHash, chain[0].Number.Uint64()-1)
+	if parent == nil {
+		return 0, consensus.ErrUnknownAncestor
+	}
+	nodes, err := v.validators(parent)
+	if err != nil {
+		return 0, err
+	}
+	config, err := v.cbftConfig(parent)
+	if err != nil {
+		return 0, err
+	}
+	startEpoch := v.chain.Genesis().Time().Int64() / 1000 * 1000
+	for i, header := range chain {
+		producer, err := cbft.BlockProducer(header)
+		if err != nil {
+			return i, err
+		}
+		idx := nodeIndex(nodes, producer)
+		if idx < 0 {
+			log.Warn("Header not sealed by a validator", "number", header.Number, "hash", header.Hash(), "producer", producer.TerminalString())
+			return i, ErrNotValidator
+		}
+		if !cbft.ProducerInTurn(header, config, startEpoch, idx, len(nodes)) {
+			log.Warn("Header out of the turn of its producer", "number", header.Number, "hash", header.Hash(), "producer", producer.TerminalString())
+			return i, ErrNotInTurn
+		}
+	}
+	return 0, nil
+}
+
+// cbftConfig returns the CBFT config of the blocks following parent, the governed
+// parameters are read from the state of parent.
+func (v *cbftVerifier) cbftConfig(parent *types.Header) (*params.CbftConfig, error) {
+	if !v.chain.Config().IsGovernance(parent.Number) {
+		return v.config, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cbftRequestTimeout)
+	defer cancel()
+
+	statedb := NewState(ctx, parent, v.chain.odr)
+	config := vm.GovernedCbftConfig(statedb, v.config)
+	return config, statedb.Error()
+}
+
+// verifySwitch checks the confirmations of an inserted switch block against the
+// consensus nodes of its round, and retrieves those of the next round.
+func (v *cbftVerifier) verifySwitch(header *types.Header) ([]discover.NodeID, error) {
+	hash, number := header.Hash(), header.Number.Uint64()
+	parent := v.chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	nodes, err := v.validators(parent)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cbftRequestTimeout)
+	defer cancel()
+
+	body, err := GetBody(ctx, v.chain.odr, hash, number)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		aggregate *types.BlockConfirmAggregate
+		statedb   *state.StateDB
+	)
+	if len(body.Aggregate) > 0 {
+		aggregate = body.Aggregate[0]
+		statedb = NewState(ctx, parent, v.chain.odr)
+	}
+	if err := cbft.VerifyConfirmations(header, body.Signatures, aggregate, nodes, statedb); err != nil {
+		log.Warn("Invalid confirmations of a switch block", "number", number, "hash", hash, "err", err)
+		return nil, err
+	}
+	req := &ValidatorsRequest{Hash: hash, Number: number}
+	req.Committed = v.chain.Config().IsWitnessCommit(header.Number)
+	if err := v.chain.odr.Retrieve(ctx, req); err != nil {
+		return nil, err
+	}
+	log.Debug("Verified switch block", "number", number, "hash", hash, "validators", len(req.Validators))
+	return req.Validators, nil
+}
+
+// Verify checks validators against the commitment of the switch block header of
+// the request, if it holds one.
+func (req *ValidatorsRequest) Verify(header *types.Header, validators []discover.NodeID) error {
+	if req.Committed && types.WitnessHash(validators) != types.HeaderWitnessHash(header) {
+		return ErrWitnessMismatch
+	}
+	return nil
+}
+
+// nodeIndex returns the index of id in nodes, -1 if it's not there.
+func nodeIndex(nodes []discover.NodeID, id discover.NodeID) int {
+	for i, node := range nodes {
+		if node == id {
+			return i
+		}
+	}
+	return -1
+}
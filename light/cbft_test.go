@@ -0,0 +1,202 @@
+package light
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft"
+	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+)
+
+// cbftTestOdr serves the validators of the next round from next.
+type cbftTestOdr struct {
+	dummyOdr
+	next []discover.NodeID
+}
+
+func (odr *cbftTestOdr) Retrieve(ctx context.Context, req OdrRequest) error {
+	if req, ok := req.(*ValidatorsRequest); ok {
+		if err := req.Verify(rawdb.ReadHeader(odr.db, req.Hash, req.Number), odr.next); err != nil {
+			return err
+		}
+		req.Validators = odr.next
+		req.StoreResult(odr.db)
+		return nil
+	}
+	return ErrNoPeers
+}
+
+func newTestKeys(n int) ([]*ecdsa.PrivateKey, []discover.NodeID) {
+	keys := make([]*ecdsa.PrivateKey, n)
+	ids := make([]discover.NodeID, n)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		ids[i] = discover.PubkeyID(&keys[i].PublicKey)
+	}
+	return keys, ids
+}
+
+// makeCbftHeaderChain creates a chain of headers rooted at parent, the header of
+// number n is sealed by producer(n) a second after its parent.
+func makeCbftHeaderChain(parent *types.Header, n int, producer func(uint64) *ecdsa.PrivateKey) []*types.Header {
+	headers := make([]*types.Header, n)
+	for i := range headers {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			GasLimit:   parent.GasLimit,
+			Time:       new(big.Int).Add(parent.Time, big.NewInt(1000)),
+			Extra:      make([]byte, 32+65),
+		}
+		sig, _ := crypto.Sign(header.SealHash().Bytes(), producer(header.Number.Uint64()))
+		copy(header.Extra[32:], sig)
+		headers[i], parent = header, header
+	}
+	return headers
+}
+
+// commitWitnesses makes the header at index of chain commit to nodes, the headers
+// following it are sealed again by producer(n).
+func commitWitnesses(chain []*types.Header, index int, nodes []discover.NodeID, producer func(uint64) *ecdsa.PrivateKey) {
+	header := types.CopyHeader(chain[index])
+	hash := types.WitnessHash(nodes)
+	copy(header.Extra, hash[:])
+	sig, _ := crypto.Sign(header.SealHash().Bytes(), producer(header.Number.Uint64()))
+	copy(header.Extra[32:], sig)
+	chain[index] = header
+	copy(chain[index+1:], makeCbftHeaderChain(header, len(chain)-index-1, producer))
+}
+
+// writeConfirmations stores the body of header with the confirmations of signers.
+func writeConfirmations(db ethdb.Database, header *types.Header, signers []*ecdsa.PrivateKey) {
+	body := new(types.Body)
+	for _, key := range signers {
+		sig, _ := crypto.Sign(header.SealHash().Bytes(), key)
+		body.Signatures = append(body.Signatures, common.NewBlockConfirmSign(sig))
+	}
+	rawdb.WriteBody(db, header.Hash(), header.Number.Uint64(), body)
+}
+
+func newCbftTestChain(t *testing.T, validators, next []discover.NodeID) (*LightChain, *types.Header) {
+	config := *params.AllCbftProtocolChanges
+	config.Cbft = &params.CbftConfig{Duration: 1}
+	for _, id := range validators {
+		config.Cbft.InitialNodes = append(config.Cbft.InitialNodes, discover.Node{ID: id})
+	}
+	db := ethdb.NewMemDatabase()
+	// the headers are sealed in the middle of the one second turns of the validators
+	genesis := (&core.Genesis{Config: &config, GasLimit: params.GenesisGasLimit, Timestamp: 500}).MustCommit(db)
+
+	chain, err := NewLightChain(&cbftTestOdr{dummyOdr: dummyOdr{db: db, indexerConfig: TestClientIndexerConfig}, next: next}, &config, cbft.NewFaker())
+	if err != nil {
+		t.Fatalf("failed to create light chain: %v", err)
+	}
+	return chain, genesis.Header()
+}
+
+func TestCbftProducers(t *testing.T) {
+	keys, validators := newTestKeys(4)
+	outsider, _ := crypto.GenerateKey()
+	chain, genesis := newCbftTestChain(t, validators, nil)
+
+	headers := makeCbftHeaderChain(genesis, 10, func(n uint64) *ecdsa.PrivateKey { return keys[n%4] })
+	if _, err := chain.InsertHeaderChain(headers, 1); err != nil {
+		t.Fatalf("failed to insert headers: %v", err)
+	}
+	forged := makeCbftHeaderChain(headers[9], 3, func(n uint64) *ecdsa.PrivateKey {
+		if n == 12 {
+			return outsider
+		}
+		return keys[n%4]
+	})
+	if i, err := chain.InsertHeaderChain(forged, 1); err != ErrNotValidator || i != 1 {
+		t.Fatalf("insert result mismatch: have %d %v, want 1 %v", i, err, ErrNotValidator)
+	}
+	// a validator sealing in the turn of another one
+	forged = makeCbftHeaderChain(headers[9], 3, func(n uint64) *ecdsa.PrivateKey {
+		if n == 12 {
+			return keys[1]
+		}
+		return keys[n%4]
+	})
+	if i, err := chain.InsertHeaderChain(forged, 1); err != ErrNotInTurn || i != 1 {
+		t.Fatalf("insert result mismatch: have %d %v, want 1 %v", i, err, ErrNotInTurn)
+	}
+}
+
+func TestCbftSwitch(t *testing.T) {
+	keys, validators := newTestKeys(4)
+	nextKeys, next := newTestKeys(4)
+	chain, genesis := newCbftTestChain(t, validators, next)
+
+	producer := func(n uint64) *ecdsa.PrivateKey {
+		if roundOf(n) == 0 {
+			return keys[n%4]
+		}
+		return nextKeys[n%4]
+	}
+	headers := makeCbftHeaderChain(genesis, common.BaseSwitchWitness+5, producer)
+	commitWitnesses(headers, common.BaseSwitchWitness-1, next, producer)
+	switchBlock := headers[common.BaseSwitchWitness-1]
+
+	// Confirmations below the threshold keep the chain in the first round
+	writeConfirmations(chain.chainDb, switchBlock, keys[:2])
+	if i, err := chain.InsertHeaderChain(headers, 1); err == nil || i != common.BaseSwitchWitness-1 {
+		t.Fatalf("insert result mismatch: have %d %v, want %d", i, err, common.BaseSwitchWitness-1)
+	}
+	if head := chain.CurrentHeader().Number.Uint64(); head != common.BaseSwitchWitness-1 {
+		t.Fatalf("head mismatch: have %d, want %d", head, common.BaseSwitchWitness-1)
+	}
+	// Confirmed switch block moves the chain to the next round
+	writeConfirmations(chain.chainDb, switchBlock, keys[:3])
+	if _, err := chain.InsertHeaderChain(headers[common.BaseSwitchWitness-1:], 1); err != nil {
+		t.Fatalf("failed to insert headers: %v", err)
+	}
+	if head := chain.CurrentHeader().Number.Uint64(); head != common.BaseSwitchWitness+5 {
+		t.Fatalf("head mismatch: have %d, want %d", head, common.BaseSwitchWitness+5)
+	}
+	if nodes := GetValidators(chain.chainDb, switchBlock.Hash()); len(nodes) != len(next) || nodes[0] != next[0] {
+		t.Fatalf("validators mismatch: have %v, want %v", nodes, next)
+	}
+}
+
+func TestCbftSwitchWitnessHash(t *testing.T) {
+	keys, validators := newTestKeys(4)
+	nextKeys, next := newTestKeys(4)
+	chain, genesis := newCbftTestChain(t, validators, validators)
+
+	producer := func(n uint64) *ecdsa.PrivateKey {
+		if roundOf(n) == 0 {
+			return keys[n%4]
+		}
+		return nextKeys[n%4]
+	}
+	headers := makeCbftHeaderChain(genesis, common.BaseSwitchWitness+5, producer)
+	commitWitnesses(headers, common.BaseSwitchWitness-1, next, producer)
+	switchBlock := headers[common.BaseSwitchWitness-1]
+	writeConfirmations(chain.chainDb, switchBlock, keys[:3])
+
+	// Validators served against the commitment of the switch block are rejected
+	if i, err := chain.InsertHeaderChain(headers, 1); err != ErrWitnessMismatch || i != common.BaseSwitchWitness-1 {
+		t.Fatalf("insert result mismatch: have %d %v, want %d %v", i, err, common.BaseSwitchWitness-1, ErrWitnessMismatch)
+	}
+	if nodes := GetValidators(chain.chainDb, switchBlock.Hash()); nodes != nil {
+		t.Fatalf("mismatching validators stored: %v", nodes)
+	}
+	chain.odr.(*cbftTestOdr).next = next
+	if _, err := chain.InsertHeaderChain(headers[common.BaseSwitchWitness-1:], 1); err != nil {
+		t.Fatalf("failed to insert headers: %v", err)
+	}
+	if head := chain.CurrentHeader().Number.Uint64(); head != common.BaseSwitchWitness+5 {
+		t.Fatalf("head mismatch: have %d, want %d", head, common.BaseSwitchWitness+5)
+	}
+}
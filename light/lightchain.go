@@ -70,6 +70,7 @@ type LightChain struct {
 	wg            sync.WaitGroup
 
 	engine consensus.Engine
+	cbft   *cbftVerifier // Verifier of the CBFT producers and confirmations, nil for other engines
 }
 
 // NewLightChain returns a fully initialised light chain using information
@@ -91,7 +92,7 @@ func NewLightChain(odr OdrBackend, config *params.ChainConfig, engine consensus.
 		engine:        engine,
 	}
 	var err error
-	bc.hc, err = core.NewHeaderChain(odr.Database(), config, bc.engine, bc.getProcInterrupt)
+	bc.hc, err = core.NewHeaderChain(odr.Database(), nil, config, bc.engine, bc.getProcInterrupt)
 	if err != nil {
 		return nil, err
 	}
@@ -99,9 +100,13 @@ func NewLightChain(odr OdrBackend, config *params.ChainConfig, engine consensus.
 	if bc.genesisBlock == nil {
 		return nil, core.ErrNoGenesis
 	}
-	if cp, ok := trustedCheckpoints[bc.genesisBlock.Hash()]; ok {
+	cp, ok := trustedCheckpoints[bc.genesisBlock.Hash()]
+	if ok {
 		bc.addTrustedCheckpoint(cp)
 	}
+	if _, ok := engine.(consensus.Bft); ok && config.Cbft != nil {
+		bc.cbft = newCbftVerifier(bc, config.Cbft, cp)
+	}
 	if err := bc.loadLastState(); err != nil {
 		return nil, err
 	}
@@ -351,8 +356,35 @@ func (self *LightChain) postChainEvents(events []interface{}) {
 // because nonces can be verified sparsely, not needing to check each.
 //
 // In the case of a light chain, InsertHeaderChain also creates and posts light
-// chain events when necessary.
+// chain events when necessary. With the CBFT engine, it also checks the producers
+// of the headers and the confirmations of the switch blocks.
 func (self *LightChain) InsertHeaderChain(chain []*types.Header, checkFreq int) (int, error) {
+	if self.cbft == nil {
+		return self.insertHeaderChain(chain, checkFreq)
+	}
+	// Insert the chain round by round, the producers of a round are only known
+	// once the switch block of the previous round is confirmed
+	for start := 0; start < len(chain); {
+		end := segmentEnd(chain, start)
+		if i, err := self.cbft.verifyProducers(chain[start:end]); err != nil {
+			return start + i, err
+		}
+		if i, err := self.insertHeaderChain(chain[start:end], checkFreq); err != nil {
+			return start + i, err
+		}
+		if last := chain[end-1]; isSwitchBlock(last.Number.Uint64()) {
+			if _, err := self.cbft.verifySwitch(last); err != nil {
+				self.Rollback([]common.Hash{last.Hash()})
+				return end - 1, err
+			}
+		}
+		start = end
+	}
+	return 0, nil
+}
+
+// insertHeaderChain validates and inserts a chain of headers.
+func (self *LightChain) insertHeaderChain(chain []*types.Header, checkFreq int) (int, error) {
 	log.Info("======LightChain InsertHeaderChain======")
 	start := time.Now()
 	if i, err := self.hc.ValidateHeaderChain(chain, checkFreq); err != nil {
@@ -26,6 +26,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 )
 
 // NoOdr is the default context passed to an ODR capable function when the ODR
@@ -132,6 +133,49 @@ func (req *ReceiptsRequest) StoreResult(db ethdb.Database) {
 	rawdb.WriteReceipts(db, req.Hash, req.Number, req.Receipts)
 }
 
+// ValidatorsRequest is the ODR request type for retrieving the CBFT consensus
+// nodes of the round following a switch block
+type ValidatorsRequest struct {
+	OdrRequest
+	Hash       common.Hash
+	Number     uint64
+	Committed  bool // Whether the switch block commits to the validators
+	Validators []discover.NodeID
+}
+
+// StoreResult stores the retrieved data in local database
+func (req *ValidatorsRequest) StoreResult(db ethdb.Database) {
+	StoreValidators(db, req.Hash, req.Validators)
+}
+
+// PposStorageRequest is the ODR request type for retrieving the ppos storage of a
+// block, Root is the hash of the storage committed in the state of the block
+type PposStorageRequest struct {
+	OdrRequest
+	Hash   common.Hash
+	Number uint64
+	Root   common.Hash
+	Data   []byte
+}
+
+// StoreResult stores nothing, the ppos storage is retrieved for each query
+func (req *PposStorageRequest) StoreResult(db ethdb.Database) {}
+
+// SystemLogsRequest is the ODR request type for retrieving the system logs of a
+// block, Receipts are the receipts of the block the logs are checked with
+type SystemLogsRequest struct {
+	OdrRequest
+	Hash     common.Hash
+	Number   uint64
+	Receipts types.Receipts
+	Logs     []*types.Log
+}
+
+// StoreResult stores the retrieved data in local database
+func (req *SystemLogsRequest) StoreResult(db ethdb.Database) {
+	rawdb.WriteSystemLogs(db, req.Hash, req.Number, req.Logs)
+}
+
 // ChtRequest is the ODR request type for state/storage trie entries
 type ChtRequest struct {
 	OdrRequest
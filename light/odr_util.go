@@ -19,17 +19,27 @@ package light
 import (
 	"bytes"
 	"context"
+	"math/big"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/ppos"
+	"github.com/PlatONnetwork/PlatON-Go/core/ppos_storage"
 	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/hashicorp/golang-lru"
 )
 
+const pposStorageCacheLimit = 16
+
 var sha3_nil = crypto.Keccak256Hash(nil)
 
+// pposStorages caches the verified ppos storages by their hash, calls against
+// the same block don't download the whole storage again
+var pposStorages, _ = lru.New(pposStorageCacheLimit)
+
 func GetHeaderByNumber(ctx context.Context, odr OdrBackend, number uint64) (*types.Header, error) {
 	db := odr.Database()
 	hash := rawdb.ReadCanonicalHash(db, number)
@@ -108,6 +118,29 @@ func GetBody(ctx context.Context, odr OdrBackend, hash common.Hash, number uint6
 	return body, nil
 }
 
+// GetPposStorage retrieves the ppos storage of the block of header. The storage
+// isn't part of the state trie, it is checked against its hash which is. The
+// encoded storage is cached, every caller gets its own decoded copy.
+func GetPposStorage(ctx context.Context, odr OdrBackend, header *types.Header) (*ppos_storage.Ppos_storage, error) {
+	statedb := NewState(ctx, header, odr)
+	root := common.BytesToHash(statedb.GetState(common.TicketPoolAddr, pposm.StorageHashKey()))
+	if err := statedb.Error(); err != nil {
+		return nil, err
+	}
+	if root == (common.Hash{}) {
+		return ppos_storage.NewPPOS_storage(), nil
+	}
+	if data, ok := pposStorages.Get(root); ok {
+		return ppos_storage.DecodePposStorage(data.([]byte))
+	}
+	r := &PposStorageRequest{Hash: header.Hash(), Number: header.Number.Uint64(), Root: root}
+	if err := odr.Retrieve(ctx, r); err != nil {
+		return nil, err
+	}
+	pposStorages.Add(root, r.Data)
+	return ppos_storage.DecodePposStorage(r.Data)
+}
+
 // GetBlock retrieves an entire block corresponding to the hash, assembling it
 // back from the stored header and body.
 func GetBlock(ctx context.Context, odr OdrBackend, hash common.Hash, number uint64) (*types.Block, error) {
@@ -121,7 +154,7 @@ func GetBlock(ctx context.Context, odr OdrBackend, hash common.Hash, number uint
 		return nil, err
 	}
 	// Reassemble the block and return
-	return types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles, body.Signatures), nil
+	return types.NewBlockWithHeader(header).WithFullBody(body), nil
 }
 
 // GetBlockReceipts retrieves the receipts generated by the transactions included
@@ -170,9 +203,33 @@ func GetBlockLogs(ctx context.Context, odr OdrBackend, hash common.Hash, number
 	for i, receipt := range receipts {
 		logs[i] = receipt.Logs
 	}
+	// From the PPOS events fork on the system logs follow the logs of the last transaction
+	genesis := rawdb.ReadCanonicalHash(odr.Database(), 0)
+	if config := rawdb.ReadChainConfig(odr.Database(), genesis); config != nil && config.IsPposEvents(new(big.Int).SetUint64(number)) {
+		systemLogs, err := GetSystemLogs(ctx, odr, hash, number, receipts)
+		if err != nil {
+			return nil, err
+		}
+		if len(systemLogs) > 0 {
+			logs = append(logs, systemLogs)
+		}
+	}
 	return logs, nil
 }
 
+// GetSystemLogs retrieves the logs emitted outside of the transactions of a block
+// given by its hash, receipts are the receipts of the block.
+func GetSystemLogs(ctx context.Context, odr OdrBackend, hash common.Hash, number uint64, receipts types.Receipts) ([]*types.Log, error) {
+	if logs := rawdb.ReadSystemLogs(odr.Database(), hash, number); logs != nil {
+		return logs, nil
+	}
+	r := &SystemLogsRequest{Hash: hash, Number: number, Receipts: receipts}
+	if err := odr.Retrieve(ctx, r); err != nil {
+		return nil, err
+	}
+	return r.Logs, nil
+}
+
 // GetBloomBits retrieves a batch of compressed bloomBits vectors belonging to the given bit index and section indexes
 func GetBloomBits(ctx context.Context, odr OdrBackend, bitIdx uint, sectionIdxList []uint64) ([][]byte, error) {
 	var (
@@ -0,0 +1,130 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/PlatONnetwork/PlatON-Go/metrics"
+)
+
+var (
+	typeGaugeTpl       = "# TYPE %s gauge\n"
+	typeCounterTpl     = "# TYPE %s counter\n"
+	typeSummaryTpl     = "# TYPE %s summary\n"
+	keyValueTpl        = "%s %v\n"
+	keyQuantileTpl     = "%s{quantile=\"%s\"} %v\n"
+	summaryQuantiles   = []float64{0.5, 0.75, 0.95, 0.99, 0.999}
+	resettingQuantiles = []float64{50, 95, 99}
+)
+
+// collector is a byte buffer that aggregates Prometheus reports of the metrics
+// in the text exposition format.
+type collector struct {
+	buff *bytes.Buffer
+}
+
+// newCollector creates a new Prometheus metric aggregator.
+func newCollector() *collector {
+	return &collector{
+		buff: &bytes.Buffer{},
+	}
+}
+
+func (c *collector) addCounter(name string, m metrics.Counter) {
+	// Counters of go-metrics can be decremented, so they are gauges
+	c.writeGauge(name, m.Count())
+}
+
+func (c *collector) addGauge(name string, m metrics.Gauge) {
+	c.writeGauge(name, m.Value())
+}
+
+func (c *collector) addGaugeFloat64(name string, m metrics.GaugeFloat64) {
+	c.writeGauge(name, m.Value())
+}
+
+func (c *collector) addHistogram(name string, m metrics.Histogram) {
+	ps := m.Percentiles(summaryQuantiles)
+	c.writeSummary(name, summaryQuantiles, ps, m.Sum(), m.Count())
+}
+
+func (c *collector) addMeter(name string, m metrics.Meter) {
+	c.writeCounter(name, m.Count())
+}
+
+func (c *collector) addTimer(name string, m metrics.Timer) {
+	ps := m.Percentiles(summaryQuantiles)
+	c.writeSummary(name, summaryQuantiles, ps, m.Sum(), m.Count())
+}
+
+func (c *collector) addResettingTimer(name string, m metrics.ResettingTimer) {
+	values := m.Values()
+	if len(values) == 0 {
+		return
+	}
+	ps := m.Percentiles(resettingQuantiles)
+	quantiles, values64 := make([]float64, len(ps)), make([]float64, len(ps))
+	for i := range ps {
+		quantiles[i] = resettingQuantiles[i] / 100
+		values64[i] = float64(ps[i])
+	}
+	var sum int64
+	for _, v := range values {
+		sum += v
+	}
+	c.writeSummary(name, quantiles, values64, sum, int64(len(values)))
+}
+
+func (c *collector) writeGauge(name string, value interface{}) {
+	name = mutateKey(name)
+	c.buff.WriteString(fmt.Sprintf(typeGaugeTpl, name))
+	c.buff.WriteString(fmt.Sprintf(keyValueTpl, name, value))
+}
+
+func (c *collector) writeCounter(name string, value interface{}) {
+	name = mutateKey(name)
+	c.buff.WriteString(fmt.Sprintf(typeCounterTpl, name))
+	c.buff.WriteString(fmt.Sprintf(keyValueTpl, name, value))
+}
+
+func (c *collector) writeSummary(name string, quantiles, values []float64, sum, count int64) {
+	name = mutateKey(name)
+	c.buff.WriteString(fmt.Sprintf(typeSummaryTpl, name))
+	for i, q := range quantiles {
+		c.buff.WriteString(fmt.Sprintf(keyQuantileTpl, name, strconv.FormatFloat(q, 'f', -1, 64), values[i]))
+	}
+	c.buff.WriteString(fmt.Sprintf(keyValueTpl, name+"_sum", sum))
+	c.buff.WriteString(fmt.Sprintf(keyValueTpl, name+"_count", count))
+}
+
+// mutateKey converts a metric name into a valid Prometheus one, replacing the
+// characters other than letters, digits, underscores and colons.
+func mutateKey(key string) string {
+	out := []byte(key)
+	for i, b := range out {
+		switch {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b == '_', b == ':':
+		case b >= '0' && b <= '9' && i > 0:
+		default:
+			out[i] = '_'
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,64 @@
+package prometheus
+
+import (
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/metrics"
+)
+
+func TestMain(m *testing.M) {
+	metrics.Enabled = true
+	os.Exit(m.Run())
+}
+
+func TestHandler(t *testing.T) {
+	reg := metrics.NewRegistry()
+
+	metrics.NewRegisteredGauge("cbft/tree/size", reg).Update(12)
+	metrics.NewRegisteredMeter("cbft/turn/misses", reg).Mark(3)
+
+	histogram := metrics.NewRegisteredHistogram("cbft/block/signs", reg, metrics.NewUniformSample(100))
+	for i := int64(1); i <= 4; i++ {
+		histogram.Update(i)
+	}
+	timer := metrics.NewRegisteredTimer("ppos/election", reg)
+	timer.Update(2 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	out := w.Body.String()
+
+	for _, want := range []string{
+		"# TYPE cbft_tree_size gauge\ncbft_tree_size 12\n",
+		"# TYPE cbft_turn_misses counter\ncbft_turn_misses 3\n",
+		"# TYPE cbft_block_signs summary\ncbft_block_signs{quantile=\"0.5\"} 2.5\n",
+		"cbft_block_signs_sum 10\ncbft_block_signs_count 4\n",
+		"ppos_election_sum 2000000\nppos_election_count 1\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+	// Metrics are listed sorted by name
+	if strings.Index(out, "cbft_block_signs") > strings.Index(out, "ppos_election") {
+		t.Errorf("metrics not sorted:\n%s", out)
+	}
+}
+
+func TestMutateKey(t *testing.T) {
+	tests := map[string]string{
+		"chain/inserts":           "chain_inserts",
+		"p2p/InboundTraffic":      "p2p_InboundTraffic",
+		"eth/db/chaindata/disk.r": "eth_db_chaindata_disk_r",
+		"1st-metric":              "_st_metric",
+	}
+	for in, want := range tests {
+		if have := mutateKey(in); have != want {
+			t.Errorf("mutateKey(%q) = %q, want %q", in, have, want)
+		}
+	}
+}
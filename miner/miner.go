@@ -152,6 +152,12 @@ func (self *Miner) SetExtra(extra []byte) error {
 	return nil
 }
 
+// SetPposGasShare sets the percentage of the block gas reserved for the PPOS
+// transactions.
+func (self *Miner) SetPposGasShare(share uint64) {
+	self.worker.setPposGasShare(share)
+}
+
 // SetRecommitInterval sets the interval for sealing work resubmitting.
 func (self *Miner) SetRecommitInterval(interval time.Duration) {
 	self.worker.setRecommitInterval(interval)
@@ -19,7 +19,6 @@ package miner
 import (
 	"bytes"
 	"errors"
-	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
 	"math/big"
 	"sync"
@@ -182,9 +181,10 @@ type worker struct {
 	remoteUncles map[common.Hash]*types.Block // A set of side blocks as the possible uncle blocks.
 	unconfirmed  *unconfirmedBlocks           // A set of locally mined blocks pending canonicalness confirmations.
 
-	mu       sync.RWMutex // The lock used to protect the coinbase and extra fields
-	coinbase common.Address
-	extra    []byte
+	mu           sync.RWMutex // The lock used to protect the coinbase, extra and pposGasShare fields
+	coinbase     common.Address
+	extra        []byte
+	pposGasShare uint64 // Percentage of the block gas reserved for the PPOS transactions
 
 	pendingMu    sync.RWMutex
 	pendingTasks map[common.Hash]*task
@@ -293,6 +293,14 @@ func (w *worker) setExtra(extra []byte) {
 	w.extra = extra
 }
 
+// setPposGasShare sets the percentage of the block gas reserved for the PPOS
+// transactions.
+func (w *worker) setPposGasShare(share uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pposGasShare = share
+}
+
 // setRecommitInterval updates the interval for miner sealing work recommitting.
 func (w *worker) setRecommitInterval(interval time.Duration) {
 	w.resubmitIntervalCh <- interval
@@ -738,6 +746,10 @@ func (w *worker) resultLoop() {
 				}
 				logs = append(logs, receipt.Logs...)
 			}
+			for _, log := range task.state.GetLogs(types.SystemLogTxHash) {
+				log.BlockHash = hash
+				logs = append(logs, log)
+			}
 			// Commit block and state to database.
 			stat, err := w.chain.WriteBlockWithState(block, receipts, task.state)
 			if err != nil {
@@ -794,7 +806,7 @@ func (w *worker) resultLoop() {
 			w.pendingMu.RUnlock()
 			var _receipts []*types.Receipt
 			var _state *state.StateDB
-			if exist && cbft.IsSignedBySelf(sealhash, block.Sign()) {
+			if exist && w.engine.(consensus.Bft).IsSignedBySelf(sealhash, block.Sign()) {
 				_receipts = task.receipts
 				_state = task.state
 				stateIsNil := _state == nil
@@ -831,8 +843,16 @@ func (w *worker) resultLoop() {
 				}
 				logs = append(logs, receipt.Logs...)
 			}
+			for _, log := range _state.GetLogs(types.SystemLogTxHash) {
+				log.BlockHash = hash
+				logs = append(logs, log)
+			}
 			// Commit block and state to database.
 			block.ConfirmSigns = blockConfirmSigns
+			if cbftResult.ConfirmAggregate != nil {
+				// the aggregate replaces the signatures of the consensus nodes
+				block.ConfirmSigns, block.ConfirmAggregate = nil, cbftResult.ConfirmAggregate
+			}
 			stat, err := w.chain.WriteBlockWithState(block, receipts, _state)
 			if err != nil {
 				log.Error("Failed writing block to chain", "hash", block.Hash(), "number", block.NumberU64(), "err", err)
@@ -1356,6 +1376,15 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 	for _, accTxs := range pending {
 		txsCount = txsCount + len(accTxs)
 	}
+	// Commit the PPOS transactions first, within the gas reserved for them. The
+	// share is read under w.mu, held by commitNewWork.
+	startTime = time.Now()
+	ok, pposTimeout := w.commitPposTransactions(header, pending, w.pposGasShare, interrupt, timestamp)
+	if ok {
+		return
+	}
+	log.Debug("ppos transactions executing stat", "hash", commitBlock.Hash(), "number", commitBlock.NumberU64(), "involvedTxCount", w.current.tcount, "time", common.PrettyDuration(time.Since(startTime)))
+
 	// Split the pending transactions into locals and remotes
 	localTxs, remoteTxs := make(map[common.Address]types.Transactions), pending
 	for _, account := range w.eth.TxPool().Locals() {
@@ -1367,8 +1396,9 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 	log.Debug("execute pending transactions", "hash", commitBlock.Hash(), "number", commitBlock.NumberU64(), "localTxCount", len(localTxs), "remoteTxCount", len(remoteTxs), "txsCount", txsCount)
 
 	startTime = time.Now()
-	var localTimeout = false
-	if len(localTxs) > 0 {
+	var localTimeout = pposTimeout
+	commitPposTxCount := w.current.tcount
+	if !localTimeout && len(localTxs) > 0 {
 		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs)
 		if ok, timeout := w.commitTransactionsWithHeader(header, txs, w.coinbase, interrupt, timestamp); ok {
 			return
@@ -1377,7 +1407,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 		}
 	}
 
-	commitLocalTxCount := w.current.tcount
+	commitLocalTxCount := w.current.tcount - commitPposTxCount
 	log.Debug("local transactions executing stat", "hash", commitBlock.Hash(), "number", commitBlock.NumberU64(), "involvedTxCount", commitLocalTxCount, "time", common.PrettyDuration(time.Since(startTime)))
 
 	startTime = time.Now()
@@ -1387,7 +1417,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 			return
 		}
 	}
-	commitRemoteTxCount := w.current.tcount - commitLocalTxCount
+	commitRemoteTxCount := w.current.tcount - commitLocalTxCount - commitPposTxCount
 	log.Debug("remote transactions executing stat", "hash", commitBlock.Hash(), "number", commitBlock.NumberU64(), "involvedTxCount", commitRemoteTxCount, "time", common.PrettyDuration(time.Since(startTime)))
 
 	w.commit(uncles, w.fullTaskHook, true, tstart, header)
@@ -1404,6 +1434,8 @@ func (w *worker) commit(uncles []*types.Header, interval func(), update bool, st
 	}
 
 	st := w.current.state.Copy()
+	// the logs emitted outside of the transactions are the system logs of the block
+	st.Prepare(types.SystemLogTxHash, common.Hash{}, len(w.current.txs))
 
 	if header != nil {
 		startPpos := time.Now().UnixNano()
@@ -1427,6 +1459,16 @@ func (w *worker) commit(uncles []*types.Header, interval func(), update bool, st
 		}
 		endSwitchWitness := time.Now().UnixNano()
 		log.Debug("Execute Time switchWitness", "nano", endSwitchWitness - endElection, "millisecond", endSwitchWitness/1e6-endElection/1e6)
+		// The switch block commits to the consensus nodes of the next round
+		if err := w.commitWitnessHash(st, header); err != nil {
+			log.Error("Failed to woker commit, commitWitnessHash is failed", "err", err)
+			return errors.New("commitWitnessHash failure")
+		}
+		// Liveness of the consensus nodes
+		if err := w.recordLiveness(st, w.current.header); err != nil {
+			log.Error("Failed to woker commit, recordLiveness is failed", "err", err)
+			return errors.New("recordLiveness failure")
+		}
 		// ppos Store Hash
 		w.storeHash(st, header.Number, header.Hash())
 	}
@@ -4,10 +4,13 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/consensus"
 	//"github.com/PlatONnetwork/PlatON-Go/consensus/cbft"
+	"github.com/PlatONnetwork/PlatON-Go/core"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"errors"
 	"math/big"
 )
@@ -65,6 +68,23 @@ func (w *worker) switchWitness(state *state.StateDB, blockNumber *big.Int) error
 	return nil
 }
 
+// commitWitnessHash writes the commitment of the switch block header to the
+// consensus nodes of the next round into the vanity of its extra data.
+func (w *worker) commitWitnessHash(state *state.StateDB, header *types.Header) error {
+	cbftEngine, ok := w.engine.(consensus.Bft)
+	if !ok || !w.shouldSwitch(header.Number) || !w.config.IsWitnessCommit(header.Number) {
+		return nil
+	}
+	parentNumber := new(big.Int).Sub(header.Number, common.Big1)
+	hash, err := core.SwitchWitnessHash(cbftEngine, state, parentNumber, header.ParentHash, header.Number)
+	if err != nil {
+		return err
+	}
+	copy(header.Extra[:common.HashLength], hash[:])
+	log.Debug("Commit the witness hash", "blockNumber", header.Number, "hash", hash)
+	return nil
+}
+
 func (w *worker) attemptAddConsensusPeer(blockNumber *big.Int, state *state.StateDB) {
 	if should := w.shouldAddNextPeers(blockNumber); should {
 		log.Debug("Attempt to connect the next round of consensus nodes", "blockNumber", blockNumber)
@@ -73,7 +93,7 @@ func (w *worker) attemptAddConsensusPeer(blockNumber *big.Int, state *state.Stat
 		nextNodes, err := w.getWitness(blockNumber, state, 1)	// flag：-1: former	  0: current   1: next
 		log.Info("Next round consensus node list:","number", blockNumber, "nextNodes", nextNodes, "nextNodes length", len(nextNodes), "err", err)
 		if err == nil && len(nextNodes) > 0 && existsNode(w.engine.(consensus.Bft).GetOwnNodeID(), nextNodes) {
-			w.addConsensusPeerFn(nextNodes)
+			w.addConsensusPeerFn(vm.ConsensusDialNodes(state, nextNodes))
 		}
 	}
 }
@@ -136,10 +156,76 @@ func (w *worker) notify(state vm.StateDB, blockNumber *big.Int) error {
 	return w.engine.(consensus.Bft).Notify(state, blockNumber)
 }
 
+func (w *worker) recordLiveness(state *state.StateDB, header *types.Header) error {
+	if !w.config.IsLiveness(header.Number) {
+		return nil
+	}
+	return w.engine.(consensus.Bft).RecordLiveness(w.chain, state, header)
+}
+
 func (w *worker) storeHash(state *state.StateDB, blockNumber *big.Int, blockHash common.Hash) {
 	w.engine.(consensus.Bft).StoreHash(state, blockNumber, blockHash)
 }
 
 func (w *worker) submit2cache(state *state.StateDB, currBlocknumber *big.Int, blockInterval *big.Int, currBlockhash common.Hash) {
 	w.engine.(consensus.Bft).Submit2Cache(state, currBlocknumber, blockInterval, currBlockhash)
-}
\ No newline at end of file
+}
+// pposTxs returns the leading PPOS transactions in the block number num of every
+// account of pending, they are committed first in a priority lane.
+func pposTxs(config *params.ChainConfig, num *big.Int, pending map[common.Address]types.Transactions) map[common.Address]types.Transactions {
+	lane := make(map[common.Address]types.Transactions)
+	for addr, txs := range pending {
+		n := 0
+		for n < len(txs) && core.IsPposTx(config, num, txs[n]) {
+			n++
+		}
+		if n > 0 {
+			lane[addr] = txs[:n]
+		}
+	}
+	return lane
+}
+
+// commitPposTransactions commits the PPOS transactions of pending within share
+// percent of the block gas, the committed ones are removed from pending. It
+// returns the results of commitTransactionsWithHeader.
+func (w *worker) commitPposTransactions(header *types.Header, pending map[common.Address]types.Transactions, share uint64, interrupt *int32, timestamp int64) (bool, bool) {
+	if share == 0 {
+		return false, false
+	}
+	lane := pposTxs(w.config, header.Number, pending)
+	if len(lane) == 0 {
+		return false, false
+	}
+	if w.current.gasPool == nil {
+		w.current.gasPool = new(core.GasPool).AddGas(w.current.header.GasLimit)
+	}
+	reserved := w.current.header.GasLimit / 100 * share
+	if gas := w.current.gasPool.Gas(); reserved > gas {
+		reserved = gas
+	}
+	gasPool := w.current.gasPool
+	w.current.gasPool = new(core.GasPool).AddGas(reserved)
+	defer func() {
+		gasPool.SubGas(reserved - w.current.gasPool.Gas())
+		w.current.gasPool = gasPool
+	}()
+
+	txs := types.NewTransactionsByPriceAndNonce(w.current.signer, lane)
+	ok, timeout := w.commitTransactionsWithHeader(header, txs, w.coinbase, interrupt, timestamp)
+
+	// Drop the committed transactions, the others are left to the ordinary lanes
+	for addr := range lane {
+		nonce := w.current.state.GetNonce(addr)
+		txs := pending[addr]
+		for len(txs) > 0 && txs[0].Nonce() < nonce {
+			txs = txs[1:]
+		}
+		if len(txs) == 0 {
+			delete(pending, addr)
+		} else {
+			pending[addr] = txs
+		}
+	}
+	return ok, timeout
+}
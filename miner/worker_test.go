@@ -95,7 +95,7 @@ func newTestWorkerBackend(t *testing.T, chainConfig *params.ChainConfig, engine
 	}
 	genesis := gspec.MustCommit(db)
 
-	chain, _ := core.NewBlockChain(db, nil, gspec.Config, engine, vm.Config{}, nil)
+	chain, _ := core.NewBlockChain(db, nil, nil, gspec.Config, engine, vm.Config{}, nil)
 	blockChainCache := core.NewBlockChainCache(chain)
 
 	txpool := core.NewTxPool(testTxPoolConfig, chainConfig, blockChainCache)
@@ -452,3 +452,34 @@ func testAdjustInterval(t *testing.T, chainConfig *params.ChainConfig, engine co
 		t.Error("interval reset timeout")
 	}
 }
+
+func TestPposTxs(t *testing.T) {
+	signer := types.HomesteadSigner{}
+	newTx := func(nonce uint64, to common.Address) *types.Transaction {
+		var input []byte
+		switch to {
+		case common.TicketPoolAddr:
+			input, _ = vm.EncodeInput("VoteTicket")
+		case common.CandidatePoolAddr:
+			input, _ = vm.EncodeInput("Unjail")
+		}
+		tx, _ := types.SignTx(types.NewTransaction(nonce, to, big.NewInt(0), 100000, big.NewInt(1), input), signer, testBankKey)
+		return tx
+	}
+	pending := map[common.Address]types.Transactions{
+		// the ppos transactions queued after an ordinary one keep their order
+		common.Address{1}: {newTx(0, common.TicketPoolAddr), newTx(1, common.CandidatePoolAddr), newTx(2, testUserAddress), newTx(3, common.TicketPoolAddr)},
+		common.Address{2}: {newTx(0, testUserAddress), newTx(1, common.TicketPoolAddr)},
+	}
+	lane := pposTxs(params.TestChainConfig, big.NewInt(1), pending)
+	if len(lane) != 1 || len(lane[common.Address{1}]) != 2 {
+		t.Fatalf("ppos lane mismatch: %v", lane)
+	}
+	// Unjail is an ordinary call before its fork
+	config := *params.TestChainConfig
+	config.LivenessBlock = big.NewInt(10)
+	lane = pposTxs(&config, big.NewInt(9), pending)
+	if len(lane) != 1 || len(lane[common.Address{1}]) != 1 {
+		t.Fatalf("ppos lane mismatch before the fork: %v", lane)
+	}
+}
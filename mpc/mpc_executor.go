@@ -0,0 +1,195 @@
+package mpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/log"
+)
+
+// The names of the supported executor backends.
+const (
+	ExecutorCgo   = "cgo"
+	ExecutorRedis = "redis"
+	ExecutorHTTP  = "http"
+	ExecutorFake  = "fake"
+)
+
+const (
+	KEY_TASK_ID    = "taskId"
+	KEY_PUB_KEY    = "pubKey"
+	KEY_ADDRESS    = "address"
+	KEY_IR_ADDRESS = "irAddress"
+	KEY_METHOD     = "method"
+	KEY_EXTRA      = "extra"
+)
+
+var (
+	ErrUnknownExecutor = errors.New("unknown mpc executor")
+	ErrEmptyEndpoint   = errors.New("empty endpoint of mpc executor")
+)
+
+// MPCExecutor hands a confirmed mpc task over to the compute backend.
+type MPCExecutor interface {
+	// Execute notifies the backend to start the computation described by params.
+	Execute(params MPCParams) error
+}
+
+// toMap converts the params into the flat json form shared by the
+// redis queue and the http backend.
+func (params MPCParams) toMap() map[string]string {
+	return map[string]string{
+		KEY_TASK_ID:    params.TaskId,
+		KEY_PUB_KEY:    params.Pubkey,
+		KEY_ADDRESS:    params.From.Hex(),
+		KEY_IR_ADDRESS: params.IRAddr.Hex(),
+		KEY_METHOD:     params.Method,
+		KEY_EXTRA:      params.Extra,
+	}
+}
+
+// CgoExecutor calls into the native mvm library through cgo.
+type CgoExecutor struct{}
+
+// NewCgoExecutor initializes the native mvm and returns an executor bound to it.
+func NewCgoExecutor(icepath string, httpEndpoint string) *CgoExecutor {
+	InitVM(icepath, httpEndpoint)
+	return &CgoExecutor{}
+}
+
+func (e *CgoExecutor) Execute(params MPCParams) error {
+	return ExecuteMPCTx(params)
+}
+
+// RedisExecutor pushes the task to the redis queues watched by the mpc participants.
+type RedisExecutor struct {
+	url  string
+	keys []string
+
+	mu    sync.Mutex
+	redis *Redis
+}
+
+// NewRedisExecutor creates an executor pushing to the given keys, the queues of
+// both participants are used if no key is given. The connection is dialed lazily.
+func NewRedisExecutor(url string, keys ...string) (*RedisExecutor, error) {
+	if url == "" {
+		return nil, ErrEmptyEndpoint
+	}
+	if len(keys) == 0 {
+		keys = []string{MPC_TASK_KEY_ALICE, MPC_TASK_KEY_BOB}
+	}
+	return &RedisExecutor{url: url, keys: keys}, nil
+}
+
+func (e *RedisExecutor) Execute(params MPCParams) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.redis == nil {
+		r, err := NewRedis(e.url)
+		if err != nil {
+			log.Error("Create connection of redis not success.", "url", e.url, "err", err)
+			return err
+		}
+		e.redis = r
+	}
+	jsonMap := params.toMap()
+	for _, key := range e.keys {
+		if err := e.redis.RPush(key, jsonMap); err != nil {
+			// drop the connection, the next task will redial
+			e.redis.Con.Close()
+			e.redis = nil
+			return fmt.Errorf("add mpc task to queue %s fail: %v", key, err)
+		}
+	}
+	log.Trace("Push mpc task to redis success", "taskId", params.TaskId, "method", params.Method, "keys", e.keys)
+	return nil
+}
+
+// HTTPExecutor posts the task as json to a remote mpc service.
+type HTTPExecutor struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewHTTPExecutor creates an executor posting tasks to endpoint.
+func NewHTTPExecutor(endpoint string, timeout time.Duration) (*HTTPExecutor, error) {
+	if endpoint == "" {
+		return nil, ErrEmptyEndpoint
+	}
+	return &HTTPExecutor{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (e *HTTPExecutor) Execute(params MPCParams) error {
+	body, err := json.Marshal(params.toMap())
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("mpc service returned status %d", resp.StatusCode)
+	}
+	log.Trace("Post mpc task to http service success", "taskId", params.TaskId, "method", params.Method, "endpoint", e.endpoint)
+	return nil
+}
+
+// FakeExecutor is an in-process executor which records the tasks it receives,
+// it allows the mpc task flow to be exercised without the native library.
+type FakeExecutor struct {
+	mu    sync.Mutex
+	tasks []MPCParams
+	err   error
+	feed  chan MPCParams
+}
+
+// NewFakeExecutor creates a fake executor, every executed task is also
+// delivered to the channel returned by Tasks if it has room.
+func NewFakeExecutor() *FakeExecutor {
+	return &FakeExecutor{feed: make(chan MPCParams, 64)}
+}
+
+// SetError makes all following executions fail with err.
+func (e *FakeExecutor) SetError(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.err = err
+}
+
+func (e *FakeExecutor) Execute(params MPCParams) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return e.err
+	}
+	e.tasks = append(e.tasks, params)
+	select {
+	case e.feed <- params:
+	default:
+	}
+	return nil
+}
+
+// Executed returns a copy of all tasks executed so far.
+func (e *FakeExecutor) Executed() []MPCParams {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]MPCParams(nil), e.tasks...)
+}
+
+// Tasks returns the channel the executed tasks are delivered to.
+func (e *FakeExecutor) Tasks() <-chan MPCParams {
+	return e.feed
+}
@@ -0,0 +1,76 @@
+package mpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+)
+
+var testParams = MPCParams{
+	TaskId: "ddc1ce5ccf0fac429a3aa075f28f73de9831dc60829bebeae08372e641a0da4b",
+	Pubkey: "a363d1243646b6eabf1d4851f646b523f5707d053caab95022f1682605aca0537ee0c5c14b4dfa76dcbce264b7e68d59de79a42b7cda059e9d358336a9ab8d80",
+	From:   common.HexToAddress("0x60Ceca9c1290EE56b98d4E160EF0453F7C40d219"),
+	IRAddr: common.HexToAddress("0xC1FB0780933718Ccb12DF862726776C840F22C33"),
+	Method: "start_calc",
+}
+
+func TestHTTPExecutor(t *testing.T) {
+	received := make(chan map[string]string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var task map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- task
+	}))
+	defer server.Close()
+
+	executor, err := NewHTTPExecutor(server.URL, time.Second)
+	if err != nil {
+		t.Fatalf("failed to create http executor: %v", err)
+	}
+	if err := executor.Execute(testParams); err != nil {
+		t.Fatalf("failed to execute: %v", err)
+	}
+	task := <-received
+	if task[KEY_TASK_ID] != testParams.TaskId || task[KEY_ADDRESS] != testParams.From.Hex() || task[KEY_METHOD] != testParams.Method {
+		t.Fatalf("task mismatch: have %v", task)
+	}
+}
+
+func TestHTTPExecutorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	executor, _ := NewHTTPExecutor(server.URL, time.Second)
+	if err := executor.Execute(testParams); err == nil {
+		t.Fatalf("expected error on failed status")
+	}
+}
+
+func TestFakeExecutor(t *testing.T) {
+	executor := NewFakeExecutor()
+	if err := executor.Execute(testParams); err != nil {
+		t.Fatalf("failed to execute: %v", err)
+	}
+	if tasks := executor.Executed(); len(tasks) != 1 || tasks[0] != testParams {
+		t.Fatalf("executed tasks mismatch: have %v", tasks)
+	}
+	if task := <-executor.Tasks(); task != testParams {
+		t.Fatalf("delivered task mismatch: have %v", task)
+	}
+
+	fail := errors.New("fail")
+	executor.SetError(fail)
+	if err := executor.Execute(testParams); err != fail {
+		t.Fatalf("error mismatch: have %v, want %v", err, fail)
+	}
+}
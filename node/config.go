@@ -41,6 +41,8 @@ const (
 	datadirDefaultKeyStore = "keystore"           // Path within the datadir to the keystore
 	datadirStaticNodes     = "static-nodes.json"  // Path within the datadir to the static node list
 	datadirTrustedNodes    = "trusted-nodes.json" // Path within the datadir to the trusted node list
+	datadirSentryNodes     = "sentry-nodes.json"  // Path within the datadir to the sentry node list
+	datadirPrivateNodes    = "private-nodes.json" // Path within the datadir to the private node list
 	datadirNodeDatabase    = "nodes"              // Path within the datadir to store the node infos
 )
 
@@ -345,6 +347,18 @@ func (c *Config) TrustedNodes() []*discover.Node {
 	return c.parsePersistentNodes(c.ResolvePath(datadirTrustedNodes))
 }
 
+// SentryNodes returns a list of node enode URLs configured as the sentries of
+// a validator.
+func (c *Config) SentryNodes() []*discover.Node {
+	return c.parsePersistentNodes(c.ResolvePath(datadirSentryNodes))
+}
+
+// PrivateNodes returns a list of node enode URLs configured as the validators
+// behind a sentry.
+func (c *Config) PrivateNodes() []*discover.Node {
+	return c.parsePersistentNodes(c.ResolvePath(datadirPrivateNodes))
+}
+
 // parsePersistentNodes parses a list of discovery node URLs loaded from a .json
 // file from within the data directory.
 func (c *Config) parsePersistentNodes(path string) []*discover.Node {
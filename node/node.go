@@ -158,6 +158,12 @@ func (n *Node) Start() error {
 	if n.serverConfig.TrustedNodes == nil {
 		n.serverConfig.TrustedNodes = n.config.TrustedNodes()
 	}
+	if n.serverConfig.SentryNodes == nil {
+		n.serverConfig.SentryNodes = n.config.SentryNodes()
+	}
+	if n.serverConfig.PrivateNodes == nil {
+		n.serverConfig.PrivateNodes = n.config.PrivateNodes()
+	}
 	if n.serverConfig.NodeDatabase == "" {
 		n.serverConfig.NodeDatabase = n.config.NodeDB()
 	}
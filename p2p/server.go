@@ -104,6 +104,15 @@ type Config struct {
 	// allowed to connect, even above the peer limit.
 	TrustedNodes []*discover.Node
 
+	// Sentry nodes are the private sentries of a validator. A validator with sentries
+	// connects only to them and never dials the other consensus nodes, its consensus
+	// messages are relayed by the sentries both ways.
+	SentryNodes []*discover.Node `toml:",omitempty"`
+
+	// Private nodes are the validators behind a sentry, they are always kept connected
+	// and allowed to connect even above the peer limit.
+	PrivateNodes []*discover.Node `toml:",omitempty"`
+
 	// Connectivity can be restricted to certain IP networks.
 	// If this option is set to a non-nil value, only hosts which match one of the
 	// IP networks contained in the list are considered.
@@ -160,6 +169,7 @@ type Server struct {
 	running bool
 
 	ntab         discoverTable
+	sentries     map[discover.NodeID]bool // Sentry nodes of a validator in sentry mode
 	listener     net.Listener
 	ourHandshake *protoHandshake
 	lastLookup   time.Time
@@ -477,6 +487,17 @@ func (srv *Server) Start() (err error) {
 	srv.peerOp = make(chan peerOpFunc)
 	srv.peerOpDone = make(chan struct{})
 
+	if len(srv.SentryNodes) > 0 {
+		// A validator behind sentries is not discoverable, it only keeps its
+		// sentries connected
+		srv.log.Info("P2P networking in sentry mode", "sentries", len(srv.SentryNodes))
+		srv.NoDiscovery, srv.DiscoveryV5 = true, false
+		srv.sentries = make(map[discover.NodeID]bool, len(srv.SentryNodes))
+		for _, n := range srv.SentryNodes {
+			srv.sentries[n.ID] = true
+		}
+	}
+
 	var (
 		conn      *net.UDPConn
 		sconn     *sharedUDPConn
@@ -547,7 +568,8 @@ func (srv *Server) Start() (err error) {
 	}
 
 	dynPeers := srv.maxDialedConns()
-	dialer := newDialState(srv.StaticNodes, srv.BootstrapNodes, srv.ntab, dynPeers, srv.NetRestrict, srv.MaxConsensusPeers)
+	static := append(append(append([]*discover.Node{}, srv.StaticNodes...), srv.SentryNodes...), srv.PrivateNodes...)
+	dialer := newDialState(static, srv.BootstrapNodes, srv.ntab, dynPeers, srv.NetRestrict, srv.MaxConsensusPeers)
 
 	// handshake
 	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name, ID: discover.PubkeyID(&srv.PrivateKey.PublicKey)}
@@ -619,6 +641,13 @@ func (srv *Server) run(dialstate dialer) {
 	for _, n := range srv.TrustedNodes {
 		trusted[n.ID] = true
 	}
+	// The sentries and the validators behind them are connected above the peer limit
+	for _, n := range srv.SentryNodes {
+		trusted[n.ID] = true
+	}
+	for _, n := range srv.PrivateNodes {
+		trusted[n.ID] = true
+	}
 
 	// removes t from runningTasks
 	delTask := func(t task) {
@@ -682,6 +711,11 @@ running:
 				p.Disconnect(DiscRequested)
 			}
 		case n := <-srv.addconsensus:
+			if srv.sentries != nil {
+				// The consensus nodes are reached through the sentries
+				srv.log.Trace("Ignoring consensus node in sentry mode", "node", n)
+				break
+			}
 			srv.log.Trace("Adding consensus node", "node", n)
 			dialstate.addConsensus(n)
 		case n := <-srv.removeconsensus:
@@ -808,6 +842,8 @@ func (srv *Server) protoHandshakeChecks(peers map[discover.NodeID]*Peer, inbound
 
 func (srv *Server) encHandshakeChecks(peers map[discover.NodeID]*Peer, inboundCount int, c *conn) error {
 	switch {
+	case srv.sentries != nil && !srv.sentries[c.id]:
+		return DiscUselessPeer
 	case !c.is(trustedConn|staticDialedConn|consensusDialedConn) && len(peers) >= srv.MaxPeers:
 		return DiscTooManyPeers
 	case !c.is(trustedConn|consensusDialedConn) && c.is(inboundConn) && inboundCount >= srv.maxInboundConns():
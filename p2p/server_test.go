@@ -415,6 +415,42 @@ func TestServerAtCap(t *testing.T) {
 	}
 }
 
+func TestServerSentryMode(t *testing.T) {
+	sentryID := randomID()
+	srv := &Server{
+		Config: Config{
+			PrivateKey:  newkey(),
+			MaxPeers:    10,
+			NoDial:      true,
+			SentryNodes: []*discover.Node{{ID: sentryID}},
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	if !srv.NoDiscovery || srv.ntab != nil {
+		t.Fatal("discovery is enabled in sentry mode")
+	}
+	newconn := func(id discover.NodeID) *conn {
+		fd, _ := net.Pipe()
+		tx := newTestTransport(id, fd)
+		return &conn{fd: fd, transport: tx, flags: inboundConn, id: id, cont: make(chan error)}
+	}
+	// Only the sentries are allowed to connect
+	if err := srv.checkpoint(newconn(randomID()), srv.posthandshake); err != DiscUselessPeer {
+		t.Error("wrong error for non-sentry conn:", err)
+	}
+	c := newconn(sentryID)
+	if err := srv.checkpoint(c, srv.posthandshake); err != nil {
+		t.Error("unexpected error for sentry conn @posthandshake:", err)
+	}
+	if !c.is(trustedConn) {
+		t.Error("Server did not set trusted flag on sentry")
+	}
+}
+
 func TestServerPeerLimits(t *testing.T) {
 	srvkey := newkey()
 
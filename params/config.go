@@ -70,16 +70,27 @@ var (
 
 	// MainnetChainConfig is the chain parameters to run a node on the main network.
 	MainnetChainConfig = &ChainConfig{
-		ChainID:             big.NewInt(101),
-		HomesteadBlock:      big.NewInt(1150000),
-		DAOForkBlock:        big.NewInt(1920000),
-		DAOForkSupport:      true,
-		EIP150Block:         big.NewInt(2463000),
-		EIP150Hash:          common.HexToHash("0x2086799aeebeae135c246c65021c82b4e15a2c451340993aacfd2751886514f0"),
-		EIP155Block:         big.NewInt(2675000),
-		EIP158Block:         big.NewInt(2675000),
-		ByzantiumBlock:      big.NewInt(4370000),
-		ConstantinopleBlock: nil,
+		ChainID:              big.NewInt(101),
+		HomesteadBlock:       big.NewInt(1150000),
+		DAOForkBlock:         big.NewInt(1920000),
+		DAOForkSupport:       true,
+		EIP150Block:          big.NewInt(2463000),
+		EIP150Hash:           common.HexToHash("0x2086799aeebeae135c246c65021c82b4e15a2c451340993aacfd2751886514f0"),
+		EIP155Block:          big.NewInt(2675000),
+		EIP158Block:          big.NewInt(2675000),
+		ByzantiumBlock:       big.NewInt(4370000),
+		ConstantinopleBlock:  nil,
+		PposGasBlock:         big.NewInt(5000000),
+		NodeSigBlock:         big.NewInt(5000000),
+		PposEventsBlock:      big.NewInt(5000000),
+		BlsBlock:             big.NewInt(5000000),
+		WitnessCommitBlock:   big.NewInt(5000000),
+		VCVerifierBlock:      big.NewInt(5000000),
+		CandidateUpdateBlock: big.NewInt(5000000),
+		GovernanceBlock:      big.NewInt(5000000),
+		TicketTransferBlock:  big.NewInt(5000000),
+		LivenessBlock:        big.NewInt(5000000),
+		SentryBlock:          big.NewInt(5000000),
 		//Ethash:              new(EthashConfig),
 		Cbft: &CbftConfig{
 			InitialNodes: convertNodeUrl(initialTestnetConsensusNodes),
@@ -98,16 +109,27 @@ var (
 
 	// TestnetChainConfig contains the chain parameters to run a node on the Alpha test network.
 	TestnetChainConfig = &ChainConfig{
-		ChainID:             big.NewInt(103),
-		HomesteadBlock:      big.NewInt(1),
-		DAOForkBlock:        nil,
-		DAOForkSupport:      false,
-		EIP150Block:         big.NewInt(2),
-		EIP150Hash:          common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000000"),
-		EIP155Block:         big.NewInt(3),
-		EIP158Block:         big.NewInt(3),
-		ByzantiumBlock:      big.NewInt(4),
-		ConstantinopleBlock: nil,
+		ChainID:              big.NewInt(103),
+		HomesteadBlock:       big.NewInt(1),
+		DAOForkBlock:         nil,
+		DAOForkSupport:       false,
+		EIP150Block:          big.NewInt(2),
+		EIP150Hash:           common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000000"),
+		EIP155Block:          big.NewInt(3),
+		EIP158Block:          big.NewInt(3),
+		ByzantiumBlock:       big.NewInt(4),
+		ConstantinopleBlock:  nil,
+		PposGasBlock:         big.NewInt(1000000),
+		NodeSigBlock:         big.NewInt(1000000),
+		PposEventsBlock:      big.NewInt(1000000),
+		BlsBlock:             big.NewInt(1000000),
+		WitnessCommitBlock:   big.NewInt(1000000),
+		VCVerifierBlock:      big.NewInt(1000000),
+		CandidateUpdateBlock: big.NewInt(1000000),
+		GovernanceBlock:      big.NewInt(1000000),
+		TicketTransferBlock:  big.NewInt(1000000),
+		LivenessBlock:        big.NewInt(1000000),
+		SentryBlock:          big.NewInt(1000000),
 		//Ethash:              new(EthashConfig),
 		Cbft: &CbftConfig{
 			InitialNodes: convertNodeUrl(initialTestnetConsensusNodes),
@@ -117,16 +139,27 @@ var (
 
 	// InnerTestnetChainConfig contains the chain parameters to run a node on the inner test network.
 	InnerTestnetChainConfig = &ChainConfig{
-		ChainID:             big.NewInt(203),
-		HomesteadBlock:      big.NewInt(1),
-		DAOForkBlock:        nil,
-		DAOForkSupport:      false,
-		EIP150Block:         big.NewInt(2),
-		EIP150Hash:          common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000000"),
-		EIP155Block:         big.NewInt(3),
-		EIP158Block:         big.NewInt(3),
-		ByzantiumBlock:      big.NewInt(4),
-		ConstantinopleBlock: nil,
+		ChainID:              big.NewInt(203),
+		HomesteadBlock:       big.NewInt(1),
+		DAOForkBlock:         nil,
+		DAOForkSupport:       false,
+		EIP150Block:          big.NewInt(2),
+		EIP150Hash:           common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000000"),
+		EIP155Block:          big.NewInt(3),
+		EIP158Block:          big.NewInt(3),
+		ByzantiumBlock:       big.NewInt(4),
+		ConstantinopleBlock:  nil,
+		PposGasBlock:         big.NewInt(1000000),
+		NodeSigBlock:         big.NewInt(1000000),
+		PposEventsBlock:      big.NewInt(1000000),
+		BlsBlock:             big.NewInt(1000000),
+		WitnessCommitBlock:   big.NewInt(1000000),
+		VCVerifierBlock:      big.NewInt(1000000),
+		CandidateUpdateBlock: big.NewInt(1000000),
+		GovernanceBlock:      big.NewInt(1000000),
+		TicketTransferBlock:  big.NewInt(1000000),
+		LivenessBlock:        big.NewInt(1000000),
+		SentryBlock:          big.NewInt(1000000),
 		//Ethash:              new(EthashConfig),
 		Cbft: &CbftConfig{
 			InitialNodes: convertNodeUrl(initialInnerTestnetConsensusNodes),
@@ -136,16 +169,27 @@ var (
 
 	// InnerDevnetChainConfig contains the chain parameters to run a node on the inner test network.
 	InnerDevnetChainConfig = &ChainConfig{
-		ChainID:             big.NewInt(204),
-		HomesteadBlock:      big.NewInt(1),
-		DAOForkBlock:        nil,
-		DAOForkSupport:      false,
-		EIP150Block:         big.NewInt(2),
-		EIP150Hash:          common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000000"),
-		EIP155Block:         big.NewInt(3),
-		EIP158Block:         big.NewInt(3),
-		ByzantiumBlock:      big.NewInt(4),
-		ConstantinopleBlock: nil,
+		ChainID:              big.NewInt(204),
+		HomesteadBlock:       big.NewInt(1),
+		DAOForkBlock:         nil,
+		DAOForkSupport:       false,
+		EIP150Block:          big.NewInt(2),
+		EIP150Hash:           common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000000"),
+		EIP155Block:          big.NewInt(3),
+		EIP158Block:          big.NewInt(3),
+		ByzantiumBlock:       big.NewInt(4),
+		ConstantinopleBlock:  nil,
+		PposGasBlock:         big.NewInt(1000000),
+		NodeSigBlock:         big.NewInt(1000000),
+		PposEventsBlock:      big.NewInt(1000000),
+		BlsBlock:             big.NewInt(1000000),
+		WitnessCommitBlock:   big.NewInt(1000000),
+		VCVerifierBlock:      big.NewInt(1000000),
+		CandidateUpdateBlock: big.NewInt(1000000),
+		GovernanceBlock:      big.NewInt(1000000),
+		TicketTransferBlock:  big.NewInt(1000000),
+		LivenessBlock:        big.NewInt(1000000),
+		SentryBlock:          big.NewInt(1000000),
 		//Ethash:              new(EthashConfig),
 		Cbft: &CbftConfig{
 			InitialNodes: convertNodeUrl(initialInnerDevnetConsensusNodes),
@@ -182,16 +226,27 @@ var (
 
 	// BetanetChainConfig contains the chain parameters to run a node on the Beta test network.
 	BetanetChainConfig = &ChainConfig{
-		ChainID:             big.NewInt(104),
-		HomesteadBlock:      big.NewInt(1),
-		DAOForkBlock:        nil,
-		DAOForkSupport:      false,
-		EIP150Block:         big.NewInt(2),
-		EIP150Hash:          common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000000"),
-		EIP155Block:         big.NewInt(3),
-		EIP158Block:         big.NewInt(3),
-		ByzantiumBlock:      big.NewInt(4),
-		ConstantinopleBlock: nil,
+		ChainID:              big.NewInt(104),
+		HomesteadBlock:       big.NewInt(1),
+		DAOForkBlock:         nil,
+		DAOForkSupport:       false,
+		EIP150Block:          big.NewInt(2),
+		EIP150Hash:           common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000000"),
+		EIP155Block:          big.NewInt(3),
+		EIP158Block:          big.NewInt(3),
+		ByzantiumBlock:       big.NewInt(4),
+		ConstantinopleBlock:  nil,
+		PposGasBlock:         big.NewInt(1000000),
+		NodeSigBlock:         big.NewInt(1000000),
+		PposEventsBlock:      big.NewInt(1000000),
+		BlsBlock:             big.NewInt(1000000),
+		WitnessCommitBlock:   big.NewInt(1000000),
+		VCVerifierBlock:      big.NewInt(1000000),
+		CandidateUpdateBlock: big.NewInt(1000000),
+		GovernanceBlock:      big.NewInt(1000000),
+		TicketTransferBlock:  big.NewInt(1000000),
+		LivenessBlock:        big.NewInt(1000000),
+		SentryBlock:          big.NewInt(1000000),
 		//Ethash:              new(EthashConfig),
 		Cbft: &CbftConfig{
 			InitialNodes: convertNodeUrl(initialBetanetConsensusNodes),
@@ -209,16 +264,27 @@ var (
 	}
 
 	GrapeChainConfig = &ChainConfig{
-		ChainID:             big.NewInt(304),
-		HomesteadBlock:      big.NewInt(1),
-		DAOForkBlock:        nil,
-		DAOForkSupport:      true,
-		EIP150Block:         big.NewInt(2),
-		EIP150Hash:          common.HexToHash("0x9b095b36c15eaf13044373aef8ee0bd3a382a5abb92e402afa44b8249c3a90e9"),
-		EIP155Block:         big.NewInt(3),
-		EIP158Block:         big.NewInt(3),
-		ByzantiumBlock:      big.NewInt(1035301),
-		ConstantinopleBlock: nil,
+		ChainID:              big.NewInt(304),
+		HomesteadBlock:       big.NewInt(1),
+		DAOForkBlock:         nil,
+		DAOForkSupport:       true,
+		EIP150Block:          big.NewInt(2),
+		EIP150Hash:           common.HexToHash("0x9b095b36c15eaf13044373aef8ee0bd3a382a5abb92e402afa44b8249c3a90e9"),
+		EIP155Block:          big.NewInt(3),
+		EIP158Block:          big.NewInt(3),
+		ByzantiumBlock:       big.NewInt(1035301),
+		ConstantinopleBlock:  nil,
+		PposGasBlock:         big.NewInt(2000000),
+		NodeSigBlock:         big.NewInt(2000000),
+		PposEventsBlock:      big.NewInt(2000000),
+		BlsBlock:             big.NewInt(2000000),
+		WitnessCommitBlock:   big.NewInt(2000000),
+		VCVerifierBlock:      big.NewInt(2000000),
+		CandidateUpdateBlock: big.NewInt(2000000),
+		GovernanceBlock:      big.NewInt(2000000),
+		TicketTransferBlock:  big.NewInt(2000000),
+		LivenessBlock:        big.NewInt(2000000),
+		SentryBlock:          big.NewInt(2000000),
 		Cbft: &CbftConfig{
 			Period:   3,
 			Epoch:    30000,
@@ -231,6 +297,8 @@ var (
 					MaxChair:          10,
 					MaxCount:          100,
 					RefundBlockNumber: 512,
+					FeeChangeDelay:    512,
+					JailPeriod:        512,
 				},
 				TicketConfig: &TicketConfig{
 					TicketPrice:       "100000000000000000000",
@@ -246,18 +314,18 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, new(EthashConfig), nil, nil, ""}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), new(EthashConfig), nil, nil, ""}
 
 	// AllCliqueProtocolChanges contains every protocol change (EIPs) introduced
 	// and accepted by the Ethereum core developers into the Clique consensus.
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, ""}
+	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, ""}
 
-	TestChainConfig = &ChainConfig{big.NewInt(1), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, new(EthashConfig), nil, nil, ""}
+	TestChainConfig = &ChainConfig{big.NewInt(1), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), new(EthashConfig), nil, nil, ""}
 
-	AllCbftProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, new(CbftConfig), ""}
+	AllCbftProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, new(CbftConfig), ""}
 	TestRules              = TestChainConfig.Rules(new(big.Int))
 )
 
@@ -271,6 +339,10 @@ type TrustedCheckpoint struct {
 	SectionHead  common.Hash `json:"sectionHead"`
 	CHTRoot      common.Hash `json:"chtRoot"`
 	BloomRoot    common.Hash `json:"bloomRoot"`
+
+	// Validators are the CBFT consensus nodes of the round of the block following
+	// the section head, the light client verifies the headers from this set on.
+	Validators []discover.NodeID `json:"validators,omitempty"`
 }
 
 // ChainConfig is the core config which determines the blockchain settings.
@@ -293,9 +365,20 @@ type ChainConfig struct {
 	EIP155Block *big.Int `json:"eip155Block,omitempty"` // EIP155 HF block
 	EIP158Block *big.Int `json:"eip158Block,omitempty"` // EIP158 HF block
 
-	ByzantiumBlock      *big.Int `json:"byzantiumBlock,omitempty"`      // Byzantium switch block (nil = no fork, 0 = already on byzantium)
-	ConstantinopleBlock *big.Int `json:"constantinopleBlock,omitempty"` // Constantinople switch block (nil = no fork, 0 = already activated)
-	EWASMBlock          *big.Int `json:"ewasmBlock,omitempty"`          // EWASM switch block (nil = no fork, 0 = already activated)
+	ByzantiumBlock       *big.Int `json:"byzantiumBlock,omitempty"`       // Byzantium switch block (nil = no fork, 0 = already on byzantium)
+	ConstantinopleBlock  *big.Int `json:"constantinopleBlock,omitempty"`  // Constantinople switch block (nil = no fork, 0 = already activated)
+	EWASMBlock           *big.Int `json:"ewasmBlock,omitempty"`           // EWASM switch block (nil = no fork, 0 = already activated)
+	PposGasBlock         *big.Int `json:"pposGasBlock,omitempty"`         // PPOS contracts gas metering switch block (nil = no fork, 0 = already activated)
+	NodeSigBlock         *big.Int `json:"nodeSigBlock,omitempty"`         // Candidate deposit node signature switch block (nil = no fork, 0 = already activated)
+	PposEventsBlock      *big.Int `json:"pposEventsBlock,omitempty"`      // Typed PPOS events and block system logs switch block (nil = no fork, 0 = already activated)
+	BlsBlock             *big.Int `json:"blsBlock,omitempty"`             // Candidate BLS keys switch block (nil = no fork, 0 = already activated)
+	WitnessCommitBlock   *big.Int `json:"witnessCommitBlock,omitempty"`   // Witness commitment in the switch block headers switch block (nil = no fork, 0 = already activated)
+	VCVerifierBlock      *big.Int `json:"vcVerifierBlock,omitempty"`      // VC result proof verifier switch block (nil = no fork, 0 = already activated)
+	CandidateUpdateBlock *big.Int `json:"candidateUpdateBlock,omitempty"` // Candidate deposit top ups and in place updates switch block (nil = no fork, 0 = already activated)
+	GovernanceBlock      *big.Int `json:"governanceBlock,omitempty"`      // PPOS parameter governance switch block (nil = no fork, 0 = already activated)
+	TicketTransferBlock  *big.Int `json:"ticketTransferBlock,omitempty"`  // Ticket transfers and re-delegations switch block (nil = no fork, 0 = already activated)
+	LivenessBlock        *big.Int `json:"livenessBlock,omitempty"`        // Witness liveness and jailing switch block (nil = no fork, 0 = already activated)
+	SentryBlock          *big.Int `json:"sentryBlock,omitempty"`          // Candidate sentries switch block (nil = no fork, 0 = already activated)
 
 	// Various consensus engines
 	Ethash *EthashConfig `json:"ethash,omitempty"`
@@ -339,12 +422,34 @@ type CandidateConfig struct {
 	MaxCount          uint32
 	MaxChair          uint32
 	RefundBlockNumber uint32
+	// block interval before a fee change takes effect
+	FeeChangeDelay uint32
+	// missed block slots in an election round before a witness is jailed, zero disables jailing
+	LivenessThreshold uint32
+	// part of the deposit forfeited to the reward pool when a witness is jailed
+	LivenessPenalty string
+	// blocks a jailed witness stays jailed before it can be unjailed
+	JailPeriod uint32
+	// sample the witnesses from the top MaxCount candidates weighted by deposit plus
+	// ticket value, instead of electing the top MaxChair
+	RandomElection bool
 }
 
 type TicketConfig struct {
 	TicketPrice       string
 	MaxCount          uint32
 	ExpireBlockNumber uint32
+	// block interval between ticket price adjustments, zero keeps the price fixed
+	PriceEpoch uint32
+	// percentage of sold tickets the adjustments steer the pool to
+	TargetUtilization uint32
+	// the price changes at most by 1/PriceAdjustDenominator per epoch
+	PriceAdjustDenominator uint32
+	// bounds of the adjusted price, empty for no bound
+	MinTicketPrice string
+	MaxTicketPrice string
+	// blocks before an election in which tickets can't be transferred or re-delegated
+	TransferCooldown uint32
 }
 
 // CliqueConfig is the consensus engine configs for proof-of-authority based sealing.
@@ -426,6 +531,61 @@ func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return isForked(c.EWASMBlock, num)
 }
 
+// IsPposGas returns whether num is either equal to the PPOS gas metering fork block or greater.
+func (c *ChainConfig) IsPposGas(num *big.Int) bool {
+	return isForked(c.PposGasBlock, num)
+}
+
+// IsNodeSig returns whether num is either equal to the candidate deposit node signature fork block or greater.
+func (c *ChainConfig) IsNodeSig(num *big.Int) bool {
+	return isForked(c.NodeSigBlock, num)
+}
+
+// IsPposEvents returns whether num is either equal to the typed PPOS events and system logs fork block or greater.
+func (c *ChainConfig) IsPposEvents(num *big.Int) bool {
+	return isForked(c.PposEventsBlock, num)
+}
+
+// IsBls returns whether num is either equal to the candidate BLS keys fork block or greater.
+func (c *ChainConfig) IsBls(num *big.Int) bool {
+	return isForked(c.BlsBlock, num)
+}
+
+// IsWitnessCommit returns whether num is either equal to the witness commitment fork block or greater.
+func (c *ChainConfig) IsWitnessCommit(num *big.Int) bool {
+	return isForked(c.WitnessCommitBlock, num)
+}
+
+// IsVCVerifier returns whether num is either equal to the VC verifier fork block or greater.
+func (c *ChainConfig) IsVCVerifier(num *big.Int) bool {
+	return isForked(c.VCVerifierBlock, num)
+}
+
+// IsCandidateUpdate returns whether num is either equal to the candidate update fork block or greater.
+func (c *ChainConfig) IsCandidateUpdate(num *big.Int) bool {
+	return isForked(c.CandidateUpdateBlock, num)
+}
+
+// IsGovernance returns whether num is either equal to the governance fork block or greater.
+func (c *ChainConfig) IsGovernance(num *big.Int) bool {
+	return isForked(c.GovernanceBlock, num)
+}
+
+// IsTicketTransfer returns whether num is either equal to the ticket transfer fork block or greater.
+func (c *ChainConfig) IsTicketTransfer(num *big.Int) bool {
+	return isForked(c.TicketTransferBlock, num)
+}
+
+// IsLiveness returns whether num is either equal to the liveness fork block or greater.
+func (c *ChainConfig) IsLiveness(num *big.Int) bool {
+	return isForked(c.LivenessBlock, num)
+}
+
+// IsSentry returns whether num is either equal to the sentry fork block or greater.
+func (c *ChainConfig) IsSentry(num *big.Int) bool {
+	return isForked(c.SentryBlock, num)
+}
+
 // GasTable returns the gas table corresponding to the current phase (homestead or homestead reprice).
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
@@ -445,6 +605,17 @@ func (c *ChainConfig) GasTable(num *big.Int) GasTable {
 	}
 }
 
+// PposGasTable returns the gas table of the PPOS pre-compiled contracts corresponding
+// to the current phase.
+//
+// The returned PposGasTable's fields shouldn't, under any circumstances, be changed.
+func (c *ChainConfig) PposGasTable(num *big.Int) PposGasTable {
+	if c.IsPposGas(num) {
+		return PposGasTableMetered
+	}
+	return PposGasTableFlat
+}
+
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
 func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *ConfigCompatError {
@@ -494,6 +665,39 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if isForkIncompatible(c.PposGasBlock, newcfg.PposGasBlock, head) {
+		return newCompatError("PPOS gas fork block", c.PposGasBlock, newcfg.PposGasBlock)
+	}
+	if isForkIncompatible(c.NodeSigBlock, newcfg.NodeSigBlock, head) {
+		return newCompatError("Node signature fork block", c.NodeSigBlock, newcfg.NodeSigBlock)
+	}
+	if isForkIncompatible(c.PposEventsBlock, newcfg.PposEventsBlock, head) {
+		return newCompatError("PPOS events fork block", c.PposEventsBlock, newcfg.PposEventsBlock)
+	}
+	if isForkIncompatible(c.BlsBlock, newcfg.BlsBlock, head) {
+		return newCompatError("BLS fork block", c.BlsBlock, newcfg.BlsBlock)
+	}
+	if isForkIncompatible(c.WitnessCommitBlock, newcfg.WitnessCommitBlock, head) {
+		return newCompatError("Witness commitment fork block", c.WitnessCommitBlock, newcfg.WitnessCommitBlock)
+	}
+	if isForkIncompatible(c.VCVerifierBlock, newcfg.VCVerifierBlock, head) {
+		return newCompatError("VC verifier fork block", c.VCVerifierBlock, newcfg.VCVerifierBlock)
+	}
+	if isForkIncompatible(c.CandidateUpdateBlock, newcfg.CandidateUpdateBlock, head) {
+		return newCompatError("Candidate update fork block", c.CandidateUpdateBlock, newcfg.CandidateUpdateBlock)
+	}
+	if isForkIncompatible(c.GovernanceBlock, newcfg.GovernanceBlock, head) {
+		return newCompatError("Governance fork block", c.GovernanceBlock, newcfg.GovernanceBlock)
+	}
+	if isForkIncompatible(c.TicketTransferBlock, newcfg.TicketTransferBlock, head) {
+		return newCompatError("Ticket transfer fork block", c.TicketTransferBlock, newcfg.TicketTransferBlock)
+	}
+	if isForkIncompatible(c.LivenessBlock, newcfg.LivenessBlock, head) {
+		return newCompatError("Liveness fork block", c.LivenessBlock, newcfg.LivenessBlock)
+	}
+	if isForkIncompatible(c.SentryBlock, newcfg.SentryBlock, head) {
+		return newCompatError("Sentry fork block", c.SentryBlock, newcfg.SentryBlock)
+	}
 	return nil
 }
 
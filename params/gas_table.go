@@ -91,3 +91,33 @@ var (
 		CreateBySuicide: 25000,
 	}
 )
+
+// PposGasTable organizes gas prices of the PPOS pre-compiled contracts for
+// different phases. The cost of a call is Call plus the cost of the work
+// done by the called command.
+type PposGasTable struct {
+	Call        uint64 // Base cost of every call
+	Ticket      uint64 // Per ticket created, transferred or redelegated
+	QueueUpdate uint64 // Per update of the candidate queues
+	QueryItem   uint64 // Per item returned by a query
+	Refund      uint64 // Per refund checked by a withdrawal
+}
+
+// Variables containing gas prices of the PPOS pre-compiled contracts for different phases.
+var (
+	// PposGasTableFlat contain the gas prices before the
+	// PPOS gas metering phase, every call costs the same.
+	PposGasTableFlat = PposGasTable{
+		Call: EcrecoverGas,
+	}
+
+	// PposGasTableMetered contain the gas prices of the
+	// PPOS gas metering phase.
+	PposGasTableMetered = PposGasTable{
+		Call:        EcrecoverGas,
+		Ticket:      400,
+		QueueUpdate: 20000,
+		QueryItem:   800,
+		Refund:      9000,
+	}
+)
@@ -160,11 +160,12 @@ type AbiDb struct {
 	db           map[string]string
 	customdb     map[string]string
 	customdbPath string
+	wasmdb       map[common.Address]string // WASM contract address -> abi definition
 }
 
 // NewEmptyAbiDB exists for test purposes
 func NewEmptyAbiDB() (*AbiDb, error) {
-	return &AbiDb{make(map[string]string), make(map[string]string), ""}, nil
+	return &AbiDb{make(map[string]string), make(map[string]string), "", make(map[common.Address]string)}, nil
 }
 
 // NewAbiDBFromFile loads signature database from file, and
@@ -186,7 +187,7 @@ func NewAbiDBFromFile(path string) (*AbiDb, error) {
 // to write new values into if they are submitted via the API
 func NewAbiDBFromFiles(standard, custom string) (*AbiDb, error) {
 
-	db := &AbiDb{make(map[string]string), make(map[string]string), custom}
+	db := &AbiDb{make(map[string]string), make(map[string]string), custom, make(map[common.Address]string)}
 	db.customdbPath = custom
 
 	raw, err := ioutil.ReadFile(standard)
@@ -127,6 +127,7 @@ type (
 	SignTxRequest struct {
 		Transaction SendTxArgs       `json:"transaction"`
 		Callinfo    []ValidationInfo `json:"call_info"`
+		Call        *CallInfo        `json:"call,omitempty"`
 		Meta        Metadata         `json:"meta"`
 	}
 	// SignTxResponse result from SignTxRequest
@@ -326,6 +327,7 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args SendTxArgs, meth
 		Transaction: args,
 		Meta:        MetadataFromContext(ctx),
 		Callinfo:    msgs.Messages,
+		Call:        msgs.Call,
 	}
 	// Process approval
 	result, err = api.UI.ApproveTx(&req)
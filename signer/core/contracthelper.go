@@ -0,0 +1,225 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/life/utils"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+// CallArg is a decoded argument of a PPOS or WASM contract call. The values are
+// conveyed as strings, integers in decimal, so that the rules don't lose precision.
+type CallArg struct {
+	Name  string `json:"name,omitempty"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// CallInfo is a PPOS or WASM contract call decoded from the transaction data.
+type CallInfo struct {
+	Kind   string    `json:"kind"` // "ppos" or "wasm"
+	TxType uint64    `json:"tx_type"`
+	Name   string    `json:"name"`
+	Args   []CallArg `json:"args"`
+}
+
+// String implements stringer interface for CallInfo
+func (c CallInfo) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = fmt.Sprintf("%v: %v", arg.Type, arg.Value)
+	}
+	return fmt.Sprintf("%s(%s)", c.Name, strings.Join(args, ","))
+}
+
+// isPposContract reports whether addr is one of the PPOS precompiled contracts.
+func isPposContract(addr common.Address) bool {
+	_, ok := vm.PrecompiledContractsPpos[addr]
+	return ok
+}
+
+// parsePposCallData decodes the call data of a call to the PPOS contract at addr
+// against the command table of the contract.
+func parsePposCallData(addr common.Address, calldata []byte) (*CallInfo, error) {
+	call, err := vm.DecodePposInput(addr, calldata)
+	if err != nil {
+		return nil, err
+	}
+	info := &CallInfo{Kind: "ppos", TxType: call.TxType, Name: call.Name}
+	for i, arg := range call.Args {
+		info.Args = append(info.Args, CallArg{Type: call.Types[i], Value: formatPposArg(arg)})
+	}
+	return info, nil
+}
+
+func formatPposArg(arg interface{}) string {
+	switch v := arg.(type) {
+	case []byte:
+		return hexutil.Encode(v)
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	case [64]byte:
+		return hexutil.Encode(v[:])
+	case discover.NodeID:
+		return hexutil.Encode(v[:])
+	case []discover.NodeID:
+		ids := make([]string, len(v))
+		for i, id := range v {
+			ids[i] = hexutil.Encode(id[:])
+		}
+		return strings.Join(ids, ":")
+	case common.Hash:
+		return v.Hex()
+	case []common.Hash:
+		hashes := make([]string, len(v))
+		for i, hash := range v {
+			hashes[i] = hash.Hex()
+		}
+		return strings.Join(hashes, ":")
+	case common.Address:
+		return v.Hex()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// parseWasmCallData decodes the call data [txType][funcName][args...] of a WASM
+// contract call against the abi definition of the contract, the same way as the
+// WASM interpreter does.
+func parseWasmCallData(calldata []byte, abidata string) (*CallInfo, error) {
+	var source [][]byte
+	if err := rlp.Decode(bytes.NewReader(calldata), &source); err != nil {
+		return nil, fmt.Errorf("Not WASM call data: %v", err)
+	}
+	if len(source) < 2 {
+		return nil, fmt.Errorf("Invalid WASM call data, %d elements", len(source))
+	}
+	wasmabi := new(utils.WasmAbi)
+	if err := wasmabi.FromJson([]byte(abidata)); err != nil {
+		return nil, fmt.Errorf("Failed parsing WASM ABI: %v", err)
+	}
+	info := &CallInfo{Kind: "wasm", TxType: uint64(common.BytesToInt64(source[0])), Name: string(source[1])}
+
+	var method *utils.AbiStruct
+	for i, v := range wasmabi.AbiArr {
+		if strings.EqualFold(info.Name, v.Name) && strings.EqualFold(v.Type, "function") {
+			method = &wasmabi.AbiArr[i]
+			break
+		}
+	}
+	if method == nil {
+		return nil, fmt.Errorf("Function %v not found in WASM ABI", info.Name)
+	}
+	args := source[2:]
+	if len(args) != len(method.Inputs) {
+		return nil, fmt.Errorf("Invalid WASM call data, %d arguments for %v with %d inputs", len(args), method.Name, len(method.Inputs))
+	}
+	for i, input := range method.Inputs {
+		value, err := formatWasmArg(input.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode argument %d (%v): %v", i, input.Name, err)
+		}
+		info.Args = append(info.Args, CallArg{Name: input.Name, Type: input.Type, Value: value})
+	}
+	return info, nil
+}
+
+// formatWasmArg formats an argument of the WASM type typ, the integers are big
+// endian of the size of the type.
+func formatWasmArg(typ string, arg []byte) (string, error) {
+	size := map[string]int{
+		"int8": 1, "uint8": 1, "bool": 1,
+		"int16": 2, "uint16": 2,
+		"int32": 4, "int": 4, "uint32": 4, "uint": 4, "float32": 4,
+		"int64": 8, "uint64": 8, "float64": 8,
+	}
+	if typ == "string" {
+		return string(arg), nil
+	}
+	n, ok := size[typ]
+	if !ok {
+		return "", fmt.Errorf("unsupported type %v", typ)
+	}
+	if len(arg) != n {
+		return "", fmt.Errorf("%d bytes for %v", len(arg), typ)
+	}
+	switch typ {
+	case "int8":
+		return strconv.FormatInt(int64(int8(arg[0])), 10), nil
+	case "int16":
+		return strconv.FormatInt(int64(int16(binary.BigEndian.Uint16(arg))), 10), nil
+	case "int32", "int":
+		return strconv.FormatInt(int64(int32(binary.BigEndian.Uint32(arg))), 10), nil
+	case "int64":
+		return strconv.FormatInt(int64(binary.BigEndian.Uint64(arg)), 10), nil
+	case "bool":
+		return strconv.FormatBool(arg[0] != 0), nil
+	case "float32":
+		return strconv.FormatFloat(float64(math.Float32frombits(binary.BigEndian.Uint32(arg))), 'g', -1, 32), nil
+	case "float64":
+		return strconv.FormatFloat(math.Float64frombits(binary.BigEndian.Uint64(arg)), 'g', -1, 64), nil
+	default:
+		var v uint64
+		for _, b := range arg {
+			v = v<<8 | uint64(b)
+		}
+		return strconv.FormatUint(v, 10), nil
+	}
+}
+
+// LoadWasmAbis loads the abi definitions of WASM contracts from file, a json
+// object of the contract addresses to their abi arrays.
+func (db *AbiDb) LoadWasmAbis(path string) error {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var abis map[common.Address]json.RawMessage
+	if err := json.Unmarshal(raw, &abis); err != nil {
+		return err
+	}
+	for addr, abi := range abis {
+		db.wasmdb[addr] = string(abi)
+	}
+	return nil
+}
+
+// AddWasmAbi adds the abi definition of the WASM contract at addr.
+func (db *AbiDb) AddWasmAbi(addr common.Address, abidata string) {
+	db.wasmdb[addr] = abidata
+}
+
+// LookupWasmAbi returns the abi definition of the WASM contract at addr.
+func (db *AbiDb) LookupWasmAbi(addr common.Address) (string, bool) {
+	abi, ok := db.wasmdb[addr]
+	return abi, ok
+}
@@ -0,0 +1,131 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/byteutil"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+var testNodeID = discover.MustHexID("0x1f3a8672348ff6b789e416762ad53e69063138b8eb4d8780101658f24b2369f1a8e09499226b467d8bc0c4e03e1dc903df857eeb3c67733d21b6aaee2840e429")
+
+func TestParsePposCallData(t *testing.T) {
+	input, err := vm.EncodeInput("VoteTicket", uint32(10), big.NewInt(1000), testNodeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := parsePposCallData(common.TicketPoolAddr, input)
+	if err != nil {
+		t.Fatalf("failed to parse VoteTicket: %v", err)
+	}
+	if info.Kind != "ppos" || info.TxType != 1000 || info.Name != "VoteTicket" || len(info.Args) != 3 {
+		t.Fatalf("call mismatch: %+v", info)
+	}
+	want := []CallArg{
+		{Type: "uint32", Value: "10"},
+		{Type: "*big.Int", Value: "1000"},
+		{Type: "discover.NodeID", Value: hexutil.Encode(testNodeID[:])},
+	}
+	for i, arg := range info.Args {
+		if arg != want[i] {
+			t.Errorf("arg %d mismatch: have %+v, want %+v", i, arg, want[i])
+		}
+	}
+	// Commands of another contract, wrong tx types and arguments are rejected
+	if _, err := parsePposCallData(common.CandidatePoolAddr, input); err == nil {
+		t.Errorf("expected error for VoteTicket to the candidate pool")
+	}
+	bad, _ := rlp.EncodeToBytes([][]byte{byteutil.Uint64ToBytes(1001), []byte("VoteTicket"), {10}, {1}, []byte(testNodeID.String())})
+	if _, err := parsePposCallData(common.TicketPoolAddr, bad); err != vm.ErrTxType {
+		t.Errorf("error mismatch: have %v, want %v", err, vm.ErrTxType)
+	}
+	bad, _ = rlp.EncodeToBytes([][]byte{byteutil.Uint64ToBytes(1000), []byte("VoteTicket"), make([]byte, 5), {1}, []byte(testNodeID.String())})
+	if _, err := parsePposCallData(common.TicketPoolAddr, bad); err == nil {
+		t.Errorf("expected error for oversized uint32")
+	}
+}
+
+const testWasmAbi = `[{"name":"transfer","inputs":[{"name":"to","type":"string"},{"name":"amount","type":"uint64"},{"name":"memo","type":"int32"}],"outputs":[],"constant":"false","type":"function"}]`
+
+func TestParseWasmCallData(t *testing.T) {
+	input, _ := rlp.EncodeToBytes([][]byte{byteutil.Uint64ToBytes(2), []byte("transfer"), []byte("bob"), byteutil.Uint64ToBytes(500), {0xff, 0xff, 0xff, 0xfe}})
+	info, err := parseWasmCallData(input, testWasmAbi)
+	if err != nil {
+		t.Fatalf("failed to parse transfer: %v", err)
+	}
+	if info.String() != "transfer(string: bob,uint64: 500,int32: -2)" {
+		t.Errorf("summary mismatch: %v", info)
+	}
+	if info.Kind != "wasm" || info.TxType != 2 || info.Args[1].Name != "amount" {
+		t.Errorf("call mismatch: %+v", info)
+	}
+	// Unknown functions and malformed arguments are rejected
+	input, _ = rlp.EncodeToBytes([][]byte{byteutil.Uint64ToBytes(2), []byte("burn")})
+	if _, err := parseWasmCallData(input, testWasmAbi); err == nil {
+		t.Errorf("expected error for unknown function")
+	}
+	input, _ = rlp.EncodeToBytes([][]byte{byteutil.Uint64ToBytes(2), []byte("transfer"), []byte("bob"), {1, 244}, {0, 0, 0, 1}})
+	if _, err := parseWasmCallData(input, testWasmAbi); err == nil {
+		t.Errorf("expected error for short uint64")
+	}
+}
+
+func TestValidatorPlatonCalls(t *testing.T) {
+	db, _ := NewEmptyAbiDB()
+	v := NewValidator(db)
+
+	wasm := common.HexToAddress("0x00000000000000000000000000000000000c0de1")
+	db.AddWasmAbi(wasm, testWasmAbi)
+
+	vote, _ := vm.EncodeInput("VoteTicket", uint32(10), big.NewInt(1000), testNodeID)
+	transfer, _ := rlp.EncodeToBytes([][]byte{byteutil.Uint64ToBytes(2), []byte("transfer"), []byte("bob"), byteutil.Uint64ToBytes(500), {0, 0, 0, 1}})
+
+	tests := []struct {
+		to   common.Address
+		data []byte
+		name string
+	}{
+		{common.TicketPoolAddr, vote, "VoteTicket"},
+		{wasm, transfer, "transfer"},
+		{common.CandidatePoolAddr, vote, ""},
+		{wasm, vote, ""},
+	}
+	for i, test := range tests {
+		to := common.NewMixedcaseAddress(test.to)
+		data := hexutil.Bytes(test.data)
+		msgs, err := v.ValidateTransaction(&SendTxArgs{To: &to, Data: &data}, nil)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if len(msgs.Messages) != 1 {
+			t.Fatalf("test %d: expected 1 message, got %v", i, msgs.Messages)
+		}
+		switch {
+		case test.name == "" && (msgs.Call != nil || msgs.Messages[0].Typ != "WARNING"):
+			t.Errorf("test %d: expected warning, got %v", i, msgs.Messages)
+		case test.name != "" && (msgs.Call == nil || msgs.Call.Name != test.name || msgs.Messages[0].Typ != "Info"):
+			t.Errorf("test %d: expected %v call, got %v", i, test.name, msgs.Messages)
+		}
+	}
+}
@@ -58,6 +58,7 @@ type ValidationInfo struct {
 }
 type ValidationMessages struct {
 	Messages []ValidationInfo
+	Call     *CallInfo // Decoded PPOS or WASM call, if any
 }
 
 // SendTxArgs represents the arguments to submit a transaction
@@ -102,6 +102,37 @@ func (v *Validator) validateCallData(msgs *ValidationMessages, data []byte, meth
 	}
 }
 
+// validatePlatonCallData checks if the data of a call to a PPOS contract, or to a
+// WASM contract of known abi, can be parsed. It returns false if the call is to
+// neither of them.
+func (v *Validator) validatePlatonCallData(msgs *ValidationMessages, to common.Address, data []byte) bool {
+	var (
+		info *CallInfo
+		err  error
+	)
+	if isPposContract(to) {
+		info, err = parsePposCallData(to, data)
+	} else if abi, ok := v.db.LookupWasmAbi(to); ok && len(data) > 0 {
+		info, err = parseWasmCallData(data, abi)
+	} else {
+		return false
+	}
+	if err != nil {
+		msgs.warn(fmt.Sprintf("Tx contains data, but it could not be decoded as a %v call: %v", contractKind(to), err))
+	} else {
+		msgs.info(info.String())
+		msgs.Call = info
+	}
+	return true
+}
+
+func contractKind(addr common.Address) string {
+	if isPposContract(addr) {
+		return "PPOS"
+	}
+	return "WASM"
+}
+
 // validateSemantics checks if the transactions 'makes sense', and generate warnings for a couple of typical scenarios
 func (v *Validator) validate(msgs *ValidationMessages, txargs *SendTxArgs, methodSelector *string) error {
 	// Prevent accidental erroneous usage of both 'input' and 'data'
@@ -150,7 +181,9 @@ func (v *Validator) validate(msgs *ValidationMessages, txargs *SendTxArgs, metho
 			msgs.crit("Tx destination is the zero address!")
 		}
 		// Validate calldata
-		v.validateCallData(msgs, data, methodSelector)
+		if !v.validatePlatonCallData(msgs, txargs.To.Address(), data) {
+			v.validateCallData(msgs, data, methodSelector)
+		}
 	}
 	return nil
 }
@@ -504,6 +504,56 @@ func TestLimitWindow(t *testing.T) {
 
 }
 
+const ExampleTicketLimit = `
+	var limit = new BigNumber("2500");
+
+	function ApproveTx(r){
+		if(!r.call || r.call.kind != "ppos" || r.call.name != "VoteTicket"){
+			return
+		}
+		var spend = new BigNumber(r.call.args[0].value).times(new BigNumber(r.call.args[1].value));
+		var stored = storage.Get('spend');
+		var sum = new BigNumber(stored != "" ? stored : 0);
+		if(sum.plus(spend).gt(limit)){
+			return "Reject"
+		}
+		storage.Put("spend", sum.plus(spend).toString(10));
+		return "Approve"
+	}
+`
+
+func dummyVoteTx(count, price string) *core.SignTxRequest {
+	req := dummyTx(hexutil.Big(*big.NewInt(0)))
+	req.Call = &core.CallInfo{Kind: "ppos", TxType: 1000, Name: "VoteTicket", Args: []core.CallArg{
+		{Type: "uint32", Value: count},
+		{Type: "*big.Int", Value: price},
+		{Type: "discover.NodeID", Value: "0x01"},
+	}}
+	return req
+}
+
+func TestPposCallRule(t *testing.T) {
+	r, err := initRuleEngine(ExampleTicketLimit)
+	if err != nil {
+		t.Errorf("Couldn't create evaluator %v", err)
+		return
+	}
+	// Two votes of 1000 are within the limit, the third exceeds it
+	for i := 0; i < 3; i++ {
+		resp, err := r.ApproveTx(dummyVoteTx("10", "100"))
+		if err != nil {
+			t.Errorf("Unexpected error %v", err)
+		}
+		if resp.Approved != (i < 2) {
+			t.Errorf("Vote %d: approved %v, want %v", i, resp.Approved, i < 2)
+		}
+	}
+	// Other transactions are left to the next UI, which denies them
+	if resp, _ := r.ApproveTx(dummyTxWithV(1)); resp.Approved {
+		t.Errorf("Expected plain transfer to go to manual processing")
+	}
+}
+
 // dontCallMe is used as a next-handler that does not want to be called - it invokes test failure
 type dontCallMe struct {
 	t *testing.T